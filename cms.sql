@@ -275,3 +275,6 @@ INSERT INTO tbl_email_templates(id, template_slug,template_subject,template_desc
 
 INSERT INTO tbl_email_templates(id, template_slug,template_subject,template_description,template_message,module_id,created_on,created_by,is_deleted,is_active,template_name,tenant_id)VALUES(5,'Logined successfully','User login successfully','User conformation account is logged in successfully','<tr><td><p style="margin-left:0;">&nbsp;</p><h1 style="font-size: 20px; font-weight: bold;line-height: 27px;color:#000000; margin:0 0  12px 0;">Dear <strong>{FirstName}</strong>,</h1></td></tr><tr><td><p style="color:#000000;font-size:14px;">Congratulations! Your SpurtCMS account has been logined successfully.</p><p  style="color:#000000;font-size:14px;margin:0 0 16px;">Start using your Admin Account.</p></td></tr><tr><td><p style="color:#000000;font-size:16px;line-height:normal;margin:0 0 12px;">Best Regards,</p><p style="color:#000000;font-size:16px;font-weight:500;line-height:24px;margin:0 0 16px;"><strong>Spurt CMS Admin</strong></p></td></tr>',0,'current-time',1, 0,1,'Send User Login Email',1)
 
+INSERT INTO tbl_email_templates(id, template_slug,template_subject,template_description,template_message,module_id,created_on,created_by,is_deleted,is_active,template_name,tenant_id)VALUES(6,'Memberverification','Confirm your email address','Sends members who register themselves a link to confirm their email address.','<tr><td><p style="margin-left:0;">&nbsp;</p><h1 style="font-size: 20px; font-weight: bold;line-height: 27px;color:#000000; margin:0 0  12px 0;">Dear <strong>{FirstName}</strong>,</h1></td></tr><tr><td><p style="color:#000000;font-size:14px;">Thank you for registering. Please confirm your email address to activate your membership.</p><p style="color:#000000;font-size:14px;"><a href="{VerificationUrl}" style="color: #2FACD6;text-decoration: underline;">{VerificationUrl}</a></p><p style="color:#000000;font-size:14px;">Verification code: <strong>{VerificationToken}</strong></p><p style="color:#000000;font-size:14px;margin:0 0 16px;">This link expires in {Expirytime}. If you did not register, you can ignore this email.</p></td></tr><tr><td><p style="color:#000000;font-size:16px;line-height:normal;margin:0 0 12px;">Best Regards,</p><p style="color:#000000;font-size:16px;font-weight:500;line-height:24px;margin:0 0 16px;"><strong>Spurt CMS Admin</strong></p></td></tr>' ,5,'current-time',1, 0,1,'Member Email Verification',1)
+
+INSERT INTO tbl_email_templates(id, template_slug,template_subject,template_description,template_message,module_id,created_on,created_by,is_deleted,is_active,template_name,tenant_id)VALUES(7,'Entryreview','{EntryTitle} is {State}','Notifies the reviewers of an entry in a review workflow and its author when it is rejected.','<tr><td><p style="margin-left:0;">&nbsp;</p><h1 style="font-size: 20px; font-weight: bold;line-height: 27px;color:#000000; margin:0 0  12px 0;">Dear <strong>{FirstName}</strong>,</h1></td></tr><tr><td><p style="color:#000000;font-size:14px;">{ActorName} moved the entry <strong>{EntryTitle}</strong> of the {ChannelName} channel to <strong>{State}</strong>.</p><p style="color:#000000;font-size:14px;">{Comment}</p><p style="color:#000000;font-size:14px;margin:0 0 16px;"><a href="{EntryUrl}" style="color: #2FACD6;text-decoration: underline;">Open the entry</a></p></td></tr><tr><td><p style="color:#000000;font-size:16px;line-height:normal;margin:0 0 12px;">Best Regards,</p><p style="color:#000000;font-size:16px;font-weight:500;line-height:24px;margin:0 0 16px;"><strong>Spurt CMS Admin</strong></p></td></tr>' ,3,'current-time',1, 0,1,'Entry Review',1)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"spurt-cms/events"
 	storagecontroller "spurt-cms/storage-controller"
 	"strconv"
 	"strings"
@@ -159,6 +160,8 @@ func CreateCategoryGroup(c *gin.Context) {
 			return
 		}
 
+		publishCategoryEvent(events.CategoryCreated, 0)
+
 		c.SetCookie("get-toast", "Category Group Created Successfully", 3600, "", "", false, false)
 		c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		c.Redirect(http.StatusMovedPermanently, "/categories/")
@@ -217,6 +220,8 @@ func UpdateCategoryGroup(c *gin.Context) {
 			return
 		}
 
+		publishCategoryEvent(events.CategoryUpdated, id)
+
 		c.SetCookie("get-toast", "Category Group Updated Successfully", 3600, "", "", false, false)
 		c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		c.Redirect(http.StatusMovedPermanently, url)
@@ -252,6 +257,8 @@ func DeleteCategoryGroup(c *gin.Context) {
 
 	if permisison {
 
+		saveCategoryLinks([]int{categoryId})
+
 		err := CategoryConfig.DeleteCategoryGroup(categoryId, userid, TenantId)
 
 		if strings.Contains(fmt.Sprint(err), "given some values is empty") {
@@ -268,6 +275,8 @@ func DeleteCategoryGroup(c *gin.Context) {
 			return
 		}
 
+		publishCategoryEvent(events.CategoryDeleted, categoryId)
+
 		c.SetCookie("get-toast", "Category Group Deleted Successfully", 3600, "", "", false, false)
 		c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		c.Redirect(301, url)
@@ -468,6 +477,8 @@ func AddSubCategory(c *gin.Context) {
 			return
 		}
 
+		publishCategoryEvent(events.CategoryCreated, 0)
+
 		c.SetCookie("get-toast", "Category Created Successfully", 3600, "", "", false, false)
 		c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		c.Redirect(301, "/categories/addcategory/"+id)
@@ -595,6 +606,8 @@ func UpdateSubCategory(c *gin.Context) {
 			return
 		}
 
+		publishCategoryEvent(events.CategoryUpdated, Categoryid)
+
 		c.SetCookie("get-toast", "Category Updated Successfully", 3600, "", "", false, false)
 		c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		c.JSON(200, gin.H{"value": true})
@@ -632,6 +645,8 @@ func DeleteSubCategory(c *gin.Context) {
 
 	if permisison {
 
+		saveCategoryLinks([]int{categoryid})
+
 		err := CategoryConfig.DeleteSubCategory(categoryid, userid, TenantId) // delete subcategory
 		if strings.Contains(fmt.Sprint(err), "given some values is empty") {
 			ErrorLog.Printf("deletesubcategory mandatory error:")
@@ -644,6 +659,7 @@ func DeleteSubCategory(c *gin.Context) {
 			ErrorLog.Printf("deletesubcategory error: %s", perr)
 			c.SetCookie("Alert-msg", ErrInternalServerError, 3600, "", "", false, false)
 		} else {
+			publishCategoryEvent(events.CategoryDeleted, categoryid)
 			c.SetCookie("get-toast", "Category Deleted Successfully", 3600, "", "", false, false)
 			c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		}
@@ -751,6 +767,8 @@ func MultiSelectCategoryGroupDelete(c *gin.Context) {
 			categoryIntIds[i] = intId
 		}
 
+		saveCategoryLinks(categoryIntIds)
+
 		err := CategoryConfig.MultiSelectDeleteCategoryGroup(categoryIntIds, userid, TenantId) //delete selected category group
 		if err != nil {
 			ErrorLog.Printf("MultiSelectCategoryGroupDelete error: %s", err)
@@ -758,6 +776,10 @@ func MultiSelectCategoryGroupDelete(c *gin.Context) {
 			return
 		}
 
+		for _, categoryId := range categoryIntIds {
+			publishCategoryEvent(events.CategoryDeleted, categoryId)
+		}
+
 		_, Total_categories, _ := CategoryConfig.CategoryGroupList(0, 0, cat.Filter{}, TenantId)
 
 		if pageno != "" {
@@ -807,6 +829,8 @@ func MultiSelectCategoryDelete(c *gin.Context) {
 			categoryIntIds[i] = intId
 		}
 
+		saveCategoryLinks(categoryIntIds)
+
 		err := CategoryConfig.MultiselectSubCategoryDelete(categoryIntIds, userid, TenantId) //delete selected categories
 		if err != nil {
 			ErrorLog.Printf("MultiSelectCategoriesDelete error: %s", err)
@@ -814,6 +838,10 @@ func MultiSelectCategoryDelete(c *gin.Context) {
 			return
 		}
 
+		for _, categoryId := range categoryIntIds {
+			publishCategoryEvent(events.CategoryDeleted, categoryId)
+		}
+
 		_, _, _, Total_categories, err := CategoryConfig.ListCategory(0, 0, cat.Filter{}, Parentid, TenantId)
 
 		if pageno != "" {
@@ -838,3 +866,9 @@ func MultiSelectCategoryDelete(c *gin.Context) {
 	}
 
 }
+
+// publishCategoryEvent lets the graphql api drop the cached responses of the current tenant
+func publishCategoryEvent(eventType string, categoryId int) {
+
+	events.PublishCategoryEvent(events.CategoryEvent{Type: eventType, CategoryId: categoryId, TenantId: TenantId})
+}
@@ -2,7 +2,10 @@ package controllers
 
 import (
 	"encoding/json"
+	"spurt-cms/events"
 	"spurt-cms/models"
+	"spurt-cms/revisions"
+	"spurt-cms/workflow"
 	"strconv"
 	"strings"
 
@@ -121,7 +124,7 @@ func CreateChannelPage(c *gin.Context) {
 		ModuleName, _, _ := ModuleRouteName(c)
 		translate, _ := TranslateHandler(c)
 
-		c.HTML(200, "addchannel.html", gin.H{"Menu": menu, "linktitle": "Create Channel", "title": ModuleName, "csrf": csrf.GetToken(c), "Fields": field, "Button": "Save", "AllCategories": AllCategorieswithSubCategories, "Title": "Create Channel", "Back": "/settings/channels/channellist", "HeadTitle": "Create Channel", "translate": translate, "Channelsmenu": true, "Cmsmenu": true})
+		c.HTML(200, "addchannel.html", gin.H{"Menu": menu, "linktitle": "Create Channel", "title": ModuleName, "csrf": csrf.GetToken(c), "Fields": field, "Button": "Save", "Workflow": workflow.None, "DefaultRevisionLimit": revisions.DefaultLimit, "AllCategories": AllCategorieswithSubCategories, "Title": "Create Channel", "Back": "/settings/channels/channellist", "HeadTitle": "Create Channel", "translate": translate, "Channelsmenu": true, "Cmsmenu": true})
 
 		return
 
@@ -238,6 +241,20 @@ func CreateChannel(c *gin.Context) {
 			ErrorLog.Printf("channelcreate additional field error: %s", ferr)
 		}
 
+		if cerr == nil {
+			if err := models.UpdateChannelRevisionLimit(newchannel.Id, channelRevisionLimit(c.Request.PostFormValue("revisionlimit")), TenantId); err != nil {
+				ErrorLog.Printf("channelcreate revision limit error: %s", err)
+			}
+
+			if workflow.Valid(c.Request.PostFormValue("workflow")) {
+				if err := models.UpdateChannelWorkflow(newchannel.Id, c.Request.PostFormValue("workflow"), TenantId); err != nil {
+					ErrorLog.Printf("channelcreate workflow error: %s", err)
+				}
+			}
+
+			publishChannelEvent(events.ChannelCreated, newchannel.Id)
+		}
+
 		c.SetCookie("get-toast", "Channel Created Successfully", 3600, "", "", false, false)
 		c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		json.NewEncoder(c.Writer).Encode(true)
@@ -280,11 +297,21 @@ func EditChannel(c *gin.Context) {
 
 		AllCategorieswithSubCategories, _ := CategoryConfig.AllCategoriesWithSubList(TenantId)
 
+		revisionlimit, err := models.GetChannelRevisionLimit(id, TenantId)
+		if err != nil {
+			ErrorLog.Printf("editchannel revision limit error: %s", err)
+		}
+
+		channelworkflow, err := models.GetChannelWorkflow(id, TenantId)
+		if err != nil {
+			ErrorLog.Printf("editchannel workflow error: %s", err)
+		}
+
 		menu := NewMenuController(c)
 		translate, _ := TranslateHandler(c)
 		ModuleName, _, _ := ModuleRouteName(c)
 
-		c.HTML(200, "addchannel.html", gin.H{"Menu": menu, "Channelname": channelname, "translate": translate, "csrf": csrf.GetToken(c), "Fields": field, "Button": "Update", "channel": chndata, "AllCategories": AllCategorieswithSubCategories, "title": ModuleName, "linktitle": "Edit Channel", "Back": "/settings/channels/channellist", "Page": page, "HeadTitle": translate.Channell.Channels, "ChannelId": id, "SelectedCategories": FinalSelectedCategories, "Channelsmenu": true, "Cmsmenu": true, "Title": "Edit Channel", "CurrentPage": pageno})
+		c.HTML(200, "addchannel.html", gin.H{"Menu": menu, "Channelname": channelname, "RevisionLimit": revisionlimit, "Workflow": channelworkflow, "DefaultRevisionLimit": revisions.DefaultLimit, "translate": translate, "csrf": csrf.GetToken(c), "Fields": field, "Button": "Update", "channel": chndata, "AllCategories": AllCategorieswithSubCategories, "title": ModuleName, "linktitle": "Edit Channel", "Back": "/settings/channels/channellist", "Page": page, "HeadTitle": translate.Channell.Channels, "ChannelId": id, "SelectedCategories": FinalSelectedCategories, "Channelsmenu": true, "Cmsmenu": true, "Title": "Edit Channel", "CurrentPage": pageno})
 
 		return
 
@@ -521,6 +548,22 @@ func UpdateChannel(c *gin.Context) {
 		if ferr != nil {
 			ErrorLog.Printf("edit channel additional field error: %s", ferr)
 		}
+
+		// the older forms do not send the limit, their saves keep the configured one
+		if _, ok := c.Request.PostForm["revisionlimit"]; ok {
+			if err := models.UpdateChannelRevisionLimit(channelid, channelRevisionLimit(c.Request.PostFormValue("revisionlimit")), TenantId); err != nil {
+				ErrorLog.Printf("edit channel revision limit error: %s", err)
+			}
+		}
+
+		if _, ok := c.Request.PostForm["workflow"]; ok && workflow.Valid(c.Request.PostFormValue("workflow")) {
+			if err := models.UpdateChannelWorkflow(channelid, c.Request.PostFormValue("workflow"), TenantId); err != nil {
+				ErrorLog.Printf("edit channel workflow error: %s", err)
+			}
+		}
+
+		publishChannelEvent(events.ChannelUpdated, channelid)
+
 		c.SetCookie("get-toast", "Channel Updated Successfully", 3600, "", "", false, false)
 		c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		json.NewEncoder(c.Writer).Encode(true)
@@ -574,6 +617,12 @@ func DeleteChannel(c *gin.Context) {
 			return
 		}
 
+		if merr := models.MarkChannelDeleted(channelid, userid, TenantId); merr != nil {
+			ErrorLog.Printf("delete channel error: %s", merr)
+		}
+
+		publishChannelEvent(events.ChannelDeleted, channelid)
+
 		c.SetCookie("get-toast", "Channel Deleted Successfully", 3600, "", "", false, false)
 		c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		c.Redirect(301, url)
@@ -605,6 +654,7 @@ func ChannelIsActiveStatus(c *gin.Context) {
 			json.NewEncoder(c.Writer).Encode(flg)
 			return
 		}
+		publishChannelEvent(events.ChannelUpdated, id)
 		json.NewEncoder(c.Writer).Encode(flg)
 		return
 
@@ -614,6 +664,24 @@ func ChannelIsActiveStatus(c *gin.Context) {
 
 }
 
+// publishChannelEvent lets the graphql api rebuild the typed schema of the current tenant
+func publishChannelEvent(eventType string, channelId int) {
+
+	events.PublishChannelEvent(events.ChannelEvent{Type: eventType, ChannelId: channelId, TenantId: TenantId})
+}
+
+// channelRevisionLimit reads the revisions a channel keeps per entry from the form, 0 keeps the default
+func channelRevisionLimit(value string) int {
+
+	limit, err := strconv.Atoi(strings.TrimSpace(value))
+
+	if err != nil || limit <= 0 {
+		return 0
+	}
+
+	return revisions.Limit(limit)
+}
+
 // Channel Pagination list //
 func PaginationList(c *gin.Context) {
 
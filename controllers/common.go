@@ -60,6 +60,12 @@ type Breadcrum struct {
 
 func GenerateEmail(email, subject string, data map[string]interface{}, message string, wg *sync.WaitGroup) error {
 
+	return GenerateTenantEmail(TenantId, email, subject, data, message, wg)
+}
+
+// GenerateTenantEmail sends the email with the mail configuration of the given tenant
+func GenerateTenantEmail(tenantid int, email, subject string, data map[string]interface{}, message string, wg *sync.WaitGroup) error {
+
 	data1 := map[string]interface{}{
 		"Body":          template.HTML(message),
 		"AdminLogo":     data["admin_logo"].(string),
@@ -97,7 +103,7 @@ func GenerateEmail(email, subject string, data map[string]interface{}, message s
 		Port     string
 		mail     models.TblEmailConfigurations
 	)
-	models.GetMail(&mail, TenantId)
+	models.GetMail(&mail, tenantid)
 
 	if mail.SmtpConfig != nil && mail.SelectedType == "smtp" {
 		logger.Info("")
@@ -140,6 +146,12 @@ func GenerateEmail(email, subject string, data map[string]interface{}, message s
 
 func GenerateOwndeskEmail(email, subject, message string, wg *sync.WaitGroup) error {
 
+	return GenerateOwndeskTenantEmail(TenantId, email, subject, message, wg)
+}
+
+// GenerateOwndeskTenantEmail sends an owndesk email through the mail configuration of the given tenant
+func GenerateOwndeskTenantEmail(tenantid int, email, subject, message string, wg *sync.WaitGroup) error {
+
 	data1 := map[string]interface{}{
 		"Body": template.HTML(message),
 	}
@@ -167,7 +179,7 @@ func GenerateOwndeskEmail(email, subject, message string, wg *sync.WaitGroup) er
 		mail     models.TblEmailConfigurations
 	)
 
-	models.GetMail(&mail, TenantId)
+	models.GetMail(&mail, tenantid)
 
 	if mail.SmtpConfig != nil && mail.SelectedType == "smtp" {
 
@@ -494,6 +506,54 @@ func MemberCreateEmail(Chan chan<- string, wg *sync.WaitGroup, data map[string]i
 
 }
 
+func MemberVerificationEmail(wg *sync.WaitGroup, data map[string]interface{}, email string, tenantid int) {
+
+	var templates models.TblEmailTemplate
+
+	// tenants created before the template was added fall back to the default one
+	if err := models.GetTemplates(&templates, "Memberverification", tenantid); err != nil {
+
+		models.GetTemplates(&templates, "Memberverification", -1)
+	}
+
+	if templates.IsActive != 1 {
+
+		ErrorLog.Printf("Cann't send member verification email to %s error: template not found or inactive", email)
+
+		wg.Done()
+
+		return
+	}
+
+	sub := templates.TemplateSubject
+	msg := templates.TemplateMessage
+
+	replacer := strings.NewReplacer(
+		"{FirstName}", data["fname"].(string),
+		"{VerificationUrl}", data["verification_url"].(string),
+		"{VerificationToken}", data["verification_token"].(string),
+		"{Expirytime}", data["expiry"].(string),
+		"{AdminLogo}", data["admin_logo"].(string),
+		"{FbLogo}", data["fb_logo"].(string),
+		"{LinkedinLogo}", data["linkedin_logo"].(string),
+		"{TwitterLogo}", data["twitter_logo"].(string),
+		"{YoutubeLogo}", data["youtube_logo"].(string),
+		"{InstaLogo}", data["insta_log"].(string),
+		"{FacebookLink}", data["facebook"].(string),
+		"{InstagramLink}", data["instagram"].(string),
+		"{YoutubeLink}", data["youtube"].(string),
+		"{LinkedinLink}", data["linkedin"].(string),
+		"{TwitterLink}", data["twitter"].(string),
+	)
+
+	msg = replacer.Replace(msg)
+	err := GenerateTenantEmail(tenantid, email, sub, data, msg, wg)
+	if err != nil {
+		ErrorLog.Printf("Cann't send member verification email to %s error: %s", email, err)
+	}
+
+}
+
 func MemberActivationEmail(Chan chan<- string, wg *sync.WaitGroup, data map[string]interface{}, email, action string) {
 
 	logger.Info("activationmailll")
@@ -567,11 +627,39 @@ func safeGetString(data map[string]interface{}, key string) string {
 	}
 	return "" // Return an empty string if the key doesn't exist or is not a string
 }
+// MemberClaimEmail sends the claim link of a member profile, like the admin claim only while the Owndesk template is active
+func MemberClaimEmail(wg *sync.WaitGroup, data map[string]interface{}, email string, tenantid int) {
+
+	var templates models.TblEmailTemplate
+
+	models.GetTemplates(&templates, "Owndesk", tenantid)
+
+	if templates.IsActive != 1 {
+
+		ErrorLog.Printf("Cann't send member claim email to %s error: template not found or inactive", email)
+
+		wg.Done()
+
+		return
+	}
+
+	if err := OwndeskMemberTenantEmail(wg, data, email, "Owndesk", tenantid); err != nil {
+
+		ErrorLog.Printf("Cann't send member claim email to %s error: %s", email, err)
+	}
+}
+
 func OwndeskmemberEmail(Chan chan<- string, wg *sync.WaitGroup, data map[string]interface{}, email, action string) error {
 
+	return OwndeskMemberTenantEmail(wg, data, email, action, TenantId)
+}
+
+// OwndeskMemberTenantEmail sends an owndesk member email with the template and mail configuration of the given tenant
+func OwndeskMemberTenantEmail(wg *sync.WaitGroup, data map[string]interface{}, email, action string, tenantid int) error {
+
 	var templates models.TblEmailTemplate
 
-	models.GetTemplates(&templates, action, TenantId)
+	models.GetTemplates(&templates, action, tenantid)
 
 	sub := templates.TemplateSubject
 
@@ -595,7 +683,7 @@ func OwndeskmemberEmail(Chan chan<- string, wg *sync.WaitGroup, data map[string]
 
 	sub = replacer.Replace(sub)
 	msg = replacer.Replace(msg)
-	return GenerateOwndeskEmail(email, sub, msg, wg)
+	return GenerateOwndeskTenantEmail(tenantid, email, sub, msg, wg)
 }
 
 func HashingPassword(pass string) string {
@@ -719,7 +807,7 @@ func ModuleRouteName(c *gin.Context) (modulename string, tabname string, modulei
 		routeName = "/channel/entrylist"
 	}
 
-	if strings.Contains(routeName, "/updatemember/") {
+	if strings.Contains(routeName, "/updatemember/") || strings.HasPrefix(routeName, "/member/notes/") {
 
 		routeName = "/member/"
 	}
@@ -1,10 +1,19 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
+	"spurt-cms/editlock"
+	"spurt-cms/events"
+	"spurt-cms/graphql/tokens"
+	"spurt-cms/models"
+	"spurt-cms/revisions"
+	"spurt-cms/schedule"
+	"spurt-cms/workflow"
 	"strconv"
 	"strings"
 	"time"
@@ -71,6 +80,8 @@ func Entries(c *gin.Context) {
 		unpublishroute string
 		draftroute     string
 		publishroute   string
+		reviewroute    string
+		approvedroute  string
 	)
 
 	id, _ := strconv.Atoi(c.Param("id"))
@@ -132,6 +143,26 @@ func Entries(c *gin.Context) {
 		htmlname = "entrydraft.html"
 	}
 
+	// entries waiting in a review workflow share the draft list
+	var workflowtab string
+
+	if strings.Contains(routeName, "reviewentrieslist") {
+
+		filters.Status = strconv.Itoa(workflow.InReview)
+
+		htmlname = "entrydraft.html"
+
+		workflowtab = "review"
+	}
+	if strings.Contains(routeName, "approvedentrieslist") {
+
+		filters.Status = strconv.Itoa(workflow.Approved)
+
+		htmlname = "entrydraft.html"
+
+		workflowtab = "approved"
+	}
+
 	var chnallist []chn.Tblchannel
 
 	for _, val := range channelist {
@@ -209,6 +240,10 @@ func Entries(c *gin.Context) {
 
 		publishroute = "/channel/entrylist/" + c.Param("id")
 
+		reviewroute = "/channel/reviewentrieslist/" + c.Param("id")
+
+		approvedroute = "/channel/approvedentrieslist/" + c.Param("id")
+
 	} else {
 
 		unpublishroute = "/channel/unpublishentrieslist/" + c.Param("id") + "?keyword=" + filters.Keyword
@@ -216,6 +251,10 @@ func Entries(c *gin.Context) {
 		draftroute = "/channel/draftentrieslist/" + c.Param("id") + "?keyword=" + filters.Keyword
 
 		publishroute = "/channel/entrylist/" + c.Param("id") + "?keyword=" + filters.Keyword
+
+		reviewroute = "/channel/reviewentrieslist/" + c.Param("id") + "?keyword=" + filters.Keyword
+
+		approvedroute = "/channel/approvedentrieslist/" + c.Param("id") + "?keyword=" + filters.Keyword
 	}
 
 	c.HTML(200, htmlname, gin.H{"Pagination": PaginationData{
@@ -225,7 +264,7 @@ func Entries(c *gin.Context) {
 		TwoAfter:     pageno + 2,
 		TwoBelow:     pageno - 2,
 		ThreeAfter:   pageno + 3,
-	}, "Viewbaseurl": viewurl, "Menu": menu, "linktitle": ModuleName, "chcount1": entrcount, "csrf": csrf.GetToken(c), "channelname": chnanem.ChannelName, "chnid": id, "ChanEntrtlist": allchnentry, "entrycount": entrcount, "Next": Next, "Previous": Previous, "PageCount": PageCount, "CurrentPage": pageno, "limit": limt, "paginationendcount": paginationendcount, "paginationstartcount": paginationstartcount, "filter": filters, "title": ModuleName, "heading": chnanem.ChannelName, "translate": translate, "channellist": chnallist, "Page": Page, "HeadTitle": translate.Channell.Channels, "chentrycount": filtercount, "Cmsmenu": true, "filterflag": filterflag, "Entriestab": true, "Tabmenu": TabName, "StorageType": selectedtype.SelectedType, "unpublishroute": unpublishroute, "draftroute": draftroute, "publishroute": publishroute, "channelfilter": "true"})
+	}, "Viewbaseurl": viewurl, "Menu": menu, "linktitle": ModuleName, "chcount1": entrcount, "csrf": csrf.GetToken(c), "channelname": chnanem.ChannelName, "chnid": id, "ChanEntrtlist": allchnentry, "entrycount": entrcount, "Next": Next, "Previous": Previous, "PageCount": PageCount, "CurrentPage": pageno, "limit": limt, "paginationendcount": paginationendcount, "paginationstartcount": paginationstartcount, "filter": filters, "title": ModuleName, "heading": chnanem.ChannelName, "translate": translate, "channellist": chnallist, "Page": Page, "HeadTitle": translate.Channell.Channels, "chentrycount": filtercount, "Cmsmenu": true, "filterflag": filterflag, "Entriestab": true, "Tabmenu": TabName, "StorageType": selectedtype.SelectedType, "unpublishroute": unpublishroute, "draftroute": draftroute, "publishroute": publishroute, "reviewroute": reviewroute, "approvedroute": approvedroute, "Workflowtab": workflowtab, "channelfilter": "true"})
 
 	// }
 
@@ -376,6 +415,26 @@ func AllEntries(c *gin.Context) {
 		htmlname = "entrydraft.html"
 	}
 
+	// entries waiting in a review workflow share the draft list
+	var workflowtab string
+
+	if strings.Contains(routeName, "reviewentries") {
+
+		entrystatus = strconv.Itoa(workflow.InReview)
+
+		htmlname = "entrydraft.html"
+
+		workflowtab = "review"
+	}
+	if strings.Contains(routeName, "approvedentries") {
+
+		entrystatus = strconv.Itoa(workflow.Approved)
+
+		htmlname = "entrydraft.html"
+
+		workflowtab = "approved"
+	}
+
 	_, Totalentris, _, _ := ChannelConfig.ChannelEntriesList(chn.Entries{ChannelId: 0, Limit: 0, Offset: 0, Keyword: filters.Keyword, ChannelName: filters.ChannelName, Title: filters.Title, Status: entrystatus}, TenantId)
 
 	_, Totalentris1, _, _ := ChannelConfig.ChannelEntriesList(chn.Entries{}, TenantId)
@@ -461,7 +520,7 @@ func AllEntries(c *gin.Context) {
 		TwoAfter:     pageno + 2,
 		TwoBelow:     pageno - 2,
 		ThreeAfter:   pageno + 3,
-	}, "Viewbaseurl": viewurl, "Menu": menu, "translate": translate, "Page": Page, "chentrycount": Totalentris, "linktitle": ModuleName, "entrycount": Totalentris1, "Previous": Previous, "Next": Next, "PageCount": PageCount, "CurrentPage": pageno, "limit": limt, "paginationendcount": paginationendcount, "paginationstartcount": paginationstartcount, "filter": filters, "ChanEntrtlist": chlist1, "csrf": csrf.GetToken(c), "channellist": channelist, "title": ModuleName, "HeadTitle": translate.Channell.Channels, "Cmsmenu": true, "filterflag": filterflag, "Entriestab": true, "Tabmenu": tabname, "StorageType": selectedtype.SelectedType, "chnid": -1, "Membergroup": membergroup})
+	}, "Viewbaseurl": viewurl, "Menu": menu, "translate": translate, "Page": Page, "chentrycount": Totalentris, "linktitle": ModuleName, "entrycount": Totalentris1, "Previous": Previous, "Next": Next, "PageCount": PageCount, "CurrentPage": pageno, "limit": limt, "paginationendcount": paginationendcount, "paginationstartcount": paginationstartcount, "filter": filters, "ChanEntrtlist": chlist1, "csrf": csrf.GetToken(c), "channellist": channelist, "title": ModuleName, "HeadTitle": translate.Channell.Channels, "Cmsmenu": true, "filterflag": filterflag, "Entriestab": true, "Tabmenu": tabname, "StorageType": selectedtype.SelectedType, "chnid": -1, "Membergroup": membergroup, "Workflowtab": workflowtab})
 
 	// }
 
@@ -500,6 +559,16 @@ func DeleteEntries(c *gin.Context) {
 
 		route = "/channel/draftentries/"
 
+	}
+	if pagename == "review" {
+
+		route = "/channel/reviewentries/"
+
+	}
+	if pagename == "approved" {
+
+		route = "/channel/approvedentries/"
+
 	}
 
 	if pageno != "" {
@@ -517,6 +586,8 @@ func DeleteEntries(c *gin.Context) {
 
 	userid := c.GetInt("userid")
 
+	entry, _, _ := ChannelConfig.FetchChannelEntryDetail(chn.EntriesInputs{Id: entryId, TenantId: TenantId}, nil)
+
 	_, err = ChannelConfig.DeleteEntry(channame, userid, entryId, TenantId)
 	if err != nil {
 		ErrorLog.Printf("delete entries error: %s", perr)
@@ -525,6 +596,8 @@ func DeleteEntries(c *gin.Context) {
 		return
 	}
 
+	publishEntryEvent(events.EntryDeleted, entryId, entry.ChannelId)
+
 	c.SetCookie("get-toast", "Entry Deleted Successfully", 3600, "", "", false, false)
 	c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 
@@ -549,6 +622,31 @@ func EntryStatus(c *gin.Context) {
 	}
 	// if permisison {
 
+	// on a channel with a review workflow the list only publishes approved entries
+	if state, err := models.GetEntryWorkflowState(id, TenantId); err == nil && state.Workflow != workflow.None {
+		action := workflow.ActionUnpublish
+		if status == 1 {
+			action = workflow.ActionPublish
+		}
+		transition, err := workflow.Find(state.Workflow, state.Status, action)
+		if err != nil || !entryWorkflowGranted(transition.Permission) {
+			c.AbortWithStatusJSON(403, gin.H{"status": 0, "error": "the entry is " + workflow.Label(state.Status) + " and can not be " + action + "ed from the list"})
+			return
+		}
+		if err := models.TransitionEntry(models.TblChannelEntryWorkflowLog{EntryId: id, ChannelId: state.ChannelId, Action: action, FromStatus: state.Status, ToStatus: transition.To, CreatedBy: userid, CreatedOn: time.Now().UTC(), TenantId: TenantId}); err != nil {
+			ErrorLog.Printf("entry status change error: %s", err)
+			json.NewEncoder(c.Writer).Encode(false)
+			return
+		}
+		if status == 1 {
+			publishEntryEvent(events.EntryPublished, id, state.ChannelId)
+		} else {
+			publishEntryEvent(events.EntryUpdated, id, state.ChannelId)
+		}
+		json.NewEncoder(c.Writer).Encode(true)
+		return
+	}
+
 	_, err := ChannelConfig.EntryStatus(channelname, id, status, userid, TenantId)
 	if err != nil {
 		ErrorLog.Printf("entry status change error: %s", perr)
@@ -556,6 +654,12 @@ func EntryStatus(c *gin.Context) {
 		return
 	}
 
+	if status == 1 {
+		publishEntryEvent(events.EntryPublished, id, 0)
+	} else {
+		publishEntryEvent(events.EntryUpdated, id, 0)
+	}
+
 	json.NewEncoder(c.Writer).Encode(true)
 
 	// }
@@ -584,8 +688,60 @@ func PublishEntry(c *gin.Context) {
 	orderindex, _ := strconv.Atoi(c.Request.PostFormValue("orderindex"))
 	status, _ := strconv.Atoi(c.Request.PostFormValue("status"))
 	categoryids := c.PostFormArray("categoryids[]")
+	relatedarticles := relatedArticleIds(c.Request.PostFormValue("relatedarticles"), eid)
+	_, haspicker := c.Request.PostForm["relatedarticles"]
+	_, hasschedule := c.Request.PostForm["publishat"]
+	version, _ := strconv.Atoi(c.Request.PostFormValue("version"))
 	userid := c.GetInt("userid")
 
+	publishat, unpublishat, serr := entryScheduleTimes(c.Request.PostFormValue("publishat"), c.Request.PostFormValue("unpublishat"))
+	if hasschedule && serr != nil {
+		c.AbortWithStatusJSON(400, gin.H{"status": 0, "error": serr.Error()})
+		return
+	}
+
+	channelworkflow, werr := models.GetChannelWorkflow(cid, TenantId)
+	if werr != nil {
+		ErrorLog.Printf("publish entry workflow error: %s", werr)
+	}
+
+	current := workflow.Draft
+	if eid != 0 {
+		if state, err := models.GetEntryWorkflowState(eid, TenantId); err == nil {
+			current = state.Status
+		}
+	}
+
+	// a channel with a review workflow only publishes approved entries, and only editors allowed to publish
+	// publish them or change when they go out
+	requested := status
+	status, permission, werr := workflow.Save(channelworkflow, current, status)
+	if werr != nil {
+		c.AbortWithStatusJSON(400, gin.H{"status": 0, "error": werr.Error(), "workflow": true})
+		return
+	}
+
+	if hasschedule && channelworkflow != workflow.None && permission == "" {
+		if pendingpublish, pendingunpublish, _ := entrySchedules(eid); pendingpublish != schedule.FormatInput(publishat, TZONE) || pendingunpublish != schedule.FormatInput(unpublishat, TZONE) {
+			permission = workflow.PermissionPublish
+		}
+	}
+
+	if !entryWorkflowGranted(permission) {
+		c.AbortWithStatusJSON(403, gin.H{"status": 0, "error": "you are not allowed to publish entries of this channel", "workflow": true})
+		return
+	}
+
+	// publishing with a publish time still to come saves the entry unpublished, the scheduler publishes it then. An
+	// approved entry stays approved until then.
+	deferpublish := hasschedule && requested == workflow.Published && current != workflow.Published && publishat.After(time.Now().UTC())
+	if deferpublish {
+		status = workflow.Draft
+		if channelworkflow != workflow.None {
+			status = current
+		}
+	}
+
 	layout := "2006-01-02T15:04"
 
 	// Parse string to time.Time
@@ -683,22 +839,57 @@ func PublishEntry(c *gin.Context) {
 
 	if eid != 0 {
 
+		previous, _, _ := ChannelConfig.FetchChannelEntryDetail(chn.EntriesInputs{Id: eid, TenantId: TenantId}, nil)
+
 		entries.ModifiedBy = userid
-		_, err := ChannelConfig.UpdateEntry(entries, cname, eid, TenantId)
-		ChannelConfig.UpdateAdditionalField(AdditionalFields, eid, TenantId)
 
-		if err != nil {
-			ErrorLog.Printf("publishentry error: %s", err)
-			json.NewEncoder(c.Writer).Encode(0)
+		// a save started from an older version than the saved one would overwrite someone else's changes
+		saved := claimEntryVersion(c, eid, version, entryFormSnapshot(entries, AdditionalFields), func(channelconfig *chn.Channel) error {
+			if _, err := channelconfig.UpdateEntry(entries, cname, eid, TenantId); err != nil {
+				return err
+			}
+
+			_, err := channelconfig.UpdateAdditionalField(AdditionalFields, eid, TenantId)
+			return err
+		})
+		if !saved {
 			return
 		}
 
+		// the older editors do not send the picker, their saves keep the related articles
+		if haspicker {
+			if err := models.UpdateRelatedArticles(eid, relatedarticles, TenantId); err != nil {
+				ErrorLog.Printf("publishentry related articles error: %s", err)
+			}
+		}
+
+		saveEntryRevision(eid, userid)
+
+		if hasschedule {
+			scheduleEntry(eid, publishat, unpublishat, userid)
+		}
+
+		if channelworkflow != workflow.None && status != previous.Status {
+			logEntryWorkflow(eid, cid, previous.Status, status, userid)
+		}
+
+		if status == 1 && previous.Status != 1 {
+			publishEntryEvent(events.EntryPublished, eid, cid)
+		} else {
+			publishEntryEvent(events.EntryUpdated, eid, cid)
+		}
+
 		if status == 1 {
 
 			c.SetCookie("get-toast", "Entry Published Successfully", 3600, "", "", false, false)
 			c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		}
 
+		if deferpublish {
+			c.SetCookie("get-toast", "Entry Scheduled Successfully", 3600, "", "", false, false)
+			c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
+		}
+
 		c.JSON(200, gin.H{"Channelname": cname, "id": eid})
 
 	} else {
@@ -725,11 +916,32 @@ func PublishEntry(c *gin.Context) {
 			return
 		}
 
+		if haspicker {
+			if err := models.UpdateRelatedArticles(chenid.Id, relatedarticles, TenantId); err != nil {
+				ErrorLog.Printf("publishentry related articles error: %s", err)
+			}
+		}
+
+		saveEntryRevision(chenid.Id, userid)
+
+		if hasschedule {
+			scheduleEntry(chenid.Id, publishat, unpublishat, userid)
+		}
+
+		if status == 1 {
+			publishEntryEvent(events.EntryPublished, chenid.Id, chenid.ChannelId)
+		}
+
 		if status == 1 {
 			c.SetCookie("get-toast", "Entry Published Successfully", 3600, "", "", false, false)
 			c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		}
 
+		if deferpublish {
+			c.SetCookie("get-toast", "Entry Scheduled Successfully", 3600, "", "", false, false)
+			c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
+		}
+
 		c.JSON(200, gin.H{"Channelname": channeldet.ChannelName, "id": chenid.Id})
 
 	}
@@ -869,7 +1081,14 @@ func EditDetails(c *gin.Context) {
 		idstr = append(idstr, str)
 	}
 
-	json.NewEncoder(c.Writer).Encode(gin.H{"Entries": entries, "Createtime": Createtime, "Publishedtime": Publishedtime, "Section": Section, "FieldValue": Fieldvalue, "SelectedCategory": idstr, "CategoryName": FinalSelectedCategories, "Memberlist": memberfirstname.FirstName + " " + memberfirstname.LastName})
+	publishat, unpublishat, schedules := entrySchedules(id)
+
+	version, verr := models.GetEntryVersion(id, TenantId)
+	if verr != nil {
+		ErrorLog.Printf("entry version error: %s", verr)
+	}
+
+	json.NewEncoder(c.Writer).Encode(gin.H{"Entries": entries, "Createtime": Createtime, "Publishedtime": Publishedtime, "Publishat": publishat, "Unpublishat": unpublishat, "Schedules": schedules, "Version": version.Version, "Section": Section, "FieldValue": Fieldvalue, "SelectedCategory": idstr, "CategoryName": FinalSelectedCategories, "Memberlist": memberfirstname.FirstName + " " + memberfirstname.LastName})
 
 	return
 
@@ -986,6 +1205,8 @@ func ImageUpload(c *gin.Context) {
 func Updatechannelfields(c *gin.Context) {
 
 	channelid, _ := strconv.Atoi(c.Request.PostFormValue("id"))
+	entryid, _ := strconv.Atoi(c.Request.PostFormValue("entryid"))
+	version, _ := strconv.Atoi(c.Request.PostFormValue("version"))
 
 	_, perr := NewAuth.IsGranted("Entries", auth.CRUD, TenantId)
 	if perr != nil {
@@ -994,6 +1215,21 @@ func Updatechannelfields(c *gin.Context) {
 
 	// if permisison {
 
+	// the fields are changed from the entry editor, an editor behind the saved entry reloads it first
+	if entryid != 0 {
+		saved, err := models.GetEntryVersion(entryid, TenantId)
+		if err != nil {
+			ErrorLog.Printf("update channelfield entry version error: %s", err)
+			c.AbortWithStatusJSON(500, gin.H{"status": 0, "error": "the entry version could not be checked, save again"})
+			return
+		}
+
+		if editlock.Stale(version, saved.Version) {
+			entryConflict(c, saved, []revisions.Change{})
+			return
+		}
+	}
+
 	channeldetails, _, cerr := ChannelConfig.GetChannelsById(channelid, TenantId)
 	if cerr != nil {
 		ErrorLog.Printf("update channelfield error: %s", cerr)
@@ -1324,7 +1560,7 @@ func DeleteSelectedEntry(c *gin.Context) {
 		entryids = append(entryids, entryid)
 	}
 
-	_, _, err := ChannelConfig.FetchChannelEntryDetail(chn.EntriesInputs{TenantId: TenantId}, entryids)
+	_, selectedEntries, err := ChannelConfig.FetchChannelEntryDetail(chn.EntriesInputs{TenantId: TenantId}, entryids)
 
 	if err != nil {
 		ErrorLog.Printf("fetching multiple entries data: %v", err)
@@ -1355,6 +1591,10 @@ func DeleteSelectedEntry(c *gin.Context) {
 		return
 	}
 
+	for _, entry := range selectedEntries {
+		publishEntryEvent(events.EntryDeleted, entry.Id, entry.ChannelId)
+	}
+
 	c.JSON(200, gin.H{"value": true, "url": url})
 
 	// }
@@ -1417,6 +1657,24 @@ func UnpublishSelectedEntry(c *gin.Context) {
 
 	// if permisison {
 
+	// entries of channels with a review workflow go through their own transitions
+	action := workflow.ActionUnpublish
+	if statusint == 1 {
+		action = workflow.ActionPublish
+	}
+	var reviewed []models.EntryWorkflowState
+	for _, entryid := range entryids {
+		state, err := models.GetEntryWorkflowState(entryid, TenantId)
+		if err != nil || state.Workflow == workflow.None {
+			continue
+		}
+		if transition, err := workflow.Find(state.Workflow, state.Status, action); err != nil || !entryWorkflowGranted(transition.Permission) {
+			c.JSON(200, gin.H{"value": false, "error": state.Title + " is " + workflow.Label(state.Status) + " and can not be " + action + "ed from the list"})
+			return
+		}
+		reviewed = append(reviewed, state)
+	}
+
 	_, err = ChannelConfig.UnpublishSelectedEntry(entryids, statusint, userid, TenantId)
 	if err != nil {
 		ErrorLog.Printf("unpublished multiplle entries error: %s", err)
@@ -1424,6 +1682,18 @@ func UnpublishSelectedEntry(c *gin.Context) {
 		return
 	}
 
+	for _, state := range reviewed {
+		logEntryWorkflow(state.EntryId, state.ChannelId, state.Status, statusint, userid)
+	}
+
+	for _, entryid := range entryids {
+		if statusint == 1 {
+			publishEntryEvent(events.EntryPublished, entryid, 0)
+		} else {
+			publishEntryEvent(events.EntryUpdated, entryid, 0)
+		}
+	}
+
 	c.JSON(200, gin.H{"value": true, "status": statusint, "url": url})
 
 	// }
@@ -1622,3 +1892,373 @@ func UpdateAccPermissionMembergroupId(c *gin.Context) {
 		json.NewEncoder(c.Writer).Encode(true)
 	}
 }
+
+// search entries of every channel for the related articles picker, ids returns the picked entries in their order
+func RelatedEntrySearch(c *gin.Context) {
+
+	permisison, perr := NewAuth.IsGranted("Entries", auth.CRUD, TenantId)
+	if perr != nil || !permisison {
+		ErrorLog.Printf("related entries authorization error: %v", perr)
+		c.AbortWithStatusJSON(403, gin.H{"status": 0})
+		return
+	}
+
+	entryid, _ := strconv.Atoi(c.Query("entryid"))
+
+	if ids := c.Query("ids"); ids != "" {
+
+		var pickedids []int
+
+		for _, id := range strings.Split(relatedArticleIds(ids, entryid), ",") {
+			if pickedid, err := strconv.Atoi(id); err == nil {
+				pickedids = append(pickedids, pickedid)
+			}
+		}
+
+		entries, err := models.GetRelatedEntriesByIds(pickedids, TenantId)
+		if err != nil {
+			ErrorLog.Printf("related entries error: %s", err)
+		}
+
+		entriesbyid := make(map[int]models.RelatedEntry, len(entries))
+
+		for _, entry := range entries {
+			entriesbyid[entry.Id] = entry
+		}
+
+		picked := []models.RelatedEntry{}
+
+		for _, id := range pickedids {
+			if entry, ok := entriesbyid[id]; ok {
+				picked = append(picked, entry)
+			}
+		}
+
+		c.JSON(200, gin.H{"entries": picked})
+		return
+	}
+
+	entries, err := models.SearchRelatedEntries(strings.TrimSpace(c.Query("keyword")), entryid, 20, TenantId)
+	if err != nil {
+		ErrorLog.Printf("related entries search error: %s", err)
+	}
+
+	if entries == nil {
+		entries = []models.RelatedEntry{}
+	}
+
+	c.JSON(200, gin.H{"entries": entries})
+}
+
+// relatedArticleIds cleans the picked related articles, keeping their order without repeats or the entry itself
+func relatedArticleIds(value string, entryid int) string {
+
+	var ids []string
+
+	seen := make(map[int]bool)
+
+	for _, part := range strings.Split(value, ",") {
+
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+
+		if err != nil || id <= 0 || id == entryid || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	return strings.Join(ids, ",")
+}
+
+// publishEntryEvent notifies the graphql subscribers of the current tenant about an entry change
+func publishEntryEvent(eventType string, entryId int, channelId int) {
+
+	events.PublishEntryEvent(events.EntryEvent{Type: eventType, EntryId: entryId, ChannelId: channelId, TenantId: TenantId})
+}
+
+// EntryPreviewToken issues an expiring preview token of an entry. A headless frontend passes it to the graphql
+// ChannelEntryDetail query to render the draft, HEADLESS_PREVIEW_URL gives the frontend route the link opens.
+func EntryPreviewToken(c *gin.Context) {
+
+	_, perr := NewAuth.IsGranted("Entries", auth.CRUD, TenantId)
+	if perr != nil {
+		ErrorLog.Printf("preview token authorization error: %s", perr)
+		c.AbortWithStatusJSON(403, gin.H{"status": 0})
+		return
+	}
+
+	entryid, _ := strconv.Atoi(c.Param("id"))
+
+	entry, err := models.GetPreviewEntry(entryid, TenantId)
+	if err != nil {
+		ErrorLog.Printf("preview token entry error: %s", err)
+		c.AbortWithStatusJSON(404, gin.H{"status": 0})
+		return
+	}
+
+	hours, _ := strconv.Atoi(c.PostForm("hours"))
+
+	token, expiresat, err := tokens.Issue(os.Getenv("JWT_SECRET"), tokens.PreviewAudience, &tokens.PreviewClaims{EntryId: entry.Id, TenantId: TenantId}, tokens.PreviewTTL(time.Duration(hours)*time.Hour), time.Now())
+	if err != nil {
+		ErrorLog.Printf("preview token error: %s", err)
+		c.AbortWithStatusJSON(500, gin.H{"status": 0})
+		return
+	}
+
+	var previewurl string
+
+	if baseurl := os.Getenv("HEADLESS_PREVIEW_URL"); baseurl != "" {
+
+		if parsed, err := url.Parse(baseurl); err == nil {
+			query := parsed.Query()
+			query.Set("token", token)
+			query.Set("slug", entry.Slug)
+			parsed.RawQuery = query.Encode()
+			previewurl = parsed.String()
+		}
+	}
+
+	c.JSON(200, gin.H{"status": 1, "token": token, "url": previewurl, "expiresat": expiresat.In(TZONE).Format(Datelayout)})
+}
+
+// saveEntryRevision adds the saved state of an entry to its history, a failed revision does not undo the save
+func saveEntryRevision(entryid int, userid int) {
+
+	if err := models.SaveEntryRevision(models.DB, entryid, userid, TenantId); err != nil {
+		ErrorLog.Printf("entry revision error: %s", err)
+	}
+}
+
+// EntryRevisions lists the saved revisions of an entry for the history panel of the editor
+func EntryRevisions(c *gin.Context) {
+
+	permisison, perr := NewAuth.IsGranted("Entries", auth.CRUD, TenantId)
+	if perr != nil || !permisison {
+		ErrorLog.Printf("entry revisions authorization error: %v", perr)
+		c.AbortWithStatusJSON(403, gin.H{"status": 0})
+		return
+	}
+
+	entryid, _ := strconv.Atoi(c.Param("id"))
+
+	entryrevisions, err := models.GetEntryRevisions(entryid, TenantId)
+	if err != nil {
+		ErrorLog.Printf("entry revisions error: %s", err)
+	}
+
+	for index, revision := range entryrevisions {
+		entryrevisions[index].CreatedDate = revision.CreatedOn.In(TZONE).Format(Datelayout)
+	}
+
+	if entryrevisions == nil {
+		entryrevisions = []models.TblChannelEntryRevision{}
+	}
+
+	c.JSON(200, gin.H{"status": 1, "revisions": entryrevisions})
+}
+
+// EntryRevisionDiff compares two revisions of an entry side by side, a missing revision stands for the entry as it
+// is saved now
+func EntryRevisionDiff(c *gin.Context) {
+
+	permisison, perr := NewAuth.IsGranted("Entries", auth.CRUD, TenantId)
+	if perr != nil || !permisison {
+		ErrorLog.Printf("entry revision diff authorization error: %v", perr)
+		c.AbortWithStatusJSON(403, gin.H{"status": 0})
+		return
+	}
+
+	entryid, _ := strconv.Atoi(c.Param("id"))
+
+	older, err := entryRevisionSnapshot(entryid, c.Query("from"))
+	if err != nil {
+		ErrorLog.Printf("entry revision diff error: %s", err)
+		c.AbortWithStatusJSON(404, gin.H{"status": 0})
+		return
+	}
+
+	newer, err := entryRevisionSnapshot(entryid, c.Query("to"))
+	if err != nil {
+		ErrorLog.Printf("entry revision diff error: %s", err)
+		c.AbortWithStatusJSON(404, gin.H{"status": 0})
+		return
+	}
+
+	c.JSON(200, gin.H{"status": 1, "changes": revisions.Compare(older, newer)})
+}
+
+func entryRevisionSnapshot(entryid int, revisionid string) (revisions.Snapshot, error) {
+
+	if id, _ := strconv.Atoi(revisionid); id > 0 {
+		return models.GetEntryRevisionSnapshot(id, entryid, TenantId)
+	}
+
+	snapshot, _, err := models.GetEntrySnapshot(entryid, TenantId)
+
+	return snapshot, err
+}
+
+// RestoreEntryRevision writes a revision back to its entry, the restore is saved as the newest revision
+func RestoreEntryRevision(c *gin.Context) {
+
+	permisison, perr := NewAuth.IsGranted("Entries", auth.CRUD, TenantId)
+	if perr != nil || !permisison {
+		ErrorLog.Printf("restore entry revision authorization error: %v", perr)
+		c.AbortWithStatusJSON(403, gin.H{"status": 0})
+		return
+	}
+
+	entryid, _ := strconv.Atoi(c.Param("id"))
+	revisionid, _ := strconv.Atoi(c.PostForm("revisionid"))
+	userid := c.GetInt("userid")
+
+	snapshot, err := models.GetEntryRevisionSnapshot(revisionid, entryid, TenantId)
+	if err != nil {
+		ErrorLog.Printf("restore entry revision error: %s", err)
+		c.AbortWithStatusJSON(404, gin.H{"status": 0})
+		return
+	}
+
+	channelid, err := models.RestoreEntrySnapshot(entryid, snapshot, userid, TenantId)
+	if err != nil {
+		ErrorLog.Printf("restore entry revision error: %s", err)
+		c.AbortWithStatusJSON(500, gin.H{"status": 0})
+		return
+	}
+
+	if err := models.BumpEntryVersion(entryid, TenantId); err != nil {
+		ErrorLog.Printf("restore entry revision version error: %s", err)
+	}
+
+	saveEntryRevision(entryid, userid)
+
+	publishEntryEvent(events.EntryUpdated, entryid, channelid)
+
+	c.SetCookie("get-toast", "Entry Restored Successfully", 3600, "", "", false, false)
+	c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
+
+	c.JSON(200, gin.H{"status": 1})
+}
+
+// entryScheduleTimes reads the publish and unpublish times entered in the editor in the tenant's time zone
+func entryScheduleTimes(publishinput, unpublishinput string) (publishat time.Time, unpublishat time.Time, err error) {
+
+	zone := tenantTimeZone()
+
+	if publishat, _, err = schedule.ParseInput(publishinput, zone); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid publish time: %w", err)
+	}
+
+	if unpublishat, _, err = schedule.ParseInput(unpublishinput, zone); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid unpublish time: %w", err)
+	}
+
+	return publishat, unpublishat, schedule.Validate(publishat, unpublishat)
+}
+
+// tenantTimeZone loads the time zone of the tenant's general settings, TZONE when it is missing or unknown
+func tenantTimeZone() *time.Location {
+
+	setting, err := models.GetGeneralSettings(TenantId)
+	if err != nil {
+		ErrorLog.Printf("schedule time zone error: %s", err)
+		return TZONE
+	}
+
+	if setting.TimeZone == "" {
+		return TZONE
+	}
+
+	zone, err := time.LoadLocation(setting.TimeZone)
+	if err != nil {
+		ErrorLog.Printf("schedule time zone %q error: %s", setting.TimeZone, err)
+		return TZONE
+	}
+
+	return zone
+}
+
+// scheduleEntry replaces the pending publish and unpublish of an entry, a zero time clears it
+func scheduleEntry(entryid int, publishat, unpublishat time.Time, userid int) {
+
+	if err := models.ScheduleEntry(entryid, schedule.ActionPublish, publishat, userid, TenantId); err != nil {
+		ErrorLog.Printf("schedule entry publish error: %s", err)
+	}
+
+	if err := models.ScheduleEntry(entryid, schedule.ActionUnpublish, unpublishat, userid, TenantId); err != nil {
+		ErrorLog.Printf("schedule entry unpublish error: %s", err)
+	}
+}
+
+// entrySchedules returns the pending publish and unpublish times of an entry for the editor inputs with the
+// recent scheduled runs, all in the tenant's time zone
+func entrySchedules(entryid int) (publishat string, unpublishat string, schedules []models.TblChannelEntrySchedule) {
+
+	schedules, err := models.GetEntrySchedules(entryid, 10, TenantId)
+	if err != nil {
+		ErrorLog.Printf("entry schedules error: %s", err)
+	}
+
+	zone := tenantTimeZone()
+
+	for index, job := range schedules {
+
+		schedules[index].RunDate = job.RunAt.In(zone).Format(Datelayout)
+
+		if job.RunOn != nil {
+			schedules[index].RunOnDate = job.RunOn.In(zone).Format(Datelayout)
+		}
+
+		if job.Status != schedule.StatusPending {
+			continue
+		}
+
+		if job.Action == schedule.ActionPublish && publishat == "" {
+			publishat = schedule.FormatInput(job.RunAt, zone)
+		}
+
+		if job.Action == schedule.ActionUnpublish && unpublishat == "" {
+			unpublishat = schedule.FormatInput(job.RunAt, zone)
+		}
+	}
+
+	if schedules == nil {
+		schedules = []models.TblChannelEntrySchedule{}
+	}
+
+	return publishat, unpublishat, schedules
+}
+
+// RunEntryScheduler publishes and unpublishes the scheduled entries of all tenants. Jobs live in the database, so
+// the ones due while the admin was down run on start, and every instance claims a job before running it.
+func RunEntryScheduler() {
+
+	hostname, _ := os.Hostname()
+
+	scheduler := schedule.Scheduler{
+		Store:    models.EntryScheduleStore{},
+		Instance: fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		Applied: func(job schedule.Job, channelId int) {
+
+			eventType := events.EntryUpdated
+
+			if job.Action == schedule.ActionPublish {
+				eventType = events.EntryPublished
+			}
+
+			events.PublishEntryEvent(events.EntryEvent{Type: eventType, EntryId: job.EntryId, ChannelId: channelId, TenantId: job.TenantId})
+		},
+		Failed: func(err error) {
+			ErrorLog.Printf("entry scheduler error: %s", err)
+		},
+	}
+
+	if seconds, _ := strconv.Atoi(os.Getenv("ENTRY_SCHEDULER_INTERVAL")); seconds > 0 {
+		scheduler.Interval = time.Duration(seconds) * time.Second
+	}
+
+	scheduler.Run(context.Background())
+}
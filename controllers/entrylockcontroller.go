@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"net/http"
+	"spurt-cms/editlock"
+	"spurt-cms/models"
+	"spurt-cms/revisions"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	chn "github.com/spurtcms/channels"
+	"gorm.io/gorm"
+)
+
+// EntryLock renews the edit lock of the entry open in the editor, or takes it when it is free. Admins can take over
+// a lock another editor holds.
+func EntryLock(c *gin.Context) {
+
+	entryid, _ := strconv.Atoi(c.Param("id"))
+	userid := c.GetInt("userid")
+	isadmin := c.GetInt("role") == 1 || c.GetInt("role") == 2
+	takeover := c.PostForm("takeover") == "1"
+
+	if takeover && !isadmin {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"status": 0, "error": "only admins can take over editing an entry"})
+		return
+	}
+
+	current, err := models.GetEntryLock(entryid, TenantId)
+	if err != nil {
+		ErrorLog.Printf("entry lock error: %s", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": 0, "error": ErrInternalServerError})
+		return
+	}
+
+	lock, acquired := editlock.Acquire(editlock.Lock{EntryId: current.EntryId, UserId: current.UserId, LockedOn: current.LockedOn, HeartbeatOn: current.HeartbeatOn}, entryid, userid, time.Now().UTC(), editlock.TTL, takeover)
+
+	if acquired {
+		if err := models.SaveEntryLock(current, models.TblChannelEntryLock{EntryId: entryid, UserId: lock.UserId, LockedOn: lock.LockedOn, HeartbeatOn: lock.HeartbeatOn, TenantId: TenantId}); err != nil {
+			ErrorLog.Printf("entry lock save error: %s", err)
+		}
+
+		// another editor may have taken the lock at the same time
+		if current, err = models.GetEntryLock(entryid, TenantId); err != nil {
+			ErrorLog.Printf("entry lock error: %s", err)
+		}
+	}
+
+	version, err := models.GetEntryVersion(entryid, TenantId)
+	if err != nil {
+		ErrorLog.Printf("entry lock version error: %s", err)
+	}
+
+	locked := current.UserId == userid
+
+	c.JSON(200, gin.H{"status": 1, "locked": locked, "holder": current.Username, "since": current.LockedOn.In(TZONE).Format(Datelayout), "takeover": isadmin && !locked, "heartbeat": int(editlock.Heartbeat.Seconds()), "version": version.Version})
+}
+
+// ReleaseEntryLock frees the edit lock when the editor is closed
+func ReleaseEntryLock(c *gin.Context) {
+
+	entryid, _ := strconv.Atoi(c.Param("id"))
+
+	if err := models.ReleaseEntryLock(entryid, c.GetInt("userid"), TenantId); err != nil {
+		ErrorLog.Printf("entry unlock error: %s", err)
+	}
+
+	c.JSON(200, gin.H{"status": 1})
+}
+
+// claimEntryVersion moves the entry past the version the editor started from and runs the save in the same
+// transaction, so a save that fails leaves the version as it was. A stale save, or one without a version that cannot
+// show it started from the saved entry, is answered with the conflict screen and false. A failed claim or save
+// refuses the save as well.
+func claimEntryVersion(c *gin.Context, entryid int, version int, yours revisions.Snapshot, save func(channelconfig *chn.Channel) error) bool {
+
+	if version != 0 {
+		claimed := false
+
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if claimed, err = models.ClaimEntryVersion(tx, entryid, version, TenantId); err != nil || !claimed {
+				return err
+			}
+
+			channelconfig := *ChannelConfig
+			channelconfig.DB = tx
+
+			return save(&channelconfig)
+		})
+		if err != nil {
+			ErrorLog.Printf("entry save error: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": 0, "error": "the entry could not be saved, save again"})
+			return false
+		}
+
+		if claimed {
+			return true
+		}
+	}
+
+	saved, err := models.GetEntryVersion(entryid, TenantId)
+	if err != nil {
+		ErrorLog.Printf("entry version error: %s", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": 0, "error": "the entry version could not be checked, save again"})
+		return false
+	}
+
+	snapshot, _, err := models.GetEntrySnapshot(entryid, TenantId)
+	if err != nil {
+		ErrorLog.Printf("entry conflict snapshot error: %s", err)
+	}
+
+	if yours.Categories, err = models.GetCategoryNames(yours.CategoryIds, TenantId); err != nil {
+		ErrorLog.Printf("entry conflict categories error: %s", err)
+	}
+
+	entryConflict(c, saved, revisions.Compare(snapshot, yours))
+
+	return false
+}
+
+// entryConflict answers a stale save with who saved the entry last and what differs from the saved version
+func entryConflict(c *gin.Context, saved models.EntryVersion, changes []revisions.Change) {
+
+	c.AbortWithStatusJSON(http.StatusConflict, gin.H{"status": 0, "conflict": true, "error": "the entry was saved by " + saved.Username + " while you were editing it", "version": saved.Version, "savedby": saved.Username, "savedon": saved.ModifiedOn.In(TZONE).Format(Datelayout), "changes": changes})
+}
+
+// entryFormSnapshot is the entry as the editor is about to save it
+func entryFormSnapshot(entries chn.EntriesRequired, fields []chn.AdditionalFields) revisions.Snapshot {
+
+	snapshot := revisions.Snapshot{Title: entries.Title, Description: entries.Content, CoverImage: entries.CoverImage, Excerpt: entries.Excerpt, Author: entries.Author, Tags: entries.Tag, ReadingTime: entries.ReadingTime, CategoryIds: entries.CategoryIds, MetaTitle: entries.SEODetails.MetaTitle, MetaDescription: entries.SEODetails.MetaDescription, Keyword: entries.SEODetails.MetaKeywords, ImageAltTag: entries.SEODetails.ImageAltTag, Fields: []revisions.Field{}}
+
+	for _, field := range fields {
+		snapshot.Fields = append(snapshot.Fields, revisions.Field{FieldId: field.FieldId, Name: field.FieldName, Value: field.FieldValue})
+	}
+
+	return snapshot
+}
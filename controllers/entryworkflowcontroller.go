@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"net/http"
+	"os"
+	"spurt-cms/events"
+	"spurt-cms/models"
+	"spurt-cms/workflow"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spurtcms/auth"
+)
+
+var entryWorkflowToasts = map[string]string{
+	workflow.ActionSubmit:    "Entry Submitted For Review",
+	workflow.ActionApprove:   "Entry Approved Successfully",
+	workflow.ActionReject:    "Entry Rejected Successfully",
+	workflow.ActionPublish:   "Entry Published Successfully",
+	workflow.ActionUnpublish: "Entry Unpublished Successfully",
+}
+
+// EntryWorkflow returns the review state of an entry, the transitions the user can make and the history
+func EntryWorkflow(c *gin.Context) {
+
+	entryid, _ := strconv.Atoi(c.Param("id"))
+
+	state, err := models.GetEntryWorkflowState(entryid, TenantId)
+	if err != nil {
+		ErrorLog.Printf("entry workflow error: %s", err)
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": 0, "error": "entry not found"})
+		return
+	}
+
+	actions := []workflow.Transition{}
+
+	if state.Workflow != workflow.None {
+		for _, transition := range workflow.Transitions(state.Workflow, state.Status) {
+			if entryWorkflowGranted(transition.Permission) {
+				actions = append(actions, transition)
+			}
+		}
+	}
+
+	history, err := models.GetEntryWorkflowLogs(entryid, TenantId)
+	if err != nil {
+		ErrorLog.Printf("entry workflow history error: %s", err)
+	}
+
+	for index, log := range history {
+		history[index].CreatedDate = log.CreatedOn.In(TZONE).Format(Datelayout)
+		history[index].FromLabel = workflow.Label(log.FromStatus)
+		history[index].ToLabel = workflow.Label(log.ToStatus)
+	}
+
+	c.JSON(200, gin.H{"status": 1, "workflow": state.Workflow, "state": state.Status, "label": workflow.Label(state.Status), "actions": actions, "history": history})
+}
+
+// TransitionEntryWorkflow submits, approves, rejects, publishes or unpublishes an entry of a channel with a review
+// workflow and notifies whoever acts on it next
+func TransitionEntryWorkflow(c *gin.Context) {
+
+	entryid, _ := strconv.Atoi(c.Param("id"))
+	action := c.PostForm("action")
+	comment := strings.TrimSpace(c.PostForm("comment"))
+	userid := c.GetInt("userid")
+
+	state, err := models.GetEntryWorkflowState(entryid, TenantId)
+	if err != nil {
+		ErrorLog.Printf("entry transition error: %s", err)
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": 0, "error": "entry not found"})
+		return
+	}
+
+	if state.Workflow == workflow.None {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": 0, "error": "the channel of the entry has no review workflow"})
+		return
+	}
+
+	transition, err := workflow.Find(state.Workflow, state.Status, action)
+	if err == nil {
+		err = transition.Check(comment)
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": 0, "error": err.Error()})
+		return
+	}
+
+	if !entryWorkflowGranted(transition.Permission) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"status": 0, "error": "you are not allowed to " + action + " entries of this channel"})
+		return
+	}
+
+	err = models.TransitionEntry(models.TblChannelEntryWorkflowLog{EntryId: entryid, ChannelId: state.ChannelId, Action: action, FromStatus: state.Status, ToStatus: transition.To, Comment: comment, CreatedBy: userid, CreatedOn: time.Now().UTC(), TenantId: TenantId})
+	if err == models.ErrEntryStatusChanged {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"status": 0, "error": err.Error()})
+		return
+	}
+	if err != nil {
+		ErrorLog.Printf("entry transition error: %s", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": 0, "error": ErrInternalServerError})
+		return
+	}
+
+	if transition.To == workflow.Published {
+		publishEntryEvent(events.EntryPublished, entryid, state.ChannelId)
+	} else {
+		publishEntryEvent(events.EntryUpdated, entryid, state.ChannelId)
+	}
+
+	notifyEntryWorkflow(state, transition, comment, c.GetString("username"), userid)
+
+	c.SetCookie("get-toast", entryWorkflowToasts[action], 3600, "", "", false, false)
+	c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
+
+	c.JSON(200, gin.H{"status": 1, "state": transition.To, "label": workflow.Label(transition.To)})
+}
+
+// entryWorkflowGranted checks a workflow permission of the Entries module, an empty one only needs the entries access
+func entryWorkflowGranted(permission string) bool {
+
+	if permission == "" {
+		return true
+	}
+
+	permisison, perr := NewAuth.IsGranted("Entries", auth.Action(permission), TenantId)
+	if perr != nil {
+		ErrorLog.Printf("entry workflow authorization error: %s", perr)
+		return false
+	}
+
+	return permisison
+}
+
+// logEntryWorkflow records a status change made outside the workflow transitions, by an editor save or from the
+// entries list, on a channel with a review workflow
+func logEntryWorkflow(entryid, channelid, from, to, userid int) {
+
+	action := "save"
+	if to == workflow.Published {
+		action = workflow.ActionPublish
+	} else if from == workflow.Published && to == workflow.Unpublished {
+		action = workflow.ActionUnpublish
+	}
+
+	if err := models.CreateEntryWorkflowLog(models.TblChannelEntryWorkflowLog{EntryId: entryid, ChannelId: channelid, Action: action, FromStatus: from, ToStatus: to, CreatedBy: userid, CreatedOn: time.Now().UTC(), TenantId: TenantId}); err != nil {
+		ErrorLog.Printf("entry workflow log error: %s", err)
+	}
+}
+
+// notifyEntryWorkflow emails the users who act on the entry next, or its author when it is rejected
+func notifyEntryWorkflow(state models.EntryWorkflowState, transition workflow.Transition, comment string, actorname string, actorid int) {
+
+	var recipients []models.WorkflowReviewer
+
+	if transition.Action == workflow.ActionReject {
+		author, err := models.GetWorkflowUser(state.CreatedBy, TenantId)
+		if err != nil {
+			ErrorLog.Printf("entry workflow author error: %s", err)
+			return
+		}
+		recipients = append(recipients, author)
+	} else if permission := workflow.Next(state.Workflow, transition.To); permission != "" {
+		reviewers, err := models.GetWorkflowReviewers(permission, TenantId)
+		if err != nil {
+			ErrorLog.Printf("entry workflow reviewers error: %s", err)
+			return
+		}
+		recipients = reviewers
+	} else if transition.To == workflow.Published && transition.Action == workflow.ActionApprove {
+		// a single review publishes on approval, the author hears about it
+		author, err := models.GetWorkflowUser(state.CreatedBy, TenantId)
+		if err != nil {
+			ErrorLog.Printf("entry workflow author error: %s", err)
+			return
+		}
+		recipients = append(recipients, author)
+	}
+
+	var url_prefix = os.Getenv("BASE_URL")
+
+	var wg sync.WaitGroup
+
+	for _, recipient := range recipients {
+
+		if recipient.Email == "" || recipient.Id == actorid {
+			continue
+		}
+
+		data := map[string]interface{}{
+			"fname":         recipient.FirstName,
+			"actor":         actorname,
+			"title":         state.Title,
+			"channel":       state.ChannelName,
+			"state":         workflow.Label(transition.To),
+			"comment":       comment,
+			"entry_url":     url_prefix + "channel/editsentry/" + strconv.Itoa(state.EntryId),
+			"admin_logo":    url_prefix + "public/img/SpurtCMSlogo.png",
+			"fb_logo":       url_prefix + "public/img/email-icons/facebook.png",
+			"linkedin_logo": url_prefix + "public/img/email-icons/linkedin.png",
+			"twitter_logo":  url_prefix + "public/img/email-icons/x.png",
+			"youtube_logo":  url_prefix + "public/img/email-icons/youtube.png",
+			"insta_log":     url_prefix + "public/img/email-icons/instagram.png",
+			"facebook":      os.Getenv("FACEBOOK"),
+			"instagram":     os.Getenv("INSTAGRAM"),
+			"youtube":       os.Getenv("YOUTUBE"),
+			"linkedin":      os.Getenv("LINKEDIN"),
+			"twitter":       os.Getenv("TWITTER"),
+		}
+
+		wg.Add(1)
+		go EntryReviewEmail(&wg, data, recipient.Email, TenantId)
+	}
+}
+
+func EntryReviewEmail(wg *sync.WaitGroup, data map[string]interface{}, email string, tenantid int) {
+
+	var templates models.TblEmailTemplate
+
+	// tenants created before the template was added fall back to the default one
+	if err := models.GetTemplates(&templates, "Entryreview", tenantid); err != nil {
+
+		models.GetTemplates(&templates, "Entryreview", -1)
+	}
+
+	if templates.IsActive != 1 {
+
+		WarnLog.Printf("Entry review email to %s not sent: template not found or inactive", email)
+
+		wg.Done()
+
+		return
+	}
+
+	comment := data["comment"].(string)
+	if comment == "" {
+		comment = "-"
+	}
+
+	replacer := strings.NewReplacer(
+		"{FirstName}", data["fname"].(string),
+		"{ActorName}", data["actor"].(string),
+		"{EntryTitle}", data["title"].(string),
+		"{ChannelName}", data["channel"].(string),
+		"{State}", data["state"].(string),
+		"{Comment}", comment,
+		"{EntryUrl}", data["entry_url"].(string),
+		"{AdminLogo}", data["admin_logo"].(string),
+		"{FbLogo}", data["fb_logo"].(string),
+		"{LinkedinLogo}", data["linkedin_logo"].(string),
+		"{TwitterLogo}", data["twitter_logo"].(string),
+		"{YoutubeLogo}", data["youtube_logo"].(string),
+		"{InstaLogo}", data["insta_log"].(string),
+		"{FacebookLink}", data["facebook"].(string),
+		"{InstagramLink}", data["instagram"].(string),
+		"{YoutubeLink}", data["youtube"].(string),
+		"{LinkedinLink}", data["linkedin"].(string),
+		"{TwitterLink}", data["twitter"].(string),
+	)
+
+	sub := replacer.Replace(templates.TemplateSubject)
+	msg := replacer.Replace(templates.TemplateMessage)
+
+	if err := GenerateTenantEmail(tenantid, email, sub, data, msg, wg); err != nil {
+		ErrorLog.Printf("Cann't send entry review email to %s error: %s", email, err)
+	}
+}
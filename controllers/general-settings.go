@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"fmt"
+	"spurt-cms/graphql/imagetransform"
 	"spurt-cms/models"
 	storagecontroller "spurt-cms/storage-controller"
 	"strconv"
@@ -170,6 +171,7 @@ func UpdateGeneralSettings(c *gin.Context) {
 	timeForamt := c.PostForm("timeformat")
 	timezone := c.PostForm("timezon")
 	languagedefault, _ := strconv.Atoi(c.PostForm("language"))
+	imagepresets, haspresets := c.GetPostForm("imagepresets")
 	logger.Info(fmt.Sprintf("%v", "dateFOrmat", dateFormat))
 
 	var (
@@ -275,6 +277,14 @@ func UpdateGeneralSettings(c *gin.Context) {
 	gensetting.ModifiedOn, _ = time.Parse("2006-01-02 15:04:05", time.Now().In(TZONE).Format("2006-01-02 15:04:05"))
 	gensetting.StorageType = storageType.SelectedType
 
+	// the presets are stored the way the graphql image fields read them, unreadable lines are dropped
+	if haspresets {
+		gensetting.ImagePresets = imagetransform.FormatPresets(imagetransform.ParsePresets(imagepresets))
+	} else {
+		current, _ := models.GetGeneralSettings(TenantId)
+		gensetting.ImagePresets = current.ImagePresets
+	}
+
 	err = models.UpdateGeneralSettings(gensetting, TenantId)
 	if err != nil {
 		ErrorLog.Println(err)
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	chn "github.com/spurtcms/channels"
 	csrf "github.com/utrack/gin-csrf"
 	"spurt-cms/logger"
 )
@@ -43,14 +44,52 @@ func GraphqlTokenApi(c *gin.Context) {
 		ErrorLog.Printf("get the list token error: %s", err)
 	}
 
+	channelList, _, err := ChannelConfig.ListChannel(chn.Channels{Limit: 0, Offset: 0, TenantId: TenantId})
+	if err != nil {
+		ErrorLog.Printf("getchannellist error: %s", err)
+	}
+
+	var tokenIds []int
+
+	for _, token := range list {
+		tokenIds = append(tokenIds, token.Id)
+	}
+
+	now := time.Now().UTC()
+
+	tokenUsage, err := models.GetApiTokenUsage(tokenIds, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), TenantId)
+	if err != nil {
+		ErrorLog.Printf("get token usage error: %s", err)
+	}
+
+	channelNames := make(map[string]string, len(channelList))
+
+	for _, channel := range channelList {
+		channelNames[strconv.Itoa(channel.Id)] = channel.ChannelName
+	}
+
 	for _, val := range list {
 
+		if val.ChannelIds != "" {
+			for _, channelId := range strings.Split(val.ChannelIds, ",") {
+				if name, ok := channelNames[channelId]; ok {
+					val.ChannelNames = append(val.ChannelNames, name)
+				}
+			}
+		}
+
 		if !val.ModifiedOn.IsZero() {
 			val.DateString = val.ModifiedOn.In(TZONE).Format(Datelayout)
 		} else {
 			val.DateString = val.CreatedOn.In(TZONE).Format(Datelayout)
 		}
 
+		if !val.LastUsedOn.IsZero() {
+			val.LastUsed = val.LastUsedOn.In(TZONE).Format(Datelayout)
+		}
+
+		val.Usage = tokenUsage[val.Id]
+
 		lists = append(lists, val)
 
 	}
@@ -62,7 +101,7 @@ func GraphqlTokenApi(c *gin.Context) {
 	menu := NewMenuController(c)
 	translate, _ := TranslateHandler(c)
 
-	c.HTML(200, "graphql.html", gin.H{"csrf": csrf.GetToken(c), "HeadTitle": "Graphql Settings", "linktitle": "Graphql API", "Menu": menu, "translate": translate, "SettingsHead": true, "Graphqlmenu": true, "title": "Graphql Api", "Tokens": lists, "totalcount": count, "Previous": Previous, "Next": Next, "PageCount": PageCount, "CurrentPage": pageno, "Page": Page, "Limit": limt, "filter": keyword, "Paginationendcount": paginationendcount, "Paginationstartcount": paginationstartcount, "Pagination": PaginationData{
+	c.HTML(200, "graphql.html", gin.H{"csrf": csrf.GetToken(c), "HeadTitle": "Graphql Settings", "linktitle": "Graphql API", "Menu": menu, "translate": translate, "SettingsHead": true, "Graphqlmenu": true, "title": "Graphql Api", "Tokens": lists, "Channels": channelList, "totalcount": count, "Previous": Previous, "Next": Next, "PageCount": PageCount, "CurrentPage": pageno, "Page": Page, "Limit": limt, "filter": keyword, "Paginationendcount": paginationendcount, "Paginationstartcount": paginationstartcount, "Pagination": PaginationData{
 		NextPage:     pageno + 1,
 		PreviousPage: pageno - 1,
 		TotalPages:   PageCount,
@@ -101,6 +140,8 @@ func CreateToken(c *gin.Context) {
 	graphql.CreatedOn, _ = time.Parse("2006-01-02 15:04:05", time.Now().UTC().Format("2006-01-02 15:04:05"))
 	graphql.CreatedBy = c.GetInt("userid")
 	graphql.TenantId = TenantId
+	graphql.ChannelIds, graphql.WriteAccess, graphql.MemberAccess = apiTokenScopes(c)
+	graphql.RateLimit, graphql.RateBurst = apiTokenRateLimit(c)
 
 	if graphql.Duration == "7 Days" {
 
@@ -151,6 +192,8 @@ func UpdateGraphqlToken(c *gin.Context) {
 	graphql["duration"] = c.Request.FormValue("duration")
 	graphql["modified_on"], _ = time.Parse("2006-01-02 15:04:05", time.Now().UTC().Format("2006-01-02 15:04:05"))
 	graphql["modified_by"] = c.GetInt("userid")
+	graphql["channel_ids"], graphql["write_access"], graphql["member_access"] = apiTokenScopes(c)
+	graphql["rate_limit"], graphql["rate_burst"] = apiTokenRateLimit(c)
 
 	var dur = c.Request.FormValue("duration")
 
@@ -198,6 +241,47 @@ func DeleteToken(c *gin.Context) {
 	c.Redirect(301, "/graphql/")
 }
 
+// apiTokenScopes reads the scopes of a token from the token form. An empty channel list gives access to every channel.
+func apiTokenScopes(c *gin.Context) (channelIds string, writeAccess int, memberAccess int) {
+
+	var ids []string
+
+	for _, value := range strings.Split(c.PostForm("channelIds"), ",") {
+
+		if id, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && id > 0 {
+			ids = append(ids, strconv.Itoa(id))
+		}
+	}
+
+	if c.PostForm("writeAccess") == "1" {
+		writeAccess = 1
+	}
+
+	if c.PostForm("memberAccess") == "1" {
+		memberAccess = 1
+	}
+
+	return strings.Join(ids, ","), writeAccess, memberAccess
+}
+
+// apiTokenRateLimit reads the requests per minute and the burst size of a token, 0 or an invalid value is unlimited
+func apiTokenRateLimit(c *gin.Context) (rateLimit int, rateBurst int) {
+
+	rateLimit, _ = strconv.Atoi(strings.TrimSpace(c.PostForm("rateLimit")))
+
+	rateBurst, _ = strconv.Atoi(strings.TrimSpace(c.PostForm("rateBurst")))
+
+	if rateLimit < 0 {
+		rateLimit = 0
+	}
+
+	if rateBurst < 0 {
+		rateBurst = 0
+	}
+
+	return rateLimit, rateBurst
+}
+
 func GenerateApiToken(length int) (string, error) {
 	b := make([]byte, length)               // Create a slice to hold 32 bytes of random data
 	if _, err := rand.Read(b); err != nil { // Fill the slice with random data and handle any errors
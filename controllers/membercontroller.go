@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"spurt-cms/events"
 	"spurt-cms/models"
 	storagecontroller "spurt-cms/storage-controller"
 	"strconv"
@@ -259,6 +260,8 @@ func CreateMember(c *gin.Context) {
 			return
 		}
 
+		publishMemberEvent(events.MemberCreated, memberdata.Id)
+
 		c.SetCookie("get-toast", "Member Created Successfully", 3600, "", "", false, false)
 		c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 		c.Redirect(301, "/member/")
@@ -401,6 +404,89 @@ func EditMember(c *gin.Context) {
 	}
 }
 
+// member notes and highlights, read only for support cases
+func MemberNotesHighlights(c *gin.Context) {
+
+	var (
+		limt   int
+		offset int
+	)
+
+	permisison, perr := NewAuth.IsGranted("Member", auth.Read, TenantId)
+	if perr != nil {
+		ErrorLog.Printf("member notes authorization error: %s", perr)
+	}
+
+	if !permisison {
+		ErrorLog.Printf("Member authorization error: %s", perr)
+		c.Redirect(301, "/403-page")
+		return
+	}
+
+	var id, _ = strconv.Atoi(c.Param("id"))
+
+	limit := c.Query("limit")
+	pageno, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	notetype := c.DefaultQuery("type", "")
+
+	if notetype != "note" && notetype != "highlight" {
+		notetype = ""
+	}
+
+	if limit == "" {
+		limt = Limit
+	} else {
+		limt, _ = strconv.Atoi(limit)
+	}
+
+	if pageno != 0 {
+		offset = (pageno - 1) * limt
+	}
+
+	member, err := MemberConfig.GetMemberDetails(id, TenantId)
+	if err != nil {
+		ErrorLog.Printf("get member details error: %s", err)
+	}
+
+	notes, count, err := models.GetMemberNotes(id, notetype, limt, offset, TenantId)
+	if err != nil {
+		ErrorLog.Printf("get member notes error: %s", err)
+	}
+
+	for index, note := range notes {
+
+		notes[index].CreatedDate = note.CreatedOn.In(TZONE).Format(Datelayout)
+
+		if !note.ModifiedOn.IsZero() {
+			notes[index].ModifiedDate = note.ModifiedOn.In(TZONE).Format(Datelayout)
+		}
+
+		if len(note.HighlightsConfiguration) > 0 {
+			configuration, _ := json.MarshalIndent(note.HighlightsConfiguration, "", "  ")
+			notes[index].Configuration = string(configuration)
+		}
+	}
+
+	var paginationendcount = len(notes) + offset
+	paginationstartcount := offset + 1
+	Previous, Next, PageCount, Page := Pagination(pageno, int(count), limt)
+
+	translate, _ := TranslateHandler(c)
+
+	menu := NewMenuController(c)
+
+	ModuleName, TabName, _ := ModuleRouteName(c)
+
+	c.HTML(200, "membernotes.html", gin.H{"csrf": csrf.GetToken(c), "Pagination": PaginationData{
+		NextPage:     pageno + 1,
+		PreviousPage: pageno - 1,
+		TotalPages:   PageCount,
+		TwoAfter:     pageno + 2,
+		TwoBelow:     pageno - 2,
+		ThreeAfter:   pageno + 3,
+	}, "Menu": menu, "Member": member, "Notes": notes, "NoteType": notetype, "Count": count, "Previous": Previous, "Next": Next, "Page": Page, "Limit": limt, "PageCount": PageCount, "CurrentPage": pageno, "Paginationstartcount": paginationstartcount, "Paginationendcount": paginationendcount, "title": ModuleName, "HeadTitle": translate.Memberss.Members, "translate": translate, "Membermenu": true, "membermenu": true, "Tabmenu": TabName})
+}
+
 // update member
 func UpdateMember(c *gin.Context) {
 
@@ -612,6 +698,8 @@ func UpdateMember(c *gin.Context) {
 		return
 	}
 
+	publishMemberEvent(events.MemberUpdated, member_id)
+
 	c.SetCookie("get-toast", "Member Updated Successfully", 3600, "", "", false, false)
 	logger.Info("member updayte")
 	// c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
@@ -669,6 +757,8 @@ func DeleteMember(c *gin.Context) {
 		url = "/member?page=" + strconv.Itoa(totalPages)
 	}
 
+	publishMemberEvent(events.MemberDeleted, id)
+
 	c.SetCookie("get-toast", "Member Deleted Successfully", 3600, "", "", false, false)
 	c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
 
@@ -832,6 +922,7 @@ func MemberStatus(c *gin.Context) {
 			json.NewEncoder(c.Writer).Encode(flg)
 
 		} else {
+			publishMemberEvent(events.MemberUpdated, id)
 			json.NewEncoder(c.Writer).Encode(flg)
 		}
 	}
@@ -881,6 +972,7 @@ func MultiSelectDeleteMember(c *gin.Context) {
 			c.JSON(200, gin.H{"value": false})
 			return
 		}
+		publishMemberEvent(events.MemberDeleted, 0)
 		_, totalRecords, _ := MemberConfig.ListMembers(0, 100, mem.Filter{}, false, TenantId)
 
 		recordsPerPage := Limit
@@ -959,6 +1051,7 @@ func MultiSelectMembersStatus(c *gin.Context) {
 			c.JSON(200, gin.H{"value": false})
 			return
 		}
+		publishMemberEvent(events.MemberUpdated, 0)
 
 		c.JSON(200, gin.H{"value": true, "status": status, "url": url})
 	}
@@ -1040,6 +1133,8 @@ func ActivateClaimStatus(c *gin.Context) {
 	if err := DB.Debug().Table("tbl_member_profiles").Where("member_id=?", memberid).UpdateColumns(map[string]interface{}{
 		"claim_status": claimint, "modified_by": c.GetInt("userid"), "claim_date": currenttime}).Error; err != nil {
 		ErrorLog.Printf("Activate claim query status error: %s", err)
+	} else {
+		publishMemberEvent(events.MemberUpdated, memberid)
 	}
 
 	if member.IsActive == 0 {
@@ -1157,3 +1252,9 @@ func SendMemberClaimEmail(email, memberName, companyName string, Token string, p
 	close(Chan)
 
 }
+
+// publishMemberEvent lets the graphql api drop the cached responses of the current tenant
+func publishMemberEvent(eventType string, memberId int) {
+
+	events.PublishMemberEvent(events.MemberEvent{Type: eventType, MemberId: memberId, TenantId: TenantId})
+}
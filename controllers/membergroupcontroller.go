@@ -11,6 +11,7 @@ import (
 	mem "github.com/spurtcms/member"
 	csrf "github.com/utrack/gin-csrf"
 	"spurt-cms/logger"
+	"spurt-cms/models"
 )
 
 type memfilter struct {
@@ -257,6 +258,8 @@ func DeleteMemberGroup(c *gin.Context) {
 		err := MemberConfig.DeleteMemberGroup(MemberGroupId, userid, TenantId) //delete member group
 		if err != nil {
 			ErrorLog.Printf("membergroup delete error: %s", err)
+		} else if merr := models.MarkMemberGroupDeleted(MemberGroupId, userid, TenantId); merr != nil {
+			ErrorLog.Printf("membergroup delete error: %s", merr)
 		}
 
 		_, Total_membergrp, err := MemberConfig.ListMemberGroup(mem.MemberGroupListReq{Keyword:""}, TenantId)
@@ -34,9 +34,11 @@ func MemberSettings(c *gin.Context) {
 
 	Adminmember, _ := NewTeamWP.GetAdminRoleUsers(Adminroleids, TenantId)
 
+	membergroups, _ := MemberConfig.GetGroupData(TenantId)
+
 	translate, _ := TranslateHandler(c)
 
-	c.HTML(200, "membersettings.html", gin.H{"Menu": menu, "linktitle": "Member Settings", "Cmsmenu": true, "title": moulename, "Tabmenu": TabName, "HeadTitle": translate.Memberss.Members, "Templatelist": templatelist, "csrf": csrf.GetToken(c), "Membersettings": membersetttings, "Adminmembers": Adminmember, "translate": translate})
+	c.HTML(200, "membersettings.html", gin.H{"Menu": menu, "linktitle": "Member Settings", "Cmsmenu": true, "title": moulename, "Tabmenu": TabName, "HeadTitle": translate.Memberss.Members, "Templatelist": templatelist, "csrf": csrf.GetToken(c), "Membersettings": membersetttings, "Adminmembers": Adminmember, "Membergroups": membergroups, "translate": translate})
 }
 
 func MemberSettingUpdate(c *gin.Context) {
@@ -48,6 +50,9 @@ func MemberSettingUpdate(c *gin.Context) {
 	updatedetails.ModifiedBy = c.GetInt("userid")
 	updatedetails.Id, _ = strconv.Atoi(c.PostForm("membersettingid"))
 	updatedetails.NotificationUsers = c.PostForm("multiselectuser")
+	updatedetails.DefaultGroupId, _ = strconv.Atoi(c.PostForm("defaultgroupid"))
+	updatedetails.EmailVerification, _ = strconv.Atoi(c.PostForm("emailverification"))
+	updatedetails.VerificationUrl = strings.TrimSpace(c.PostForm("verificationurl"))
 
 	var templatedata []map[string]string
 	if err := json.Unmarshal([]byte(c.Request.PostFormValue("templatestatus")), &templatedata); err != nil {
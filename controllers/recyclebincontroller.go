@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"spurt-cms/events"
+	"spurt-cms/models"
+	"spurt-cms/recyclebin"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	csrf "github.com/utrack/gin-csrf"
+	"gorm.io/gorm"
+)
+
+// RecycleBin lists the deleted records of a module with who deleted them and when
+func RecycleBin(c *gin.Context) {
+
+	if !isRecycleBinAdmin(c) {
+		c.Redirect(301, "/403-page")
+		return
+	}
+
+	var limt, offset int
+
+	limit := c.Query("limit")
+	pageno, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if limit == "" {
+		limt = Limit
+	} else {
+		limt, _ = strconv.Atoi(limit)
+	}
+
+	if pageno != 0 {
+		offset = (pageno - 1) * limt
+	}
+
+	module, _ := recyclebin.Find(c.Query("module"))
+	keyword := strings.Trim(c.DefaultQuery("keyword", ""), " ")
+
+	items, count, err := models.GetRecycleBin(module.Key, limt, offset, keyword, TenantId)
+	if err != nil {
+		ErrorLog.Printf("recycle bin list error: %s", err)
+	}
+
+	for index, item := range items {
+		items[index].DeletedDate = item.DeletedOn.In(TZONE).Format(Datelayout)
+	}
+
+	counts, err := models.GetRecycleBinCounts(TenantId)
+	if err != nil {
+		ErrorLog.Printf("recycle bin count error: %s", err)
+	}
+
+	setting, err := models.GetGeneralSettings(TenantId)
+	if err != nil {
+		ErrorLog.Printf("recycle bin settings error: %s", err)
+	}
+
+	var paginationendcount = len(items) + offset
+	paginationstartcount := offset + 1
+	Previous, Next, PageCount, Page := Pagination(pageno, int(count), limt)
+
+	menu := NewMenuController(c)
+	translate, _ := TranslateHandler(c)
+
+	c.HTML(200, "recyclebin.html", gin.H{"csrf": csrf.GetToken(c), "Pagination": PaginationData{
+		NextPage:     pageno + 1,
+		PreviousPage: pageno - 1,
+		TotalPages:   PageCount,
+		TwoAfter:     pageno + 2,
+		TwoBelow:     pageno - 2,
+		ThreeAfter:   pageno + 3,
+	}, "HeadTitle": translate.Setting.Recyclebin, "linktitle": "Recycle Bin", "title": "Recycle Bin", "Menu": menu, "translate": translate, "SettingsHead": true, "Recyclebinmenu": true, "Modules": recyclebin.Modules, "Module": module, "Counts": counts, "Items": items, "Keyword": keyword, "Searchtrue": keyword != "", "Days": setting.RecycleBinDays, "MaxDays": recyclebin.MaxPurgeDays, "Count": count, "Previous": Previous, "Next": Next, "PageCount": PageCount, "CurrentPage": pageno, "Page": Page, "Limit": limt, "Paginationendcount": paginationendcount, "Paginationstartcount": paginationstartcount})
+}
+
+// RestoreRecycleBin brings a deleted record back with the records deleted along with it
+func RestoreRecycleBin(c *gin.Context) {
+
+	if !isRecycleBinAdmin(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"status": 0, "error": "only admins can restore deleted records"})
+		return
+	}
+
+	module, ok := recyclebin.Find(c.PostForm("module"))
+	id, _ := strconv.Atoi(c.PostForm("id"))
+
+	if !ok || id == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": 0, "error": "unknown record"})
+		return
+	}
+
+	if err := models.RestoreRecycleBin(module.Key, id, TenantId); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": 0, "error": "the record is not in the recycle bin"})
+		case errors.Is(err, models.ErrRestoreChannelFirst), errors.Is(err, models.ErrRestoreParentFirst):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": 0, "error": err.Error()})
+		case errors.Is(err, models.ErrRestoreTaken):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"status": 0, "error": err.Error()})
+		default:
+			ErrorLog.Printf("recycle bin restore error: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": 0, "error": ErrInternalServerError})
+		}
+		return
+	}
+
+	switch module.Key {
+	case recyclebin.Entries:
+		_, channelid, err := models.GetEntrySnapshot(id, TenantId)
+		if err != nil {
+			ErrorLog.Printf("recycle bin restored entry error: %s", err)
+		}
+		publishEntryEvent(events.EntryUpdated, id, channelid)
+	case recyclebin.Channels:
+		publishChannelEvent(events.ChannelCreated, id)
+	case recyclebin.Categories:
+		publishCategoryEvent(events.CategoryCreated, id)
+	}
+
+	c.SetCookie("get-toast", "Record Restored Successfully", 3600, "", "", false, false)
+	c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
+	c.JSON(200, gin.H{"status": 1})
+}
+
+// DeleteRecycleBin deletes records in the recycle bin permanently
+func DeleteRecycleBin(c *gin.Context) {
+
+	if !isRecycleBinAdmin(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"status": 0, "error": "only admins can delete records permanently"})
+		return
+	}
+
+	module, ok := recyclebin.Find(c.PostForm("module"))
+
+	var ids []int
+	for _, value := range c.PostFormArray("ids[]") {
+		if id, err := strconv.Atoi(value); err == nil && id != 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	if !ok || len(ids) == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": 0, "error": "no records selected"})
+		return
+	}
+
+	if err := models.DeleteRecycleBin(module.Key, ids, TenantId); err != nil {
+		ErrorLog.Printf("recycle bin delete error: %s", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": 0, "error": ErrInternalServerError})
+		return
+	}
+
+	c.SetCookie("get-toast", "Record Deleted Permanently", 3600, "", "", false, false)
+	c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
+	c.JSON(200, gin.H{"status": 1})
+}
+
+// UpdateRecycleBinSettings sets the days deleted records are kept before the purge deletes them
+func UpdateRecycleBinSettings(c *gin.Context) {
+
+	if !isRecycleBinAdmin(c) {
+		c.Redirect(301, "/403-page")
+		return
+	}
+
+	days, err := recyclebin.ParsePurgeDays(c.PostForm("days"))
+	if err != nil {
+		c.SetCookie("Alert-msg", "alert", 3600, "", "", false, false)
+		c.SetCookie("get-toast", "Invalid Recycle Bin Days", 3600, "", "", false, false)
+		c.Redirect(301, "/settings/recyclebin/")
+		return
+	}
+
+	if err := models.UpdateRecycleBinDays(days, TenantId); err != nil {
+		ErrorLog.Printf("recycle bin settings update error: %s", err)
+		c.SetCookie("Alert-msg", ErrInternalServerError, 3600, "", "", false, false)
+		c.Redirect(301, "/settings/recyclebin/")
+		return
+	}
+
+	c.SetCookie("get-toast", "Recycle Bin Settings Updated", 3600, "", "", false, false)
+	c.SetCookie("Alert-msg", "success", 3600, "", "", false, false)
+	c.Redirect(301, "/settings/recyclebin/")
+}
+
+// RunRecycleBinPurge permanently deletes the records kept longer than their tenant allows, RECYCLE_BIN_PURGE_INTERVAL
+// sets the seconds between the runs
+func RunRecycleBinPurge() {
+
+	interval := recyclebin.DefaultInterval
+
+	if seconds, _ := strconv.Atoi(os.Getenv("RECYCLE_BIN_PURGE_INTERVAL")); seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeRecycleBin(time.Now().UTC())
+
+		<-ticker.C
+	}
+}
+
+func purgeRecycleBin(now time.Time) {
+
+	purges, err := models.GetRecycleBinPurges()
+	if err != nil {
+		ErrorLog.Printf("recycle bin purge error: %s", err)
+		return
+	}
+
+	for tenantid, days := range purges {
+		before, ok := recyclebin.PurgeBefore(now, days)
+		if !ok {
+			continue
+		}
+
+		purged, err := models.PurgeRecycleBin(before, tenantid)
+		if err != nil {
+			ErrorLog.Printf("recycle bin purge error of tenant %d: %s", tenantid, err)
+		}
+
+		if purged > 0 {
+			WarnLog.Printf("recycle bin purged %d records of tenant %d", purged, tenantid)
+		}
+	}
+}
+
+// saveCategoryLinks keeps the channels and entries a category deletion unlinks so a restore can link them again
+func saveCategoryLinks(categoryids []int) {
+
+	if err := models.SaveCategoryLinks(categoryids, TenantId); err != nil {
+		ErrorLog.Printf("recycle bin category links error: %s", err)
+	}
+}
+
+func isRecycleBinAdmin(c *gin.Context) bool {
+
+	return c.GetInt("role") == 1 || c.GetInt("role") == 2
+}
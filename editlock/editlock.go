@@ -0,0 +1,57 @@
+package editlock
+
+import "time"
+
+const (
+	// how often an open editor renews its lock
+	Heartbeat = 30 * time.Second
+
+	// a lock without a heartbeat for this long is free to take, the editor was closed or lost its connection
+	TTL = 90 * time.Second
+)
+
+// Lock is the soft edit lock of an entry, it only warns the other editors and never blocks a save
+type Lock struct {
+	EntryId     int
+	UserId      int
+	LockedOn    time.Time
+	HeartbeatOn time.Time
+}
+
+// Held reports whether the lock still belongs to its user
+func (lock Lock) Held(now time.Time, ttl time.Duration) bool {
+
+	if lock.UserId == 0 || lock.HeartbeatOn.IsZero() {
+
+		return false
+	}
+
+	return now.Sub(lock.HeartbeatOn) < ttl
+}
+
+// Acquire returns the lock a user gets on an entry. The user renews a lock they hold, takes a free or expired one,
+// and only takes one someone else holds when taking over. The current lock is returned unchanged when they do not
+// get it.
+func Acquire(current Lock, entryId int, userId int, now time.Time, ttl time.Duration, takeover bool) (Lock, bool) {
+
+	if current.UserId == userId && current.Held(now, ttl) {
+
+		current.HeartbeatOn = now
+
+		return current, true
+	}
+
+	if current.Held(now, ttl) && !takeover {
+
+		return current, false
+	}
+
+	return Lock{EntryId: entryId, UserId: userId, LockedOn: now, HeartbeatOn: now}, true
+}
+
+// Stale reports whether a save started from a version of an entry is behind the saved one. A save without a
+// version cannot show it started from the saved entry and is stale as well.
+func Stale(base int, current int) bool {
+
+	return base != current
+}
@@ -0,0 +1,48 @@
+package editlock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAcquire(t *testing.T) {
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	held := Lock{EntryId: 7, UserId: 1, LockedOn: now.Add(-time.Hour), HeartbeatOn: now.Add(-time.Minute)}
+
+	if lock, ok := Acquire(held, 7, 1, now, TTL, false); !ok || !lock.HeartbeatOn.Equal(now) || !lock.LockedOn.Equal(held.LockedOn) {
+		t.Fatalf("expected the holder to renew the lock, got %+v %v", lock, ok)
+	}
+
+	if lock, ok := Acquire(held, 7, 2, now, TTL, false); ok || lock.UserId != 1 {
+		t.Fatalf("expected another user not to get a held lock, got %+v %v", lock, ok)
+	}
+
+	if lock, ok := Acquire(held, 7, 2, now, TTL, true); !ok || lock.UserId != 2 || !lock.LockedOn.Equal(now) {
+		t.Fatalf("expected a take over to move the lock, got %+v %v", lock, ok)
+	}
+
+	expired := held
+
+	expired.HeartbeatOn = now.Add(-TTL)
+
+	if lock, ok := Acquire(expired, 7, 2, now, TTL, false); !ok || lock.UserId != 2 {
+		t.Fatalf("expected an expired lock to be free, got %+v %v", lock, ok)
+	}
+
+	if lock, ok := Acquire(Lock{}, 7, 3, now, TTL, false); !ok || lock.UserId != 3 || lock.EntryId != 7 {
+		t.Fatalf("expected a free entry to be locked, got %+v %v", lock, ok)
+	}
+}
+
+func TestStale(t *testing.T) {
+
+	if Stale(3, 3) || !Stale(2, 3) {
+		t.Fatalf("expected only an older version to be stale")
+	}
+
+	if !Stale(0, 3) {
+		t.Fatalf("expected a save without a version to be stale")
+	}
+}
@@ -0,0 +1,239 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// entry lifecycle events raised by the admin panel and the graphql mutations
+const (
+	EntryPublished = "published"
+	EntryUpdated   = "updated"
+	EntryDeleted   = "deleted"
+)
+
+// channel events raised by the admin panel when the definition of a channel changes
+const (
+	ChannelCreated = "channel_created"
+	ChannelUpdated = "channel_updated"
+	ChannelDeleted = "channel_deleted"
+)
+
+// category events raised by the admin panel, CategoryId is 0 when the id of a new category is not known
+const (
+	CategoryCreated = "category_created"
+	CategoryUpdated = "category_updated"
+	CategoryDeleted = "category_deleted"
+)
+
+// member events raised when a member registers or changes, MemberId is 0 when several members change at once
+const (
+	MemberCreated = "member_created"
+	MemberUpdated = "member_updated"
+	MemberDeleted = "member_deleted"
+)
+
+// subscriber buffer size, events are dropped for a subscriber that falls this far behind unless it subscribed blocking
+const subscriberBuffer = 64
+
+type EntryEvent struct {
+	Type      string
+	EntryId   int
+	ChannelId int
+	TenantId  int
+	CreatedOn time.Time
+}
+
+type ChannelEvent struct {
+	Type      string
+	ChannelId int
+	TenantId  int
+	CreatedOn time.Time
+}
+
+type CategoryEvent struct {
+	Type       string
+	CategoryId int
+	TenantId   int
+	CreatedOn  time.Time
+}
+
+type MemberEvent struct {
+	Type      string
+	MemberId  int
+	TenantId  int
+	CreatedOn time.Time
+}
+
+type subscriber[T any] struct {
+	tenantId int
+	events   chan T
+	blocking bool
+	done     chan struct{}
+}
+
+type broker[T any] struct {
+	mu          sync.RWMutex
+	lastId      int
+	subscribers map[int]subscriber[T]
+}
+
+var (
+	entryBroker = broker[EntryEvent]{subscribers: make(map[int]subscriber[EntryEvent])}
+
+	channelBroker = broker[ChannelEvent]{subscribers: make(map[int]subscriber[ChannelEvent])}
+
+	categoryBroker = broker[CategoryEvent]{subscribers: make(map[int]subscriber[CategoryEvent])}
+
+	memberBroker = broker[MemberEvent]{subscribers: make(map[int]subscriber[MemberEvent])}
+)
+
+// PublishEntryEvent delivers the event to every subscriber of the event tenant. The caller only waits for blocking
+// subscribers whose buffer is full.
+func PublishEntryEvent(event EntryEvent) {
+
+	if event.CreatedOn.IsZero() {
+
+		event.CreatedOn = time.Now().UTC()
+	}
+
+	entryBroker.publish(event.TenantId, event)
+}
+
+// SubscribeEntryEvents listens for entry events of a tenant, -1 listens to every tenant.
+// The returned func must be called to release the subscription.
+func SubscribeEntryEvents(tenantId int) (<-chan EntryEvent, func()) {
+
+	return entryBroker.subscribe(tenantId, false)
+}
+
+// SubscribeEntryEventsBlocking listens like SubscribeEntryEvents, but a full buffer makes the publisher wait instead of
+// dropping the event. It is meant for consumers that must see every event, like the search indexer.
+func SubscribeEntryEventsBlocking(tenantId int) (<-chan EntryEvent, func()) {
+
+	return entryBroker.subscribe(tenantId, true)
+}
+
+// PublishChannelEvent delivers the event to every subscriber of the event tenant without blocking the caller
+func PublishChannelEvent(event ChannelEvent) {
+
+	if event.CreatedOn.IsZero() {
+
+		event.CreatedOn = time.Now().UTC()
+	}
+
+	channelBroker.publish(event.TenantId, event)
+}
+
+// SubscribeChannelEvents listens for channel events of a tenant, -1 listens to every tenant.
+// The returned func must be called to release the subscription.
+func SubscribeChannelEvents(tenantId int) (<-chan ChannelEvent, func()) {
+
+	return channelBroker.subscribe(tenantId, false)
+}
+
+// PublishCategoryEvent delivers the event to every subscriber of the event tenant without blocking the caller
+func PublishCategoryEvent(event CategoryEvent) {
+
+	if event.CreatedOn.IsZero() {
+
+		event.CreatedOn = time.Now().UTC()
+	}
+
+	categoryBroker.publish(event.TenantId, event)
+}
+
+// SubscribeCategoryEvents listens for category events of a tenant, -1 listens to every tenant.
+// The returned func must be called to release the subscription.
+func SubscribeCategoryEvents(tenantId int) (<-chan CategoryEvent, func()) {
+
+	return categoryBroker.subscribe(tenantId, false)
+}
+
+// PublishMemberEvent delivers the event to every subscriber of the event tenant without blocking the caller
+func PublishMemberEvent(event MemberEvent) {
+
+	if event.CreatedOn.IsZero() {
+
+		event.CreatedOn = time.Now().UTC()
+	}
+
+	memberBroker.publish(event.TenantId, event)
+}
+
+// SubscribeMemberEvents listens for member events of a tenant, -1 listens to every tenant.
+// The returned func must be called to release the subscription.
+func SubscribeMemberEvents(tenantId int) (<-chan MemberEvent, func()) {
+
+	return memberBroker.subscribe(tenantId, false)
+}
+
+func (b *broker[T]) publish(tenantId int, event T) {
+
+	b.mu.RLock()
+
+	defer b.mu.RUnlock()
+
+	for _, sub := range b.subscribers {
+
+		if sub.tenantId != -1 && sub.tenantId != tenantId {
+
+			continue
+		}
+
+		if sub.blocking {
+
+			select {
+
+			case sub.events <- event:
+
+			case <-sub.done:
+			}
+
+			continue
+		}
+
+		select {
+
+		case sub.events <- event:
+
+		default:
+		}
+	}
+}
+
+func (b *broker[T]) subscribe(tenantId int, blocking bool) (<-chan T, func()) {
+
+	b.mu.Lock()
+
+	defer b.mu.Unlock()
+
+	b.lastId++
+
+	id := b.lastId
+
+	sub := subscriber[T]{tenantId: tenantId, events: make(chan T, subscriberBuffer), blocking: blocking, done: make(chan struct{})}
+
+	b.subscribers[id] = sub
+
+	var once sync.Once
+
+	unsubscribe := func() {
+
+		once.Do(func() {
+
+			// release a publisher waiting on the subscriber before taking the lock it holds
+			close(sub.done)
+
+			b.mu.Lock()
+
+			defer b.mu.Unlock()
+
+			delete(b.subscribers, id)
+
+			close(sub.events)
+		})
+	}
+
+	return sub.events, unsubscribe
+}
@@ -0,0 +1,181 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntryEventsAreScopedToTenant(t *testing.T) {
+
+	tenantEvents, unsubscribe := SubscribeEntryEvents(1)
+
+	defer unsubscribe()
+
+	allEvents, unsubscribeAll := SubscribeEntryEvents(-1)
+
+	defer unsubscribeAll()
+
+	PublishEntryEvent(EntryEvent{Type: EntryPublished, EntryId: 10, TenantId: 2})
+
+	PublishEntryEvent(EntryEvent{Type: EntryDeleted, EntryId: 11, TenantId: 1})
+
+	select {
+
+	case event := <-tenantEvents:
+
+		if event.EntryId != 11 || event.Type != EntryDeleted || event.CreatedOn.IsZero() {
+			t.Fatalf("unexpected event %+v", event)
+		}
+
+	case <-time.After(time.Second):
+
+		t.Fatal("tenant subscriber did not receive its event")
+	}
+
+	select {
+
+	case event := <-tenantEvents:
+
+		t.Fatalf("tenant subscriber received an event of another tenant %+v", event)
+
+	default:
+	}
+
+	if got := len(allEvents); got != 2 {
+		t.Fatalf("expected 2 events for the all tenant subscriber, got %d", got)
+	}
+}
+
+func TestUnsubscribeClosesChannel(t *testing.T) {
+
+	events, unsubscribe := SubscribeEntryEvents(1)
+
+	unsubscribe()
+
+	unsubscribe()
+
+	if _, ok := <-events; ok {
+		t.Fatal("expected the event channel to be closed")
+	}
+
+	PublishEntryEvent(EntryEvent{Type: EntryUpdated, TenantId: 1})
+}
+
+func TestSlowSubscriberDoesNotBlockPublisher(t *testing.T) {
+
+	_, unsubscribe := SubscribeEntryEvents(3)
+
+	defer unsubscribe()
+
+	done := make(chan struct{})
+
+	go func() {
+
+		for i := 0; i < subscriberBuffer*2; i++ {
+
+			PublishEntryEvent(EntryEvent{Type: EntryUpdated, TenantId: 3})
+		}
+
+		close(done)
+	}()
+
+	select {
+
+	case <-done:
+
+	case <-time.After(time.Second):
+
+		t.Fatal("publisher blocked on a full subscriber")
+	}
+}
+
+func TestBlockingSubscriberReceivesEveryEvent(t *testing.T) {
+
+	events, unsubscribe := SubscribeEntryEventsBlocking(5)
+
+	defer unsubscribe()
+
+	go func() {
+
+		for i := 0; i < subscriberBuffer*2; i++ {
+
+			PublishEntryEvent(EntryEvent{Type: EntryUpdated, EntryId: i, TenantId: 5})
+		}
+	}()
+
+	for i := 0; i < subscriberBuffer*2; i++ {
+
+		select {
+
+		case event := <-events:
+
+			if event.EntryId != i {
+				t.Fatalf("expected entry %d, got %d", i, event.EntryId)
+			}
+
+		case <-time.After(time.Second):
+
+			t.Fatalf("blocking subscriber received %d of %d events", i, subscriberBuffer*2)
+		}
+	}
+}
+
+func TestUnsubscribeReleasesWaitingPublisher(t *testing.T) {
+
+	_, unsubscribe := SubscribeEntryEventsBlocking(6)
+
+	done := make(chan struct{})
+
+	go func() {
+
+		for i := 0; i <= subscriberBuffer; i++ {
+
+			PublishEntryEvent(EntryEvent{Type: EntryUpdated, TenantId: 6})
+		}
+
+		close(done)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	unsubscribe()
+
+	select {
+
+	case <-done:
+
+	case <-time.After(time.Second):
+
+		t.Fatal("publisher stayed blocked after the subscriber left")
+	}
+}
+
+func TestChannelEventsAreSeparateFromEntryEvents(t *testing.T) {
+
+	channelEvents, unsubscribe := SubscribeChannelEvents(4)
+
+	defer unsubscribe()
+
+	entryEvents, unsubscribeEntries := SubscribeEntryEvents(4)
+
+	defer unsubscribeEntries()
+
+	PublishChannelEvent(ChannelEvent{Type: ChannelUpdated, ChannelId: 7, TenantId: 4})
+
+	select {
+
+	case event := <-channelEvents:
+
+		if event.ChannelId != 7 || event.Type != ChannelUpdated || event.CreatedOn.IsZero() {
+			t.Fatalf("unexpected event %+v", event)
+		}
+
+	case <-time.After(time.Second):
+
+		t.Fatal("channel subscriber did not receive its event")
+	}
+
+	if got := len(entryEvents); got != 0 {
+		t.Fatalf("expected no entry events, got %d", got)
+	}
+}
@@ -0,0 +1,158 @@
+package categorytree
+
+// Node is a category placed in the tree of its group
+type Node struct {
+	Id         int
+	ParentId   int
+	Slug       string
+	Path       string
+	Depth      int
+	EntryCount int
+	Children   []*Node
+}
+
+// Build arranges the categories under the root category and sets their slug paths and depths, the root has depth 0.
+// Children keep the order of the given categories. Categories that can not be reached from the root, e.g. below a
+// deleted parent, are left out, and so is everything deeper than maxDepth when it is above 0.
+func Build(categories []Node, rootId int, maxDepth int) *Node {
+
+	children := make(map[int][]Node)
+
+	var root *Node
+
+	for _, category := range categories {
+
+		if category.Id == rootId {
+
+			node := category
+
+			root = &node
+
+			continue
+		}
+
+		children[category.ParentId] = append(children[category.ParentId], category)
+	}
+
+	if root == nil {
+
+		return nil
+	}
+
+	root.Path, root.Depth, root.Children = root.Slug, 0, nil
+
+	visited := map[int]bool{root.Id: true}
+
+	var attach func(parent *Node)
+
+	attach = func(parent *Node) {
+
+		if maxDepth > 0 && parent.Depth >= maxDepth {
+
+			return
+		}
+
+		for _, child := range children[parent.Id] {
+
+			// guards against a parent loop in broken data
+			if visited[child.Id] {
+
+				continue
+			}
+
+			visited[child.Id] = true
+
+			node := child
+
+			node.Path, node.Depth, node.Children = parent.Path+"/"+child.Slug, parent.Depth+1, nil
+
+			parent.Children = append(parent.Children, &node)
+
+			attach(&node)
+		}
+	}
+
+	attach(root)
+
+	return root
+}
+
+// CountEntries sets the number of entries filed under every node, in the node itself or in any category below it.
+// An entry is counted once per node however many of its categories sit below that node.
+func (root *Node) CountEntries(entryCategoryIds [][]int) {
+
+	nodes := make(map[int]*Node)
+
+	parents := make(map[int]int)
+
+	var index func(node *Node)
+
+	index = func(node *Node) {
+
+		nodes[node.Id] = node
+
+		for _, child := range node.Children {
+
+			parents[child.Id] = node.Id
+
+			index(child)
+		}
+	}
+
+	index(root)
+
+	for _, categoryIds := range entryCategoryIds {
+
+		counted := make(map[int]bool)
+
+		for _, categoryId := range categoryIds {
+
+			// the root has no parent in the tree, so the walk up stops there
+			for id := categoryId; nodes[id] != nil && !counted[id]; id = parents[id] {
+
+				counted[id] = true
+
+				nodes[id].EntryCount++
+			}
+		}
+	}
+}
+
+// Trail returns the category ids from the top of the hierarchy down to the category
+func Trail(categoryId int, parents map[int]int) []int {
+
+	trail := []int{categoryId}
+
+	visited := map[int]bool{categoryId: true}
+
+	for parentId := parents[categoryId]; parentId != 0 && !visited[parentId]; parentId = parents[parentId] {
+
+		visited[parentId] = true
+
+		trail = append(trail, parentId)
+	}
+
+	for i, j := 0, len(trail)-1; i < j; i, j = i+1, j-1 {
+
+		trail[i], trail[j] = trail[j], trail[i]
+	}
+
+	return trail
+}
+
+// Leaf picks the category of an entry that the breadcrumbs lead to, the deepest of the entry categories. Of two
+// equally deep categories the first one wins.
+func Leaf(categoryIds []int, parents map[int]int) int {
+
+	leaf, leafDepth := 0, -1
+
+	for _, categoryId := range categoryIds {
+
+		if depth := len(Trail(categoryId, parents)); depth > leafDepth {
+
+			leaf, leafDepth = categoryId, depth
+		}
+	}
+
+	return leaf
+}
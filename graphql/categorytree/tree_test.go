@@ -0,0 +1,71 @@
+package categorytree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func sample() []Node {
+
+	return []Node{
+		{Id: 1, ParentId: 0, Slug: "blog"},
+		{Id: 2, ParentId: 1, Slug: "tech"},
+		{Id: 3, ParentId: 2, Slug: "go"},
+		{Id: 4, ParentId: 1, Slug: "life"},
+		{Id: 5, ParentId: 9, Slug: "orphan"},
+	}
+}
+
+func TestBuild(t *testing.T) {
+
+	root := Build(sample(), 1, 0)
+
+	if root == nil || len(root.Children) != 2 {
+		t.Fatalf("expected the group with two children, got %+v", root)
+	}
+
+	tech := root.Children[0]
+
+	if tech.Path != "blog/tech" || tech.Depth != 1 || tech.Children[0].Path != "blog/tech/go" || tech.Children[0].Depth != 2 {
+		t.Fatalf("unexpected paths %+v %+v", tech, tech.Children[0])
+	}
+
+	if shallow := Build(sample(), 1, 1); len(shallow.Children[0].Children) != 0 {
+		t.Fatalf("expected the tree to stop at depth 1")
+	}
+
+	if Build(sample(), 7, 0) != nil {
+		t.Fatalf("expected no tree for a missing root")
+	}
+}
+
+func TestCountEntries(t *testing.T) {
+
+	root := Build(sample(), 1, 0)
+
+	// the first entry is filed with its whole path, it still counts once per node
+	root.CountEntries([][]int{{1, 2, 3}, {4}, {5}})
+
+	tech, goNode, life := root.Children[0], root.Children[0].Children[0], root.Children[1]
+
+	if root.EntryCount != 2 || tech.EntryCount != 1 || goNode.EntryCount != 1 || life.EntryCount != 1 {
+		t.Fatalf("unexpected counts root=%d tech=%d go=%d life=%d", root.EntryCount, tech.EntryCount, goNode.EntryCount, life.EntryCount)
+	}
+}
+
+func TestTrailAndLeaf(t *testing.T) {
+
+	parents := map[int]int{1: 0, 2: 1, 3: 2, 4: 1}
+
+	if trail := Trail(3, parents); !reflect.DeepEqual(trail, []int{1, 2, 3}) {
+		t.Fatalf("Trail = %v", trail)
+	}
+
+	if leaf := Leaf([]int{1, 4, 3, 2}, parents); leaf != 3 {
+		t.Fatalf("Leaf = %d, expected the deepest category", leaf)
+	}
+
+	if trail := Trail(1, map[int]int{1: 2, 2: 1}); len(trail) != 2 {
+		t.Fatalf("expected a parent loop to stop, got %v", trail)
+	}
+}
@@ -0,0 +1,759 @@
+package channelschema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"spurt-cms/graphql/channeltypes"
+	"spurt-cms/graphql/controller"
+	"spurt-cms/graphql/graph"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/limits"
+	"spurt-cms/graphql/middleware"
+	"spurt-cms/graphql/model"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"gorm.io/gorm"
+)
+
+// the entries of a typed query are published entries only, same as a website would show them
+const publishedStatus = "Publish"
+
+// typedSchema runs the generated channel queries itself and hands every other field of the operation to the
+// generated executable schema
+type typedSchema struct {
+	graphql.ExecutableSchema
+
+	types channeltypes.Schema
+}
+
+// Load builds the executable schema of a tenant, the generated schema extended with a typed object per channel.
+// The generated schema is returned unchanged when the tenant has no channel to add.
+func Load(config graph.Config, tenantId int) (graphql.ExecutableSchema, error) {
+
+	base := graph.NewExecutableSchema(config)
+
+	channelList, err := model.Model.ActiveChannels(tenantId)
+
+	if err != nil {
+
+		return base, err
+	}
+
+	if len(channelList) == 0 {
+
+		return base, nil
+	}
+
+	channelIds := make([]int, len(channelList))
+
+	for index, channel := range channelList {
+
+		channelIds[index] = channel.Id
+	}
+
+	channelFields, err := model.Model.ChannelFieldsByChannelIds(channelIds, tenantId)
+
+	if err != nil {
+
+		return base, err
+	}
+
+	sources := make([]channeltypes.Channel, len(channelList))
+
+	for index, channel := range channelList {
+
+		fields := make([]channeltypes.Field, 0, len(channelFields[channel.Id]))
+
+		for _, field := range channelFields[channel.Id] {
+
+			fields = append(fields, channeltypes.Field{Id: field.Id, Name: field.FieldName, TypeId: field.FieldTypeId})
+		}
+
+		sources[index] = channeltypes.Channel{Id: channel.Id, Slug: channel.SlugName, Name: channel.ChannelName, Fields: fields}
+	}
+
+	types := channeltypes.Build(sources, base.Schema())
+
+	for _, slug := range types.Skipped {
+
+		controller.ErrorLog.Printf("channel %q of tenant %v clashes with the graphql schema and has no typed queries", slug, tenantId)
+	}
+
+	if types.Input == "" {
+
+		return base, nil
+	}
+
+	schema, err := gqlparser.LoadSchema(append(graph.Sources(), &ast.Source{Name: "channeltypes.graphqls", Input: types.Input})...)
+
+	if err != nil {
+
+		return base, err
+	}
+
+	config.Schema = schema
+
+	return typedSchema{ExecutableSchema: graph.NewExecutableSchema(config), types: types}, nil
+}
+
+// CostField charges the typed queries as the entry list and entry queries they run
+func (schema typedSchema) CostField(typeName, fieldName string) (string, bool) {
+
+	query, ok := schema.types.Queries[fieldName]
+
+	if typeName != "Query" || !ok {
+
+		return "", false
+	}
+
+	if query.List {
+
+		return limits.TypedEntriesField, true
+	}
+
+	return limits.TypedEntryField, true
+}
+
+func (schema typedSchema) Exec(ctx context.Context) graphql.ResponseHandler {
+
+	opCtx := graphql.GetOperationContext(ctx)
+
+	if opCtx.Operation.Operation != ast.Query {
+
+		return schema.ExecutableSchema.Exec(ctx)
+	}
+
+	fields := graphql.CollectFields(opCtx, opCtx.Operation.SelectionSet, []string{"Query"})
+
+	var (
+		typed     bool
+		remaining ast.SelectionSet
+	)
+
+	for _, field := range fields {
+
+		if _, ok := schema.types.Queries[field.Name]; ok {
+
+			typed = true
+
+			continue
+		}
+
+		// fields of the same response name are merged by CollectFields, the copy keeps all of their selections
+		remainingField := *field.Field
+
+		remainingField.SelectionSet = field.Selections
+
+		remaining = append(remaining, &remainingField)
+	}
+
+	if !typed {
+
+		return schema.ExecutableSchema.Exec(ctx)
+	}
+
+	var next graphql.ResponseHandler
+
+	if len(remaining) > 0 {
+
+		operation := *opCtx.Operation
+
+		operation.SelectionSet = remaining
+
+		remainingCtx := *opCtx
+
+		remainingCtx.Operation = &operation
+
+		next = schema.ExecutableSchema.Exec(graphql.WithOperationContext(ctx, &remainingCtx))
+	}
+
+	first := true
+
+	return func(ctx context.Context) *graphql.Response {
+
+		// later responses only carry the deferred fragments of the generated fields
+		if !first {
+
+			if next == nil {
+
+				return nil
+			}
+
+			return next(ctx)
+		}
+
+		first = false
+
+		response := &graphql.Response{}
+
+		results := make(map[string]json.RawMessage)
+
+		if next != nil {
+
+			response = next(ctx)
+
+			if response == nil {
+
+				response = &graphql.Response{}
+			}
+
+			// a failed non null field of the generated schema nulls the whole data
+			if err := json.Unmarshal(response.Data, &results); err != nil || results == nil {
+
+				return response
+			}
+		}
+
+		var data bytes.Buffer
+
+		data.WriteByte('{')
+
+		for index, field := range fields {
+
+			if index > 0 {
+
+				data.WriteByte(',')
+			}
+
+			graphql.MarshalString(field.Alias).MarshalGQL(&data)
+
+			data.WriteByte(':')
+
+			query, ok := schema.types.Queries[field.Name]
+
+			if !ok {
+
+				if result, found := results[field.Alias]; found {
+
+					data.Write(result)
+
+				} else {
+
+					data.WriteString("null")
+				}
+
+				continue
+			}
+
+			value, ok := schema.resolveQuery(ctx, opCtx, field, query)
+
+			if !ok && query.List {
+
+				response.Data = json.RawMessage("null")
+
+				return response
+			}
+
+			data.Write(value)
+		}
+
+		data.WriteByte('}')
+
+		response.Data = data.Bytes()
+
+		return response
+	}
+}
+
+// resolveQuery runs a channel query behind the @auth directive, false when the query failed and its value is null
+func (schema typedSchema) resolveQuery(ctx context.Context, opCtx *graphql.OperationContext, field graphql.CollectedField, query channeltypes.Query) (json.RawMessage, bool) {
+
+	args := field.ArgumentMap(opCtx.Variables)
+
+	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{Object: "Query", Field: field, Args: args, IsMethod: true, IsResolver: true})
+
+	result, err := middleware.AuthMiddleware(ctx, nil, func(ctx context.Context) (interface{}, error) {
+
+		if query.List {
+
+			return fetchEntries(ctx, query, args)
+		}
+
+		return fetchEntry(ctx, query, args)
+
+	}, model.ScopeRead)
+
+	if err != nil {
+
+		graphql.AddError(ctx, err)
+
+		return json.RawMessage("null"), false
+	}
+
+	channelType := schema.types.Types[query.TypeName]
+
+	var buf bytes.Buffer
+
+	switch result := result.(type) {
+
+	case *model.ChannelEntryDetails:
+
+		writeEntryList(ctx, &buf, opCtx, field.Selections, channelType, result)
+
+	case *model.ChannelEntries:
+
+		if result == nil {
+
+			buf.WriteString("null")
+
+			break
+		}
+
+		writeEntry(ctx, &buf, opCtx, field.Selections, channelType, *result)
+
+	default:
+
+		buf.WriteString("null")
+	}
+
+	return buf.Bytes(), true
+}
+
+func fetchEntries(ctx context.Context, query channeltypes.Query, args map[string]interface{}) (*model.ChannelEntryDetails, error) {
+
+	filter := &model.Filter{}
+
+	if limit, ok, err := intArg(args, "limit"); err != nil {
+
+		return nil, err
+
+	} else if ok {
+
+		filter.Limit = graphql.OmittableOf(&limit)
+	}
+
+	if offset, ok, err := intArg(args, "offset"); err != nil {
+
+		return nil, err
+
+	} else if ok {
+
+		filter.Offset = graphql.OmittableOf(&offset)
+	}
+
+	channelId, status, fields := query.ChannelId, publishedStatus, true
+
+	entryFilter := &model.EntriesFilter{ChannelID: graphql.OmittableOf(&channelId), Status: graphql.OmittableOf(&status)}
+
+	if categorySlug, ok := args["categorySlug"].(string); ok && categorySlug != "" {
+
+		entryFilter.CategorySlug = graphql.OmittableOf(&categorySlug)
+	}
+
+	return controller.ChannelEntriesList(ctx, filter, nil, entryFilter, &model.EntriesAdditionalData{AdditionalFields: graphql.OmittableOf(&fields)}, nil, nil)
+}
+
+func fetchEntry(ctx context.Context, query channeltypes.Query, args map[string]interface{}) (*model.ChannelEntries, error) {
+
+	var (
+		id   *int
+		slug *string
+	)
+
+	if value, ok, err := intArg(args, "id"); err != nil {
+
+		return nil, err
+
+	} else if ok {
+
+		id = &value
+	}
+
+	if value, ok := args["slug"].(string); ok {
+
+		slug = &value
+	}
+
+	channelId, fields := query.ChannelId, true
+
+	entry, err := controller.ChannelEntryDetail(ctx, id, slug, &model.EntriesAdditionalData{AdditionalFields: graphql.OmittableOf(&fields)}, &channelId, nil)
+
+	if err != nil {
+
+		if err == info.ErrRecordNotFound || err == gorm.ErrRecordNotFound {
+
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	// drafts and entries of another channel are not part of the typed query
+	if entry.ChannelID != query.ChannelId || entry.Status != 1 {
+
+		return nil, nil
+	}
+
+	return entry, nil
+}
+
+func intArg(args map[string]interface{}, name string) (int, bool, error) {
+
+	value, ok := args[name]
+
+	if !ok || value == nil {
+
+		return 0, false, nil
+	}
+
+	number, err := graphql.UnmarshalInt(value)
+
+	if err != nil {
+
+		return 0, false, err
+	}
+
+	return number, true, nil
+}
+
+func writeEntryList(ctx context.Context, buf *bytes.Buffer, opCtx *graphql.OperationContext, selections ast.SelectionSet, channelType *channeltypes.Type, list *model.ChannelEntryDetails) {
+
+	writeObject(buf, opCtx, selections, channelType.ListName, func(field graphql.CollectedField) {
+
+		switch field.Name {
+
+		case "entries":
+
+			buf.WriteByte('[')
+
+			for index, entry := range list.ChannelEntriesList {
+
+				if index > 0 {
+
+					buf.WriteByte(',')
+				}
+
+				writeEntry(ctx, buf, opCtx, field.Selections, channelType, entry)
+			}
+
+			buf.WriteByte(']')
+
+		case "count":
+
+			graphql.MarshalInt(list.Count).MarshalGQL(buf)
+
+		default:
+
+			buf.WriteString("null")
+		}
+	})
+}
+
+func writeEntry(ctx context.Context, buf *bytes.Buffer, opCtx *graphql.OperationContext, selections ast.SelectionSet, channelType *channeltypes.Type, entry model.ChannelEntries) {
+
+	typedFields := make(map[string]channeltypes.TypedField, len(channelType.Fields))
+
+	for _, field := range channelType.Fields {
+
+		typedFields[field.Name] = field
+	}
+
+	fieldValues := make(map[int]string)
+
+	if entry.AdditionalFields != nil {
+
+		for _, field := range entry.AdditionalFields.Fields {
+
+			if field.FieldValue != nil {
+
+				fieldValues[field.ID] = field.FieldValue.FieldValue
+			}
+		}
+	}
+
+	writeObject(buf, opCtx, selections, channelType.Name, func(field graphql.CollectedField) {
+
+		if typedField, ok := typedFields[field.Name]; ok {
+
+			writeValue(ctx, buf, opCtx, field, channeltypes.Value(typedField.Kind, fieldValues[typedField.FieldId]))
+
+			return
+		}
+
+		writeValue(ctx, buf, opCtx, field, entryValue(entry, field.Name))
+	})
+}
+
+// entryValue returns the value of one of the entry columns every channel type has
+func entryValue(entry model.ChannelEntries, name string) interface{} {
+
+	switch name {
+
+	case "id":
+
+		return entry.ID
+
+	case "title":
+
+		return entry.Title
+
+	case "slug":
+
+		return entry.Slug
+
+	case "description":
+
+		return string(entry.Description)
+
+	case "coverImage":
+
+		if entry.CoverImage == "" {
+
+			return nil
+		}
+
+		return channeltypes.NewImage(entry.CoverImage)
+
+	case "categoryIds":
+
+		categoryIds := []int{}
+
+		for _, value := range channeltypes.SplitList(entry.CategoriesID) {
+
+			if categoryId, err := strconv.Atoi(value); err == nil {
+
+				categoryIds = append(categoryIds, categoryId)
+			}
+		}
+
+		return categoryIds
+
+	case "tags":
+
+		return stringValue(entry.Tags)
+
+	case "excerpt":
+
+		return stringValue(entry.Excerpt)
+
+	case "author":
+
+		return stringValue(entry.Author)
+
+	case "viewCount":
+
+		return entry.ViewCount
+
+	case "createdOn":
+
+		return entry.CreatedOn
+
+	case "modifiedOn":
+
+		return timeValue(entry.ModifiedOn)
+
+	case "publishedTime":
+
+		return timeValue(entry.PublishedTime)
+	}
+
+	return nil
+}
+
+func stringValue(value *string) interface{} {
+
+	if value == nil {
+
+		return nil
+	}
+
+	return *value
+}
+
+func timeValue(value *time.Time) interface{} {
+
+	if value == nil {
+
+		return nil
+	}
+
+	return *value
+}
+
+func writeValue(ctx context.Context, buf *bytes.Buffer, opCtx *graphql.OperationContext, field graphql.CollectedField, value interface{}) {
+
+	switch value := value.(type) {
+
+	case string:
+
+		graphql.MarshalString(value).MarshalGQL(buf)
+
+	case int:
+
+		graphql.MarshalInt(value).MarshalGQL(buf)
+
+	case time.Time:
+
+		graphql.MarshalTime(value).MarshalGQL(buf)
+
+	case []string:
+
+		buf.WriteByte('[')
+
+		for index, item := range value {
+
+			if index > 0 {
+
+				buf.WriteByte(',')
+			}
+
+			graphql.MarshalString(item).MarshalGQL(buf)
+		}
+
+		buf.WriteByte(']')
+
+	case []int:
+
+		buf.WriteByte('[')
+
+		for index, item := range value {
+
+			if index > 0 {
+
+				buf.WriteByte(',')
+			}
+
+			graphql.MarshalInt(item).MarshalGQL(buf)
+		}
+
+		buf.WriteByte(']')
+
+	case channeltypes.Image:
+
+		writeImage(ctx, buf, opCtx, field.Selections, value)
+
+	case []channeltypes.Image:
+
+		buf.WriteByte('[')
+
+		for index, image := range value {
+
+			if index > 0 {
+
+				buf.WriteByte(',')
+			}
+
+			writeImage(ctx, buf, opCtx, field.Selections, image)
+		}
+
+		buf.WriteByte(']')
+
+	default:
+
+		buf.WriteString("null")
+	}
+}
+
+func writeImage(ctx context.Context, buf *bytes.Buffer, opCtx *graphql.OperationContext, selections ast.SelectionSet, image channeltypes.Image) {
+
+	writeObject(buf, opCtx, selections, channeltypes.ImageTypeName, func(field graphql.CollectedField) {
+
+		switch field.Name {
+
+		case "path":
+
+			graphql.MarshalString(image.Path).MarshalGQL(buf)
+
+		case "url":
+
+			url, err := imageURL(ctx, opCtx, field, image)
+
+			if err != nil {
+
+				graphql.AddError(ctx, err)
+
+				buf.WriteString("null")
+
+				return
+			}
+
+			graphql.MarshalString(url).MarshalGQL(buf)
+
+		default:
+
+			buf.WriteString("null")
+		}
+	})
+}
+
+// imageURL returns the url of an image, a signed transform url when the field is given transform arguments
+func imageURL(ctx context.Context, opCtx *graphql.OperationContext, field graphql.CollectedField, image channeltypes.Image) (string, error) {
+
+	args := field.ArgumentMap(opCtx.Variables)
+
+	var (
+		width, height, quality *int
+		fit                    *model.ImageFit
+		format                 *model.ImageFormat
+	)
+
+	for name, target := range map[string]**int{"width": &width, "height": &height, "quality": &quality} {
+
+		value, ok, err := intArg(args, name)
+
+		if err != nil {
+
+			return "", err
+		}
+
+		if ok {
+
+			*target = &value
+		}
+	}
+
+	if value, ok := args["fit"].(string); ok {
+
+		imageFit := model.ImageFit(value)
+
+		fit = &imageFit
+	}
+
+	if value, ok := args["format"].(string); ok {
+
+		imageFormat := model.ImageFormat(value)
+
+		format = &imageFormat
+	}
+
+	if width == nil && height == nil && quality == nil && fit == nil && format == nil {
+
+		return image.Url, nil
+	}
+
+	return controller.TransformImage(ctx, image.Path, width, height, fit, format, quality)
+}
+
+// writeObject writes the selected fields of an object in the order they were asked for, __typename is answered here
+func writeObject(buf *bytes.Buffer, opCtx *graphql.OperationContext, selections ast.SelectionSet, typeName string, writeField func(field graphql.CollectedField)) {
+
+	buf.WriteByte('{')
+
+	for index, field := range graphql.CollectFields(opCtx, selections, []string{typeName}) {
+
+		if index > 0 {
+
+			buf.WriteByte(',')
+		}
+
+		graphql.MarshalString(field.Alias).MarshalGQL(buf)
+
+		buf.WriteByte(':')
+
+		if field.Name == "__typename" {
+
+			graphql.MarshalString(typeName).MarshalGQL(buf)
+
+			continue
+		}
+
+		writeField(field)
+	}
+
+	buf.WriteByte('}')
+}
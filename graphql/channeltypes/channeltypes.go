@@ -0,0 +1,447 @@
+package channeltypes
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"spurt-cms/graphql/fieldfilter"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Kind is the graphql type an additional field is exposed as
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindStrings
+	KindInt
+	KindTime
+	KindImages
+)
+
+// field type ids of tbl_field_types
+const (
+	labelFieldType        = 1
+	dateTimeFieldType     = 4
+	dateFieldType         = 6
+	checkboxFieldType     = 10
+	sectionFieldType      = 12
+	sectionBreakFieldType = 13
+	memberFieldType       = 14
+	mediaGalleryFieldType = 15
+)
+
+// ImageTypeName is the object type image fields are exposed as, its url takes the transform arguments of the base
+// schema image fields
+const ImageTypeName = "Image"
+
+// Field is an additional field of a channel as defined in its field group
+type Field struct {
+	Id     int
+	Name   string
+	TypeId int
+}
+
+type Channel struct {
+	Id     int
+	Slug   string
+	Name   string
+	Fields []Field
+}
+
+// TypedField is a graphql field of a channel type that reads an additional field
+type TypedField struct {
+	Name    string
+	FieldId int
+	Kind    Kind
+}
+
+// Type is the object type generated for a channel
+type Type struct {
+	Name      string
+	ListName  string
+	ChannelId int
+	Fields    []TypedField
+}
+
+// Query is a root query field generated for a channel, List queries return a page of entries and the others a single entry
+type Query struct {
+	ChannelId int
+	TypeName  string
+	List      bool
+}
+
+type Schema struct {
+	Types   map[string]*Type
+	Queries map[string]Query
+
+	// the channels left out because their names clash with the base schema
+	Skipped []string
+
+	// schema definition language extending the base schema
+	Input string
+}
+
+// Image is the value of an image field
+type Image struct {
+	Path string
+	Url  string
+}
+
+// the entry columns every channel type has, additional fields with one of these names are suffixed with their id
+var entryFields = []struct{ name, definition string }{
+	{"id", "Int!"},
+	{"title", "String!"},
+	{"slug", "String!"},
+	{"description", "String!"},
+	{"coverImage", ImageTypeName},
+	{"categoryIds", "[Int!]!"},
+	{"tags", "String"},
+	{"excerpt", "String"},
+	{"author", "String"},
+	{"viewCount", "Int!"},
+	{"createdOn", "Time!"},
+	{"modifiedOn", "Time"},
+	{"publishedTime", "Time"},
+}
+
+// KindOf reports the kind a field type is exposed as, false for the types that hold no value such as sections
+func KindOf(fieldTypeId int) (Kind, bool) {
+
+	switch fieldTypeId {
+
+	case labelFieldType, sectionFieldType, sectionBreakFieldType:
+
+		return KindString, false
+
+	case dateTimeFieldType, dateFieldType:
+
+		return KindTime, true
+
+	case checkboxFieldType:
+
+		return KindStrings, true
+
+	case memberFieldType:
+
+		return KindInt, true
+
+	case mediaGalleryFieldType:
+
+		return KindImages, true
+	}
+
+	return KindString, true
+}
+
+// Build generates an object type and two query fields for every channel, e.g. a "blog" channel gets the BlogEntry
+// type with the blogEntries and blogEntry queries. Channels whose names clash with the base schema are skipped.
+func Build(channels []Channel, base *ast.Schema) Schema {
+
+	schema := Schema{Types: make(map[string]*Type), Queries: make(map[string]Query)}
+
+	sorted := make([]Channel, len(channels))
+
+	copy(sorted, channels)
+
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Id < sorted[j].Id })
+
+	var types, queries strings.Builder
+
+	for _, channel := range sorted {
+
+		baseName := pascalName(channel.Slug)
+
+		if baseName == "" {
+
+			baseName = pascalName(channel.Name)
+		}
+
+		if baseName == "" {
+
+			schema.Skipped = append(schema.Skipped, channel.Slug)
+
+			continue
+		}
+
+		typeName, listName := baseName+"Entry", baseName+"EntryList"
+
+		listQuery, entryQuery := lowerFirst(baseName)+"Entries", lowerFirst(baseName)+"Entry"
+
+		if schemaTaken(base, typeName, listName, listQuery, entryQuery) || schema.Types[typeName] != nil || schema.Types[listName] != nil || typeName == ImageTypeName {
+
+			schema.Skipped = append(schema.Skipped, channel.Slug)
+
+			continue
+		}
+
+		channelType := &Type{Name: typeName, ListName: listName, ChannelId: channel.Id, Fields: typedFields(channel.Fields)}
+
+		schema.Types[typeName] = channelType
+
+		schema.Queries[listQuery] = Query{ChannelId: channel.Id, TypeName: typeName, List: true}
+
+		schema.Queries[entryQuery] = Query{ChannelId: channel.Id, TypeName: typeName}
+
+		writeType(&types, channelType)
+
+		queries.WriteString("\t" + listQuery + "(limit: Int, offset: Int, categorySlug: String): " + listName + "! @auth\n")
+
+		queries.WriteString("\t" + entryQuery + "(id: Int, slug: String): " + typeName + " @auth\n")
+	}
+
+	if len(schema.Types) == 0 {
+
+		return schema
+	}
+
+	schema.Input = "type " + ImageTypeName + "{\n\tpath: String!\n\turl(width: Int, height: Int, fit: ImageFit, format: ImageFormat, quality: Int): String!\n}\n\n" + types.String() + "extend type Query{\n" + queries.String() + "}\n"
+
+	return schema
+}
+
+func typedFields(fields []Field) []TypedField {
+
+	taken := make(map[string]bool, len(entryFields)+len(fields))
+
+	for _, field := range entryFields {
+
+		taken[field.name] = true
+	}
+
+	var typed []TypedField
+
+	for _, field := range fields {
+
+		kind, ok := KindOf(field.TypeId)
+
+		if !ok {
+
+			continue
+		}
+
+		name := lowerFirst(pascalName(field.Name))
+
+		if name == "" {
+
+			name = "field"
+		}
+
+		if taken[name] {
+
+			name += strconv.Itoa(field.Id)
+		}
+
+		if taken[name] {
+
+			continue
+		}
+
+		taken[name] = true
+
+		typed = append(typed, TypedField{Name: name, FieldId: field.Id, Kind: kind})
+	}
+
+	return typed
+}
+
+func writeType(w *strings.Builder, channelType *Type) {
+
+	w.WriteString("type " + channelType.Name + "{\n")
+
+	for _, field := range entryFields {
+
+		w.WriteString("\t" + field.name + ": " + field.definition + "\n")
+	}
+
+	for _, field := range channelType.Fields {
+
+		w.WriteString("\t" + field.Name + ": " + kindDefinition(field.Kind) + "\n")
+	}
+
+	w.WriteString("}\n\n")
+
+	w.WriteString("type " + channelType.ListName + "{\n\tentries: [" + channelType.Name + "!]!\n\tcount: Int!\n}\n\n")
+}
+
+func kindDefinition(kind Kind) string {
+
+	switch kind {
+
+	case KindStrings:
+
+		return "[String!]!"
+
+	case KindInt:
+
+		return "Int"
+
+	case KindTime:
+
+		return "Time"
+
+	case KindImages:
+
+		return "[" + ImageTypeName + "!]!"
+	}
+
+	return "String"
+}
+
+func schemaTaken(base *ast.Schema, typeName, listName, listQuery, entryQuery string) bool {
+
+	if base == nil {
+
+		return false
+	}
+
+	if base.Types[typeName] != nil || base.Types[listName] != nil {
+
+		return true
+	}
+
+	return base.Query != nil && (base.Query.Fields.ForName(listQuery) != nil || base.Query.Fields.ForName(entryQuery) != nil)
+}
+
+// pascalName turns a slug or label into a graphql type name, "blog-posts" becomes BlogPosts. Characters that can
+// not be part of a graphql name are dropped and a name starting with a digit gets a Channel prefix.
+func pascalName(value string) string {
+
+	words := strings.FieldsFunc(value, func(r rune) bool {
+
+		return r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r))
+	})
+
+	var name strings.Builder
+
+	for _, word := range words {
+
+		name.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+
+	result := name.String()
+
+	if result != "" && unicode.IsDigit(rune(result[0])) {
+
+		result = "Channel" + result
+	}
+
+	return result
+}
+
+func lowerFirst(value string) string {
+
+	if value == "" {
+
+		return value
+	}
+
+	// an acronym such as FAQ stays readable as faq rather than fAQ
+	upper := 0
+
+	for upper < len(value) && unicode.IsUpper(rune(value[upper])) {
+
+		upper++
+	}
+
+	switch {
+
+	case upper == len(value):
+
+		return strings.ToLower(value)
+
+	case upper > 1:
+
+		return strings.ToLower(value[:upper-1]) + value[upper-1:]
+	}
+
+	return strings.ToLower(value[:1]) + value[1:]
+}
+
+// Value converts a stored field value to the value of its graphql field, nil for an empty or unreadable value
+func Value(kind Kind, raw string) interface{} {
+
+	raw = strings.TrimSpace(raw)
+
+	switch kind {
+
+	case KindStrings:
+
+		return SplitList(raw)
+
+	case KindImages:
+
+		images := []Image{}
+
+		for _, imagePath := range SplitList(raw) {
+
+			images = append(images, NewImage(imagePath))
+		}
+
+		return images
+	}
+
+	if raw == "" {
+
+		return nil
+	}
+
+	switch kind {
+
+	case KindInt:
+
+		number, err := strconv.Atoi(raw)
+
+		if err != nil {
+
+			return nil
+		}
+
+		return number
+
+	case KindTime:
+
+		parsed, err := fieldfilter.ParseDate(raw)
+
+		if err != nil {
+
+			return nil
+		}
+
+		return parsed
+	}
+
+	return raw
+}
+
+// NewImage returns the image stored at the path, the url serves it through the image-resize route
+func NewImage(imagePath string) Image {
+
+	query := url.Values{}
+
+	query.Set("name", path.Base(imagePath))
+
+	query.Set("path", path.Dir(imagePath))
+
+	return Image{Path: imagePath, Url: "/image-resize?" + query.Encode()}
+}
+
+// SplitList splits a comma separated value and drops the empty items
+func SplitList(value string) []string {
+
+	items := []string{}
+
+	for _, item := range strings.Split(value, ",") {
+
+		if item = strings.TrimSpace(item); item != "" {
+
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,187 @@
+package channeltypes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const baseSchema = `
+directive @auth on FIELD_DEFINITION
+
+scalar Time
+
+enum ImageFit{
+	CONTAIN
+	COVER
+	FILL
+}
+
+enum ImageFormat{
+	JPEG
+	PNG
+}
+
+type Channel{
+	id: Int!
+}
+
+type PageEntry{
+	id: Int!
+}
+
+type Query{
+	channelList: [Channel!]!
+	newsEntries: [Channel!]!
+}
+`
+
+func loadBase(t *testing.T) *ast.Schema {
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "base.graphqls", Input: baseSchema})
+
+	if err != nil {
+		t.Fatalf("loading the base schema: %v", err)
+	}
+
+	return schema
+}
+
+func TestBuildGeneratesTypedChannels(t *testing.T) {
+
+	base := loadBase(t)
+
+	schema := Build([]Channel{
+		{Id: 3, Slug: "blog", Fields: []Field{
+			{Id: 1, Name: "Hero Image", TypeId: mediaGalleryFieldType},
+			{Id: 2, Name: "Rating", TypeId: 2},
+			{Id: 3, Name: "Intro", TypeId: sectionFieldType},
+			{Id: 4, Name: "Title", TypeId: 7},
+			{Id: 5, Name: "Start Date", TypeId: dateFieldType},
+			{Id: 6, Name: "Tags List", TypeId: checkboxFieldType},
+		}},
+		{Id: 4, Slug: "news"},
+		{Id: 5, Slug: "page"},
+	}, base)
+
+	blog := schema.Types["BlogEntry"]
+
+	if blog == nil {
+		t.Fatalf("expected a BlogEntry type, got %v", schema.Types)
+	}
+
+	want := []TypedField{
+		{Name: "heroImage", FieldId: 1, Kind: KindImages},
+		{Name: "rating", FieldId: 2, Kind: KindString},
+		{Name: "title4", FieldId: 4, Kind: KindString},
+		{Name: "startDate", FieldId: 5, Kind: KindTime},
+		{Name: "tagsList", FieldId: 6, Kind: KindStrings},
+	}
+
+	if !reflect.DeepEqual(blog.Fields, want) {
+		t.Fatalf("unexpected fields %+v", blog.Fields)
+	}
+
+	if query := schema.Queries["blogEntries"]; !query.List || query.ChannelId != 3 || query.TypeName != "BlogEntry" {
+		t.Fatalf("unexpected list query %+v", query)
+	}
+
+	if query := schema.Queries["blogEntry"]; query.List || query.ChannelId != 3 {
+		t.Fatalf("unexpected entry query %+v", query)
+	}
+
+	// newsEntries is a query of the base schema and PageEntry is one of its types
+	if !reflect.DeepEqual(schema.Skipped, []string{"news", "page"}) {
+		t.Fatalf("unexpected skipped channels %v", schema.Skipped)
+	}
+
+	extended, err := gqlparser.LoadSchema(&ast.Source{Name: "base.graphqls", Input: baseSchema}, &ast.Source{Name: "channels.graphqls", Input: schema.Input})
+
+	if err != nil {
+		t.Fatalf("generated schema does not load: %v\n%s", err, schema.Input)
+	}
+
+	if field := extended.Types["BlogEntry"].Fields.ForName("heroImage"); field == nil || field.Type.String() != "[Image!]!" {
+		t.Fatalf("unexpected heroImage field %v", field)
+	}
+
+	if url := extended.Types[ImageTypeName].Fields.ForName("url"); url == nil || url.Arguments.ForName("fit") == nil {
+		t.Fatalf("expected the image url to take transform arguments, got %v", url)
+	}
+
+	if extended.Query.Fields.ForName("blogEntries") == nil {
+		t.Fatal("expected the blogEntries query")
+	}
+}
+
+func TestBuildWithoutChannels(t *testing.T) {
+
+	schema := Build(nil, loadBase(t))
+
+	if schema.Input != "" || len(schema.Types) != 0 {
+		t.Fatalf("expected an empty schema, got %+v", schema)
+	}
+}
+
+func TestNames(t *testing.T) {
+
+	cases := map[string]string{
+		"blog":          "Blog",
+		"blog-posts":    "BlogPosts",
+		"product_items": "ProductItems",
+		"2024 events":   "Channel2024Events",
+		"café menu":     "CafMenu",
+		"!!":            "",
+	}
+
+	for value, want := range cases {
+
+		if got := pascalName(value); got != want {
+			t.Errorf("pascalName(%q) = %q, want %q", value, got, want)
+		}
+	}
+
+	for value, want := range map[string]string{"Blog": "blog", "FAQ": "faq", "FAQItems": "faqItems", "HeroImage": "heroImage"} {
+
+		if got := lowerFirst(value); got != want {
+			t.Errorf("lowerFirst(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestValue(t *testing.T) {
+
+	if got := Value(KindInt, " 42 "); got != 42 {
+		t.Fatalf("expected 42, got %v", got)
+	}
+
+	if got := Value(KindInt, "four"); got != nil {
+		t.Fatalf("expected nil for an unreadable number, got %v", got)
+	}
+
+	if got := Value(KindString, ""); got != nil {
+		t.Fatalf("expected nil for an empty value, got %v", got)
+	}
+
+	if got := Value(KindTime, "2024-05-01"); got != time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC) {
+		t.Fatalf("unexpected date %v", got)
+	}
+
+	if got := Value(KindStrings, "red, ,blue"); !reflect.DeepEqual(got, []string{"red", "blue"}) {
+		t.Fatalf("unexpected list %v", got)
+	}
+
+	images := Value(KindImages, "media/2024/hero.png").([]Image)
+
+	if len(images) != 1 || images[0].Path != "media/2024/hero.png" || !strings.Contains(images[0].Url, "name=hero.png") || !strings.Contains(images[0].Url, "path=media%2F2024") {
+		t.Fatalf("unexpected images %+v", images)
+	}
+
+	if got := Value(KindImages, ""); !reflect.DeepEqual(got, []Image{}) {
+		t.Fatalf("expected no images, got %v", got)
+	}
+}
@@ -0,0 +1,368 @@
+package controller
+
+import (
+	"context"
+	"spurt-cms/graphql/entryviews"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/pagination"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spurtcms/channels"
+	"gorm.io/gorm"
+)
+
+// a visitor counts once per entry within this window, refreshes and reopened tabs do not add views
+const viewDedupWindow = 30 * time.Minute
+
+// the longest range EntryViewStats returns the days of
+const maxViewStatsDays = 366
+
+// the most views remembered for the dedup window, beyond it the oldest are forgotten and may count again
+const viewDedupSize = 100000
+
+var entryViewDeduper = entryviews.NewDeduper(viewDedupWindow, viewDedupSize)
+
+// entryVisitor is the reader a frontend reports a view for when it calls the api from its server, so the client ip
+// and user agent of the request are the frontend's own. Every part is optional.
+type entryVisitor struct {
+	Ip        string
+	UserAgent string
+	Id        string
+}
+
+// firstEntryView reports whether a view of the entry should be counted. Crawlers never count, a signed in member is
+// recognised by the member id, a visitor id reported by the frontend by that id and anybody else by the client ip and
+// user agent.
+func firstEntryView(c *gin.Context, entryId int, visitor entryVisitor) bool {
+
+	userAgent, clientIp := c.GetHeader("User-Agent"), c.ClientIP()
+
+	if visitor.UserAgent != "" {
+
+		userAgent = visitor.UserAgent
+	}
+
+	if visitor.Ip != "" {
+
+		clientIp = visitor.Ip
+	}
+
+	if entryviews.IsBot(userAgent) {
+
+		return false
+	}
+
+	fingerprint := entryviews.Fingerprint(clientIp, userAgent)
+
+	if visitor.Id != "" {
+
+		fingerprint = entryviews.Fingerprint("visitor", visitor.Id)
+	}
+
+	// an invalid member token is reported by the queries that need the member, here it only falls back to the visitor
+	if access, ok, err := GetMemberAccess(c); err == nil && ok {
+
+		fingerprint = entryviews.Fingerprint("member", strconv.Itoa(access.MemberId))
+	}
+
+	return entryViewDeduper.First(entryId, fingerprint, time.Now())
+}
+
+// viewPeriodStart returns the first day counted for the period, zero for all time
+func viewPeriodStart(period model.ViewPeriod, now time.Time) time.Time {
+
+	today := entryviews.Day(now)
+
+	switch period {
+
+	case model.ViewPeriodDay:
+
+		return today
+
+	case model.ViewPeriodWeek:
+
+		return today.AddDate(0, 0, -6)
+
+	case model.ViewPeriodMonth:
+
+		return today.AddDate(0, 0, -29)
+
+	case model.ViewPeriodYear:
+
+		return today.AddDate(0, 0, -364)
+	}
+
+	return time.Time{}
+}
+
+// PopularEntries returns the published entries viewed most within the period, most viewed first
+func PopularEntries(ctx context.Context, channelSlug *string, period model.ViewPeriod, limit *int, additionalData *model.EntriesAdditionalData) ([]model.PopularEntry, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return []model.PopularEntry{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return []model.PopularEntry{}, info.ErrFetchTenantDetails
+	}
+
+	inputs := model.PopularEntriesReq{Since: viewPeriodStart(period, time.Now()), Limit: pagination.DefaultPageSize, TenantId: tenantDetails.TenantId}
+
+	if limit != nil && *limit > 0 {
+
+		inputs.Limit = *limit
+	}
+
+	if inputs.Limit > pagination.MaxPageSize {
+
+		inputs.Limit = pagination.MaxPageSize
+	}
+
+	if channelSlug != nil && *channelSlug != "" {
+
+		channel, err := ChannelConfigWP.ChannelDetail(channels.Channels{Slug: *channelSlug, TenantId: tenantDetails.TenantId})
+
+		if err != nil && err != gorm.ErrRecordNotFound {
+
+			ErrorLog.Printf("%v", err)
+
+			c.AbortWithStatus(500)
+
+			return []model.PopularEntry{}, err
+		}
+
+		if channel.Id == 0 {
+
+			c.AbortWithStatus(404)
+
+			return []model.PopularEntry{}, info.ErrChannelNotFound
+		}
+
+		if err := checkApiKeyChannel(c, channel.Id); err != nil {
+
+			return []model.PopularEntry{}, err
+		}
+
+		inputs.ChannelId = channel.Id
+
+	} else {
+
+		scope, err := GetApiKeyScope(c)
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			c.AbortWithStatus(500)
+
+			return []model.PopularEntry{}, err
+		}
+
+		inputs.ChannelIds = scope.ChannelIds
+	}
+
+	inputs.MemberGroupId, err = requestMemberGroupId(c)
+
+	if err != nil {
+
+		return []model.PopularEntry{}, err
+	}
+
+	popular, err := model.Model.PopularEntries(inputs)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return []model.PopularEntry{}, err
+	}
+
+	if len(popular) == 0 {
+
+		return []model.PopularEntry{}, nil
+	}
+
+	entryIds := make([]int, len(popular))
+
+	for index, views := range popular {
+
+		entryIds[index] = views.EntryId
+	}
+
+	_, channelEntries, err := ChannelConfigWP.FetchChannelEntryDetail(channels.EntriesInputs{TenantId: tenantDetails.TenantId}, entryIds)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return []model.PopularEntry{}, err
+	}
+
+	entriesById := make(map[int]channels.Tblchannelentries, len(channelEntries))
+
+	for _, entry := range channelEntries {
+
+		entriesById[entry.Id] = entry
+	}
+
+	var (
+		nodes     []model.ChannelEntries
+		nodeViews []int
+	)
+
+	// the details come back unordered, so follow the ranking of the views
+	for _, views := range popular {
+
+		entry, ok := entriesById[views.EntryId]
+
+		if !ok {
+
+			continue
+		}
+
+		nodes = append(nodes, convertChannelEntry(entry))
+
+		nodeViews = append(nodeViews, views.Views)
+	}
+
+	if err := loadEntryRelations(ctx, nodes, searchEntryRelations(additionalData), tenantDetails.TenantId); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return []model.PopularEntry{}, err
+	}
+
+	result := make([]model.PopularEntry, len(nodes))
+
+	for index := range nodes {
+
+		result[index] = model.PopularEntry{Entry: &nodes[index], Views: nodeViews[index]}
+	}
+
+	return result, nil
+}
+
+// EntryViewStats returns the views of an entry per day, the last 30 days unless a range is given
+func EntryViewStats(ctx context.Context, id int, from *time.Time, to *time.Time) (*model.EntryViewStats, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.EntryViewStats{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.EntryViewStats{}, info.ErrFetchTenantDetails
+	}
+
+	lastDay := entryviews.Day(time.Now())
+
+	if to != nil {
+
+		lastDay = entryviews.Day(*to)
+	}
+
+	firstDay := lastDay.AddDate(0, 0, -29)
+
+	if from != nil {
+
+		firstDay = entryviews.Day(*from)
+	}
+
+	if firstDay.After(lastDay) || lastDay.Sub(firstDay) >= maxViewStatsDays*24*time.Hour {
+
+		c.AbortWithStatus(400)
+
+		return &model.EntryViewStats{}, info.ErrViewStatsRange
+	}
+
+	channelEntry, _, err := ChannelConfigWP.FetchChannelEntryDetail(channels.EntriesInputs{Id: id, TenantId: tenantDetails.TenantId}, nil)
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.EntryViewStats{}, err
+	}
+
+	if channelEntry.Id == 0 {
+
+		c.AbortWithStatus(404)
+
+		return &model.EntryViewStats{}, info.ErrRecordNotFound
+	}
+
+	if err := checkApiKeyChannel(c, channelEntry.ChannelId); err != nil {
+
+		return &model.EntryViewStats{}, err
+	}
+
+	hidden, err := memberEntryHidden(c, channelEntry.Id, tenantDetails.TenantId)
+
+	if err != nil {
+
+		return &model.EntryViewStats{}, err
+	}
+
+	if hidden {
+
+		c.AbortWithStatus(403)
+
+		return &model.EntryViewStats{}, info.ErrMemberRestricted
+	}
+
+	dayViews, err := model.Model.EntryDayViews(channelEntry.Id, firstDay, lastDay, tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.EntryViewStats{}, err
+	}
+
+	stats := &model.EntryViewStats{EntryID: channelEntry.Id, Days: []model.EntryDayViews{}}
+
+	for _, day := range entryviews.Series(dayViews, firstDay, lastDay) {
+
+		stats.Total += day.Views
+
+		stats.Days = append(stats.Days, model.EntryDayViews{Day: day.Day, Views: day.Views})
+	}
+
+	return stats, nil
+}
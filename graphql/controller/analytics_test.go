@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"spurt-cms/graphql/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordEntryViewUpsertsTheDayRow(t *testing.T) {
+
+	counting := withCountingDB(t)
+
+	if err := model.Model.RecordEntryView(3, 1, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), 1); err != nil {
+		t.Fatalf("record entry view: %v", err)
+	}
+
+	if len(counting.statements) != 1 {
+		t.Fatalf("expected 1 statement, got %d: %v", len(counting.statements), counting.statements)
+	}
+
+	if statement := counting.statements[0]; !strings.Contains(statement, `ON CONFLICT ("entry_id","view_date") DO UPDATE`) {
+		t.Fatalf("expected the view to be counted up on conflict, got %s", statement)
+	}
+}
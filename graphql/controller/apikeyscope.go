@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spurtcms/channels"
+)
+
+// ApiKeyScope is what the api key of a request may access. Every key can read, an empty channel list allows every channel.
+type ApiKeyScope struct {
+	ChannelIds []int
+	Write      bool
+	MemberData bool
+}
+
+func NewApiKeyScope(settings model.TblGraphqlSettings) ApiKeyScope {
+
+	return ApiKeyScope{
+		ChannelIds: splitIds(settings.ChannelIds),
+		Write:      settings.WriteAccess == 1,
+		MemberData: settings.MemberAccess == 1,
+	}
+}
+
+// Allows reports whether the scope grants the access required by an @auth directive
+func (scope ApiKeyScope) Allows(requires model.Scope) bool {
+
+	switch requires {
+
+	case model.ScopeWrite:
+
+		return scope.Write
+
+	case model.ScopeMemberData:
+
+		return scope.MemberData
+	}
+
+	return true
+}
+
+func (scope ApiKeyScope) AllowsChannel(channelId int) bool {
+
+	if len(scope.ChannelIds) == 0 {
+
+		return true
+	}
+
+	for _, id := range scope.ChannelIds {
+
+		if id == channelId {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func GetApiKeyScope(c *gin.Context) (ApiKeyScope, error) {
+
+	scope, ok := c.Get("apiKeyScope")
+
+	if !ok {
+
+		return ApiKeyScope{}, info.ErrFetchApiKeyScope
+	}
+
+	return scope.(ApiKeyScope), nil
+}
+
+// apiKeyStatus returns the entry status a list is filtered by. Only published entries are readable with keys without
+// write access, they list published entries when no status is asked for and are refused draft or unpublished ones.
+func apiKeyStatus(c *gin.Context, status int) (int, error) {
+
+	if status == 1 {
+
+		return status, nil
+	}
+
+	scope, err := GetApiKeyScope(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return status, err
+	}
+
+	if scope.Write {
+
+		return status, nil
+	}
+
+	if status == -1 {
+
+		return 1, nil
+	}
+
+	c.AbortWithStatus(403)
+
+	return status, info.ErrApiKeyScope
+}
+
+// checkApiKeyChannel aborts the request when the api key has no access to the channel
+func checkApiKeyChannel(c *gin.Context, channelId int) error {
+
+	scope, err := GetApiKeyScope(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return err
+	}
+
+	if !scope.AllowsChannel(channelId) {
+
+		c.AbortWithStatus(403)
+
+		return info.ErrApiKeyChannel
+	}
+
+	return nil
+}
+
+// scopedChannelPage keeps the channels the scope allows and returns the requested page of them with their count
+func scopedChannelPage(channelList []channels.Tblchannel, scope ApiKeyScope, limit, offset int) ([]channels.Tblchannel, int) {
+
+	var allowed []channels.Tblchannel
+
+	for _, channel := range channelList {
+
+		if scope.AllowsChannel(channel.Id) {
+
+			allowed = append(allowed, channel)
+		}
+	}
+
+	count := len(allowed)
+
+	switch {
+
+	case offset > count:
+
+		allowed = nil
+
+	case offset > 0:
+
+		allowed = allowed[offset:]
+	}
+
+	if limit > 0 && limit < len(allowed) {
+
+		allowed = allowed[:limit]
+	}
+
+	return allowed, count
+}
+
+// CacheKey identifies the scope in cache keys, api keys with the same scope share their cached responses
+func (scope ApiKeyScope) CacheKey() string {
+
+	sorted := append([]int(nil), scope.ChannelIds...)
+
+	sort.Ints(sorted)
+
+	channelIds := make([]string, len(sorted))
+
+	for index, channelId := range sorted {
+
+		channelIds[index] = strconv.Itoa(channelId)
+	}
+
+	return fmt.Sprintf("channels=%v;write=%v;member=%v", strings.Join(channelIds, ","), scope.Write, scope.MemberData)
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestApiKeyStatus(t *testing.T) {
+
+	for _, test := range []struct {
+		status   int
+		write    bool
+		allowed  bool
+		expected int
+	}{
+		{status: -1, allowed: true, expected: 1},
+		{status: 1, allowed: true, expected: 1},
+		{status: 0, allowed: false},
+		{status: 2, allowed: false},
+		{status: -1, write: true, allowed: true, expected: -1},
+		{status: 0, write: true, allowed: true, expected: 0},
+		{status: 2, write: true, allowed: true, expected: 2},
+	} {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+		c.Set("apiKeyScope", ApiKeyScope{Write: test.write})
+
+		status, err := apiKeyStatus(c, test.status)
+
+		if (err == nil) != test.allowed {
+			t.Fatalf("status %d with write access %v: expected allowed %v, got %v", test.status, test.write, test.allowed, err)
+		}
+
+		if !test.allowed {
+
+			if c.Writer.Status() != 403 {
+				t.Fatalf("status %d: expected 403, got %d", test.status, c.Writer.Status())
+			}
+
+			continue
+		}
+
+		if status != test.expected {
+			t.Fatalf("status %d with write access %v: expected to list status %d, got %d", test.status, test.write, test.expected, status)
+		}
+	}
+}
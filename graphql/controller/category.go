@@ -2,10 +2,13 @@ package controller
 
 import (
 	"context"
+	"sort"
+	"spurt-cms/graphql/categorytree"
 	"spurt-cms/graphql/info"
 	"spurt-cms/graphql/model"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spurtcms/categories"
 	"gorm.io/gorm"
 )
 
@@ -39,6 +42,8 @@ func CategoryList(ctx context.Context, categoryFilter *model.CategoryFilter, com
 		}
 	}
 
+	limit = listLimit(limit)
+
 	if categoryFilter != nil {
 
 		if categoryFilter.CategoryGroupID.IsSet() {
@@ -130,3 +135,151 @@ func CategoryList(ctx context.Context, categoryFilter *model.CategoryFilter, com
 	return &model.CategoryDetails{Categorylist: finalCategoriesList, Count: count}, nil
 
 }
+
+// CategoryTree returns the categories of a group as a nested tree with the number of readable entries in every category
+func CategoryTree(ctx context.Context, categoryGroupSlug string, depth *int) (*model.CategoryNode, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return nil, info.ErrGinCtx
+	}
+
+	if categoryGroupSlug == "" {
+
+		c.AbortWithStatus(400)
+
+		return nil, info.ErrReqMandatory
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return nil, info.ErrFetchTenantDetails
+	}
+
+	groupId, err := model.Model.CategoryIdBySlug(categoryGroupSlug, tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return nil, info.ErrFetchCategoryDetails
+	}
+
+	if groupId == 0 {
+
+		return nil, nil
+	}
+
+	var maxDepth, hierarchyLevel int
+
+	if depth != nil && *depth > 0 {
+
+		// the hierarchy level of the categories package counts the group as level 1
+		maxDepth, hierarchyLevel = *depth, *depth+1
+	}
+
+	categoryList, _, err := CategoryInstance.CategoryList(0, 0, groupId, hierarchyLevel, tenantDetails.TenantId, false, false, false, false, "", "")
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			return nil, nil
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return nil, info.ErrFetchCategoryDetails
+	}
+
+	// the list comes newest first, the tree keeps siblings in the order they were created
+	sort.SliceStable(categoryList, func(i, j int) bool { return categoryList[i].Id < categoryList[j].Id })
+
+	categoriesById := make(map[int]categories.TblCategories, len(categoryList))
+
+	nodes := make([]categorytree.Node, 0, len(categoryList))
+
+	for _, category := range categoryList {
+
+		categoriesById[category.Id] = category
+
+		nodes = append(nodes, categorytree.Node{Id: category.Id, ParentId: category.ParentId, Slug: category.CategorySlug})
+	}
+
+	root := categorytree.Build(nodes, groupId, maxDepth)
+
+	if root == nil {
+
+		return nil, nil
+	}
+
+	scope, err := GetApiKeyScope(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return nil, err
+	}
+
+	memberGroupId, err := requestMemberGroupId(c)
+
+	if err != nil {
+
+		return nil, err
+	}
+
+	entryCategories, err := model.Model.PublishedEntryCategoryIds(scope.ChannelIds, memberGroupId, tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return nil, info.ErrFetchCategoryDetails
+	}
+
+	entryCategoryIds := make([][]int, 0, len(entryCategories))
+
+	for _, categoryIds := range entryCategories {
+
+		entryCategoryIds = append(entryCategoryIds, splitIds(categoryIds))
+	}
+
+	root.CountEntries(entryCategoryIds)
+
+	tree := convertCategoryNode(root, categoriesById)
+
+	return &tree, nil
+}
+
+func convertCategoryNode(node *categorytree.Node, categoriesById map[int]categories.TblCategories) model.CategoryNode {
+
+	category := convertCategory(categoriesById[node.Id])
+
+	children := make([]model.CategoryNode, 0, len(node.Children))
+
+	for _, child := range node.Children {
+
+		children = append(children, convertCategoryNode(child, categoriesById))
+	}
+
+	return model.CategoryNode{Category: &category, Path: node.Path, Depth: node.Depth, EntryCount: node.EntryCount, Children: children}
+}
@@ -3,13 +3,22 @@ package controller
 import (
 	"context"
 	"fmt"
+	"os"
+	"spurt-cms/graphql/entryviews"
 	"spurt-cms/graphql/info"
 	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/pagination"
 	"spurt-cms/graphql/scalars"
+	"spurt-cms/graphql/tokens"
+	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spurtcms/categories"
 	"github.com/spurtcms/channels"
+	"github.com/spurtcms/member"
+	"github.com/spurtcms/team"
 	"gorm.io/gorm"
 	"spurt-cms/logger"
 )
@@ -78,6 +87,8 @@ func ChannelList(ctx context.Context, filter *model.Filter, sort *model.Sort) (*
 		}
 	}
 
+	limit = listLimit(limit)
+
 	input := channels.Channels{
 		Limit:        limit,
 		Offset:       offset,
@@ -92,6 +103,23 @@ func ChannelList(ctx context.Context, filter *model.Filter, sort *model.Sort) (*
 		// EntriesCount:   true,
 	}
 
+	scope, err := GetApiKeyScope(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelDetails{}, err
+	}
+
+	// the channels package can not filter by id, a channel scoped key reads all channels and pages the allowed ones here
+	if len(scope.ChannelIds) > 0 {
+
+		input.Limit, input.Offset = 0, 0
+	}
+
 	channels, count, err := ChannelConfigWP.ListChannel(input)
 
 	if err != nil {
@@ -110,6 +138,11 @@ func ChannelList(ctx context.Context, filter *model.Filter, sort *model.Sort) (*
 		return &model.ChannelDetails{}, err
 	}
 
+	if len(scope.ChannelIds) > 0 {
+
+		channels, count = scopedChannelPage(channels, scope, limit, offset)
+	}
+
 	var channelList []model.Channel
 
 	for _, channel := range channels {
@@ -210,6 +243,11 @@ func ChannelDetails(ctx context.Context, channelId *int, channelSlug *string, st
 		return &model.Channel{}, err
 	}
 
+	if err := checkApiKeyChannel(c, channel.Id); err != nil {
+
+		return &model.Channel{}, err
+	}
+
 	channelDetails := model.Channel{
 		ID:                 channel.Id,
 		ChannelName:        channel.ChannelName,
@@ -228,7 +266,7 @@ func ChannelDetails(ctx context.Context, channelId *int, channelSlug *string, st
 	return &channelDetails, nil
 }
 
-func ChannelEntriesList(ctx context.Context, commonFilter *model.Filter, sort *model.Sort, EntryFilter *model.EntriesFilter, additionalData *model.EntriesAdditionalData) (*model.ChannelEntryDetails, error) {
+func ChannelEntriesList(ctx context.Context, commonFilter *model.Filter, sort *model.Sort, EntryFilter *model.EntriesFilter, additionalData *model.EntriesAdditionalData, fieldFilter []model.FieldPredicate, fieldSort *model.FieldSort) (*model.ChannelEntryDetails, error) {
 
 	c, ok := ctx.Value(GinContext).(*gin.Context)
 
@@ -253,7 +291,7 @@ func ChannelEntriesList(ctx context.Context, commonFilter *model.Filter, sort *m
 		limit, offset                                                                   int = 0, -1
 		order                                                                           int
 		isActive                                                                        bool
-		keyword, title, sortBy                                                          string
+		keyword, sortBy                                                                 string
 		channelId, categoryId                                                           int
 		categorySlug                                                                    string
 		status                                                                          string
@@ -337,6 +375,8 @@ func ChannelEntriesList(ctx context.Context, commonFilter *model.Filter, sort *m
 		}
 	}
 
+	limit = listLimit(limit)
+
 	if sort != nil {
 
 		if sort.SortBy.IsSet() && sort.SortBy.Value() != nil {
@@ -373,32 +413,106 @@ func ChannelEntriesList(ctx context.Context, commonFilter *model.Filter, sort *m
 		}
 	}
 
+	entryStatus, err := apiKeyStatus(c, entryStatusValue(status))
+
+	if err != nil {
+
+		return &model.ChannelEntryDetails{}, err
+	}
+
+	scope, err := GetApiKeyScope(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntryDetails{}, err
+	}
+
+	// without a channel id the list covers every channel the api key is scoped to
+	var scopeChannelIds []int
+
+	if channelId != 0 {
+
+		if err := checkApiKeyChannel(c, channelId); err != nil {
+
+			return &model.ChannelEntryDetails{}, err
+		}
+
+	} else {
+
+		scopeChannelIds = scope.ChannelIds
+	}
+
+	memberGroupId, err := requestMemberGroupId(c)
+
+	if err != nil {
+
+		return &model.ChannelEntryDetails{}, err
+	}
+
 	// logger.Info(fmt.Sprintf("limit: %v,offset: %v,isActive: %v,order: %v,sort: %v,title: %v,keyword: %v,channelid: %v,categoryid: %v,categorySlug: %v,status: %v,memflag: %v,categoryFlag: %v,authorflag: %v,fieldsFlag: %v\n", limit, offset, isActive, order, sortBy, title, keyword, channelId, categoryId, categorySlug, status, memberProfFlag, categoriesFlag, authorFlag, fieldsFlg))
 
-	inputs := channels.EntriesInputs{
-		ChannelId:              channelId,
-		Limit:                  limit,
-		Offset:                 offset,
-		Keyword:                keyword,
-		Status:                 status,
-		Title:                  title,
-		CategoryId:             categoryId,
-		CategorySlug:           categorySlug,
-		ActiveEntriesonly:      isActive,
-		TenantId:               tenantDetails.TenantId,
-		GetMemberProfile:       memberProfFlag,
-		GetAuthorDetails:       authorFlag,
-		GetAdditionalFields:    fieldsFlg,
-		GetLinkedCategories:    categoriesFlag,
-		SelectedCategoryFilter: selectedCategoriesFilter,
-		TotalCount:             true,
-		Order:                  order,
-		SortBy:                 sortBy,
-		SectionFieldTypeId:     12,
-		MemberFieldTypeId:      14,
-	}
-
-	channelEntries, commonCount, _, err := ChannelConfigWP.FlexibleChannelEntriesList(inputs)
+	fieldChannelIds := scopeChannelIds
+
+	if channelId != 0 {
+
+		fieldChannelIds = []int{channelId}
+	}
+
+	fieldConditions, fieldOrder, err := entryFieldQuery(fieldFilter, fieldSort, fieldChannelIds, nil, tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == info.ErrFieldPredicate {
+
+			c.AbortWithStatus(400)
+
+			return &model.ChannelEntryDetails{}, err
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntryDetails{}, err
+	}
+
+	inputs := model.EntriesPageReq{
+		EntriesCursorReq: model.EntriesCursorReq{
+			ChannelId:              channelId,
+			CategoryId:             categoryId,
+			CategorySlug:           categorySlug,
+			SelectedCategoryFilter: selectedCategoriesFilter,
+			Keyword:                keyword,
+			Status:                 entryStatus,
+			ActiveEntriesOnly:      isActive,
+			ChannelIds:             scopeChannelIds,
+			MemberGroupId:          memberGroupId,
+			FieldConditions:        fieldConditions,
+			FieldOrder:             fieldOrder,
+			TenantId:               tenantDetails.TenantId,
+		},
+		SortBy: sortBy,
+		Order:  order,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	entryIds, count, err := model.Model.EntriesPage(inputs)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntryDetails{}, err
+	}
+
+	channelEntries, err := entriesInOrder(entryIds, tenantDetails.TenantId)
 
 	if err != nil {
 
@@ -409,346 +523,439 @@ func ChannelEntriesList(ctx context.Context, commonFilter *model.Filter, sort *m
 		return &model.ChannelEntryDetails{}, err
 	}
 
+	commonCount := int(count)
+
 	finalChannelEntries := make([]model.ChannelEntries, len(channelEntries))
 
 	for i, v := range channelEntries {
 
-		finalChannelEntries[i].ID = v.Id
+		finalChannelEntries[i] = convertChannelEntry(v)
+	}
+
+	// the relations are batched per list rather than read entry by entry inside the channels package
+	relations := entryRelations{Author: authorFlag, MemberProfile: memberProfFlag, Categories: categoriesFlag, AdditionalFields: fieldsFlg}
+
+	if err := loadEntryRelations(ctx, finalChannelEntries, relations, tenantDetails.TenantId); err != nil {
 
-		finalChannelEntries[i].Title = v.Title
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntryDetails{}, err
+	}
 
-		author := v.Author
+	return &model.ChannelEntryDetails{ChannelEntriesList: finalChannelEntries, Count: commonCount}, nil
+}
 
-		finalChannelEntries[i].Author = &author
+// entryStatusValue returns the stored status of a status filter, -1 lists entries of every status
+func entryStatusValue(status string) int {
 
-		finalChannelEntries[i].ChannelID = v.ChannelId
+	switch status {
 
-		finalChannelEntries[i].CategoriesID = v.CategoriesId
+	case "Draft":
 
-		finalChannelEntries[i].IsActive = v.IsActive
+		return 0
 
-		finalChannelEntries[i].CoverImage = v.CoverImage
+	case "Publish":
 
-		createTime := v.CreateTime
+		return 1
 
-		finalChannelEntries[i].CreateTime = &createTime
+	case "Unpublish":
 
-		finalChannelEntries[i].CreatedBy = v.CreatedBy
+		return 2
+	}
 
-		finalChannelEntries[i].CreatedOn = v.CreatedOn
+	return -1
+}
 
-		finalChannelEntries[i].Description = scalars.CustomString(v.Description)
+// entriesInOrder reads the details of a page of entries in the order of their ids
+func entriesInOrder(entryIds []int, tenantId int) ([]channels.Tblchannelentries, error) {
 
-		excerpt := &v.Excerpt
+	if len(entryIds) == 0 {
 
-		finalChannelEntries[i].Excerpt = excerpt
+		return []channels.Tblchannelentries{}, nil
+	}
 
-		finalChannelEntries[i].FeaturedEntry = v.Feature
+	_, channelEntries, err := ChannelConfigWP.FetchChannelEntryDetail(channels.EntriesInputs{TenantId: tenantId}, entryIds)
 
-		imgAltTag := v.ImageAltTag
+	if err != nil {
 
-		finalChannelEntries[i].ImageAltTag = &imgAltTag
+		return []channels.Tblchannelentries{}, err
+	}
 
-		finalChannelEntries[i].Keyword = v.Keyword
+	entriesById := make(map[int]channels.Tblchannelentries, len(channelEntries))
 
-		finalChannelEntries[i].MetaDescription = v.MetaDescription
+	for _, entry := range channelEntries {
 
-		finalChannelEntries[i].MetaTitle = v.MetaTitle
+		entriesById[entry.Id] = entry
+	}
 
-		publishTime := v.PublishedTime
+	ordered := make([]channels.Tblchannelentries, 0, len(entryIds))
 
-		finalChannelEntries[i].PublishedTime = &publishTime
+	// the details come back unordered, so follow the order of the page
+	for _, entryId := range entryIds {
 
-		readTime := v.ReadingTime
+		if entry, ok := entriesById[entryId]; ok {
 
-		finalChannelEntries[i].ReadingTime = &readTime
+			ordered = append(ordered, entry)
+		}
+	}
 
-		finalChannelEntries[i].RelatedArticles = v.RelatedArticles
+	return ordered, nil
+}
 
-		finalChannelEntries[i].ViewCount = v.ViewCount
+func convertChannelEntry(v channels.Tblchannelentries) model.ChannelEntries {
 
-		finalChannelEntries[i].Slug = v.Slug
+	var entry model.ChannelEntries
 
-		sortOrder := v.SortOrder
+	entry.ID = v.Id
 
-		finalChannelEntries[i].SortOrder = &sortOrder
+	entry.Title = v.Title
 
-		finalChannelEntries[i].Status = v.Status
+	author := v.Author
 
-		tags := v.Tags
+	entry.Author = &author
 
-		finalChannelEntries[i].Tags = &tags
+	entry.ChannelID = v.ChannelId
 
-		finalChannelEntries[i].ThumbnailImage = v.ThumbnailImage
+	entry.CategoriesID = v.CategoriesId
 
-		finalChannelEntries[i].UserID = v.UserId
+	entry.IsActive = v.IsActive
 
-		finalChannelEntries[i].TenantID = v.TenantId
+	entry.CoverImage = v.CoverImage
 
-		switch {
+	createTime := v.CreateTime
 
-		case utf8.RuneCountInString(v.Description) > MaxChunkLength:
+	entry.CreateTime = &createTime
 
-			chunks := FetchChunkData(v.Description)
+	entry.CreatedBy = v.CreatedBy
 
-			finalChannelEntries[i].ContentChunk = &model.Chunk{Data: chunks, Length: len(chunks)}
+	entry.CreatedOn = v.CreatedOn
 
-		default:
+	entry.Description = scalars.CustomString(v.Description)
 
-			finalChannelEntries[i].ContentChunk = &model.Chunk{Data: []string{v.Description}, Length: 1}
-		}
+	excerpt := &v.Excerpt
 
-		if v.AuthorDetail.Id != 0 {
+	entry.Excerpt = excerpt
 
-			authorMobile := v.AuthorDetail.MobileNo
+	entry.FeaturedEntry = v.Feature
 
-			authorActive := v.AuthorDetail.IsActive
+	imgAltTag := v.ImageAltTag
 
-			authorProfileImgPath := v.AuthorDetail.ProfileImagePath
+	entry.ImageAltTag = &imgAltTag
 
-			authorModon := v.AuthorDetail.ModifiedOn
+	entry.Keyword = v.Keyword
 
-			authorModby := v.AuthorDetail.ModifiedBy
+	entry.MetaDescription = v.MetaDescription
 
-			authorDetails := &model.Author{
-				ID:               v.AuthorDetail.Id,
-				FirstName:        v.AuthorDetail.FirstName,
-				LastName:         v.AuthorDetail.LastName,
-				Email:            v.AuthorDetail.Email,
-				MobileNo:         &authorMobile,
-				IsActive:         &authorActive,
-				ProfileImagePath: &authorProfileImgPath,
-				CreatedOn:        v.AuthorDetail.CreatedOn,
-				CreatedBy:        v.AuthorDetail.CreatedBy,
-				ModifiedOn:       &authorModon,
-				ModifiedBy:       &authorModby,
-				TenantID:         v.AuthorDetail.TenantId,
-			}
+	entry.MetaTitle = v.MetaTitle
 
-			finalChannelEntries[i].AuthorDetails = authorDetails
+	publishTime := v.PublishedTime
 
-		}
+	entry.PublishedTime = &publishTime
 
-		if v.MemberProfiles.Id != 0 {
+	readTime := v.ReadingTime
 
-			profilePage := v.MemberProfiles.ProfilePage
+	entry.ReadingTime = &readTime
 
-			companyName := v.MemberProfiles.CompanyName
+	entry.RelatedArticles = v.RelatedArticles
 
-			companyLocation := v.MemberProfiles.CompanyLocation
+	entry.ViewCount = v.ViewCount
 
-			companyLogo := v.MemberProfiles.CompanyLogo
+	entry.Slug = v.Slug
 
-			aboutComapny := v.MemberProfiles.About
+	sortOrder := v.SortOrder
 
-			seoTitle := v.MemberProfiles.SeoTitle
+	entry.SortOrder = &sortOrder
 
-			seoDesc := v.MemberProfiles.SeoDescription
+	entry.Status = v.Status
 
-			seoKey := v.MemberProfiles.SeoKeyword
+	tags := v.Tags
 
-			linkedin := v.MemberProfiles.Linkedin
+	entry.Tags = &tags
 
-			twitter := v.MemberProfiles.Twitter
+	entry.ThumbnailImage = v.ThumbnailImage
 
-			website := v.MemberProfiles.Website
+	entry.UserID = v.UserId
 
-			profModon := v.MemberProfiles.ModifiedOn
+	entry.TenantID = v.TenantId
 
-			profModby := v.MemberProfiles.ModifiedBy
+	switch {
 
-			claimStatus := v.MemberProfiles.ClaimStatus
+	case utf8.RuneCountInString(v.Description) > MaxChunkLength:
 
-			claimDate := v.MemberProfiles.ClaimDate
+		chunks := FetchChunkData(v.Description)
 
-			createdOn := v.MemberProfiles.CreatedOn
+		entry.ContentChunk = &model.Chunk{Data: chunks, Length: len(chunks)}
 
-			createdBy := v.MemberProfiles.CreatedBy
+	default:
 
-			memberProfile := &model.MemberProfile{
-				ID:              v.MemberProfiles.Id,
-				MemberID:        v.MemberProfiles.MemberId,
-				ProfileName:     v.MemberProfiles.ProfileName,
-				ProfileSlug:     v.MemberProfiles.ProfileSlug,
-				ProfilePage:     &profilePage,
-				MemberDetails:   v.MemberProfiles.MemberDetails,
-				CompanyName:     &companyName,
-				CompanyLocation: &companyLocation,
-				CompanyLogo:     &companyLogo,
-				About:           &aboutComapny,
-				SeoTitle:        &seoTitle,
-				SeoDescription:  &seoDesc,
-				SeoKeyword:      &seoKey,
-				Linkedin:        &linkedin,
-				Twitter:         &twitter,
-				Website:         &website,
-				CreatedBy:       &createdBy,
-				CreatedOn:       &createdOn,
-				ModifiedOn:      &profModon,
-				ModifiedBy:      &profModby,
-				ClaimStatus:     &claimStatus,
-				TenantID:        v.MemberProfiles.TenantId,
-				ClaimDate:       &claimDate,
-			}
+		entry.ContentChunk = &model.Chunk{Data: []string{v.Description}, Length: 1}
+	}
 
-			finalChannelEntries[i].MemberProfile = memberProfile
-		}
+	if v.AuthorDetail.Id != 0 {
 
-		if len(v.Categories) > 0 {
+		entry.AuthorDetails = convertAuthor(v.AuthorDetail)
+	}
 
-			conv_categories := make([][]model.Category, len(v.Categories))
+	if v.MemberProfiles.Id != 0 {
 
-			for cat_index, categories := range v.Categories {
+		entry.MemberProfile = convertMemberProfile(v.MemberProfiles)
+	}
 
-				conv_categoryz := make([]model.Category, len(categories))
+	if len(v.Categories) > 0 {
 
-				for i, category := range categories {
+		entry.Categories = convertCategoryHierarchy(v.Categories)
+	}
 
-					categoryModon := category.ModifiedOn
+	conv_sections := make([]model.Section, len(v.Sections))
 
-					categoryModBy := category.ModifiedBy
+	if len(v.Sections) > 0 {
 
-					conv_category := model.Category{
-						ID:           category.Id,
-						CategoryName: category.CategoryName,
-						CategorySlug: category.CategorySlug,
-						Description:  category.Description,
-						ImagePath:    category.ImagePath,
-						CreatedOn:    category.CreatedOn,
-						CreatedBy:    category.CreatedBy,
-						ModifiedOn:   &categoryModon,
-						ModifiedBy:   &categoryModBy,
-						ParentID:     category.ParentId,
-						TenantID:     category.TenantId,
-					}
+		for section_index, section := range v.Sections {
 
-					conv_categoryz[i] = conv_category
+			sectionModon := section.ModifiedOn
 
-				}
+			sectionModBy := section.ModifiedBy
 
-				conv_categories[cat_index] = conv_categoryz
+			conv_section := model.Section{
+				ID:            section.Id,
+				SectionName:   section.FieldName,
+				SectionTypeID: section.FieldTypeId,
+				CreatedOn:     section.CreatedOn,
+				CreatedBy:     section.CreatedBy,
+				ModifiedOn:    &sectionModon,
+				ModifiedBy:    &sectionModBy,
+				OrderIndex:    section.OrderIndex,
+				TenantID:      section.TenantId,
 			}
 
-			finalChannelEntries[i].Categories = conv_categories
+			conv_sections[section_index] = conv_section
+
 		}
+	}
+
+	conv_fields := make([]model.Field, len(v.Fields))
+
+	if len(v.Fields) > 0 {
 
-		conv_sections := make([]model.Section, len(v.Sections))
+		for field_index, field := range v.Fields {
+
+			fieldValueModon := field.FieldValue.ModifiedOn
+
+			fieldValueModBy := field.FieldValue.ModifiedBy
+
+			conv_field_value := model.FieldValue{
+				ID:         field.FieldValue.FieldId,
+				FieldValue: field.FieldValue.FieldValue,
+				CreatedOn:  field.FieldValue.CreatedOn,
+				CreatedBy:  field.FieldValue.CreatedBy,
+				ModifiedOn: &fieldValueModon,
+				ModifiedBy: &fieldValueModBy,
+				TenantID:   field.FieldValue.TenantId,
+			}
 
-		if len(v.Sections) > 0 {
+			conv_fieldOptions := make([]model.FieldOptions, len(field.FieldOptions))
 
-			for section_index, section := range v.Sections {
+			for option_index, field_option := range field.FieldOptions {
 
-				sectionModon := section.ModifiedOn
+				optionModOn := field_option.ModifiedOn
 
-				sectionModBy := section.ModifiedBy
+				optionModBy := field_option.ModifiedBy
 
-				conv_section := model.Section{
-					ID:            section.Id,
-					SectionName:   section.FieldName,
-					SectionTypeID: section.FieldTypeId,
-					CreatedOn:     section.CreatedOn,
-					CreatedBy:     section.CreatedBy,
-					ModifiedOn:    &sectionModon,
-					ModifiedBy:    &sectionModBy,
-					OrderIndex:    section.OrderIndex,
-					TenantID:      section.TenantId,
+				conv_fieldOption := model.FieldOptions{
+					ID:          field_option.Id,
+					OptionName:  field_option.OptionName,
+					OptionValue: field_option.OptionValue,
+					CreatedOn:   field_option.CreatedOn,
+					CreatedBy:   field_option.CreatedBy,
+					ModifiedOn:  &optionModOn,
+					ModifiedBy:  &optionModBy,
+					TenantID:    field_option.TenantId,
 				}
 
-				conv_sections[section_index] = conv_section
+				conv_fieldOptions[option_index] = conv_fieldOption
+			}
+
+			fieldModon := field.ModifiedOn
+
+			fieldModBy := field.ModifiedBy
+
+			fieldDateTime := field.DatetimeFormat
+
+			fieldTime := field.TimeFormat
+
+			fieldSectionParentId := field.SectionParentId
+
+			fieldCharAllowed := field.CharacterAllowed
 
+			conv_field := model.Field{
+				ID:               field.Id,
+				FieldName:        field.FieldName,
+				FieldTypeID:      field.FieldTypeId,
+				MandatoryField:   field.MandatoryField,
+				OptionExist:      field.OptionExist,
+				CreatedOn:        field.CreatedOn,
+				CreatedBy:        field.CreatedBy,
+				ModifiedOn:       &fieldModon,
+				ModifiedBy:       &fieldModBy,
+				FieldDesc:        field.FieldDesc,
+				OrderIndex:       field.OrderIndex,
+				ImagePath:        field.ImagePath,
+				DatetimeFormat:   &fieldDateTime,
+				TimeFormat:       &fieldTime,
+				SectionParentID:  &fieldSectionParentId,
+				CharacterAllowed: &fieldCharAllowed,
+				FieldTypeName:    field.FieldTypeName,
+				FieldValue:       &conv_field_value,
+				FieldOptions:     conv_fieldOptions,
+				TenantID:         field.TenantId,
 			}
+
+			conv_fields[field_index] = conv_field
+
 		}
+	}
 
-		conv_fields := make([]model.Field, len(v.Fields))
+	entry.AdditionalFields = &model.AdditionalFields{Sections: conv_sections, Fields: conv_fields}
 
-		if len(v.Fields) > 0 {
+	return entry
+}
 
-			for field_index, field := range v.Fields {
+func convertAuthor(author team.TblUser) *model.Author {
 
-				fieldValueModon := field.FieldValue.ModifiedOn
+	authorMobile := author.MobileNo
 
-				fieldValueModBy := field.FieldValue.ModifiedBy
+	authorActive := author.IsActive
 
-				conv_field_value := model.FieldValue{
-					ID:         field.FieldValue.FieldId,
-					FieldValue: field.FieldValue.FieldValue,
-					CreatedOn:  field.FieldValue.CreatedOn,
-					CreatedBy:  field.FieldValue.CreatedBy,
-					ModifiedOn: &fieldValueModon,
-					ModifiedBy: &fieldValueModBy,
-					TenantID:   field.FieldValue.TenantId,
-				}
+	authorProfileImgPath := author.ProfileImagePath
 
-				conv_fieldOptions := make([]model.FieldOptions, len(field.FieldOptions))
+	authorModon := author.ModifiedOn
 
-				for option_index, field_option := range field.FieldOptions {
+	authorModby := author.ModifiedBy
 
-					optionModOn := field_option.ModifiedOn
+	return &model.Author{
+		ID:               author.Id,
+		FirstName:        author.FirstName,
+		LastName:         author.LastName,
+		Email:            author.Email,
+		MobileNo:         &authorMobile,
+		IsActive:         &authorActive,
+		ProfileImagePath: &authorProfileImgPath,
+		CreatedOn:        author.CreatedOn,
+		CreatedBy:        author.CreatedBy,
+		ModifiedOn:       &authorModon,
+		ModifiedBy:       &authorModby,
+		TenantID:         author.TenantId,
+	}
+}
 
-					optionModBy := field_option.ModifiedBy
+func convertMemberProfile(profile member.TblMemberProfile) *model.MemberProfile {
 
-					conv_fieldOption := model.FieldOptions{
-						ID:          field_option.Id,
-						OptionName:  field_option.OptionName,
-						OptionValue: field_option.OptionValue,
-						CreatedOn:   field_option.CreatedOn,
-						CreatedBy:   field_option.CreatedBy,
-						ModifiedOn:  &optionModOn,
-						ModifiedBy:  &optionModBy,
-						TenantID:    field_option.TenantId,
-					}
+	profilePage := profile.ProfilePage
 
-					conv_fieldOptions[option_index] = conv_fieldOption
-				}
+	companyName := profile.CompanyName
 
-				fieldModon := field.ModifiedOn
-
-				fieldModBy := field.ModifiedBy
-
-				fieldDateTime := field.DatetimeFormat
-
-				fieldTime := field.TimeFormat
-
-				fieldSectionParentId := field.SectionParentId
-
-				fieldCharAllowed := field.CharacterAllowed
-
-				conv_field := model.Field{
-					ID:               field.Id,
-					FieldName:        field.FieldName,
-					FieldTypeID:      field.FieldTypeId,
-					MandatoryField:   field.MandatoryField,
-					OptionExist:      field.OptionExist,
-					CreatedOn:        field.CreatedOn,
-					CreatedBy:        field.CreatedBy,
-					ModifiedOn:       &fieldModon,
-					ModifiedBy:       &fieldModBy,
-					FieldDesc:        field.FieldDesc,
-					OrderIndex:       field.OrderIndex,
-					ImagePath:        field.ImagePath,
-					DatetimeFormat:   &fieldDateTime,
-					TimeFormat:       &fieldTime,
-					SectionParentID:  &fieldSectionParentId,
-					CharacterAllowed: &fieldCharAllowed,
-					FieldTypeName:    field.FieldTypeName,
-					FieldValue:       &conv_field_value,
-					FieldOptions:     conv_fieldOptions,
-					TenantID:         field.TenantId,
-				}
+	companyLocation := profile.CompanyLocation
 
-				conv_fields[field_index] = conv_field
+	companyLogo := profile.CompanyLogo
 
-			}
-		}
+	aboutComapny := profile.About
+
+	seoTitle := profile.SeoTitle
+
+	seoDesc := profile.SeoDescription
+
+	seoKey := profile.SeoKeyword
+
+	linkedin := profile.Linkedin
+
+	twitter := profile.Twitter
+
+	website := profile.Website
 
-		finalChannelEntries[i].AdditionalFields = &model.AdditionalFields{Sections: conv_sections, Fields: conv_fields}
+	profModon := profile.ModifiedOn
 
+	profModby := profile.ModifiedBy
+
+	claimStatus := profile.ClaimStatus
+
+	claimDate := profile.ClaimDate
+
+	createdOn := profile.CreatedOn
+
+	createdBy := profile.CreatedBy
+
+	return &model.MemberProfile{
+		ID:              profile.Id,
+		MemberID:        profile.MemberId,
+		ProfileName:     profile.ProfileName,
+		ProfileSlug:     profile.ProfileSlug,
+		ProfilePage:     &profilePage,
+		MemberDetails:   profile.MemberDetails,
+		CompanyName:     &companyName,
+		CompanyLocation: &companyLocation,
+		CompanyLogo:     &companyLogo,
+		About:           &aboutComapny,
+		SeoTitle:        &seoTitle,
+		SeoDescription:  &seoDesc,
+		SeoKeyword:      &seoKey,
+		Linkedin:        &linkedin,
+		Twitter:         &twitter,
+		Website:         &website,
+		CreatedBy:       &createdBy,
+		CreatedOn:       &createdOn,
+		ModifiedOn:      &profModon,
+		ModifiedBy:      &profModby,
+		ClaimStatus:     &claimStatus,
+		TenantID:        profile.TenantId,
+		ClaimDate:       &claimDate,
 	}
+}
 
-	return &model.ChannelEntryDetails{ChannelEntriesList: finalChannelEntries, Count: commonCount}, nil
+func convertCategory(category categories.TblCategories) model.Category {
+
+	categoryModon := category.ModifiedOn
+
+	categoryModBy := category.ModifiedBy
+
+	return model.Category{
+		ID:           category.Id,
+		CategoryName: category.CategoryName,
+		CategorySlug: category.CategorySlug,
+		Description:  category.Description,
+		ImagePath:    category.ImagePath,
+		CreatedOn:    category.CreatedOn,
+		CreatedBy:    category.CreatedBy,
+		ModifiedOn:   &categoryModon,
+		ModifiedBy:   &categoryModBy,
+		ParentID:     category.ParentId,
+		TenantID:     category.TenantId,
+	}
+}
+
+func convertCategoryHierarchy(categoryHierarchy [][]categories.TblCategories) [][]model.Category {
+
+	conv_categories := make([][]model.Category, len(categoryHierarchy))
+
+	for cat_index, categoryStream := range categoryHierarchy {
+
+		conv_categoryz := make([]model.Category, len(categoryStream))
+
+		for i, category := range categoryStream {
+
+			conv_categoryz[i] = convertCategory(category)
+		}
+
+		conv_categories[cat_index] = conv_categoryz
+	}
+
+	return conv_categories
 }
 
-func ChannelEntryDetail(ctx context.Context, id *int, slug *string, additionalData *model.EntriesAdditionalData, channelId *int) (*model.ChannelEntries, error) {
+// ChannelEntryDetail returns a published entry by id or slug. Drafts and unpublished entries are returned to api keys
+// with write access and to a valid preview token of the entry, which may also pick the entry on its own.
+func ChannelEntryDetail(ctx context.Context, id *int, slug *string, additionalData *model.EntriesAdditionalData, channelId *int, previewToken *string) (*model.ChannelEntries, error) {
 
 	c, ok := ctx.Value(GinContext).(*gin.Context)
 
@@ -759,7 +966,7 @@ func ChannelEntryDetail(ctx context.Context, id *int, slug *string, additionalDa
 		return &model.ChannelEntries{}, info.ErrGinCtx
 	}
 
-	if id == nil && slug == nil {
+	if id == nil && slug == nil && (previewToken == nil || *previewToken == "") {
 
 		ErrorLog.Printf("%v", info.ErrReqMandatory)
 
@@ -788,7 +995,7 @@ func ChannelEntryDetail(ctx context.Context, id *int, slug *string, additionalDa
 		chanId = *channelId
 	}
 
-	logger.Info(fmt.Sprintf("%v", "chaannan", chanId))
+	logger.Info(fmt.Sprint("chaannan", chanId))
 
 	if additionalData != nil {
 
@@ -820,6 +1027,31 @@ func ChannelEntryDetail(ctx context.Context, id *int, slug *string, additionalDa
 		return &model.ChannelEntries{}, err
 	}
 
+	var preview *tokens.PreviewClaims
+
+	if previewToken != nil && *previewToken != "" {
+
+		var claims tokens.PreviewClaims
+
+		err := tokens.Parse(os.Getenv("JWT_SECRET"), tokens.PreviewAudience, *previewToken, &claims, time.Now())
+
+		if err != nil || claims.TenantId != tenantData.TenantId {
+
+			ErrorLog.Printf("%v", info.ErrPreviewToken)
+
+			c.AbortWithStatus(401)
+
+			return &model.ChannelEntries{}, info.ErrPreviewToken
+		}
+
+		preview = &claims
+
+		if entryId == 0 && entrySlug == "" {
+
+			entryId = claims.EntryId
+		}
+	}
+
 	inputs := channels.EntriesInputs{
 		Id:                  entryId,
 		Slug:                entrySlug,
@@ -833,7 +1065,7 @@ func ChannelEntryDetail(ctx context.Context, id *int, slug *string, additionalDa
 
 	channelEntry, _, err := ChannelConfigWP.FetchChannelEntryDetail(inputs, nil)
 
-	logger.Info(fmt.Sprintf("%v", "aithorDetails", channelEntry.AuthorDetail.CreatedOn))
+	logger.Info(fmt.Sprint("aithorDetails", channelEntry.AuthorDetail.CreatedOn))
 
 	switch {
 
@@ -847,11 +1079,61 @@ func ChannelEntryDetail(ctx context.Context, id *int, slug *string, additionalDa
 
 	}
 
-	conv_categories := make([][]model.Category, len(channelEntry.Categories))
+	if preview != nil && preview.EntryId != channelEntry.Id {
 
-	if len(channelEntry.Categories) > 0 {
+		ErrorLog.Printf("%v", info.ErrPreviewToken)
 
-		for cat_index, categories := range channelEntry.Categories {
+		c.AbortWithStatus(403)
+
+		return &model.ChannelEntries{}, info.ErrPreviewToken
+	}
+
+	if err := checkApiKeyChannel(c, channelEntry.ChannelId); err != nil {
+
+		return &model.ChannelEntries{}, err
+	}
+
+	if channelEntry.Status != 1 && preview == nil {
+
+		scope, err := GetApiKeyScope(c)
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			return &model.ChannelEntries{}, err
+		}
+
+		// an unpublished entry is reported the same as a missing one
+		if !scope.Write {
+
+			return &model.ChannelEntries{}, info.ErrRecordNotFound
+		}
+	}
+
+	// the preview token was issued by an editor, the member groups of the entry do not apply to it
+	if preview == nil {
+
+		hidden, err := memberEntryHidden(c, channelEntry.Id, tenantData.TenantId)
+
+		if err != nil {
+
+			return &model.ChannelEntries{}, err
+		}
+
+		if hidden {
+
+			c.AbortWithStatus(403)
+
+			return &model.ChannelEntries{}, info.ErrMemberRestricted
+		}
+	}
+
+	conv_categories := make([][]model.Category, len(channelEntry.Categories))
+
+	if len(channelEntry.Categories) > 0 {
+
+		for cat_index, categories := range channelEntry.Categories {
 
 			conv_categoryz := make([]model.Category, len(categories))
 
@@ -1102,12 +1384,12 @@ func ChannelEntryDetail(ctx context.Context, id *int, slug *string, additionalDa
 		convChannelEntry.ContentChunk = &model.Chunk{Data: []string{channelEntry.Description}, Length: 1}
 	}
 
-	logger.Info(fmt.Sprintf("%v", "aouthoorrr", convChannelEntry.AuthorDetails.CreatedOn))
+	logger.Info(fmt.Sprint("aouthoorrr", convChannelEntry.AuthorDetails.CreatedOn))
 
 	return &convChannelEntry, nil
 }
 
-func UpdateEntryViewCount(ctx context.Context, id *int, slug *string) (*model.CountUpdate, error) {
+func UpdateEntryViewCount(ctx context.Context, id *int, slug *string, visitorIP *string, visitorAgent *string, visitorID *string) (*model.CountUpdate, error) {
 
 	c, ok := ctx.Value(GinContext).(*gin.Context)
 
@@ -1149,7 +1431,63 @@ func UpdateEntryViewCount(ctx context.Context, id *int, slug *string) (*model.Co
 		return &model.CountUpdate{Count: 0, Status: false}, err
 	}
 
-	viewCount, err := ChannelConfigWP.UpdateChannelEntryViewCount(entryId, entrySlug, tenantData.TenantId)
+	channelEntry, _, err := ChannelConfigWP.FetchChannelEntryDetail(channels.EntriesInputs{Id: entryId, Slug: entrySlug, TenantId: tenantData.TenantId}, nil)
+
+	if err != nil || channelEntry.Id == 0 {
+
+		return &model.CountUpdate{Count: 0, Status: false}, info.ErrUpdateViewCount
+	}
+
+	// a channel scoped key only counts views of entries in its channels
+	if err := checkApiKeyChannel(c, channelEntry.ChannelId); err != nil {
+
+		return &model.CountUpdate{Count: 0, Status: false}, err
+	}
+
+	var visitor entryVisitor
+
+	if visitorIP != nil {
+
+		visitor.Ip = strings.TrimSpace(*visitorIP)
+	}
+
+	if visitorAgent != nil {
+
+		visitor.UserAgent = strings.TrimSpace(*visitorAgent)
+	}
+
+	if visitorID != nil {
+
+		visitor.Id = strings.TrimSpace(*visitorID)
+	}
+
+	// only a frontend trusted with a write key may report the visitor, anybody else could count a view per request
+	if visitor != (entryVisitor{}) {
+
+		scope, err := GetApiKeyScope(c)
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			return &model.CountUpdate{Count: 0, Status: false}, err
+		}
+
+		if !scope.Write {
+
+			c.AbortWithStatus(403)
+
+			return &model.CountUpdate{Count: 0, Status: false}, info.ErrApiKeyScope
+		}
+	}
+
+	// refreshes and crawlers leave the count as it is
+	if !firstEntryView(c, channelEntry.Id, visitor) {
+
+		return &model.CountUpdate{Count: channelEntry.ViewCount, Status: false}, nil
+	}
+
+	viewCount, err := ChannelConfigWP.UpdateChannelEntryViewCount(channelEntry.Id, "", tenantData.TenantId)
 
 	if err != nil {
 
@@ -1165,5 +1503,427 @@ func UpdateEntryViewCount(ctx context.Context, id *int, slug *string) (*model.Co
 		}
 	}
 
+	if err := model.Model.RecordEntryView(channelEntry.Id, channelEntry.ChannelId, entryviews.Day(time.Now()), tenantData.TenantId); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.CountUpdate{Count: 0, Status: false}, err
+	}
+
 	return &model.CountUpdate{Count: viewCount, Status: true}, nil
 }
+
+func ChannelListConnection(ctx context.Context, first *int, after *string, last *int, before *string, filter *model.Filter, sort *model.Sort) (*model.ChannelConnection, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.ChannelConnection{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelConnection{}, info.ErrFetchTenantDetails
+	}
+
+	window, err := connectionWindow(first, after, last, before, sort, pagination.SortById, pagination.SortByCreatedOn)
+
+	if err != nil {
+
+		c.AbortWithStatus(400)
+
+		return &model.ChannelConnection{}, err
+	}
+
+	inputs := model.ChannelsCursorReq{
+		TenantId: tenantDetails.TenantId,
+		Window:   window,
+	}
+
+	if filter != nil {
+
+		if filter.Keyword.IsSet() && filter.Keyword.Value() != nil {
+
+			inputs.Keyword = *filter.Keyword.Value()
+		}
+
+		if filter.IsActive.IsSet() && filter.IsActive.Value() != nil {
+
+			inputs.IsActive = *filter.IsActive.Value()
+		}
+	}
+
+	scope, err := GetApiKeyScope(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelConnection{}, err
+	}
+
+	inputs.ChannelIds = scope.ChannelIds
+
+	channelList, count, err := model.Model.ChannelsCursorPage(inputs)
+
+	if err != nil {
+
+		if err == info.ErrInvalidCursor {
+
+			c.AbortWithStatus(400)
+
+			return &model.ChannelConnection{}, err
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelConnection{}, err
+	}
+
+	size, hasNextPage, hasPreviousPage := window.Page(len(channelList))
+
+	channelList = channelList[:size]
+
+	if window.Backward {
+
+		pagination.Reverse(channelList)
+	}
+
+	edges := make([]model.ChannelEdge, len(channelList))
+
+	cursors := make([]pagination.Cursor, len(channelList))
+
+	for i, channel := range channelList {
+
+		if window.SortKey == pagination.SortByCreatedOn {
+
+			cursors[i] = pagination.TimeCursor(window.SortKey, channel.CreatedOn, channel.Id)
+
+		} else {
+
+			cursors[i] = pagination.IntCursor(window.SortKey, channel.Id, channel.Id)
+		}
+
+		modifiedOn := channel.ModifiedOn
+
+		modifiedBy := channel.ModifiedBy
+
+		edges[i] = model.ChannelEdge{
+			Node: &model.Channel{
+				ID:                 channel.Id,
+				ChannelName:        channel.ChannelName,
+				ChannelDescription: channel.ChannelDescription,
+				SlugName:           channel.SlugName,
+				FieldGroupID:       channel.FieldGroupId,
+				IsActive:           channel.IsActive,
+				CreatedOn:          channel.CreatedOn,
+				CreatedBy:          channel.CreatedBy,
+				IsDeleted:          channel.IsDeleted,
+				ModifiedOn:         &modifiedOn,
+				ModifiedBy:         &modifiedBy,
+				TenantID:           channel.TenantId,
+			},
+			Cursor: cursors[i].Encode(),
+		}
+	}
+
+	return &model.ChannelConnection{Edges: edges, PageInfo: connectionPageInfo(cursors, hasNextPage, hasPreviousPage), TotalCount: int(count)}, nil
+}
+
+func ChannelEntriesListConnection(ctx context.Context, first *int, after *string, last *int, before *string, commonFilter *model.Filter, sort *model.Sort, entryFilter *model.EntriesFilter, additionalData *model.EntriesAdditionalData, fieldFilter []model.FieldPredicate, fieldSort *model.FieldSort) (*model.ChannelEntriesConnection, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.ChannelEntriesConnection{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntriesConnection{}, info.ErrFetchTenantDetails
+	}
+
+	var window pagination.Window
+
+	// a field sort takes the place of the sort argument, the entry id decides between equal values
+	if fieldSort != nil {
+
+		window, err = pagination.NewWindow(pagination.Args{First: first, After: after, Last: last, Before: before}, fieldSortKey(fieldSort), false)
+
+	} else {
+
+		window, err = connectionWindow(first, after, last, before, sort, pagination.SortById, pagination.SortByCreatedOn, pagination.SortByPublishedTime, pagination.SortBySortOrder)
+	}
+
+	if err != nil {
+
+		c.AbortWithStatus(400)
+
+		return &model.ChannelEntriesConnection{}, err
+	}
+
+	inputs := model.EntriesCursorReq{
+		Status:   -1,
+		TenantId: tenantDetails.TenantId,
+		Window:   window,
+	}
+
+	if commonFilter != nil {
+
+		if commonFilter.IsActive.IsSet() && commonFilter.IsActive.Value() != nil {
+
+			inputs.ActiveEntriesOnly = *commonFilter.IsActive.Value()
+		}
+
+		if commonFilter.Keyword.IsSet() && commonFilter.Keyword.Value() != nil {
+
+			inputs.Keyword = *commonFilter.Keyword.Value()
+		}
+	}
+
+	if entryFilter != nil {
+
+		if entryFilter.ChannelID.IsSet() && entryFilter.ChannelID.Value() != nil {
+
+			inputs.ChannelId = *entryFilter.ChannelID.Value()
+		}
+
+		if entryFilter.CategoryID.IsSet() && entryFilter.CategoryID.Value() != nil {
+
+			inputs.CategoryId = *entryFilter.CategoryID.Value()
+		}
+
+		if entryFilter.CategorySlug.IsSet() && entryFilter.CategorySlug.Value() != nil {
+
+			inputs.CategorySlug = *entryFilter.CategorySlug.Value()
+		}
+
+		if entryFilter.GetChildCategories.IsSet() && entryFilter.GetChildCategories.Value() != nil && !*entryFilter.GetChildCategories.Value() {
+
+			inputs.SelectedCategoryFilter = true
+		}
+
+		if entryFilter.Status.IsSet() && entryFilter.Status.Value() != nil {
+
+			switch *entryFilter.Status.Value() {
+
+			case "Draft":
+
+				inputs.Status = 0
+
+			case "Publish":
+
+				inputs.Status = 1
+
+			case "Unpublish":
+
+				inputs.Status = 2
+			}
+		}
+	}
+
+	inputs.Status, err = apiKeyStatus(c, inputs.Status)
+
+	if err != nil {
+
+		return &model.ChannelEntriesConnection{}, err
+	}
+
+	scope, err := GetApiKeyScope(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntriesConnection{}, err
+	}
+
+	if inputs.ChannelId != 0 {
+
+		if err := checkApiKeyChannel(c, inputs.ChannelId); err != nil {
+
+			return &model.ChannelEntriesConnection{}, err
+		}
+
+	} else {
+
+		inputs.ChannelIds = scope.ChannelIds
+	}
+
+	inputs.MemberGroupId, err = requestMemberGroupId(c)
+
+	if err != nil {
+
+		return &model.ChannelEntriesConnection{}, err
+	}
+
+	fieldChannelIds := inputs.ChannelIds
+
+	if inputs.ChannelId != 0 {
+
+		fieldChannelIds = []int{inputs.ChannelId}
+	}
+
+	inputs.FieldConditions, inputs.FieldOrder, err = entryFieldQuery(fieldFilter, fieldSort, fieldChannelIds, window.Cursor, tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == info.ErrFieldPredicate {
+
+			c.AbortWithStatus(400)
+
+			return &model.ChannelEntriesConnection{}, err
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntriesConnection{}, err
+	}
+
+	detailInputs := channels.EntriesInputs{TenantId: tenantDetails.TenantId}
+
+	var relations entryRelations
+
+	if additionalData != nil {
+
+		if additionalData.AuthorDetails.IsSet() && additionalData.AuthorDetails.Value() != nil {
+
+			relations.Author = *additionalData.AuthorDetails.Value()
+		}
+
+		if additionalData.MemberProfile.IsSet() && additionalData.MemberProfile.Value() != nil {
+
+			relations.MemberProfile = *additionalData.MemberProfile.Value()
+		}
+
+		if additionalData.Categories.IsSet() && additionalData.Categories.Value() != nil {
+
+			relations.Categories = *additionalData.Categories.Value()
+		}
+
+		if additionalData.AdditionalFields.IsSet() && additionalData.AdditionalFields.Value() != nil {
+
+			relations.AdditionalFields = *additionalData.AdditionalFields.Value()
+		}
+	}
+
+	cursors, count, err := model.Model.EntriesCursorPage(inputs)
+
+	if err != nil {
+
+		if err == info.ErrInvalidCursor {
+
+			c.AbortWithStatus(400)
+
+			return &model.ChannelEntriesConnection{}, err
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntriesConnection{}, err
+	}
+
+	size, hasNextPage, hasPreviousPage := window.Page(len(cursors))
+
+	cursors = cursors[:size]
+
+	if window.Backward {
+
+		pagination.Reverse(cursors)
+	}
+
+	edges := make([]model.ChannelEntriesEdge, 0, len(cursors))
+
+	if len(cursors) > 0 {
+
+		entryIds := make([]int, len(cursors))
+
+		for i, cursor := range cursors {
+
+			entryIds[i] = cursor.Id
+		}
+
+		_, channelEntries, err := ChannelConfigWP.FetchChannelEntryDetail(detailInputs, entryIds)
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			c.AbortWithStatus(500)
+
+			return &model.ChannelEntriesConnection{}, err
+		}
+
+		entriesById := make(map[int]channels.Tblchannelentries, len(channelEntries))
+
+		for _, entry := range channelEntries {
+
+			entriesById[entry.Id] = entry
+		}
+
+		nodes := make([]model.ChannelEntries, 0, len(cursors))
+
+		var nodeCursors []pagination.Cursor
+
+		// the details come back unordered, so follow the cursor order of the page
+		for _, cursor := range cursors {
+
+			entry, ok := entriesById[cursor.Id]
+
+			if !ok {
+
+				continue
+			}
+
+			nodes = append(nodes, convertChannelEntry(entry))
+
+			nodeCursors = append(nodeCursors, cursor)
+		}
+
+		if err := loadEntryRelations(ctx, nodes, relations, tenantDetails.TenantId); err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			c.AbortWithStatus(500)
+
+			return &model.ChannelEntriesConnection{}, err
+		}
+
+		for index := range nodes {
+
+			edges = append(edges, model.ChannelEntriesEdge{Node: &nodes[index], Cursor: nodeCursors[index].Encode()})
+		}
+	}
+
+	return &model.ChannelEntriesConnection{Edges: edges, PageInfo: connectionPageInfo(cursors, hasNextPage, hasPreviousPage), TotalCount: int(count)}, nil
+}
@@ -9,12 +9,12 @@ import (
 	"log"
 	"os"
 	"path"
+	"spurt-cms/graphql/imagetransform"
 	"spurt-cms/graphql/info"
 	logPkg "spurt-cms/graphql/logger"
 	"spurt-cms/graphql/model"
 	"spurt-cms/models"
 	"spurt-cms/logger"
-	"strconv"
 	"strings"
 	"unicode/utf8"
 
@@ -24,7 +24,6 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	"github.com/nfnt/resize"
 	newauth "github.com/spurtcms/auth"
 	"github.com/spurtcms/categories"
 	chn "github.com/spurtcms/channels"
@@ -212,6 +211,8 @@ func GetCategoryAuthInstance() *categories.Categories {
 	return CategoryAuthInstance
 }
 
+// ImageResize serves a stored image, resized and converted when the url carries a transform signed by TransformImage.
+// Unsigned transforms are rejected so clients cannot ask for arbitrary sizes.
 func ImageResize(c *gin.Context) {
 
 	fileName := c.Query("name")
@@ -220,9 +221,20 @@ func ImageResize(c *gin.Context) {
 
 	extension := path.Ext(fileName)
 
+	transform, transformed, err := imagetransform.Verify(imageSigningSecret(), c.Request.URL.Query())
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithError(403, info.ErrImageSignature)
+
+		return
+	}
+
 	var storageType model.TblStorageTypes
 
-	err := model.GetStorageType(models.DB, &storageType)
+	err = model.GetStorageType(models.DB, &storageType)
 
 	if err != nil {
 
@@ -235,7 +247,7 @@ func ImageResize(c *gin.Context) {
 
 	var byteData []byte
 
-	rawObject, err := GetObjectFromS3(storageType.Aws, strings.TrimSuffix(filePath, "/")+"/"+fileName)
+	rawObject, err := GetObjectFromS3(storageType.Aws, imagetransform.ObjectKey(filePath, fileName))
 
 	if err != nil {
 
@@ -254,7 +266,7 @@ func ImageResize(c *gin.Context) {
 
 	extType := strings.Trim(extension, ".")
 
-	if c.Query("width") == "" || c.Query("height") == "" {
+	if !transformed {
 
 		if extType == "svg" {
 
@@ -266,10 +278,6 @@ func ImageResize(c *gin.Context) {
 		return
 	}
 
-	width, _ := strconv.ParseUint(c.Query("width"), 10, 64)
-
-	height, _ := strconv.ParseUint(c.Query("height"), 10, 64)
-
 	Image, _, err := image.Decode(bytes.NewReader(byteData))
 
 	if err != nil {
@@ -281,37 +289,47 @@ func ImageResize(c *gin.Context) {
 		return
 	}
 
-	newImage := resize.Resize(uint(width), uint(height), Image, resize.Lanczos3)
+	newImage := imagetransform.Apply(Image, transform)
 
-	if extension == ".png" {
+	format := transform.Format
 
-		err = png.Encode(c.Writer, newImage)
+	if format == "" {
 
-		if err != nil {
+		// the original format is kept, images other than png are served as jpeg
+		format = imagetransform.FormatJpeg
 
-			logger.Error("Error occurred", logger.WithError(err))
+		if extension == ".png" {
 
-			c.AbortWithError(500, fmt.Errorf("%v-%v", info.ErrImageResize, err))
-
-			return
+			format = imagetransform.FormatPng
 		}
 	}
 
-	if extension == ".jpeg" || extension == ".jpg" {
+	c.Header("Content-Type", "image/"+format)
+
+	if format == imagetransform.FormatPng {
 
-		err = jpeg.Encode(c.Writer, newImage, nil)
+		err = png.Encode(c.Writer, newImage)
 
-		if err != nil {
+	} else {
 
-			logger.Error("Error occurred", logger.WithError(err))
+		quality := transform.Quality
 
-			c.AbortWithError(500, fmt.Errorf("%v-%v", info.ErrImageResize, err))
+		if quality == 0 {
 
-			return
+			quality = imagetransform.DefaultQuality
 		}
 
+		err = jpeg.Encode(c.Writer, newImage, &jpeg.Options{Quality: quality})
 	}
 
+	if err != nil {
+
+		logger.Error("Error occurred", logger.WithError(err))
+
+		c.AbortWithError(500, fmt.Errorf("%v-%v", info.ErrImageResize, err))
+
+		return
+	}
 }
 
 func GetObjectFromS3(AwsCredentials map[string]interface{}, key string) (*s3.GetObjectOutput, error) {
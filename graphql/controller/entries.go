@@ -0,0 +1,927 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"spurt-cms/events"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+	"spurt-cms/models"
+	"spurt-cms/workflow"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gin-gonic/gin"
+	"github.com/spurtcms/channels"
+	"gorm.io/gorm"
+)
+
+func CreateEntry(ctx context.Context, input model.CreateEntryInput) (*model.ChannelEntries, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.ChannelEntries{}, info.ErrGinCtx
+	}
+
+	if strings.TrimSpace(input.Title) == "" || input.ChannelID == 0 {
+
+		c.AbortWithStatus(400)
+
+		return &model.ChannelEntries{}, info.ErrReqMandatory
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntries{}, info.ErrFetchTenantDetails
+	}
+
+	if err := checkApiKeyChannel(c, input.ChannelID); err != nil {
+
+		return &model.ChannelEntries{}, err
+	}
+
+	if err := checkEntryChannel(input.ChannelID, tenantDetails.TenantId); err != nil {
+
+		if err == info.ErrChannelNotFound {
+
+			c.AbortWithStatus(400)
+
+			return &model.ChannelEntries{}, err
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntries{}, err
+	}
+
+	status, _, err := omittableEntryStatus(input.Status)
+
+	if err != nil {
+
+		c.AbortWithStatus(400)
+
+		return &model.ChannelEntries{}, err
+	}
+
+	status, err = entryWorkflowStatus(input.ChannelID, workflow.Draft, status, tenantDetails.TenantId)
+
+	if err != nil {
+
+		abortEntryWorkflow(c, err)
+
+		return &model.ChannelEntries{}, err
+	}
+
+	entry := channels.EntriesRequired{
+		Title:       input.Title,
+		ChannelId:   input.ChannelID,
+		Status:      status,
+		CategoryIds: joinCategoryIds(input.CategoryIds.Value()),
+		CreatedBy:   tenantDetails.Id,
+	}
+
+	entry.Content, _ = omittableString(input.Description)
+
+	entry.CoverImage, _ = omittableString(input.CoverImage)
+
+	entry.Author, _ = omittableString(input.Author)
+
+	entry.Tag, _ = omittableString(input.Tags)
+
+	entry.Excerpt, _ = omittableString(input.Excerpt)
+
+	entry.ReadingTime, _ = omittableInt(input.ReadingTime)
+
+	entry.SortOrder, _ = omittableInt(input.SortOrder)
+
+	if input.CreateTime.Value() != nil {
+
+		entry.CreateTime = input.CreateTime.Value().UTC()
+	}
+
+	if input.PublishedTime.Value() != nil {
+
+		entry.PublishTime = input.PublishedTime.Value().UTC()
+
+	} else if status == 1 {
+
+		entry.PublishTime, _ = time.Parse("2006-01-02 15:04:05", time.Now().UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	if seo := input.Seo.Value(); seo != nil {
+
+		entry.SEODetails.MetaTitle, _ = omittableString(seo.MetaTitle)
+
+		entry.SEODetails.MetaDescription, _ = omittableString(seo.MetaDescription)
+
+		entry.SEODetails.MetaKeywords, _ = omittableString(seo.MetaKeywords)
+
+		entry.SEODetails.ImageAltTag, _ = omittableString(seo.ImageAltTag)
+	}
+
+	if status == 1 {
+
+		entry.IsActive = 1
+	}
+
+	if err := checkEntryRelations(input.ChannelID, input.AdditionalFields.Value(), input.CategoryIds.Value(), tenantDetails.TenantId); err != nil {
+
+		return &model.ChannelEntries{}, abortEntryRelations(c, err)
+	}
+
+	var createdEntry channels.Tblchannelentries
+
+	// the order shift, the entry, its slug and its fields are written together or not at all
+	err = writeEntry(tenantDetails.Id, tenantDetails.TenantId, func(channelConfig *channels.Channel, models model.ModelConfig) (int, error) {
+
+		if err := models.ShiftEntriesOrderIndex(tenantDetails.TenantId); err != nil {
+
+			return 0, err
+		}
+
+		if createdEntry, _, err = channelConfig.CreateEntry(entry, tenantDetails.TenantId); err != nil {
+
+			return 0, err
+		}
+
+		if createdEntry.Id == 0 {
+
+			return 0, info.ErrCreateEntry
+		}
+
+		if slug, ok := omittableString(input.Slug); ok && strings.TrimSpace(slug) != "" {
+
+			if err := models.UpdateEntryDetails(createdEntry.Id, map[string]interface{}{"slug": strings.TrimSpace(slug)}, tenantDetails.TenantId); err != nil {
+
+				return 0, err
+			}
+		}
+
+		if fields := input.AdditionalFields.Value(); len(fields) > 0 {
+
+			return createdEntry.Id, channelConfig.CreateChannelEntryFields(createdEntry.Id, tenantDetails.Id, entryAdditionalFields(fields, 0, nil), tenantDetails.TenantId)
+		}
+
+		return createdEntry.Id, nil
+	})
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntries{}, info.ErrCreateEntry
+	}
+
+	if status == 1 {
+
+		events.PublishEntryEvent(events.EntryEvent{Type: events.EntryPublished, EntryId: createdEntry.Id, ChannelId: createdEntry.ChannelId, TenantId: tenantDetails.TenantId})
+	}
+
+	return mutatedEntryDetail(ctx, createdEntry.Id)
+}
+
+func UpdateEntry(ctx context.Context, id int, input model.UpdateEntryInput) (*model.ChannelEntries, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.ChannelEntries{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntries{}, info.ErrFetchTenantDetails
+	}
+
+	existing, err := fetchEntry(id, tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == info.ErrRecordNotFound {
+
+			c.AbortWithStatus(400)
+		}
+
+		return &model.ChannelEntries{}, err
+	}
+
+	if err := checkApiKeyChannel(c, existing.ChannelId); err != nil {
+
+		return &model.ChannelEntries{}, err
+	}
+
+	// UpdateEntry rewrites every column, so start from the stored entry and overlay the given input
+	entry := channels.EntriesRequired{
+		Title:      existing.Title,
+		Content:    existing.Description,
+		CoverImage: existing.CoverImage,
+		SEODetails: channels.SEODetails{
+			MetaTitle:       existing.MetaTitle,
+			MetaDescription: existing.MetaDescription,
+			MetaKeywords:    existing.Keyword,
+			ImageAltTag:     existing.ImageAltTag,
+		},
+		CategoryIds: existing.CategoriesId,
+		Status:      existing.Status,
+		ChannelId:   existing.ChannelId,
+		Author:      existing.Author,
+		CreateTime:  existing.CreateTime,
+		PublishTime: existing.PublishedTime,
+		ReadingTime: existing.ReadingTime,
+		SortOrder:   existing.SortOrder,
+		Tag:         existing.Tags,
+		Excerpt:     existing.Excerpt,
+		OrderIndex:  existing.OrderIndex,
+		ModifiedBy:  tenantDetails.Id,
+	}
+
+	if title, ok := omittableString(input.Title); ok {
+
+		if strings.TrimSpace(title) == "" {
+
+			c.AbortWithStatus(400)
+
+			return &model.ChannelEntries{}, info.ErrReqMandatory
+		}
+
+		entry.Title = title
+	}
+
+	if channelId, ok := omittableInt(input.ChannelID); ok {
+
+		if err := checkApiKeyChannel(c, channelId); err != nil {
+
+			return &model.ChannelEntries{}, err
+		}
+
+		if err := checkEntryChannel(channelId, tenantDetails.TenantId); err != nil {
+
+			if err == info.ErrChannelNotFound {
+
+				c.AbortWithStatus(400)
+			}
+
+			return &model.ChannelEntries{}, err
+		}
+
+		entry.ChannelId = channelId
+	}
+
+	status, statusSet, err := omittableEntryStatus(input.Status)
+
+	if err != nil {
+
+		c.AbortWithStatus(400)
+
+		return &model.ChannelEntries{}, err
+	}
+
+	if statusSet {
+
+		entry.Status = status
+	}
+
+	entry.Status, err = entryWorkflowStatus(entry.ChannelId, existing.Status, entry.Status, tenantDetails.TenantId)
+
+	if err != nil {
+
+		abortEntryWorkflow(c, err)
+
+		return &model.ChannelEntries{}, err
+	}
+
+	if val, ok := omittableString(input.Description); ok {
+
+		entry.Content = val
+	}
+
+	if val, ok := omittableString(input.CoverImage); ok {
+
+		entry.CoverImage = val
+	}
+
+	if val, ok := omittableString(input.Author); ok {
+
+		entry.Author = val
+	}
+
+	if val, ok := omittableString(input.Tags); ok {
+
+		entry.Tag = val
+	}
+
+	if val, ok := omittableString(input.Excerpt); ok {
+
+		entry.Excerpt = val
+	}
+
+	if val, ok := omittableInt(input.ReadingTime); ok {
+
+		entry.ReadingTime = val
+	}
+
+	if val, ok := omittableInt(input.SortOrder); ok {
+
+		entry.SortOrder = val
+	}
+
+	if input.CreateTime.IsSet() {
+
+		entry.CreateTime = time.Time{}
+
+		if input.CreateTime.Value() != nil {
+
+			entry.CreateTime = input.CreateTime.Value().UTC()
+		}
+	}
+
+	if input.PublishedTime.IsSet() {
+
+		entry.PublishTime = time.Time{}
+
+		if input.PublishedTime.Value() != nil {
+
+			entry.PublishTime = input.PublishedTime.Value().UTC()
+		}
+	}
+
+	if entry.Status == 1 && entry.PublishTime.IsZero() {
+
+		entry.PublishTime, _ = time.Parse("2006-01-02 15:04:05", time.Now().UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	if input.CategoryIds.IsSet() {
+
+		entry.CategoryIds = joinCategoryIds(input.CategoryIds.Value())
+	}
+
+	if seo := input.Seo.Value(); seo != nil {
+
+		if val, ok := omittableString(seo.MetaTitle); ok {
+
+			entry.SEODetails.MetaTitle = val
+		}
+
+		if val, ok := omittableString(seo.MetaDescription); ok {
+
+			entry.SEODetails.MetaDescription = val
+		}
+
+		if val, ok := omittableString(seo.MetaKeywords); ok {
+
+			entry.SEODetails.MetaKeywords = val
+		}
+
+		if val, ok := omittableString(seo.ImageAltTag); ok {
+
+			entry.SEODetails.ImageAltTag = val
+		}
+	}
+
+	var categoryIds []int
+
+	if input.CategoryIds.IsSet() {
+
+		categoryIds = input.CategoryIds.Value()
+	}
+
+	if err := checkEntryRelations(entry.ChannelId, input.AdditionalFields.Value(), categoryIds, tenantDetails.TenantId); err != nil {
+
+		return &model.ChannelEntries{}, abortEntryRelations(c, err)
+	}
+
+	// UpdateEntry derives the slug from the title and clears columns it does not take as input, so put them back
+	columns := map[string]interface{}{
+		"slug":             existing.Slug,
+		"thumbnail_image":  existing.ThumbnailImage,
+		"related_articles": existing.RelatedArticles,
+		"user_id":          existing.UserId,
+		// an editor holding the entry open in the admin is told it changed when they save
+		"version": gorm.Expr("coalesce(version, 1) + 1"),
+	}
+
+	if slug, ok := omittableString(input.Slug); ok && strings.TrimSpace(slug) != "" {
+
+		columns["slug"] = strings.TrimSpace(slug)
+	}
+
+	if entry.CreateTime.IsZero() {
+
+		columns["create_time"] = nil
+	}
+
+	if entry.PublishTime.IsZero() {
+
+		columns["published_time"] = nil
+	}
+
+	// the entry, its restored columns and its fields are written together or not at all
+	err = writeEntry(tenantDetails.Id, tenantDetails.TenantId, func(channelConfig *channels.Channel, models model.ModelConfig) (int, error) {
+
+		if _, err := channelConfig.UpdateEntry(entry, "", id, tenantDetails.TenantId); err != nil {
+
+			return 0, err
+		}
+
+		if err := models.UpdateEntryDetails(id, columns, tenantDetails.TenantId); err != nil {
+
+			return 0, err
+		}
+
+		fields := input.AdditionalFields.Value()
+
+		if len(fields) == 0 {
+
+			return id, nil
+		}
+
+		fieldRows, err := models.GetEntryFieldRows(id, tenantDetails.TenantId)
+
+		if err != nil {
+
+			return 0, err
+		}
+
+		var newFields, existingFields []channels.AdditionalFields
+
+		for _, field := range entryAdditionalFields(fields, tenantDetails.Id, fieldRows) {
+
+			if field.Id == 0 {
+
+				newFields = append(newFields, field)
+
+				continue
+			}
+
+			existingFields = append(existingFields, field)
+		}
+
+		if len(existingFields) > 0 {
+
+			if _, err := channelConfig.UpdateAdditionalField(existingFields, id, tenantDetails.TenantId); err != nil {
+
+				return 0, err
+			}
+		}
+
+		// UpdateAdditionalField inserts new rows without a tenant, so create them separately
+		if len(newFields) > 0 {
+
+			return id, channelConfig.CreateChannelEntryFields(id, tenantDetails.Id, newFields, tenantDetails.TenantId)
+		}
+
+		return id, nil
+	})
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntries{}, info.ErrUpdateEntry
+	}
+
+	eventType := events.EntryUpdated
+
+	if entry.Status == 1 && existing.Status != 1 {
+
+		eventType = events.EntryPublished
+	}
+
+	events.PublishEntryEvent(events.EntryEvent{Type: eventType, EntryId: id, ChannelId: entry.ChannelId, TenantId: tenantDetails.TenantId})
+
+	return mutatedEntryDetail(ctx, id)
+}
+
+// writeEntry runs the writes of an entry mutation in one transaction, the channels package and the models both write
+// through it so a failed step leaves nothing behind. The entry written is added to its revision history like a save in
+// the admin.
+func writeEntry(userId, tenantId int, write func(channelConfig *channels.Channel, models model.ModelConfig) (int, error)) error {
+
+	return model.Model.DB.Transaction(func(tx *gorm.DB) error {
+
+		channelConfig := *ChannelConfigWP
+
+		channelConfig.DB = tx
+
+		entryId, err := write(&channelConfig, model.ModelConfig{DB: tx})
+
+		if err != nil {
+
+			return err
+		}
+
+		return models.SaveEntryRevision(tx, entryId, userId, tenantId)
+	})
+}
+
+// checkEntryRelations rejects additional fields that are not fields of the entry's channel and categories of another
+// tenant, the channel itself is checked by checkEntryChannel
+func checkEntryRelations(channelId int, fields []model.EntryFieldInput, categoryIds []int, tenantId int) error {
+
+	if len(fields) > 0 {
+
+		fieldIds, err := model.Model.ChannelFieldIds(channelId, tenantId)
+
+		if err != nil {
+
+			return err
+		}
+
+		for _, field := range fields {
+
+			if !containsId(fieldIds, field.FieldID) {
+
+				return info.ErrEntryField
+			}
+		}
+	}
+
+	if len(categoryIds) > 0 {
+
+		tenantCategoryIds, err := model.Model.TenantCategoryIds(categoryIds, tenantId)
+
+		if err != nil {
+
+			return err
+		}
+
+		for _, categoryId := range categoryIds {
+
+			if !containsId(tenantCategoryIds, categoryId) {
+
+				return info.ErrEntryCategory
+			}
+		}
+	}
+
+	return nil
+}
+
+func abortEntryRelations(c *gin.Context, err error) error {
+
+	if err == info.ErrEntryField || err == info.ErrEntryCategory {
+
+		c.AbortWithStatus(400)
+
+		return err
+	}
+
+	ErrorLog.Printf("%v", err)
+
+	c.AbortWithStatus(500)
+
+	return err
+}
+
+func PublishEntry(ctx context.Context, id int) (*model.ChannelEntries, error) {
+
+	return changeEntryStatus(ctx, id, 1)
+}
+
+func UnpublishEntry(ctx context.Context, id int) (*model.ChannelEntries, error) {
+
+	return changeEntryStatus(ctx, id, 2)
+}
+
+func DeleteEntry(ctx context.Context, id int) (bool, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return false, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return false, info.ErrFetchTenantDetails
+	}
+
+	entry, err := fetchEntry(id, tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == info.ErrRecordNotFound {
+
+			c.AbortWithStatus(400)
+		}
+
+		return false, err
+	}
+
+	if err := checkApiKeyChannel(c, entry.ChannelId); err != nil {
+
+		return false, err
+	}
+
+	if _, err := ChannelConfigWP.DeleteEntry("", tenantDetails.Id, id, tenantDetails.TenantId); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	events.PublishEntryEvent(events.EntryEvent{Type: events.EntryDeleted, EntryId: id, ChannelId: entry.ChannelId, TenantId: tenantDetails.TenantId})
+
+	return true, nil
+}
+
+func changeEntryStatus(ctx context.Context, id int, status int) (*model.ChannelEntries, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.ChannelEntries{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntries{}, info.ErrFetchTenantDetails
+	}
+
+	entry, err := fetchEntry(id, tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == info.ErrRecordNotFound {
+
+			c.AbortWithStatus(400)
+		}
+
+		return &model.ChannelEntries{}, err
+	}
+
+	if err := checkApiKeyChannel(c, entry.ChannelId); err != nil {
+
+		return &model.ChannelEntries{}, err
+	}
+
+	channelWorkflow, err := model.Model.GetChannelWorkflow(entry.ChannelId, tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntries{}, err
+	}
+
+	action := workflow.ActionPublish
+
+	if status == 2 {
+
+		action = workflow.ActionUnpublish
+	}
+
+	if channelWorkflow != workflow.None {
+
+		if _, err := workflow.Find(channelWorkflow, entry.Status, action); err != nil {
+
+			c.AbortWithStatus(400)
+
+			return &model.ChannelEntries{}, err
+		}
+	}
+
+	if _, err := ChannelConfigWP.EntryStatus("", id, status, tenantDetails.Id, tenantDetails.TenantId); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.ChannelEntries{}, err
+	}
+
+	if status == 1 {
+
+		publishedTime, _ := time.Parse("2006-01-02 15:04:05", time.Now().UTC().Format("2006-01-02 15:04:05"))
+
+		if err := model.Model.UpdateEntryPublishedTime(id, publishedTime, tenantDetails.TenantId); err != nil {
+
+			ErrorLog.Printf("%v", err)
+		}
+	}
+
+	eventType := events.EntryUpdated
+
+	if status == 1 {
+
+		eventType = events.EntryPublished
+	}
+
+	events.PublishEntryEvent(events.EntryEvent{Type: eventType, EntryId: id, ChannelId: entry.ChannelId, TenantId: tenantDetails.TenantId})
+
+	return mutatedEntryDetail(ctx, id)
+}
+
+// return the stored entry with its categories and additional fields after a mutation
+func mutatedEntryDetail(ctx context.Context, id int) (*model.ChannelEntries, error) {
+
+	getData := true
+
+	additionalData := model.EntriesAdditionalData{
+		Categories:       graphql.OmittableOf(&getData),
+		AdditionalFields: graphql.OmittableOf(&getData),
+	}
+
+	return ChannelEntryDetail(ctx, &id, nil, &additionalData, nil, nil)
+}
+
+func fetchEntry(id int, tenantId int) (channels.Tblchannelentries, error) {
+
+	entry, _, err := ChannelConfigWP.FetchChannelEntryDetail(channels.EntriesInputs{Id: id, TenantId: tenantId}, nil)
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			return channels.Tblchannelentries{}, info.ErrRecordNotFound
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		return channels.Tblchannelentries{}, err
+	}
+
+	if entry.Id == 0 {
+
+		return channels.Tblchannelentries{}, info.ErrRecordNotFound
+	}
+
+	return entry, nil
+}
+
+// entryWorkflowStatus returns the status a save leaves an entry in. On a channel with a review workflow the API only
+// publishes entries approved in the admin, the review itself is not done through API keys.
+func entryWorkflowStatus(channelId int, current int, requested int, tenantId int) (int, error) {
+
+	channelWorkflow, err := model.Model.GetChannelWorkflow(channelId, tenantId)
+
+	if err != nil {
+
+		return current, err
+	}
+
+	status, _, err := workflow.Save(channelWorkflow, current, requested)
+
+	return status, err
+}
+
+func abortEntryWorkflow(c *gin.Context, err error) {
+
+	if err == workflow.ErrReviewRequired {
+
+		c.AbortWithStatus(400)
+
+		return
+	}
+
+	ErrorLog.Printf("%v", err)
+
+	c.AbortWithStatus(500)
+}
+
+func checkEntryChannel(channelId int, tenantId int) error {
+
+	channel, err := ChannelConfigWP.ChannelDetail(channels.Channels{Id: channelId, TenantId: tenantId})
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			return info.ErrChannelNotFound
+		}
+
+		return err
+	}
+
+	if channel.Id == 0 {
+
+		return info.ErrChannelNotFound
+	}
+
+	return nil
+}
+
+func omittableString(value graphql.Omittable[*string]) (string, bool) {
+
+	if !value.IsSet() {
+
+		return "", false
+	}
+
+	if value.Value() == nil {
+
+		return "", true
+	}
+
+	return *value.Value(), true
+}
+
+func omittableInt(value graphql.Omittable[*int]) (int, bool) {
+
+	if !value.IsSet() {
+
+		return 0, false
+	}
+
+	if value.Value() == nil {
+
+		return 0, true
+	}
+
+	return *value.Value(), true
+}
+
+// entry status defaults to draft, 1 is published and 2 is unpublished
+func omittableEntryStatus(value graphql.Omittable[*int]) (int, bool, error) {
+
+	status, ok := omittableInt(value)
+
+	if status < 0 || status > 2 {
+
+		return 0, false, info.ErrInvalidEntryStatus
+	}
+
+	return status, ok, nil
+}
+
+func joinCategoryIds(categoryIds []int) string {
+
+	ids := make([]string, len(categoryIds))
+
+	for index, categoryId := range categoryIds {
+
+		ids[index] = strconv.Itoa(categoryId)
+	}
+
+	return strings.Join(ids, ",")
+}
+
+// fieldRows maps field id to an existing entry field row, matched rows are updated instead of inserted
+func entryAdditionalFields(fields []model.EntryFieldInput, modifiedBy int, fieldRows map[int]int) []channels.AdditionalFields {
+
+	additionalFields := make([]channels.AdditionalFields, len(fields))
+
+	for index, field := range fields {
+
+		fieldName, _ := omittableString(field.FieldName)
+
+		additionalFields[index] = channels.AdditionalFields{
+			Id:         fieldRows[field.FieldID],
+			FieldId:    field.FieldID,
+			FieldName:  fieldName,
+			FieldValue: field.FieldValue,
+			ModifiedBy: modifiedBy,
+		}
+	}
+
+	return additionalFields
+}
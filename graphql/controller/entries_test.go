@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+
+	"github.com/spurtcms/channels"
+)
+
+func TestCheckEntryRelationsRejectsUnknownIds(t *testing.T) {
+
+	// the counting database knows no fields or categories
+	withCountingDB(t)
+
+	if err := checkEntryRelations(1, nil, nil, 1); err != nil {
+		t.Fatalf("expected an entry without fields and categories to pass, got %v", err)
+	}
+
+	if err := checkEntryRelations(1, []model.EntryFieldInput{{FieldID: 7, FieldValue: "x"}}, nil, 1); err != info.ErrEntryField {
+		t.Fatalf("expected a field of another channel to be rejected, got %v", err)
+	}
+
+	if err := checkEntryRelations(1, nil, []int{3}, 1); err != info.ErrEntryCategory {
+		t.Fatalf("expected a category of another tenant to be rejected, got %v", err)
+	}
+}
+
+func TestWriteEntrySavesARevision(t *testing.T) {
+
+	counting := withCountingDB(t)
+
+	err := writeEntry(2, 1, func(channelConfig *channels.Channel, models model.ModelConfig) (int, error) {
+
+		return 5, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, statement := range counting.statements {
+
+		if strings.HasPrefix(statement, `INSERT INTO "tbl_channel_entry_revisions"`) {
+			return
+		}
+	}
+
+	t.Fatalf("expected the entry to be added to its revisions, got %v", counting.statements)
+}
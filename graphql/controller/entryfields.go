@@ -0,0 +1,364 @@
+package controller
+
+import (
+	"sort"
+	"spurt-cms/graphql/fieldfilter"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/pagination"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// entryFieldQuery turns the field predicates and the field sort of a list into the conditions and the order the entry
+// queries apply. Each predicate is matched against the values stored for its fields once, so the entries themselves are
+// filtered, sorted and paged by the database. The cursor, when given, is placed in the field order.
+func entryFieldQuery(predicates []model.FieldPredicate, fieldSort *model.FieldSort, channelIds []int, cursor *pagination.Cursor, tenantId int) ([]model.FieldCondition, *model.FieldOrder, error) {
+
+	if len(predicates) == 0 && fieldSort == nil {
+
+		return nil, nil, nil
+	}
+
+	if len(channelIds) == 0 {
+
+		var err error
+
+		if channelIds, err = model.Model.TenantChannelIds(tenantId); err != nil {
+
+			return nil, nil, err
+		}
+	}
+
+	channelFields, err := model.Model.ChannelFieldsByChannelIds(channelIds, tenantId)
+
+	if err != nil {
+
+		return nil, nil, err
+	}
+
+	resolved := make([]fieldPredicate, len(predicates))
+
+	var fieldIds []int
+
+	for index, predicate := range predicates {
+
+		fieldId, _ := omittableInt(predicate.FieldID)
+
+		fieldName, _ := omittableString(predicate.FieldName)
+
+		fields, err := resolveEntryFields(channelFields, fieldId, fieldName)
+
+		if err != nil {
+
+			return nil, nil, err
+		}
+
+		value, _ := omittableString(predicate.Value)
+
+		var values []string
+
+		if predicate.Values.IsSet() {
+
+			values = predicate.Values.Value()
+		}
+
+		resolved[index] = fieldPredicate{fields: fields, predicate: fieldfilter.Predicate{Op: fieldfilter.Operator(predicate.Op), Value: value, Values: values}}
+
+		fieldIds = appendFieldIds(fieldIds, fields)
+	}
+
+	var sortFields map[int]fieldfilter.Kind
+
+	if fieldSort != nil {
+
+		fieldId, _ := omittableInt(fieldSort.FieldID)
+
+		fieldName, _ := omittableString(fieldSort.FieldName)
+
+		if sortFields, err = resolveEntryFields(channelFields, fieldId, fieldName); err != nil {
+
+			return nil, nil, err
+		}
+
+		fieldIds = appendFieldIds(fieldIds, sortFields)
+	}
+
+	storedValues := make(map[int][]string)
+
+	if len(fieldIds) > 0 {
+
+		values, err := model.Model.DistinctFieldValues(fieldIds, tenantId)
+
+		if err != nil {
+
+			return nil, nil, err
+		}
+
+		for _, value := range values {
+
+			storedValues[value.FieldId] = append(storedValues[value.FieldId], value.FieldValue)
+		}
+	}
+
+	// "today" and "now" are the day and time of the tenant
+	now := time.Now().In(tenantLocation(tenantId))
+
+	conditions := make([]model.FieldCondition, len(resolved))
+
+	for index, field := range resolved {
+
+		if conditions[index], err = field.condition(storedValues, now); err != nil {
+
+			return nil, nil, info.ErrFieldPredicate
+		}
+	}
+
+	if fieldSort == nil {
+
+		return conditions, nil, nil
+	}
+
+	descending := false
+
+	if order, ok := omittableInt(fieldSort.Order); ok && order == 1 {
+
+		descending = true
+	}
+
+	return conditions, fieldOrder(sortFields, storedValues, descending, cursor), nil
+}
+
+type fieldPredicate struct {
+	fields    map[int]fieldfilter.Kind
+	predicate fieldfilter.Predicate
+}
+
+// condition splits the stored values of the predicate fields into the ones it matches and the ones it does not, the
+// shorter list goes into the query
+func (field fieldPredicate) condition(storedValues map[int][]string, now time.Time) (model.FieldCondition, error) {
+
+	missing, err := field.predicate.Match(fieldfilter.KindText, "", false, now)
+
+	if err != nil {
+
+		return model.FieldCondition{}, err
+	}
+
+	matching, other := make(map[int][]string, len(field.fields)), make(map[int][]string, len(field.fields))
+
+	var matchingCount, otherCount int
+
+	for fieldId, kind := range field.fields {
+
+		matching[fieldId], other[fieldId] = []string{}, []string{}
+
+		for _, value := range storedValues[fieldId] {
+
+			matched, err := field.predicate.Match(kind, value, true, now)
+
+			if err != nil {
+
+				return model.FieldCondition{}, err
+			}
+
+			if matched {
+
+				matching[fieldId] = append(matching[fieldId], value)
+
+				matchingCount++
+
+			} else {
+
+				other[fieldId] = append(other[fieldId], value)
+
+				otherCount++
+			}
+		}
+	}
+
+	if otherCount < matchingCount {
+
+		return model.FieldCondition{Values: other, Inverse: true, Missing: missing}, nil
+	}
+
+	return model.FieldCondition{Values: matching, Missing: missing}, nil
+}
+
+type sortValue struct {
+	value string
+	kind  fieldfilter.Kind
+}
+
+// fieldOrder places the stored values of the sort fields, values that compare equal share a place. The places are even
+// so a cursor value that is no longer stored falls between its neighbours.
+func fieldOrder(sortFields map[int]fieldfilter.Kind, storedValues map[int][]string, descending bool, cursor *pagination.Cursor) *model.FieldOrder {
+
+	order := &model.FieldOrder{}
+
+	var values []sortValue
+
+	taken := make(map[string]bool)
+
+	for _, fieldId := range appendFieldIds(nil, sortFields) {
+
+		order.FieldIds = append(order.FieldIds, fieldId)
+
+		for _, value := range storedValues[fieldId] {
+
+			if !taken[value] {
+
+				taken[value] = true
+
+				values = append(values, sortValue{value: value, kind: sortFields[fieldId]})
+			}
+		}
+	}
+
+	compare := func(a, b sortValue) int {
+
+		if descending {
+
+			return fieldfilter.Compare(a.kind, b.value, a.value)
+		}
+
+		return fieldfilter.Compare(a.kind, a.value, b.value)
+	}
+
+	sort.SliceStable(values, func(i, j int) bool { return compare(values[i], values[j]) < 0 })
+
+	position := 0
+
+	for index, value := range values {
+
+		if index == 0 || compare(values[index-1], value) != 0 {
+
+			position += 2
+		}
+
+		order.Positions = append(order.Positions, model.FieldPosition{Value: value.value, Position: position})
+	}
+
+	// entries without a value stay last in both orders
+	order.Missing = position + 2
+
+	if cursor == nil {
+
+		return order
+	}
+
+	order.CursorPosition = order.Missing
+
+	if strings.TrimSpace(cursor.Value) == "" {
+
+		return order
+	}
+
+	kind := fieldfilter.KindText
+
+	if len(values) > 0 {
+
+		kind = values[0].kind
+	}
+
+	for index, value := range values {
+
+		if comparison := compare(value, sortValue{value: cursor.Value, kind: kind}); comparison >= 0 {
+
+			order.CursorPosition = order.Positions[index].Position
+
+			if comparison > 0 {
+
+				order.CursorPosition--
+			}
+
+			return order
+		}
+	}
+
+	order.CursorPosition = order.Missing - 1
+
+	return order
+}
+
+// fieldSortKey names the field order of a connection in its cursors
+func fieldSortKey(fieldSort *model.FieldSort) string {
+
+	field := ""
+
+	if fieldId, ok := omittableInt(fieldSort.FieldID); ok && fieldId != 0 {
+
+		field = strconv.Itoa(fieldId)
+
+	} else if fieldName, ok := omittableString(fieldSort.FieldName); ok {
+
+		field = fieldfilter.NormalizeName(fieldName)
+	}
+
+	order, _ := omittableInt(fieldSort.Order)
+
+	return pagination.FieldSortKey(field, order == 1)
+}
+
+// appendFieldIds adds the fields to the list in id order, each field once
+func appendFieldIds(fieldIds []int, fields map[int]fieldfilter.Kind) []int {
+
+	for fieldId := range fields {
+
+		if !containsId(fieldIds, fieldId) {
+
+			fieldIds = append(fieldIds, fieldId)
+		}
+	}
+
+	sort.Ints(fieldIds)
+
+	return fieldIds
+}
+
+// tenantLocation returns the time zone of the tenant, UTC when it has none or an unknown one
+func tenantLocation(tenantId int) *time.Location {
+
+	timeZone, err := model.Model.TimeZone(tenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		return time.UTC
+	}
+
+	location, err := time.LoadLocation(timeZone)
+
+	if err != nil {
+
+		return time.UTC
+	}
+
+	return location
+}
+
+// resolveEntryFields returns the fields a predicate names with their kinds. A name can match a field in each channel.
+func resolveEntryFields(channelFields map[int][]model.ChannelField, fieldId int, fieldName string) (map[int]fieldfilter.Kind, error) {
+
+	if fieldId == 0 && fieldName == "" {
+
+		return nil, info.ErrFieldPredicate
+	}
+
+	fields := make(map[int]fieldfilter.Kind)
+
+	for _, channelFieldList := range channelFields {
+
+		for _, field := range channelFieldList {
+
+			if (fieldId != 0 && field.Id == fieldId) || (fieldId == 0 && fieldfilter.NormalizeName(field.FieldName) == fieldfilter.NormalizeName(fieldName)) {
+
+				fields[field.Id] = fieldfilter.KindOf(field.FieldTypeId)
+			}
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"spurt-cms/graphql/fieldfilter"
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/pagination"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFieldPredicateCondition(t *testing.T) {
+
+	now := time.Date(2024, 5, 10, 15, 0, 0, 0, time.UTC)
+
+	field := fieldPredicate{fields: map[int]fieldfilter.Kind{7: fieldfilter.KindDate}, predicate: fieldfilter.Predicate{Op: fieldfilter.OpGt, Value: "today"}}
+
+	condition, err := field.condition(map[int][]string{7: {"2024-05-11", "2024-05-01", "2024-04-01"}}, now)
+
+	if err != nil {
+		t.Fatalf("condition: %v", err)
+	}
+
+	if condition.Missing || condition.Inverse || len(condition.Values[7]) != 1 || condition.Values[7][0] != "2024-05-11" {
+		t.Fatalf("expected the one matching value to be listed, got %+v", condition)
+	}
+
+	field.predicate = fieldfilter.Predicate{Op: fieldfilter.OpNe, Value: "2024-05-11"}
+
+	if condition, _ = field.condition(map[int][]string{7: {"2024-05-11", "2024-05-01", "2024-04-01"}}, now); !condition.Missing || !condition.Inverse || len(condition.Values[7]) != 1 {
+		t.Fatalf("expected the shorter list of values that do not match, got %+v", condition)
+	}
+}
+
+func TestFieldOrder(t *testing.T) {
+
+	sortFields := map[int]fieldfilter.Kind{3: fieldfilter.KindText}
+
+	stored := map[int][]string{3: {"10", "9", "10.0", "b"}}
+
+	order := fieldOrder(sortFields, stored, false, nil)
+
+	positions := make(map[string]int)
+
+	for _, position := range order.Positions {
+
+		positions[position.Value] = position.Position
+	}
+
+	if !(positions["9"] < positions["10"] && positions["10"] == positions["10.0"] && positions["10"] < positions["b"] && positions["b"] < order.Missing) {
+		t.Fatalf("unexpected positions %v, missing at %d", positions, order.Missing)
+	}
+
+	descending := fieldOrder(sortFields, stored, true, &pagination.Cursor{Value: "9.5"})
+
+	if descending.Positions[0].Value != "b" {
+		t.Fatalf("expected the largest value first, got %+v", descending.Positions)
+	}
+
+	// 9.5 is gone from the stored values, it falls between 10 and 9
+	for _, position := range descending.Positions {
+
+		if position.Value == "9" && descending.CursorPosition != position.Position-1 {
+			t.Fatalf("expected the cursor just before 9, got %d in %+v", descending.CursorPosition, descending.Positions)
+		}
+	}
+}
+
+func TestEntriesPageFiltersInSQL(t *testing.T) {
+
+	counting := withCountingDB(t)
+
+	inputs := model.EntriesPageReq{
+		EntriesCursorReq: model.EntriesCursorReq{
+			Status:          -1,
+			MemberGroupId:   3,
+			FieldConditions: []model.FieldCondition{{Values: map[int][]string{7: {"2024-05-11"}}}},
+			FieldOrder:      &model.FieldOrder{FieldIds: []int{7}, Positions: []model.FieldPosition{{Value: "2024-05-11", Position: 2}}, Missing: 4},
+			TenantId:        1,
+		},
+		Limit:  10,
+		Offset: 20,
+	}
+
+	if _, _, err := model.Model.EntriesPage(inputs); err != nil {
+		t.Fatalf("entries page: %v", err)
+	}
+
+	page := counting.statements[len(counting.statements)-1]
+
+	for _, part := range []string{"en.id not in (SELECT he.id FROM tbl_channel_entries as he", "exists (SELECT 1 FROM tbl_channel_entry_fields as cef", "ORDER BY case (select cef.field_value", "LIMIT $", "OFFSET $"} {
+
+		if !strings.Contains(page, part) {
+			t.Fatalf("expected %q in the page query %s", part, page)
+		}
+	}
+}
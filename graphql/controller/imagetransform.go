@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"spurt-cms/graphql/imagetransform"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// imageSigningSecret signs the image transform urls, the jwt secret is used when no separate one is configured
+func imageSigningSecret() string {
+
+	if secret := os.Getenv("IMAGE_SIGNING_SECRET"); secret != "" {
+
+		return secret
+	}
+
+	return os.Getenv("JWT_SECRET")
+}
+
+// TransformImage returns the signed image-resize url of an image field resolved with transform arguments. Without
+// arguments the stored path is returned as it always was. The size and fit have to be one of the tenant's presets.
+func TransformImage(ctx context.Context, imagePath string, width, height *int, fit *model.ImageFit, format *model.ImageFormat, quality *int) (string, error) {
+
+	if width == nil && height == nil && fit == nil && format == nil && quality == nil {
+
+		return imagePath, nil
+	}
+
+	// external images are not served by the image-resize route
+	if imagePath == "" || strings.Contains(imagePath, "://") {
+
+		return imagePath, nil
+	}
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return "", info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		return "", info.ErrFetchTenantDetails
+	}
+
+	var transform imagetransform.Transform
+
+	if width != nil {
+
+		transform.Width = *width
+	}
+
+	if height != nil {
+
+		transform.Height = *height
+	}
+
+	if fit != nil {
+
+		transform.Fit = strings.ToLower(fit.String())
+	}
+
+	if format != nil {
+
+		transform.Format = strings.ToLower(format.String())
+	}
+
+	if quality != nil {
+
+		transform.Quality = *quality
+	}
+
+	if transform, err = imagetransform.Normalize(transform); err != nil {
+
+		return "", info.ErrImageTransform
+	}
+
+	presets, _, err := loadersFor(ctx, tenantDetails.TenantId).imagePresets.Load(tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		return "", err
+	}
+
+	if !imagetransform.Allowed(presets, transform) {
+
+		return "", info.ErrImagePreset
+	}
+
+	secret := imageSigningSecret()
+
+	if secret == "" {
+
+		ErrorLog.Printf("%v", info.ErrImageSigningSecret)
+
+		return "", info.ErrImageSigningSecret
+	}
+
+	return imagetransform.URL(secret, imagePath, transform), nil
+}
+
+// TransformOptionalImage is TransformImage for the image fields that may be unset
+func TransformOptionalImage(ctx context.Context, imagePath *string, width, height *int, fit *model.ImageFit, format *model.ImageFormat, quality *int) (*string, error) {
+
+	if imagePath == nil {
+
+		return nil, nil
+	}
+
+	url, err := TransformImage(ctx, *imagePath, width, height, fit, format, quality)
+
+	if err != nil {
+
+		return nil, err
+	}
+
+	return &url, nil
+}
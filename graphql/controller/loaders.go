@@ -0,0 +1,753 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"spurt-cms/graphql/categorytree"
+	"spurt-cms/graphql/dataloader"
+	"spurt-cms/graphql/imagetransform"
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/related"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/spurtcms/categories"
+	"github.com/spurtcms/channels"
+	"github.com/spurtcms/member"
+	"github.com/spurtcms/team"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	sectionFieldTypeId = 12
+	memberFieldTypeId  = 14
+)
+
+// relations requested through the additionalData argument of the entry lists
+type entryRelations struct {
+	Author           bool
+	MemberProfile    bool
+	Categories       bool
+	AdditionalFields bool
+}
+
+// entryLoaders read the relations of a list of entries with one query per relation instead of one per entry
+type entryLoaders struct {
+	authors        *dataloader.Loader[int, team.TblUser]
+	categories     *dataloader.Loader[int, categories.TblCategories]
+	memberProfiles *dataloader.Loader[int, member.TblMemberProfile]
+	channelFields  *dataloader.Loader[int, []model.ChannelField]
+	entryFields    *dataloader.Loader[int, []model.EntryFieldValue]
+
+	// the published entries picked as related articles
+	relatedEntries *dataloader.Loader[int, channels.Tblchannelentries]
+
+	// the related articles suggested for the entries without picks
+	relatedSuggestions *dataloader.Loader[relatedSuggestionKey, []channels.Tblchannelentries]
+
+	// the picked entries hidden from a member group
+	hiddenEntries *dataloader.Loader[hiddenEntryKey, bool]
+
+	// the image transform presets of a tenant
+	imagePresets *dataloader.Loader[int, []imagetransform.Preset]
+}
+
+type entryLoadersKey struct {
+	tenantId int
+}
+
+// hiddenEntryKey is an entry and the member group that may not read it
+type hiddenEntryKey struct {
+	EntryId       int
+	MemberGroupId int
+}
+
+func newEntryLoaders(tenantId int) *entryLoaders {
+
+	return &entryLoaders{
+		authors: dataloader.NewLoader(func(userIds []int) (map[int]team.TblUser, error) {
+
+			return model.Model.AuthorsByIds(userIds)
+		}),
+		categories: dataloader.NewLoader(func(categoryIds []int) (map[int]categories.TblCategories, error) {
+
+			return model.Model.CategoriesWithParents(categoryIds)
+		}),
+		memberProfiles: dataloader.NewLoader(func(memberIds []int) (map[int]member.TblMemberProfile, error) {
+
+			return model.Model.MemberProfilesByMemberIds(memberIds, tenantId)
+		}),
+		channelFields: dataloader.NewLoader(func(channelIds []int) (map[int][]model.ChannelField, error) {
+
+			return model.Model.ChannelFieldsByChannelIds(channelIds, tenantId)
+		}),
+		entryFields: dataloader.NewLoader(func(entryIds []int) (map[int][]model.EntryFieldValue, error) {
+
+			return model.Model.EntryFieldValuesByEntryIds(entryIds, tenantId)
+		}),
+		relatedEntries: dataloader.NewLoader(func(entryIds []int) (map[int]channels.Tblchannelentries, error) {
+
+			return model.Model.PublishedEntriesByIds(entryIds, tenantId)
+		}),
+		hiddenEntries: dataloader.NewLoader(func(keys []hiddenEntryKey) (map[hiddenEntryKey]bool, error) {
+
+			entryIds := make(map[int][]int)
+
+			for _, key := range keys {
+
+				entryIds[key.MemberGroupId] = append(entryIds[key.MemberGroupId], key.EntryId)
+			}
+
+			hidden := make(map[hiddenEntryKey]bool)
+
+			for memberGroupId, ids := range entryIds {
+
+				hiddenIds, err := model.Model.HiddenEntryIds(ids, memberGroupId, tenantId)
+
+				if err != nil {
+
+					return map[hiddenEntryKey]bool{}, err
+				}
+
+				for _, entryId := range hiddenIds {
+
+					hidden[hiddenEntryKey{EntryId: entryId, MemberGroupId: memberGroupId}] = true
+				}
+			}
+
+			return hidden, nil
+		}),
+		imagePresets: dataloader.NewLoader(func(tenantIds []int) (map[int][]imagetransform.Preset, error) {
+
+			presets := make(map[int][]imagetransform.Preset, len(tenantIds))
+
+			for _, presetTenantId := range tenantIds {
+
+				value, err := model.Model.ImagePresets(presetTenantId)
+
+				if err != nil {
+
+					return map[int][]imagetransform.Preset{}, err
+				}
+
+				if presets[presetTenantId] = imagetransform.ParsePresets(value); len(presets[presetTenantId]) == 0 {
+
+					presets[presetTenantId] = imagetransform.DefaultPresets
+				}
+			}
+
+			return presets, nil
+		}),
+		relatedSuggestions: dataloader.NewLoader(func(keys []relatedSuggestionKey) (map[relatedSuggestionKey][]channels.Tblchannelentries, error) {
+
+			return relatedSuggestions(keys, tenantId)
+		}),
+	}
+}
+
+// loadersFor returns the loaders of the current request, the cache is shared by every list resolved in it
+func loadersFor(ctx context.Context, tenantId int) *entryLoaders {
+
+	return dataloader.Scoped(ctx, entryLoadersKey{tenantId: tenantId}, func() *entryLoaders {
+
+		return newEntryLoaders(tenantId)
+	})
+}
+
+// loadEntryRelations fills the requested relations of a page of entries
+func loadEntryRelations(ctx context.Context, entries []model.ChannelEntries, relations entryRelations, tenantId int) error {
+
+	if len(entries) == 0 {
+
+		return nil
+	}
+
+	loaders := loadersFor(ctx, tenantId)
+
+	if relations.Author {
+
+		if err := loadEntryAuthors(loaders, entries); err != nil {
+
+			return err
+		}
+	}
+
+	if relations.Categories {
+
+		if err := loadEntryCategories(loaders, entries); err != nil {
+
+			return err
+		}
+
+	} else if operationSelects(ctx, "breadcrumbs") {
+
+		// the breadcrumbs are resolved per entry, reading the categories of the page up front keeps that to one query
+		var categoryIds []int
+
+		for _, entry := range entries {
+
+			categoryIds = append(categoryIds, splitIds(entry.CategoriesID)...)
+		}
+
+		if _, err := loaders.categories.LoadMany(categoryIds); err != nil {
+
+			return err
+		}
+	}
+
+	if relatedField := operationField(ctx, "related"); relatedField != nil {
+
+		// the related entries are resolved per entry, the picked ones of the whole page are read with one query and
+		// the suggestions for the entries without picks with another
+		var (
+			relatedIds     []int
+			suggestionKeys []relatedSuggestionKey
+		)
+
+		keyFor, err := relatedSuggestionKeys(ctx, relatedPageSize(fieldIntArgument(ctx, relatedField, "limit")))
+
+		if err != nil {
+
+			return err
+		}
+
+		for _, entry := range entries {
+
+			if picked := related.Picked(entry.RelatedArticles, entry.ID); len(picked) > 0 {
+
+				relatedIds = append(relatedIds, picked...)
+
+			} else {
+
+				suggestionKeys = append(suggestionKeys, keyFor(entry))
+			}
+		}
+
+		if len(relatedIds) > 0 {
+
+			if _, err := loaders.relatedEntries.LoadMany(relatedIds); err != nil {
+
+				return err
+			}
+		}
+
+		if len(suggestionKeys) > 0 {
+
+			if _, err := loaders.relatedSuggestions.LoadMany(suggestionKeys); err != nil {
+
+				return err
+			}
+		}
+	}
+
+	if relations.MemberProfile || relations.AdditionalFields {
+
+		entryIds := make([]int, len(entries))
+
+		for index, entry := range entries {
+
+			entryIds[index] = entry.ID
+		}
+
+		entryFields, err := loaders.entryFields.LoadMany(entryIds)
+
+		if err != nil {
+
+			return err
+		}
+
+		if relations.MemberProfile {
+
+			if err := loadEntryMemberProfiles(loaders, entries, entryFields); err != nil {
+
+				return err
+			}
+		}
+
+		if relations.AdditionalFields {
+
+			if err := loadEntryAdditionalFields(loaders, entries, entryFields); err != nil {
+
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// operationSelects reports whether the graphql operation of the request selects a field of the name anywhere
+func operationSelects(ctx context.Context, fieldName string) bool {
+
+	return operationField(ctx, fieldName) != nil
+}
+
+// operationField returns the first field of the operation with the name, nil when it is not selected
+func operationField(ctx context.Context, fieldName string) *ast.Field {
+
+	if !graphql.HasOperationContext(ctx) {
+
+		return nil
+	}
+
+	operation := graphql.GetOperationContext(ctx).Operation
+
+	if operation == nil {
+
+		return nil
+	}
+
+	visited := make(map[string]bool)
+
+	var find func(selectionSet ast.SelectionSet) *ast.Field
+
+	find = func(selectionSet ast.SelectionSet) *ast.Field {
+
+		for _, selection := range selectionSet {
+
+			switch selection := selection.(type) {
+
+			case *ast.Field:
+
+				if selection.Name == fieldName {
+
+					return selection
+				}
+
+				if field := find(selection.SelectionSet); field != nil {
+
+					return field
+				}
+
+			case *ast.InlineFragment:
+
+				if field := find(selection.SelectionSet); field != nil {
+
+					return field
+				}
+
+			case *ast.FragmentSpread:
+
+				if selection.Definition != nil && !visited[selection.Name] {
+
+					visited[selection.Name] = true
+
+					if field := find(selection.Definition.SelectionSet); field != nil {
+
+						return field
+					}
+				}
+			}
+		}
+
+		return nil
+	}
+
+	return find(operation.SelectionSet)
+}
+
+// fieldIntArgument reads an int argument of a field of the operation with its variables, nil when it is not given
+func fieldIntArgument(ctx context.Context, field *ast.Field, name string) *int {
+
+	value, ok := field.ArgumentMap(graphql.GetOperationContext(ctx).Variables)[name]
+
+	if !ok || value == nil {
+
+		return nil
+	}
+
+	number, err := strconv.Atoi(fmt.Sprint(value))
+
+	if err != nil {
+
+		return nil
+	}
+
+	return &number
+}
+
+func loadEntryAuthors(loaders *entryLoaders, entries []model.ChannelEntries) error {
+
+	userIds := make([]int, len(entries))
+
+	for index, entry := range entries {
+
+		userIds[index] = entry.CreatedBy
+	}
+
+	authors, err := loaders.authors.LoadMany(userIds)
+
+	if err != nil {
+
+		return err
+	}
+
+	for index, entry := range entries {
+
+		if author, ok := authors[entry.CreatedBy]; ok {
+
+			entries[index].AuthorDetails = convertAuthor(author)
+		}
+	}
+
+	return nil
+}
+
+// loadEntryCategories builds a category -> parent -> ... chain for every category of an entry, the parents are
+// read by the same recursive query as the categories themselves
+func loadEntryCategories(loaders *entryLoaders, entries []model.ChannelEntries) error {
+
+	entryCategoryIds := make([][]int, len(entries))
+
+	var categoryIds []int
+
+	for index, entry := range entries {
+
+		entryCategoryIds[index] = splitIds(entry.CategoriesID)
+
+		categoryIds = append(categoryIds, entryCategoryIds[index]...)
+	}
+
+	if len(categoryIds) == 0 {
+
+		return nil
+	}
+
+	categoriesById, err := loaders.categories.LoadMany(categoryIds)
+
+	if err != nil {
+
+		return err
+	}
+
+	for index := range entries {
+
+		var categoryHierarchy [][]categories.TblCategories
+
+		for _, categoryId := range entryCategoryIds[index] {
+
+			category, ok := categoriesById[categoryId]
+
+			if !ok {
+
+				continue
+			}
+
+			categoryStream := []categories.TblCategories{category}
+
+			// guards against a parent loop in broken data
+			visited := map[int]bool{category.Id: true}
+
+			for parentId := category.ParentId; parentId != 0 && !visited[parentId]; {
+
+				parent, found, err := loaders.categories.Load(parentId)
+
+				if err != nil {
+
+					return err
+				}
+
+				if !found {
+
+					break
+				}
+
+				categoryStream = append(categoryStream, parent)
+
+				visited[parentId] = true
+
+				parentId = parent.ParentId
+			}
+
+			categoryHierarchy = append(categoryHierarchy, categoryStream)
+		}
+
+		if len(categoryHierarchy) > 0 {
+
+			entries[index].Categories = convertCategoryHierarchy(categoryHierarchy)
+		}
+	}
+
+	return nil
+}
+
+// EntryBreadcrumbs resolves the category trail of an entry from its group down to its deepest category
+func EntryBreadcrumbs(ctx context.Context, entry *model.ChannelEntries) ([]model.Category, error) {
+
+	categoryIds := splitIds(entry.CategoriesID)
+
+	if len(categoryIds) == 0 {
+
+		return []model.Category{}, nil
+	}
+
+	loaders := loadersFor(ctx, entry.TenantID)
+
+	categoriesById, err := loaders.categories.LoadMany(categoryIds)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		return []model.Category{}, err
+	}
+
+	parents := make(map[int]int)
+
+	for _, category := range categoriesById {
+
+		// guards against a parent loop in broken data
+		for current := category; current.ParentId != 0 && parents[current.Id] == 0; {
+
+			parents[current.Id] = current.ParentId
+
+			parent, found, err := loaders.categories.Load(current.ParentId)
+
+			if err != nil {
+
+				ErrorLog.Printf("%v", err)
+
+				return []model.Category{}, err
+			}
+
+			if !found {
+
+				break
+			}
+
+			categoriesById[parent.Id] = parent
+
+			current = parent
+		}
+	}
+
+	var entryCategoryIds []int
+
+	for _, categoryId := range categoryIds {
+
+		if _, ok := categoriesById[categoryId]; ok {
+
+			entryCategoryIds = append(entryCategoryIds, categoryId)
+		}
+	}
+
+	if len(entryCategoryIds) == 0 {
+
+		return []model.Category{}, nil
+	}
+
+	var breadcrumbs []model.Category
+
+	for _, categoryId := range categorytree.Trail(categorytree.Leaf(entryCategoryIds, parents), parents) {
+
+		if category, ok := categoriesById[categoryId]; ok {
+
+			breadcrumbs = append(breadcrumbs, convertCategory(category))
+		}
+	}
+
+	return breadcrumbs, nil
+}
+
+// the member profile of an entry is the profile of the member picked in its member field
+func loadEntryMemberProfiles(loaders *entryLoaders, entries []model.ChannelEntries, entryFields map[int][]model.EntryFieldValue) error {
+
+	entryMemberIds := make(map[int][]int, len(entries))
+
+	var memberIds []int
+
+	for _, entry := range entries {
+
+		for _, field := range entryFields[entry.ID] {
+
+			if field.FieldTypeId == memberFieldTypeId {
+
+				entryMemberIds[entry.ID] = append(entryMemberIds[entry.ID], splitIds(field.FieldValue)...)
+			}
+		}
+
+		memberIds = append(memberIds, entryMemberIds[entry.ID]...)
+	}
+
+	if len(memberIds) == 0 {
+
+		return nil
+	}
+
+	profiles, err := loaders.memberProfiles.LoadMany(memberIds)
+
+	if err != nil {
+
+		return err
+	}
+
+	for index, entry := range entries {
+
+		for _, memberId := range entryMemberIds[entry.ID] {
+
+			if profile, ok := profiles[memberId]; ok {
+
+				entries[index].MemberProfile = convertMemberProfile(profile)
+
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func loadEntryAdditionalFields(loaders *entryLoaders, entries []model.ChannelEntries, entryFields map[int][]model.EntryFieldValue) error {
+
+	channelIds := make([]int, len(entries))
+
+	for index, entry := range entries {
+
+		channelIds[index] = entry.ChannelID
+	}
+
+	channelFields, err := loaders.channelFields.LoadMany(channelIds)
+
+	if err != nil {
+
+		return err
+	}
+
+	for index, entry := range entries {
+
+		fieldValues := make(map[int]model.EntryFieldValue, len(entryFields[entry.ID]))
+
+		for _, value := range entryFields[entry.ID] {
+
+			fieldValues[value.FieldId] = value
+		}
+
+		sections := []model.Section{}
+
+		fields := []model.Field{}
+
+		for _, field := range channelFields[entry.ChannelID] {
+
+			if field.FieldTypeId == sectionFieldTypeId {
+
+				sections = append(sections, convertSection(field))
+
+				continue
+			}
+
+			fields = append(fields, convertField(field, fieldValues[field.Id]))
+		}
+
+		entries[index].AdditionalFields = &model.AdditionalFields{Sections: sections, Fields: fields}
+	}
+
+	return nil
+}
+
+func convertSection(section model.ChannelField) model.Section {
+
+	sectionModon := section.ModifiedOn
+
+	sectionModBy := section.ModifiedBy
+
+	return model.Section{
+		ID:            section.Id,
+		SectionName:   section.FieldName,
+		SectionTypeID: section.FieldTypeId,
+		CreatedOn:     section.CreatedOn,
+		CreatedBy:     section.CreatedBy,
+		ModifiedOn:    &sectionModon,
+		ModifiedBy:    &sectionModBy,
+		OrderIndex:    section.OrderIndex,
+		TenantID:      section.TenantId,
+	}
+}
+
+func convertField(field model.ChannelField, value model.EntryFieldValue) model.Field {
+
+	fieldValueModon := value.ModifiedOn
+
+	fieldValueModBy := value.ModifiedBy
+
+	fieldOptions := make([]model.FieldOptions, len(field.FieldOptions))
+
+	for optionIndex, option := range field.FieldOptions {
+
+		optionModOn := option.ModifiedOn
+
+		optionModBy := option.ModifiedBy
+
+		fieldOptions[optionIndex] = model.FieldOptions{
+			ID:          option.Id,
+			OptionName:  option.OptionName,
+			OptionValue: option.OptionValue,
+			CreatedOn:   option.CreatedOn,
+			CreatedBy:   option.CreatedBy,
+			ModifiedOn:  &optionModOn,
+			ModifiedBy:  &optionModBy,
+			TenantID:    option.TenantId,
+		}
+	}
+
+	fieldModon := field.ModifiedOn
+
+	fieldModBy := field.ModifiedBy
+
+	fieldDateTime := field.DatetimeFormat
+
+	fieldTime := field.TimeFormat
+
+	fieldSectionParentId := field.SectionParentId
+
+	fieldCharAllowed := field.CharacterAllowed
+
+	return model.Field{
+		ID:               field.Id,
+		FieldName:        field.FieldName,
+		FieldTypeID:      field.FieldTypeId,
+		MandatoryField:   field.MandatoryField,
+		OptionExist:      field.OptionExist,
+		CreatedOn:        field.CreatedOn,
+		CreatedBy:        field.CreatedBy,
+		ModifiedOn:       &fieldModon,
+		ModifiedBy:       &fieldModBy,
+		FieldDesc:        field.FieldDesc,
+		OrderIndex:       field.OrderIndex,
+		ImagePath:        field.ImagePath,
+		DatetimeFormat:   &fieldDateTime,
+		TimeFormat:       &fieldTime,
+		SectionParentID:  &fieldSectionParentId,
+		CharacterAllowed: &fieldCharAllowed,
+		FieldTypeName:    field.TypeName,
+		FieldValue: &model.FieldValue{
+			ID:         value.FieldId,
+			FieldValue: value.FieldValue,
+			CreatedOn:  value.CreatedOn,
+			CreatedBy:  value.CreatedBy,
+			ModifiedOn: &fieldValueModon,
+			ModifiedBy: &fieldValueModBy,
+			TenantID:   value.TenantId,
+		},
+		FieldOptions: fieldOptions,
+		TenantID:     field.TenantId,
+	}
+}
+
+// splitIds parses a comma separated id column such as categories_id, invalid parts are skipped
+func splitIds(value string) []int {
+
+	var ids []int
+
+	for _, part := range strings.Split(value, ",") {
+
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+
+		if err == nil && id > 0 {
+
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
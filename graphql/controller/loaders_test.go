@@ -0,0 +1,334 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"spurt-cms/graphql/dataloader"
+	"spurt-cms/graphql/model"
+	applog "spurt-cms/logger"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// countingDriver answers every query of the loaders with canned rows and counts the queries it is sent
+type countingDriver struct {
+	mu         sync.Mutex
+	queries    int
+	statements []string
+}
+
+func (d *countingDriver) Open(string) (driver.Conn, error) {
+
+	return &countingConn{driver: d}, nil
+}
+
+func (d *countingDriver) count() int {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.queries
+}
+
+type countingConn struct {
+	driver *countingDriver
+}
+
+func (conn *countingConn) Prepare(query string) (driver.Stmt, error) {
+
+	return &countingStmt{conn: conn, query: query}, nil
+}
+
+func (conn *countingConn) Close() error { return nil }
+
+func (conn *countingConn) Begin() (driver.Tx, error) { return conn, nil }
+
+func (conn *countingConn) Commit() error { return nil }
+
+func (conn *countingConn) Rollback() error { return nil }
+
+func (conn *countingConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+
+	conn.driver.mu.Lock()
+	conn.driver.queries++
+	conn.driver.statements = append(conn.driver.statements, query)
+	conn.driver.mu.Unlock()
+
+	return cannedRows(query, args), nil
+}
+
+type countingStmt struct {
+	conn  *countingConn
+	query string
+}
+
+func (stmt *countingStmt) Close() error { return nil }
+
+func (stmt *countingStmt) NumInput() int { return -1 }
+
+func (stmt *countingStmt) Exec([]driver.Value) (driver.Result, error) {
+
+	return driver.RowsAffected(0), nil
+}
+
+func (stmt *countingStmt) Query(values []driver.Value) (driver.Rows, error) {
+
+	args := make([]driver.NamedValue, len(values))
+
+	for index, value := range values {
+
+		args[index] = driver.NamedValue{Ordinal: index + 1, Value: value}
+	}
+
+	return stmt.conn.QueryContext(context.Background(), stmt.query, args)
+}
+
+// cannedRows returns a user per author id, a member field per entry and the saved state of an entry, everything
+// else comes back empty
+func cannedRows(query string, args []driver.NamedValue) driver.Rows {
+
+	rows := &staticRows{}
+
+	switch {
+
+	case strings.Contains(query, "tbl_users"):
+
+		rows.columns = []string{"id", "first_name"}
+
+		for _, arg := range args {
+
+			rows.values = append(rows.values, []driver.Value{arg.Value, "author"})
+		}
+
+	case strings.HasPrefix(query, "SELECT cef.*"):
+
+		rows.columns = []string{"id", "field_id", "field_type_id", "field_value", "channel_entry_id"}
+
+		// the entry ids come first, the tenant id last
+		for _, arg := range args[:len(args)-1] {
+
+			entryId := arg.Value.(int64)
+
+			rows.values = append(rows.values, []driver.Value{entryId, int64(1), int64(memberFieldTypeId), fmt.Sprint(entryId%13 + 1), entryId})
+		}
+
+	case strings.HasPrefix(query, "SELECT channel_id, title, description"):
+
+		rows.columns = []string{"channel_id", "title"}
+
+		rows.values = [][]driver.Value{{int64(1), "entry"}}
+	}
+
+	return rows
+}
+
+type staticRows struct {
+	columns []string
+	values  [][]driver.Value
+	next    int
+}
+
+func (rows *staticRows) Columns() []string { return rows.columns }
+
+func (rows *staticRows) Close() error { return nil }
+
+func (rows *staticRows) Next(dest []driver.Value) error {
+
+	if rows.next >= len(rows.values) {
+
+		return io.EOF
+	}
+
+	copy(dest, rows.values[rows.next])
+
+	rows.next++
+
+	return nil
+}
+
+// every database gets its own driver name, sql.Register refuses a name twice
+var countingDrivers int
+
+// withCountingDB points the models at a database that counts the queries sent to it,
+// and the logs of the controllers at a temporary directory
+func withCountingDB(tb testing.TB) *countingDriver {
+
+	applog.SetLogDir(tb.TempDir())
+
+	counting := &countingDriver{}
+
+	countingDrivers++
+
+	name := fmt.Sprintf("counting-%d", countingDrivers)
+
+	sql.Register(name, counting)
+
+	conn, err := sql.Open(name, "")
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Discard})
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	previous := model.Model
+
+	model.Model = model.ModelConfig{DB: db}
+
+	tb.Cleanup(func() {
+
+		model.Model = previous
+
+		conn.Close()
+	})
+
+	return counting
+}
+
+func entryPage(size int) []model.ChannelEntries {
+
+	entries := make([]model.ChannelEntries, size)
+
+	for index := range entries {
+
+		entries[index] = model.ChannelEntries{
+			ID:           index + 1,
+			ChannelID:    index%3 + 1,
+			CreatedBy:    index%7 + 1,
+			CategoriesID: fmt.Sprintf("%d,%d", index%5+1, index%11+20),
+			TenantID:     1,
+		}
+	}
+
+	return entries
+}
+
+var allRelations = entryRelations{Author: true, MemberProfile: true, Categories: true, AdditionalFields: true}
+
+// authors, categories, entry fields, member profiles and channel fields are read with one query each
+const relationQueries = 5
+
+func TestLoadEntryRelationsQueriesPerPage(t *testing.T) {
+
+	counting := withCountingDB(t)
+
+	for _, size := range []int{1, 10, 100} {
+
+		before := counting.count()
+
+		entries := entryPage(size)
+
+		if err := loadEntryRelations(dataloader.WithRequestScope(context.Background()), entries, allRelations, 1); err != nil {
+			t.Fatalf("load relations: %v", err)
+		}
+
+		if queries := counting.count() - before; queries != relationQueries {
+			t.Fatalf("expected %d queries for a page of %d entries, got %d", relationQueries, size, queries)
+		}
+
+		if entries[size-1].AuthorDetails == nil {
+			t.Fatalf("expected the author of the last entry to be filled")
+		}
+	}
+}
+
+func TestLoadEntryRelationsSharesTheRequestCache(t *testing.T) {
+
+	counting := withCountingDB(t)
+
+	ctx := dataloader.WithRequestScope(context.Background())
+
+	for page := 0; page < 2; page++ {
+
+		if err := loadEntryRelations(ctx, entryPage(10), allRelations, 1); err != nil {
+			t.Fatalf("load relations: %v", err)
+		}
+	}
+
+	if queries := counting.count(); queries != relationQueries {
+		t.Fatalf("expected the second list of the request to be served from the loaders, got %d queries", queries)
+	}
+}
+
+// BenchmarkEntryRelations reports the queries needed for the relations of a page, batched as the lists load them
+// and one entry at a time as the resolvers did before the loaders.
+func BenchmarkEntryRelations(b *testing.B) {
+
+	for _, size := range []int{10, 100, 1000} {
+
+		b.Run(fmt.Sprintf("batched/%d", size), func(b *testing.B) {
+
+			counting := withCountingDB(b)
+
+			for i := 0; i < b.N; i++ {
+
+				if err := loadEntryRelations(dataloader.WithRequestScope(context.Background()), entryPage(size), allRelations, 1); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(counting.count())/float64(b.N), "queries/op")
+		})
+
+		b.Run(fmt.Sprintf("per-entry/%d", size), func(b *testing.B) {
+
+			counting := withCountingDB(b)
+
+			for i := 0; i < b.N; i++ {
+
+				for _, entry := range entryPage(size) {
+
+					if err := loadEntryRelations(dataloader.WithRequestScope(context.Background()), []model.ChannelEntries{entry}, allRelations, 1); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+
+			b.ReportMetric(float64(counting.count())/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func TestRelatedSuggestionsQueryPerPage(t *testing.T) {
+
+	counting := withCountingDB(t)
+
+	loaders := newEntryLoaders(1)
+
+	var keys []relatedSuggestionKey
+
+	for _, entry := range entryPage(10) {
+
+		keys = append(keys, relatedSuggestionKey{EntryId: entry.ID, CategoriesId: entry.CategoriesID, Limit: defaultRelatedLimit})
+	}
+
+	if _, err := loaders.relatedSuggestions.LoadMany(keys); err != nil {
+		t.Fatalf("load suggestions: %v", err)
+	}
+
+	var candidateQueries int
+
+	for _, statement := range counting.statements {
+
+		if strings.Contains(statement, "candidate_set") {
+
+			candidateQueries++
+		}
+	}
+
+	if candidateQueries != 1 {
+		t.Fatalf("expected the suggestions of a page to be read with one query, got %d", candidateQueries)
+	}
+}
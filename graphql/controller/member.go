@@ -2,18 +2,21 @@ package controller
 
 import (
 	"context"
-	"errors"
-	"net/http"
-	"spurt-cms/controllers"
+	"spurt-cms/events"
 	"spurt-cms/graphql/info"
 	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/pagination"
+	"strconv"
 	"strings"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/gin-gonic/gin"
 	"github.com/spurtcms/member"
 	"gorm.io/gorm"
 )
 
+// MemberRegister creates a member in the default member group of the tenant. When the member settings ask for email
+// verification the member stays inactive until the link sent by email is opened.
 func MemberRegister(ctx context.Context, memberData *model.MemberDetails, arguments *model.MemberArguments) (bool, error) {
 
 	c, ok := ctx.Value(GinContext).(*gin.Context)
@@ -22,88 +25,212 @@ func MemberRegister(ctx context.Context, memberData *model.MemberDetails, argume
 
 		ErrorLog.Printf("%v", info.ErrGinCtx)
 
+		return false, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
 		c.AbortWithStatus(500)
 
-		return false, info.ErrGinCtx
+		return false, info.ErrFetchTenantDetails
 	}
 
-	tenantDetails := c.GetStringMap("tenant")
-	tenantId := tenantDetails["TenantId"].(int)
+	tenantId := tenantDetails.TenantId
 
-	var (
-		memberSettings member.TblMemberSetting
-		err            error
-	)
+	memberSettings, err := model.Model.GetMemberRegistrationSettings(tenantId)
 
-	memberSettings, err = MemberInstance.GetMemberSettings(tenantId)
 	if err != nil {
 
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
 		return false, err
 	}
 
 	if memberSettings.AllowRegistration == 0 {
 
+		c.AbortWithStatus(403)
+
 		return false, info.ErrMemberRegisterPerm
 	}
 
-	var memberDetails member.TblMember
+	firstName, email := strings.TrimSpace(memberData.FirstName), strings.TrimSpace(memberData.Email)
+
+	password, _ := omittableString(memberData.Password)
 
-	if memberData.Mobile.IsSet() {
+	if firstName == "" || email == "" {
 
-		memberDetails.MobileNo = *memberData.Mobile.Value()
+		c.AbortWithStatus(400)
+
+		return false, info.ErrReqMandatory
 	}
 
-	if memberData.LastName.IsSet() {
+	// members of a tenant that signs in with otp do not need a password
+	if memberSettings.MemberLogin != "otp" && password == "" {
+
+		c.AbortWithStatus(400)
 
-		memberDetails.LastName = *memberData.LastName.Value()
+		return false, info.ErrMemberPassword
 	}
 
-	if memberData.Password.IsSet() {
+	isExist, err := MemberInstance.CheckEmailInMember(0, email, tenantId)
 
-		hashPass := controllers.HashingPassword(*memberData.Password.Value())
+	if err != nil && err != gorm.ErrRecordNotFound {
 
-		memberDetails.Password = hashPass
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
 	}
 
-	if memberData.Username.IsSet() {
+	if isExist {
 
-		memberDetails.Username = *memberData.Username.Value()
+		c.AbortWithStatus(422)
 
-		isExist, err := MemberInstance.CheckNameInMember(0, *memberData.Username.Value(), tenantId)
-		if isExist || err == nil {
-			err = errors.New("member already exists")
+		return false, info.ErrMemberExists
+	}
 
-			c.AbortWithError(422, err)
+	username, usernameGiven := omittableString(memberData.Username)
 
-			return isExist, err
-		}
+	username = strings.TrimSpace(username)
+
+	if !usernameGiven || username == "" {
+
+		username = strings.ToLower(strings.ReplaceAll(firstName, " ", ""))
 	}
 
-	if memberData.Email != "" {
+	isExist, err = MemberInstance.CheckNameInMember(0, username, tenantId)
+
+	if err != nil && err != gorm.ErrRecordNotFound {
 
-		memberDetails.Email = memberData.Email
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
 
-		isExist, err := MemberInstance.CheckEmailInMember(0, memberData.Email, tenantId)
+		return false, err
+	}
 
-		if isExist || err == nil {
+	if isExist {
 
-			err = errors.New("member already exists")
+		if usernameGiven {
 
-			c.AbortWithError(http.StatusBadRequest, err)
+			c.AbortWithStatus(422)
 
-			return isExist, err
+			return false, info.ErrMemberExists
 		}
+
+		// a username taken from the first name gets a number appended rather than failing the registration
+		taken, err := model.Model.MemberUsernames(username, tenantId)
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			c.AbortWithStatus(500)
+
+			return false, err
+		}
+
+		username = uniqueUsername(username, taken)
+	}
+
+	groupId, err := model.Model.DefaultMemberGroupId(memberSettings.DefaultGroupId, tenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	if groupId == 0 {
+
+		ErrorLog.Printf("%v", info.ErrMemberGroup)
+
+		c.AbortWithStatus(500)
+
+		return false, info.ErrMemberGroup
 	}
 
-	memberDetails.FirstName = memberData.FirstName
-	memberDetails.Username = strings.ToLower(memberData.FirstName)
-	memberDetails.IsActive = 1
+	lastName, _ := omittableString(memberData.LastName)
+
+	mobile, _ := omittableString(memberData.Mobile)
+
+	isActive := 1
 
-	// MemberInstance.CreateMember()
+	if memberSettings.EmailVerification == 1 {
+
+		isActive = 0
+	}
+
+	// the member package hashes the password itself
+	createdMember, err := MemberInstance.CreateMember(member.MemberCreationUpdation{
+		FirstName: firstName,
+		LastName:  lastName,
+		Email:     email,
+		MobileNo:  mobile,
+		Username:  username,
+		Password:  password,
+		IsActive:  isActive,
+		GroupId:   groupId,
+		TenantId:  tenantId,
+	})
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	events.PublishMemberEvent(events.MemberEvent{Type: events.MemberCreated, MemberId: createdMember.Id, TenantId: tenantId})
+
+	// the member is stored by now, a failed verification email is reported alongside the result and can be sent
+	// again with resendMemberVerification
+	if memberSettings.EmailVerification == 1 {
+
+		if err := sendMemberVerification(createdMember.Id, createdMember.FirstName, createdMember.Email, memberSettings.VerificationUrl, tenantId); err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			graphql.AddError(ctx, info.ErrVerificationMail)
+		}
+	}
 
 	return true, nil
 }
 
+// uniqueUsername appends the lowest number to the username that makes it differ from the taken ones
+func uniqueUsername(username string, taken []string) string {
+
+	takenNames := make(map[string]bool, len(taken))
+
+	for _, name := range taken {
+
+		takenNames[name] = true
+	}
+
+	for suffix := 1; ; suffix++ {
+
+		candidate := username + strconv.Itoa(suffix)
+
+		if !takenNames[strings.ToLower(candidate)] {
+
+			return candidate
+		}
+	}
+}
+
 func MembersList(ctx context.Context, filter *model.Filter) (*model.MembersDetails, error) {
 
 	c, ok := ctx.Value(GinContext).(*gin.Context)
@@ -150,6 +277,8 @@ func MembersList(ctx context.Context, filter *model.Filter) (*model.MembersDetai
 
 	}
 
+	limit = listLimit(limit)
+
 	input := model.MembersListReq{
 		Limit:    limit,
 		Offset:   offset,
@@ -204,3 +333,99 @@ func MembersList(ctx context.Context, filter *model.Filter) (*model.MembersDetai
 
 	return &model.MembersDetails{MembersList: memberlist, Count: int(count)}, nil
 }
+
+func MembersListConnection(ctx context.Context, first *int, after *string, last *int, before *string, filter *model.Filter, sort *model.Sort) (*model.MembersConnection, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.MembersConnection{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.MembersConnection{}, info.ErrFetchTenantDetails
+	}
+
+	window, err := connectionWindow(first, after, last, before, sort, pagination.SortById, pagination.SortByCreatedOn)
+
+	if err != nil {
+
+		c.AbortWithStatus(400)
+
+		return &model.MembersConnection{}, err
+	}
+
+	input := model.MembersCursorReq{
+		TenantId: tenantDetails.TenantId,
+		Window:   window,
+	}
+
+	if filter != nil && filter.Keyword.IsSet() && filter.Keyword.Value() != nil {
+
+		input.Keyword = *filter.Keyword.Value()
+	}
+
+	members, count, err := model.Model.MembersCursorPage(input)
+
+	if err != nil {
+
+		if err == info.ErrInvalidCursor {
+
+			c.AbortWithStatus(400)
+
+			return &model.MembersConnection{}, err
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MembersConnection{}, err
+	}
+
+	size, hasNextPage, hasPreviousPage := window.Page(len(members))
+
+	members = members[:size]
+
+	if window.Backward {
+
+		pagination.Reverse(members)
+	}
+
+	edges := make([]model.MembersEdge, len(members))
+
+	cursors := make([]pagination.Cursor, len(members))
+
+	for i := range members {
+
+		var memberId int
+
+		if members[i].ID != nil {
+
+			memberId = *members[i].ID
+		}
+
+		if window.SortKey == pagination.SortByCreatedOn && members[i].CreatedOn != nil {
+
+			cursors[i] = pagination.TimeCursor(window.SortKey, *members[i].CreatedOn, memberId)
+
+		} else {
+
+			cursors[i] = pagination.IntCursor(window.SortKey, memberId, memberId)
+		}
+
+		edges[i] = model.MembersEdge{Node: &members[i], Cursor: cursors[i].Encode()}
+	}
+
+	return &model.MembersConnection{Edges: edges, PageInfo: connectionPageInfo(cursors, hasNextPage, hasPreviousPage), TotalCount: int(count)}, nil
+}
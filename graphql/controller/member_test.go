@@ -0,0 +1,20 @@
+package controller
+
+import "testing"
+
+func TestUniqueUsername(t *testing.T) {
+
+	for _, test := range []struct {
+		taken    []string
+		expected string
+	}{
+		{taken: []string{"anna"}, expected: "anna1"},
+		{taken: []string{"anna", "anna1", "anna2"}, expected: "anna3"},
+		{taken: []string{"anna", "anna2", "annabel"}, expected: "anna1"},
+	} {
+
+		if username := uniqueUsername("anna", test.taken); username != test.expected {
+			t.Fatalf("taken %v: expected %q, got %q", test.taken, test.expected, username)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/tokens"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	newauth "github.com/spurtcms/auth"
+	"github.com/spurtcms/member"
+	"gorm.io/gorm"
+)
+
+// gin key of the member read from the Authorization header of the request
+const memberAccessKey = "memberAccess"
+
+type memberAccessResult struct {
+	access model.MemberAccess
+	ok     bool
+	err    error
+}
+
+func MemberLogin(ctx context.Context, input model.MemberLoginInput) (*model.MemberAuth, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.MemberAuth{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberAuth{}, info.ErrFetchTenantDetails
+	}
+
+	email, _ := omittableString(input.Email)
+
+	username, _ := omittableString(input.Username)
+
+	email, username = strings.TrimSpace(email), strings.TrimSpace(username)
+
+	if (email == "" && username == "") || input.Password == "" {
+
+		c.AbortWithStatus(400)
+
+		return &model.MemberAuth{}, info.ErrReqMandatory
+	}
+
+	memberSettings, err := MemberInstance.GetMemberSettings(tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberAuth{}, err
+	}
+
+	// members of a tenant that signs in with otp only have no password to check
+	if memberSettings.MemberLogin == "otp" {
+
+		c.AbortWithStatus(403)
+
+		return &model.MemberAuth{}, info.ErrMemberLoginPerm
+	}
+
+	loginCheck := newauth.MemberLoginCheck{Email: email, Username: username, Password: input.Password}
+
+	if email != "" {
+
+		loginCheck.EmailwithPassword = true
+
+	} else {
+
+		loginCheck.UsernameWithPassword = true
+	}
+
+	loggedMember, err := NewAuth.CheckMemberLogin(loginCheck, tenantDetails.TenantId)
+
+	switch {
+
+	case err == gorm.ErrRecordNotFound || err == newauth.ErrorPassword:
+
+		c.AbortWithStatus(401)
+
+		return &model.MemberAuth{}, info.ErrMemberCredentials
+
+	case err != nil:
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberAuth{}, err
+
+	case loggedMember.IsActive != 1:
+
+		c.AbortWithStatus(403)
+
+		return &model.MemberAuth{}, info.ErrMemberInactive
+	}
+
+	refreshToken, err := tokens.NewRefreshToken()
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberAuth{}, err
+	}
+
+	currentTime := time.Now().UTC()
+
+	session := model.TblGraphqlMemberSessions{
+		MemberId:  loggedMember.Id,
+		TokenHash: tokens.HashRefreshToken(refreshToken),
+		ExpiresOn: currentTime.Add(tokens.RefreshTokenTTL),
+		CreatedOn: currentTime,
+		TenantId:  tenantDetails.TenantId,
+	}
+
+	if err := model.Model.CreateMemberSession(&session); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberAuth{}, err
+	}
+
+	return memberAuth(c, session, refreshToken, currentTime)
+}
+
+// MemberRefreshToken exchanges a refresh token for a new access token. The refresh token is rotated, so a leaked
+// token stops working once its owner has refreshed.
+func MemberRefreshToken(ctx context.Context, refreshToken string) (*model.MemberAuth, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.MemberAuth{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberAuth{}, info.ErrFetchTenantDetails
+	}
+
+	oldHash := tokens.HashRefreshToken(refreshToken)
+
+	session, err := model.Model.ActiveMemberSession(oldHash, tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			c.AbortWithStatus(401)
+
+			return &model.MemberAuth{}, info.ErrMemberToken
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberAuth{}, err
+	}
+
+	newToken, err := tokens.NewRefreshToken()
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberAuth{}, err
+	}
+
+	currentTime := time.Now().UTC()
+
+	session.TokenHash = tokens.HashRefreshToken(newToken)
+
+	session.ExpiresOn = currentTime.Add(tokens.RefreshTokenTTL)
+
+	if err := model.Model.RotateMemberSession(session.Id, oldHash, session.TokenHash, session.ExpiresOn, tenantDetails.TenantId); err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			c.AbortWithStatus(401)
+
+			return &model.MemberAuth{}, info.ErrMemberToken
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberAuth{}, err
+	}
+
+	return memberAuth(c, session, newToken, currentTime)
+}
+
+func MemberLogout(ctx context.Context, refreshToken string) (bool, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return false, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return false, info.ErrFetchTenantDetails
+	}
+
+	session, err := model.Model.ActiveMemberSession(tokens.HashRefreshToken(refreshToken), tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			c.AbortWithStatus(401)
+
+			return false, info.ErrMemberToken
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	if err := model.Model.RevokeMemberSession(session.Id, tenantDetails.TenantId); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// memberAuth signs the access token of a session and returns it with the member details
+func memberAuth(c *gin.Context, session model.TblGraphqlMemberSessions, refreshToken string, currentTime time.Time) (*model.MemberAuth, error) {
+
+	memberDetails, err := MemberInstance.GetMemberDetails(session.MemberId, session.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberAuth{}, err
+	}
+
+	accessToken, expiresAt, err := tokens.Issue(os.Getenv("JWT_SECRET"), tokens.MemberAudience, &tokens.MemberClaims{MemberId: session.MemberId, TenantId: session.TenantId, SessionId: session.Id}, tokens.AccessTokenTTL, currentTime)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberAuth{}, err
+	}
+
+	return &model.MemberAuth{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresAt: expiresAt, Member: convertMember(memberDetails)}, nil
+}
+
+func convertMember(memberDetails member.Tblmember) *model.Members {
+
+	return &model.Members{
+		ID:               &memberDetails.Id,
+		FirstName:        memberDetails.FirstName,
+		LastName:         &memberDetails.LastName,
+		Mobile:           &memberDetails.MobileNo,
+		Email:            memberDetails.Email,
+		IsActive:         &memberDetails.IsActive,
+		ProfileImage:     &memberDetails.ProfileImage,
+		ProfileImagePath: &memberDetails.ProfileImagePath,
+		Username:         &memberDetails.Username,
+		GroupID:          &memberDetails.MemberGroupId,
+		CreatedBy:        &memberDetails.CreatedBy,
+		CreatedOn:        &memberDetails.CreatedOn,
+		ModifiedOn:       &memberDetails.ModifiedOn,
+		ModifiedBy:       &memberDetails.ModifiedBy,
+		TenantID:         memberDetails.TenantId,
+		IsDeleted:        memberDetails.IsDeleted,
+	}
+}
+
+// GetMemberAccess returns the member of the bearer token sent with the request. ok is false for a request without a
+// member token, which reads the entries as before.
+func GetMemberAccess(c *gin.Context) (access model.MemberAccess, ok bool, err error) {
+
+	if cached, exists := c.Get(memberAccessKey); exists {
+
+		result := cached.(memberAccessResult)
+
+		return result.access, result.ok, result.err
+	}
+
+	access, ok, err = memberAccess(c)
+
+	c.Set(memberAccessKey, memberAccessResult{access: access, ok: ok, err: err})
+
+	return access, ok, err
+}
+
+func memberAccess(c *gin.Context) (model.MemberAccess, bool, error) {
+
+	token := tokens.BearerToken(c.GetHeader("Authorization"))
+
+	if token == "" {
+
+		return model.MemberAccess{}, false, nil
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		return model.MemberAccess{}, false, info.ErrFetchTenantDetails
+	}
+
+	var claims tokens.MemberClaims
+
+	err = tokens.Parse(os.Getenv("JWT_SECRET"), tokens.MemberAudience, token, &claims, time.Now())
+
+	if err != nil || claims.TenantId != tenantDetails.TenantId {
+
+		return model.MemberAccess{}, false, info.ErrMemberToken
+	}
+
+	access, err := model.Model.SessionMemberAccess(claims.SessionId, claims.MemberId, claims.TenantId)
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			return model.MemberAccess{}, false, info.ErrMemberToken
+		}
+
+		return model.MemberAccess{}, false, err
+	}
+
+	return access, true, nil
+}
+
+// requestMemberGroupId returns the member group of the member of the request. An anonymous request has no member
+// group, which no content access right or entry group list grants, so every entry restricted to a group is hidden.
+func requestMemberGroupId(c *gin.Context) (int, error) {
+
+	access, _, err := GetMemberAccess(c)
+
+	if err != nil {
+
+		if err == info.ErrMemberToken {
+
+			c.AbortWithStatus(401)
+
+			return 0, err
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return 0, err
+	}
+
+	return access.MemberGroupId, nil
+}
+
+// memberEntryHidden reports whether the member of the request may not read the entry
+func memberEntryHidden(c *gin.Context, entryId int, tenantId int) (bool, error) {
+
+	memberGroupId, err := requestMemberGroupId(c)
+
+	if err != nil {
+
+		return false, err
+	}
+
+	hiddenEntryIds, err := model.Model.HiddenEntryIds([]int{entryId}, memberGroupId, tenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	return len(hiddenEntryIds) > 0, nil
+}
+
+func containsId(ids []int, id int) bool {
+
+	for _, value := range ids {
+
+		if value == id {
+
+			return true
+		}
+	}
+
+	return false
+}
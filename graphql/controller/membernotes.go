@@ -0,0 +1,376 @@
+package controller
+
+import (
+	"context"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/pagination"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spurtcms/channels"
+	"gorm.io/gorm"
+)
+
+const maxNoteLength = 10000
+
+// MemberNotes returns the notes and highlights of the signed in member, newest first
+func MemberNotes(ctx context.Context, entryId *int, noteType *model.MemberNoteType, limit *int, offset *int) (*model.MemberNoteList, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.MemberNoteList{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberNoteList{}, info.ErrFetchTenantDetails
+	}
+
+	access, err := signedInMember(c)
+
+	if err != nil {
+
+		return &model.MemberNoteList{}, err
+	}
+
+	inputs := model.MemberNotesReq{MemberId: access.MemberId, Limit: pagination.DefaultPageSize, TenantId: tenantDetails.TenantId}
+
+	if entryId != nil {
+
+		inputs.EntryId = *entryId
+	}
+
+	if noteType != nil {
+
+		inputs.Type = strings.ToLower(noteType.String())
+	}
+
+	if limit != nil && *limit > 0 {
+
+		inputs.Limit = *limit
+	}
+
+	if inputs.Limit > pagination.MaxPageSize {
+
+		inputs.Limit = pagination.MaxPageSize
+	}
+
+	if offset != nil && *offset > 0 {
+
+		inputs.Offset = *offset
+	}
+
+	notes, count, err := model.Model.MemberNotes(inputs)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberNoteList{}, err
+	}
+
+	list := &model.MemberNoteList{Notes: []model.MemberNote{}, Count: int(count)}
+
+	for _, note := range notes {
+
+		list.Notes = append(list.Notes, convertMemberNote(note))
+	}
+
+	return list, nil
+}
+
+// CreateNote adds a note or highlight of the signed in member to an entry the member can read
+func CreateNote(ctx context.Context, input model.MemberNoteInput) (*model.MemberNote, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.MemberNote{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberNote{}, info.ErrFetchTenantDetails
+	}
+
+	access, err := signedInMember(c)
+
+	if err != nil {
+
+		return &model.MemberNote{}, err
+	}
+
+	content := strings.TrimSpace(input.Content)
+
+	configuration := input.Configuration.Value()
+
+	if err := validateMemberNote(c, input.Type, content, configuration); err != nil {
+
+		return &model.MemberNote{}, err
+	}
+
+	if err := checkNoteEntry(c, input.EntryID, tenantDetails.TenantId); err != nil {
+
+		return &model.MemberNote{}, err
+	}
+
+	note := model.TblMemberNotesHighlights{
+		MemberId:                access.MemberId,
+		PageId:                  input.EntryID,
+		NotesHighlightsContent:  content,
+		NotesHighlightsType:     strings.ToLower(input.Type.String()),
+		HighlightsConfiguration: configuration,
+		CreatedBy:               access.MemberId,
+		CreatedOn:               time.Now().UTC(),
+		TenantId:                tenantDetails.TenantId,
+	}
+
+	if err := model.Model.CreateMemberNote(&note); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberNote{}, err
+	}
+
+	result := convertMemberNote(note)
+
+	return &result, nil
+}
+
+// UpdateNote changes the content or configuration of a note of the signed in member
+func UpdateNote(ctx context.Context, id int, input model.UpdateMemberNoteInput) (*model.MemberNote, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.MemberNote{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberNote{}, info.ErrFetchTenantDetails
+	}
+
+	access, err := signedInMember(c)
+
+	if err != nil {
+
+		return &model.MemberNote{}, err
+	}
+
+	note, err := model.Model.MemberNote(id, access.MemberId, tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			c.AbortWithStatus(404)
+
+			return &model.MemberNote{}, info.ErrRecordNotFound
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberNote{}, err
+	}
+
+	if content, ok := omittableString(input.Content); ok {
+
+		note.NotesHighlightsContent = strings.TrimSpace(content)
+	}
+
+	if input.Configuration.IsSet() {
+
+		note.HighlightsConfiguration = input.Configuration.Value()
+	}
+
+	noteType := model.MemberNoteType(strings.ToUpper(note.NotesHighlightsType))
+
+	if err := validateMemberNote(c, noteType, note.NotesHighlightsContent, note.HighlightsConfiguration); err != nil {
+
+		return &model.MemberNote{}, err
+	}
+
+	currentTime := time.Now().UTC()
+
+	note.ModifiedBy = access.MemberId
+
+	note.ModifiedOn = &currentTime
+
+	if err := model.Model.UpdateMemberNote(&note); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberNote{}, err
+	}
+
+	result := convertMemberNote(note)
+
+	return &result, nil
+}
+
+// DeleteNote removes a note of the signed in member
+func DeleteNote(ctx context.Context, id int) (bool, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return false, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return false, info.ErrFetchTenantDetails
+	}
+
+	access, err := signedInMember(c)
+
+	if err != nil {
+
+		return false, err
+	}
+
+	deleted, err := model.Model.DeleteMemberNote(id, access.MemberId, tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	if !deleted {
+
+		c.AbortWithStatus(404)
+
+		return false, info.ErrRecordNotFound
+	}
+
+	return true, nil
+}
+
+// validateMemberNote checks the content of a note, a highlight also needs the configuration that anchors it in the entry
+func validateMemberNote(c *gin.Context, noteType model.MemberNoteType, content string, configuration map[string]interface{}) error {
+
+	if content == "" || utf8.RuneCountInString(content) > maxNoteLength {
+
+		c.AbortWithStatus(400)
+
+		return info.ErrNoteContent
+	}
+
+	if noteType == model.MemberNoteTypeHighlight && len(configuration) == 0 {
+
+		c.AbortWithStatus(400)
+
+		return info.ErrHighlightConfig
+	}
+
+	return nil
+}
+
+// checkNoteEntry checks that the entry exists and the api key and member can read it
+func checkNoteEntry(c *gin.Context, entryId, tenantId int) error {
+
+	channelEntry, _, err := ChannelConfigWP.FetchChannelEntryDetail(channels.EntriesInputs{Id: entryId, TenantId: tenantId}, nil)
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return err
+	}
+
+	if channelEntry.Id == 0 {
+
+		c.AbortWithStatus(404)
+
+		return info.ErrRecordNotFound
+	}
+
+	if err := checkApiKeyChannel(c, channelEntry.ChannelId); err != nil {
+
+		return err
+	}
+
+	hidden, err := memberEntryHidden(c, channelEntry.Id, tenantId)
+
+	if err != nil {
+
+		return err
+	}
+
+	if hidden {
+
+		c.AbortWithStatus(403)
+
+		return info.ErrMemberRestricted
+	}
+
+	return nil
+}
+
+func convertMemberNote(note model.TblMemberNotesHighlights) model.MemberNote {
+
+	return model.MemberNote{
+		ID:            note.Id,
+		EntryID:       note.PageId,
+		Type:          model.MemberNoteType(strings.ToUpper(note.NotesHighlightsType)),
+		Content:       note.NotesHighlightsContent,
+		Configuration: note.HighlightsConfiguration,
+		CreatedOn:     note.CreatedOn,
+		ModifiedOn:    note.ModifiedOn,
+	}
+}
@@ -0,0 +1,469 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"spurt-cms/controllers"
+	"spurt-cms/events"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/tokens"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gin-gonic/gin"
+	"github.com/spurtcms/member"
+	"gorm.io/gorm"
+)
+
+const (
+	claimTokenTTL = 72 * time.Hour
+
+	// a profile is sent a new claim email once in this interval
+	claimResendInterval = time.Minute
+)
+
+var profileSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// MemberProfile returns the profile of an active member by its slug
+func MemberProfile(ctx context.Context, slug string) (*model.MemberProfile, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.MemberProfile{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberProfile{}, info.ErrFetchTenantDetails
+	}
+
+	profile, err := model.Model.MemberProfileBySlug(strings.TrimSpace(slug), tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			c.AbortWithStatus(404)
+
+			return &model.MemberProfile{}, info.ErrRecordNotFound
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberProfile{}, err
+	}
+
+	return convertMemberProfile(profile), nil
+}
+
+// UpdateMyProfile updates the profile of the signed in member, a member without a profile gets one as long as a name
+// and slug are given
+func UpdateMyProfile(ctx context.Context, input model.MemberProfileInput) (*model.MemberProfile, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.MemberProfile{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberProfile{}, info.ErrFetchTenantDetails
+	}
+
+	access, err := signedInMember(c)
+
+	if err != nil {
+
+		return &model.MemberProfile{}, err
+	}
+
+	profile, err := model.Model.MemberProfileByMemberId(access.MemberId, tenantDetails.TenantId)
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberProfile{}, err
+	}
+
+	columns := map[string]interface{}{}
+
+	if name, ok := omittableString(input.ProfileName); ok {
+
+		if strings.TrimSpace(name) == "" {
+
+			c.AbortWithStatus(400)
+
+			return &model.MemberProfile{}, info.ErrReqMandatory
+		}
+
+		columns["profile_name"] = strings.TrimSpace(name)
+	}
+
+	if slug, ok := omittableString(input.ProfileSlug); ok {
+
+		slug = strings.ToLower(strings.TrimSpace(slug))
+
+		if !profileSlugPattern.MatchString(slug) {
+
+			c.AbortWithStatus(400)
+
+			return &model.MemberProfile{}, info.ErrProfileSlug
+		}
+
+		taken, err := model.Model.ProfileSlugTaken(slug, profile.Id, tenantDetails.TenantId)
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			c.AbortWithStatus(500)
+
+			return &model.MemberProfile{}, err
+		}
+
+		if taken {
+
+			c.AbortWithStatus(400)
+
+			return &model.MemberProfile{}, info.ErrProfileSlugTaken
+		}
+
+		columns["profile_slug"] = slug
+	}
+
+	for column, value := range map[string]graphql.Omittable[*string]{
+		"profile_page":     input.ProfilePage,
+		"company_name":     input.CompanyName,
+		"company_location": input.CompanyLocation,
+		"company_logo":     input.CompanyLogo,
+		"about":            input.About,
+		"seo_title":        input.SeoTitle,
+		"seo_description":  input.SeoDescription,
+		"seo_keyword":      input.SeoKeyword,
+		"linkedin":         input.Linkedin,
+		"twitter":          input.Twitter,
+		"website":          input.Website,
+	} {
+
+		if val, ok := omittableString(value); ok {
+
+			columns[column] = val
+		}
+	}
+
+	if profile.Id == 0 {
+
+		if columns["profile_name"] == nil || columns["profile_slug"] == nil {
+
+			c.AbortWithStatus(400)
+
+			return &model.MemberProfile{}, info.ErrReqMandatory
+		}
+
+		err := MemberInstance.CreateMemberProfile(member.MemberprofilecreationUpdation{
+			MemberId:    access.MemberId,
+			ProfileName: columns["profile_name"].(string),
+			ProfileSlug: columns["profile_slug"].(string),
+			ModifiedBy:  access.MemberId,
+			TenantId:    tenantDetails.TenantId,
+		})
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			c.AbortWithStatus(500)
+
+			return &model.MemberProfile{}, err
+		}
+	}
+
+	if len(columns) > 0 {
+
+		if err := MemberInstance.MemberProfileFlexibleUpdate(columns, access.MemberId, access.MemberId, tenantDetails.TenantId); err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			c.AbortWithStatus(500)
+
+			return &model.MemberProfile{}, err
+		}
+	}
+
+	events.PublishMemberEvent(events.MemberEvent{Type: events.MemberUpdated, MemberId: access.MemberId, TenantId: tenantDetails.TenantId})
+
+	profile, err = model.Model.MemberProfileByMemberId(access.MemberId, tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			c.AbortWithStatus(404)
+
+			return &model.MemberProfile{}, info.ErrRecordNotFound
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberProfile{}, err
+	}
+
+	return convertMemberProfile(profile), nil
+}
+
+// RequestProfileClaim emails a claim link to the member a profile belongs to, the same email an admin sends when
+// enabling the claim. Whoever asks for the claim, only the owner of the member email can confirm it.
+func RequestProfileClaim(ctx context.Context, slug string) (bool, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return false, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return false, info.ErrFetchTenantDetails
+	}
+
+	profile, err := model.Model.ClaimableProfileBySlug(strings.TrimSpace(slug), tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			c.AbortWithStatus(404)
+
+			return false, info.ErrRecordNotFound
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	if profile.ClaimStatus == 1 {
+
+		c.AbortWithStatus(400)
+
+		return false, info.ErrProfileClaimed
+	}
+
+	// like the admin claim, inactive members are not emailed, without telling the caller
+	if profile.IsActive == 0 {
+
+		return true, nil
+	}
+
+	lastSent, err := model.Model.LastProfileClaim(profile.ProfileId, tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	if time.Since(lastSent) < claimResendInterval {
+
+		return true, nil
+	}
+
+	if err := sendProfileClaim(profile, tenantDetails.TenantId); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ConfirmProfileClaim claims the profile of a token sent by RequestProfileClaim
+func ConfirmProfileClaim(ctx context.Context, token string) (*model.MemberProfile, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.MemberProfile{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberProfile{}, info.ErrFetchTenantDetails
+	}
+
+	claim, err := model.Model.ActiveProfileClaim(tokens.HashRefreshToken(strings.TrimSpace(token)), tenantDetails.TenantId)
+
+	if err == nil {
+
+		err = model.Model.ClaimMemberProfile(claim)
+	}
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			c.AbortWithStatus(400)
+
+			return &model.MemberProfile{}, info.ErrClaimToken
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberProfile{}, err
+	}
+
+	events.PublishMemberEvent(events.MemberEvent{Type: events.MemberUpdated, MemberId: claim.MemberId, TenantId: tenantDetails.TenantId})
+
+	profile, err := model.Model.MemberProfileByMemberId(claim.MemberId, tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.MemberProfile{}, err
+	}
+
+	return convertMemberProfile(profile), nil
+}
+
+// sendProfileClaim stores a new claim token for the profile and emails its link to the member
+func sendProfileClaim(profile model.ClaimableProfile, tenantId int) error {
+
+	token, err := tokens.NewRefreshToken()
+
+	if err != nil {
+
+		return err
+	}
+
+	currentTime := time.Now().UTC()
+
+	claim := model.TblMemberProfileClaims{
+		ProfileId: profile.ProfileId,
+		MemberId:  profile.MemberId,
+		TokenHash: tokens.HashRefreshToken(token),
+		ExpiresOn: currentTime.Add(claimTokenTTL),
+		CreatedOn: currentTime,
+		TenantId:  tenantId,
+	}
+
+	if err := model.Model.CreateProfileClaim(&claim); err != nil {
+
+		return err
+	}
+
+	var url_prefix = os.Getenv("BASE_URL")
+
+	data := map[string]interface{}{
+		"client_name":    strings.TrimSpace(profile.FirstName + " " + profile.LastName),
+		"company_name":   profile.CompanyName,
+		"logo":           url_prefix + "public/img/email-icons/logo.png",
+		"fb_logo":        url_prefix + "public/img/email-icons/facebook.png",
+		"linkedin_logo":  url_prefix + "public/img/email-icons/linkedin.png",
+		"spacex_logo":    url_prefix + "public/img/email-icons/x.png",
+		"youtube_logo":   url_prefix + "public/img/email-icons/youtube.png",
+		"instagram_logo": url_prefix + "public/img/email-icons/instagram.png",
+		"Link":           os.Getenv("OWNDESK_URL") + "company/" + profile.ProfileSlug + "/" + token,
+		"Slug":           profile.ProfileSlug,
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go controllers.MemberClaimEmail(&wg, data, profile.Email, tenantId)
+
+	return nil
+}
+
+// signedInMember returns the member of the bearer token, for the operations on the data of the member itself
+func signedInMember(c *gin.Context) (model.MemberAccess, error) {
+
+	access, ok, err := GetMemberAccess(c)
+
+	if err != nil {
+
+		if err == info.ErrMemberToken {
+
+			c.AbortWithStatus(401)
+
+			return model.MemberAccess{}, err
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return model.MemberAccess{}, err
+	}
+
+	if !ok {
+
+		c.AbortWithStatus(401)
+
+		return model.MemberAccess{}, info.ErrMemberSignIn
+	}
+
+	return access, nil
+}
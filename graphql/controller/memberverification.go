@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"spurt-cms/controllers"
+	"spurt-cms/events"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/tokens"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	verificationTokenTTL = 24 * time.Hour
+
+	// a member can ask for a new verification email once in this interval
+	verificationResendInterval = time.Minute
+)
+
+// VerifyMemberEmail activates the member of a verification token sent by email
+func VerifyMemberEmail(ctx context.Context, token string) (bool, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return false, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return false, info.ErrFetchTenantDetails
+	}
+
+	verification, err := model.Model.ActiveMemberVerification(tokens.HashRefreshToken(strings.TrimSpace(token)), tenantDetails.TenantId)
+
+	if err == nil {
+
+		err = model.Model.VerifyMember(verification)
+	}
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			c.AbortWithStatus(400)
+
+			return false, info.ErrVerificationToken
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	events.PublishMemberEvent(events.MemberEvent{Type: events.MemberUpdated, MemberId: verification.MemberId, TenantId: tenantDetails.TenantId})
+
+	return true, nil
+}
+
+// ResendMemberVerification sends a new verification email. It answers true for unknown and verified emails as well,
+// so the mutation can not be used to find out which emails are registered.
+func ResendMemberVerification(ctx context.Context, email string) (bool, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return false, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return false, info.ErrFetchTenantDetails
+	}
+
+	memberSettings, err := model.Model.GetMemberRegistrationSettings(tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	if memberSettings.EmailVerification != 1 {
+
+		return true, nil
+	}
+
+	unverified, err := model.Model.UnverifiedMemberByEmail(strings.TrimSpace(email), tenantDetails.TenantId)
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			return true, nil
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	lastSent, err := model.Model.LastMemberVerification(unverified.Id, tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	if time.Since(lastSent) < verificationResendInterval {
+
+		return true, nil
+	}
+
+	if err := sendMemberVerification(unverified.Id, unverified.FirstName, unverified.Email, memberSettings.VerificationUrl, tenantDetails.TenantId); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// sendMemberVerification stores a new verification token for the member and emails its link
+func sendMemberVerification(memberId int, firstName, email, verificationUrl string, tenantId int) error {
+
+	token, err := tokens.NewRefreshToken()
+
+	if err != nil {
+
+		return err
+	}
+
+	currentTime := time.Now().UTC()
+
+	verification := model.TblMemberVerifications{
+		MemberId:  memberId,
+		TokenHash: tokens.HashRefreshToken(token),
+		ExpiresOn: currentTime.Add(verificationTokenTTL),
+		CreatedOn: currentTime,
+		TenantId:  tenantId,
+	}
+
+	if err := model.Model.CreateMemberVerification(&verification); err != nil {
+
+		return err
+	}
+
+	var url_prefix = os.Getenv("BASE_URL")
+
+	data := map[string]interface{}{
+		"fname":              firstName,
+		"verification_url":   verificationLink(verificationUrl, token),
+		"verification_token": token,
+		"expiry":             fmt.Sprintf("%d hours", int(verificationTokenTTL.Hours())),
+		"admin_logo":         url_prefix + "public/img/SpurtCMSlogo.png",
+		"fb_logo":            url_prefix + "public/img/email-icons/facebook.png",
+		"linkedin_logo":      url_prefix + "public/img/email-icons/linkedin.png",
+		"twitter_logo":       url_prefix + "public/img/email-icons/x.png",
+		"youtube_logo":       url_prefix + "public/img/email-icons/youtube.png",
+		"insta_log":          url_prefix + "public/img/email-icons/instagram.png",
+		"facebook":           os.Getenv("FACEBOOK"),
+		"instagram":          os.Getenv("INSTAGRAM"),
+		"youtube":            os.Getenv("YOUTUBE"),
+		"linkedin":           os.Getenv("LINKEDIN"),
+		"twitter":            os.Getenv("TWITTER"),
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go controllers.MemberVerificationEmail(&wg, data, email, tenantId)
+
+	return nil
+}
+
+// verificationLink adds the token to the verification page of the website
+func verificationLink(verificationUrl, token string) string {
+
+	if verificationUrl == "" {
+
+		return ""
+	}
+
+	separator := "?"
+
+	if strings.Contains(verificationUrl, "?") {
+
+		separator = "&"
+	}
+
+	return verificationUrl + separator + "token=" + url.QueryEscape(token)
+}
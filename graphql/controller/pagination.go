@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/pagination"
+)
+
+// listLimit returns the page size of a list query. A list without a limit, or with a larger one, gets the largest page,
+// which is also the page size the query cost charges for it.
+func listLimit(limit int) int {
+
+	if limit <= 0 || limit > pagination.MaxPageSize {
+
+		return pagination.MaxPageSize
+	}
+
+	return limit
+}
+
+// connectionWindow resolves the relay arguments and sort of a connection query, without a sort the newest rows come first
+func connectionWindow(first *int, after *string, last *int, before *string, sort *model.Sort, allowedSortKeys ...string) (pagination.Window, error) {
+
+	var (
+		sortBy string
+		desc   = true
+	)
+
+	if sort != nil {
+
+		if sort.SortBy.IsSet() && sort.SortBy.Value() != nil {
+
+			sortBy = *sort.SortBy.Value()
+		}
+
+		desc = sort.Order.IsSet() && sort.Order.Value() != nil && *sort.Order.Value() > 0
+	}
+
+	sortKey, err := pagination.NormalizeSortKey(sortBy, allowedSortKeys...)
+
+	if err != nil {
+
+		return pagination.Window{}, err
+	}
+
+	return pagination.NewWindow(pagination.Args{First: first, After: after, Last: last, Before: before}, sortKey, desc)
+}
+
+func connectionPageInfo(cursors []pagination.Cursor, hasNextPage, hasPreviousPage bool) *model.PageInfo {
+
+	pageInfo := model.PageInfo{
+		HasNextPage:     hasNextPage,
+		HasPreviousPage: hasPreviousPage,
+	}
+
+	if len(cursors) > 0 {
+
+		startCursor, endCursor := cursors[0].Encode(), cursors[len(cursors)-1].Encode()
+
+		pageInfo.StartCursor, pageInfo.EndCursor = &startCursor, &endCursor
+	}
+
+	return &pageInfo
+}
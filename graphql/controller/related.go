@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"context"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/pagination"
+	"spurt-cms/graphql/related"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spurtcms/channels"
+)
+
+const (
+	defaultRelatedLimit = 5
+
+	// the suggestions are picked from this many of the latest candidates per related entry asked for
+	relatedCandidatesPerEntry = 4
+)
+
+// EntryRelated resolves the related articles the editor picked for an entry, in the order they were picked. An entry
+// without picks gets the latest entries sharing a category or a tag with it instead. Either way only published
+// entries the api key and the member can read are returned.
+func EntryRelated(ctx context.Context, entry *model.ChannelEntries, limit *int, additionalData *model.EntriesAdditionalData) ([]model.ChannelEntries, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return []model.ChannelEntries{}, info.ErrGinCtx
+	}
+
+	relatedLimit := relatedPageSize(limit)
+
+	scope, err := GetApiKeyScope(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		return []model.ChannelEntries{}, err
+	}
+
+	loaders := loadersFor(ctx, entry.TenantID)
+
+	var relatedEntries []channels.Tblchannelentries
+
+	if picked := related.Picked(entry.RelatedArticles, entry.ID); len(picked) > 0 {
+
+		// an invalid member token is reported by the query the entry came from, anonymous requests load the entries
+		// hidden from every member group
+		access, _, _ := GetMemberAccess(c)
+
+		hiddenKeys := make([]hiddenEntryKey, len(picked))
+
+		for index, entryId := range picked {
+
+			hiddenKeys[index] = hiddenEntryKey{EntryId: entryId, MemberGroupId: access.MemberGroupId}
+		}
+
+		hiddenEntries, err := loaders.hiddenEntries.LoadMany(hiddenKeys)
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			return []model.ChannelEntries{}, err
+		}
+
+		entriesById, err := loaders.relatedEntries.LoadMany(picked)
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			return []model.ChannelEntries{}, err
+		}
+
+		for _, entryId := range picked {
+
+			relatedEntry, ok := entriesById[entryId]
+
+			if !ok || !scope.AllowsChannel(relatedEntry.ChannelId) || hiddenEntries[hiddenEntryKey{EntryId: entryId, MemberGroupId: access.MemberGroupId}] {
+
+				continue
+			}
+
+			relatedEntries = append(relatedEntries, relatedEntry)
+
+			if len(relatedEntries) == relatedLimit {
+
+				break
+			}
+		}
+
+	} else {
+
+		keyFor, err := relatedSuggestionKeys(ctx, relatedLimit)
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			return []model.ChannelEntries{}, err
+		}
+
+		// the suggestions of a page were read up front by loadEntryRelations, a single entry reads its own
+		if relatedEntries, _, err = loaders.relatedSuggestions.Load(keyFor(*entry)); err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			return []model.ChannelEntries{}, err
+		}
+	}
+
+	nodes := []model.ChannelEntries{}
+
+	for _, relatedEntry := range relatedEntries {
+
+		nodes = append(nodes, convertChannelEntry(relatedEntry))
+	}
+
+	if err := loadEntryRelations(ctx, nodes, searchEntryRelations(additionalData), entry.TenantID); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		return []model.ChannelEntries{}, err
+	}
+
+	return nodes, nil
+}
+
+// relatedSuggestionKey is an entry without picks and what its suggestions depend on, the api key's channels are kept
+// comma separated so the key can be compared
+type relatedSuggestionKey struct {
+	EntryId       int
+	CategoriesId  string
+	Tags          string
+	ChannelIds    string
+	MemberGroupId int
+	Limit         int
+}
+
+// relatedPageSize returns the number of related entries asked for, defaultRelatedLimit when no limit is given
+func relatedPageSize(limit *int) int {
+
+	relatedLimit := defaultRelatedLimit
+
+	if limit != nil && *limit > 0 {
+
+		relatedLimit = *limit
+	}
+
+	if relatedLimit > pagination.MaxPageSize {
+
+		relatedLimit = pagination.MaxPageSize
+	}
+
+	return relatedLimit
+}
+
+// relatedSuggestionKeys returns the suggestion key of an entry for the api key and the member of the request
+func relatedSuggestionKeys(ctx context.Context, limit int) (func(entry model.ChannelEntries) relatedSuggestionKey, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		return nil, info.ErrGinCtx
+	}
+
+	scope, err := GetApiKeyScope(c)
+
+	if err != nil {
+
+		return nil, err
+	}
+
+	channelIds := make([]string, len(scope.ChannelIds))
+
+	for index, channelId := range scope.ChannelIds {
+
+		channelIds[index] = strconv.Itoa(channelId)
+	}
+
+	// an invalid member token is reported by the query the entry came from, anonymous requests load the entries hidden
+	// from every member group
+	access, _, _ := GetMemberAccess(c)
+
+	return func(entry model.ChannelEntries) relatedSuggestionKey {
+
+		key := relatedSuggestionKey{
+			EntryId:       entry.ID,
+			CategoriesId:  entry.CategoriesID,
+			ChannelIds:    strings.Join(channelIds, ","),
+			MemberGroupId: access.MemberGroupId,
+			Limit:         limit,
+		}
+
+		if entry.Tags != nil {
+
+			key.Tags = *entry.Tags
+		}
+
+		return key
+
+	}, nil
+}
+
+// relatedSuggestions reads the latest published entries sharing a category or a tag with each entry with one query
+func relatedSuggestions(keys []relatedSuggestionKey, tenantId int) (map[relatedSuggestionKey][]channels.Tblchannelentries, error) {
+
+	inputs := make([]model.RelatedEntriesReq, len(keys))
+
+	for index, key := range keys {
+
+		inputs[index] = model.RelatedEntriesReq{
+			EntryId:       key.EntryId,
+			CategoryIds:   splitIds(key.CategoriesId),
+			Tags:          related.Tags(key.Tags),
+			ChannelIds:    splitIds(key.ChannelIds),
+			MemberGroupId: key.MemberGroupId,
+			Limit:         key.Limit * relatedCandidatesPerEntry,
+			TenantId:      tenantId,
+		}
+	}
+
+	candidates, err := model.Model.RelatedEntryCandidates(inputs)
+
+	if err != nil {
+
+		return map[relatedSuggestionKey][]channels.Tblchannelentries{}, err
+	}
+
+	suggestions := make(map[relatedSuggestionKey][]channels.Tblchannelentries, len(keys))
+
+	for index, key := range keys {
+
+		suggestions[key] = []channels.Tblchannelentries{}
+
+		for _, candidate := range candidates[index] {
+
+			if !related.Shares(inputs[index].CategoryIds, inputs[index].Tags, splitIds(candidate.CategoriesId), related.Tags(candidate.Tags)) {
+
+				continue
+			}
+
+			suggestions[key] = append(suggestions[key], candidate)
+
+			if len(suggestions[key]) == key.Limit {
+
+				break
+			}
+		}
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,482 @@
+package controller
+
+import (
+	"context"
+	"spurt-cms/events"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/pagination"
+	"spurt-cms/graphql/search"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spurtcms/channels"
+	"gorm.io/gorm"
+)
+
+// StartSearchIndexer keeps the search index in step with the entry events and catches up on the entries changed while
+// the server was down
+func StartSearchIndexer() {
+
+	// subscribe before the catch up, so no change made in between is missed. The subscription is blocking, a burst of
+	// changes waits for the indexer rather than leaving entries out of step with the index.
+	entryEvents, _ := events.SubscribeEntryEventsBlocking(-1)
+
+	go func() {
+
+		entryIds, err := model.Model.UnsyncedSearchEntryIds()
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+		}
+
+		for _, entryId := range entryIds {
+
+			indexSearchEntry(entryId)
+		}
+	}()
+
+	go func() {
+
+		for event := range entryEvents {
+
+			indexSearchEntry(event.EntryId)
+		}
+	}()
+}
+
+// indexSearchEntry indexes a published entry and removes any other entry from the index
+func indexSearchEntry(entryId int) {
+
+	source, err := model.Model.SearchEntrySource(entryId)
+
+	if err == gorm.ErrRecordNotFound {
+
+		if err := model.Model.RemoveSearchEntry(entryId); err != nil {
+
+			ErrorLog.Printf("%v", err)
+		}
+
+		return
+	}
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		return
+	}
+
+	content := []string{search.PlainText(source.Excerpt), search.PlainText(source.Description)}
+
+	for _, value := range source.FieldValues {
+
+		content = append(content, search.PlainText(value))
+	}
+
+	document := model.TblGraphqlSearchEntries{
+		EntryId:       source.Id,
+		ChannelId:     source.ChannelId,
+		Title:         source.Title,
+		Tags:          source.Tags,
+		Content:       strings.Join(strings.Fields(strings.Join(content, " ")), " "),
+		CategoriesId:  source.CategoriesId,
+		PublishedTime: source.PublishedTime,
+		TenantId:      source.TenantId,
+	}
+
+	if err := model.Model.IndexSearchEntry(&document); err != nil {
+
+		ErrorLog.Printf("%v", err)
+	}
+}
+
+// SearchEntries runs a full text search over the published entries, best match first, with a highlighted snippet per
+// entry and the counts of the requested facets over all matches
+func SearchEntries(ctx context.Context, query string, channelSlug *string, categorySlug *string, facets []model.SearchFacet, limit *int, offset *int, additionalData *model.EntriesAdditionalData) (*model.SearchResults, error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return &model.SearchResults{}, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		c.AbortWithStatus(500)
+
+		return &model.SearchResults{}, info.ErrFetchTenantDetails
+	}
+
+	terms := search.Terms(query)
+
+	if len(terms) == 0 {
+
+		c.AbortWithStatus(400)
+
+		return &model.SearchResults{}, info.ErrSearchQuery
+	}
+
+	pageSize, pageOffset := pagination.DefaultPageSize, 0
+
+	if limit != nil && *limit > 0 {
+
+		pageSize = *limit
+	}
+
+	if pageSize > pagination.MaxPageSize {
+
+		pageSize = pagination.MaxPageSize
+	}
+
+	if offset != nil && *offset > 0 {
+
+		pageOffset = *offset
+	}
+
+	inputs := model.SearchEntriesReq{Terms: terms, TenantId: tenantDetails.TenantId}
+
+	if categorySlug != nil {
+
+		inputs.CategorySlug = *categorySlug
+	}
+
+	if channelSlug != nil && *channelSlug != "" {
+
+		channel, err := ChannelConfigWP.ChannelDetail(channels.Channels{Slug: *channelSlug, TenantId: tenantDetails.TenantId})
+
+		if err != nil && err != gorm.ErrRecordNotFound {
+
+			ErrorLog.Printf("%v", err)
+
+			c.AbortWithStatus(500)
+
+			return &model.SearchResults{}, err
+		}
+
+		if channel.Id == 0 {
+
+			c.AbortWithStatus(404)
+
+			return &model.SearchResults{}, info.ErrChannelNotFound
+		}
+
+		if err := checkApiKeyChannel(c, channel.Id); err != nil {
+
+			return &model.SearchResults{}, err
+		}
+
+		inputs.ChannelId = channel.Id
+
+	} else {
+
+		scope, err := GetApiKeyScope(c)
+
+		if err != nil {
+
+			ErrorLog.Printf("%v", err)
+
+			c.AbortWithStatus(500)
+
+			return &model.SearchResults{}, err
+		}
+
+		inputs.ChannelIds = scope.ChannelIds
+	}
+
+	inputs.MemberGroupId, err = requestMemberGroupId(c)
+
+	if err != nil {
+
+		return &model.SearchResults{}, err
+	}
+
+	matches, err := model.Model.SearchEntryMatches(inputs)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.SearchResults{}, err
+	}
+
+	searchFacets, err := searchFacetCounts(matches, facets, tenantDetails.TenantId)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.SearchResults{}, err
+	}
+
+	results := &model.SearchResults{Hits: []model.SearchHit{}, Count: len(matches), Facets: searchFacets}
+
+	if pageOffset >= len(matches) {
+
+		return results, nil
+	}
+
+	page := matches[pageOffset:]
+
+	if len(page) > pageSize {
+
+		page = page[:pageSize]
+	}
+
+	entryIds := make([]int, len(page))
+
+	for index, match := range page {
+
+		entryIds[index] = match.EntryId
+	}
+
+	_, channelEntries, err := ChannelConfigWP.FetchChannelEntryDetail(channels.EntriesInputs{TenantId: tenantDetails.TenantId}, entryIds)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.SearchResults{}, err
+	}
+
+	documents, err := model.Model.SearchEntriesByIds(entryIds)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.SearchResults{}, err
+	}
+
+	entriesById := make(map[int]channels.Tblchannelentries, len(channelEntries))
+
+	for _, entry := range channelEntries {
+
+		entriesById[entry.Id] = entry
+	}
+
+	var (
+		nodes      []model.ChannelEntries
+		nodeScores []model.SearchMatch
+	)
+
+	// the details come back unordered, so follow the ranking of the matches
+	for _, match := range page {
+
+		entry, ok := entriesById[match.EntryId]
+
+		if !ok {
+
+			continue
+		}
+
+		nodes = append(nodes, convertChannelEntry(entry))
+
+		nodeScores = append(nodeScores, match)
+	}
+
+	if err := loadEntryRelations(ctx, nodes, searchEntryRelations(additionalData), tenantDetails.TenantId); err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		c.AbortWithStatus(500)
+
+		return &model.SearchResults{}, err
+	}
+
+	for index := range nodes {
+
+		document := documents[nodes[index].ID]
+
+		results.Hits = append(results.Hits, model.SearchHit{
+			Entry:        &nodes[index],
+			Score:        nodeScores[index].Score,
+			TitleSnippet: search.Snippet(document.Title, terms, len([]rune(document.Title))),
+			Snippet:      search.Snippet(document.Content, terms, search.DefaultSnippetLength),
+		})
+	}
+
+	return results, nil
+}
+
+func searchEntryRelations(additionalData *model.EntriesAdditionalData) (relations entryRelations) {
+
+	if additionalData == nil {
+
+		return relations
+	}
+
+	if additionalData.AuthorDetails.IsSet() && additionalData.AuthorDetails.Value() != nil {
+
+		relations.Author = *additionalData.AuthorDetails.Value()
+	}
+
+	if additionalData.MemberProfile.IsSet() && additionalData.MemberProfile.Value() != nil {
+
+		relations.MemberProfile = *additionalData.MemberProfile.Value()
+	}
+
+	if additionalData.Categories.IsSet() && additionalData.Categories.Value() != nil {
+
+		relations.Categories = *additionalData.Categories.Value()
+	}
+
+	if additionalData.AdditionalFields.IsSet() && additionalData.AdditionalFields.Value() != nil {
+
+		relations.AdditionalFields = *additionalData.AdditionalFields.Value()
+	}
+
+	return relations
+}
+
+// searchFacetCounts counts the matches per channel, category and tag, only for the requested facets
+func searchFacetCounts(matches []model.SearchMatch, facets []model.SearchFacet, tenantId int) (*model.SearchFacets, error) {
+
+	searchFacets := &model.SearchFacets{}
+
+	for _, facet := range facets {
+
+		counts := search.Facet{}
+
+		for _, match := range matches {
+
+			switch facet {
+
+			case model.SearchFacetChannel:
+
+				counts.Add(strconv.Itoa(match.ChannelId))
+
+			case model.SearchFacetCategory:
+
+				counts.Add(search.SplitList(match.CategoriesId)...)
+
+			case model.SearchFacetTag:
+
+				counts.Add(search.SplitList(strings.ToLower(match.Tags))...)
+			}
+		}
+
+		facetCounts := counts.Counts()
+
+		names, err := searchFacetNames(facet, facetCounts, tenantId)
+
+		if err != nil {
+
+			return &model.SearchFacets{}, err
+		}
+
+		result := make([]model.SearchFacetCount, 0, len(facetCounts))
+
+		for _, count := range facetCounts {
+
+			name, ok := names[count.Key]
+
+			// channels and categories deleted since they were indexed
+			if !ok {
+
+				continue
+			}
+
+			result = append(result, model.SearchFacetCount{Key: count.Key, Name: name, Count: count.Count})
+		}
+
+		switch facet {
+
+		case model.SearchFacetChannel:
+
+			searchFacets.Channels = result
+
+		case model.SearchFacetCategory:
+
+			searchFacets.Categories = result
+
+		case model.SearchFacetTag:
+
+			searchFacets.Tags = result
+		}
+	}
+
+	return searchFacets, nil
+}
+
+// searchFacetNames returns the display name of every facet key, the channel and category keys are their ids
+func searchFacetNames(facet model.SearchFacet, counts []search.FacetCount, tenantId int) (map[string]string, error) {
+
+	names := make(map[string]string, len(counts))
+
+	if facet == model.SearchFacetTag {
+
+		for _, count := range counts {
+
+			names[count.Key] = count.Key
+		}
+
+		return names, nil
+	}
+
+	var ids []int
+
+	for _, count := range counts {
+
+		if id, err := strconv.Atoi(count.Key); err == nil {
+
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+
+		return names, nil
+	}
+
+	if facet == model.SearchFacetChannel {
+
+		channelsById, err := model.Model.ChannelsByIds(ids, tenantId)
+
+		if err != nil {
+
+			return names, err
+		}
+
+		for id, channel := range channelsById {
+
+			names[strconv.Itoa(id)] = channel.ChannelName
+		}
+
+		return names, nil
+	}
+
+	categoriesById, err := model.Model.CategoriesWithParents(ids)
+
+	if err != nil {
+
+		return names, err
+	}
+
+	for _, id := range ids {
+
+		if category, ok := categoriesById[id]; ok && category.IsDeleted == 0 {
+
+			names[strconv.Itoa(id)] = category.CategoryName
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"context"
+	"spurt-cms/events"
+	"spurt-cms/graphql/info"
+	"spurt-cms/graphql/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spurtcms/channels"
+	"gorm.io/gorm"
+)
+
+func EntryPublished(ctx context.Context, channelSlug *string) (<-chan *model.ChannelEntries, error) {
+
+	return entryEventStream(ctx, channelSlug, events.EntryPublished)
+}
+
+func EntryUpdated(ctx context.Context, channelSlug *string) (<-chan *model.ChannelEntries, error) {
+
+	return entryEventStream(ctx, channelSlug, events.EntryUpdated)
+}
+
+func EntryDeleted(ctx context.Context, channelSlug *string) (<-chan *model.DeletedEntry, error) {
+
+	tenantId, channelId, scope, err := subscriptionScope(ctx, channelSlug)
+
+	if err != nil {
+
+		return nil, err
+	}
+
+	source, unsubscribe := events.SubscribeEntryEvents(tenantId)
+
+	stream := make(chan *model.DeletedEntry, 1)
+
+	go func() {
+
+		defer close(stream)
+
+		defer unsubscribe()
+
+		for {
+
+			select {
+
+			case <-ctx.Done():
+
+				return
+
+			case event, ok := <-source:
+
+				if !ok {
+
+					return
+				}
+
+				if event.Type != events.EntryDeleted || (channelId != 0 && event.ChannelId != channelId) || !scope.AllowsChannel(event.ChannelId) {
+
+					continue
+				}
+
+				deleted := model.DeletedEntry{
+					ID:        event.EntryId,
+					ChannelID: event.ChannelId,
+					TenantID:  event.TenantId,
+					DeletedOn: event.CreatedOn,
+				}
+
+				select {
+
+				case stream <- &deleted:
+
+				case <-ctx.Done():
+
+					return
+				}
+			}
+		}
+	}()
+
+	return stream, nil
+}
+
+// entryEventStream forwards the entry events of one type to a subscription, the entry is read back so the payload matches ChannelEntryDetail
+func entryEventStream(ctx context.Context, channelSlug *string, eventType string) (<-chan *model.ChannelEntries, error) {
+
+	tenantId, channelId, scope, err := subscriptionScope(ctx, channelSlug)
+
+	if err != nil {
+
+		return nil, err
+	}
+
+	source, unsubscribe := events.SubscribeEntryEvents(tenantId)
+
+	stream := make(chan *model.ChannelEntries, 1)
+
+	go func() {
+
+		defer close(stream)
+
+		defer unsubscribe()
+
+		for {
+
+			select {
+
+			case <-ctx.Done():
+
+				return
+
+			case event, ok := <-source:
+
+				if !ok {
+
+					return
+				}
+
+				if event.Type != eventType || (channelId != 0 && event.ChannelId != 0 && event.ChannelId != channelId) {
+
+					continue
+				}
+
+				entry, _, err := ChannelConfigWP.FetchChannelEntryDetail(channels.EntriesInputs{Id: event.EntryId, TenantId: tenantId, GetLinkedCategories: true, GetAdditionalFields: true}, nil)
+
+				if err != nil {
+
+					ErrorLog.Printf("%v", err)
+
+					continue
+				}
+
+				if entry.Id == 0 || (channelId != 0 && entry.ChannelId != channelId) || !scope.AllowsChannel(entry.ChannelId) {
+
+					continue
+				}
+
+				channelEntry := convertChannelEntry(entry)
+
+				select {
+
+				case stream <- &channelEntry:
+
+				case <-ctx.Done():
+
+					return
+				}
+			}
+		}
+	}()
+
+	return stream, nil
+}
+
+// subscriptionScope returns the tenant and scope of the api key and the channel the subscription is limited to, 0 for all channels
+func subscriptionScope(ctx context.Context, channelSlug *string) (tenantId int, channelId int, scope ApiKeyScope, err error) {
+
+	c, ok := ctx.Value(GinContext).(*gin.Context)
+
+	if !ok {
+
+		ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return 0, 0, scope, info.ErrGinCtx
+	}
+
+	tenantDetails, err := GetTenantDetails(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", info.ErrFetchTenantDetails)
+
+		return 0, 0, scope, info.ErrFetchTenantDetails
+	}
+
+	scope, err = GetApiKeyScope(c)
+
+	if err != nil {
+
+		ErrorLog.Printf("%v", err)
+
+		return 0, 0, scope, err
+	}
+
+	if channelSlug == nil || *channelSlug == "" {
+
+		return tenantDetails.TenantId, 0, scope, nil
+	}
+
+	channel, err := ChannelConfigWP.ChannelDetail(channels.Channels{Slug: *channelSlug, TenantId: tenantDetails.TenantId})
+
+	if err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+
+			return 0, 0, scope, info.ErrChannelNotFound
+		}
+
+		ErrorLog.Printf("%v", err)
+
+		return 0, 0, scope, err
+	}
+
+	if channel.Id == 0 {
+
+		return 0, 0, scope, info.ErrChannelNotFound
+	}
+
+	if !scope.AllowsChannel(channel.Id) {
+
+		return 0, 0, scope, info.ErrApiKeyChannel
+	}
+
+	return tenantDetails.TenantId, channel.Id, scope, nil
+}
@@ -0,0 +1,135 @@
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// Loader batches the lookups of one relation into a single fetch and caches what it has loaded.
+// Loaders are request scoped, so the cache never outlives the request that filled it.
+type Loader[K comparable, V any] struct {
+	fetch func(keys []K) (map[K]V, error)
+
+	mu sync.Mutex
+
+	values map[K]V
+
+	// keys already asked for, including the ones the fetch found nothing for
+	fetched map[K]bool
+}
+
+func NewLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *Loader[K, V] {
+
+	return &Loader[K, V]{fetch: fetch, values: make(map[K]V), fetched: make(map[K]bool)}
+}
+
+// LoadMany returns the values found for keys. Keys that were loaded before are served from the cache and the
+// rest are fetched with one call. The fetch may return more values than it was asked for (e.g. the parents of a
+// category), those are cached as well.
+func (loader *Loader[K, V]) LoadMany(keys []K) (map[K]V, error) {
+
+	loader.mu.Lock()
+
+	defer loader.mu.Unlock()
+
+	var missing []K
+
+	for _, key := range keys {
+
+		if !loader.fetched[key] {
+
+			loader.fetched[key] = true
+
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+
+		values, err := loader.fetch(missing)
+
+		if err != nil {
+
+			for _, key := range missing {
+
+				delete(loader.fetched, key)
+			}
+
+			return map[K]V{}, err
+		}
+
+		for key, value := range values {
+
+			loader.values[key] = value
+
+			loader.fetched[key] = true
+		}
+	}
+
+	found := make(map[K]V, len(keys))
+
+	for _, key := range keys {
+
+		if value, ok := loader.values[key]; ok {
+
+			found[key] = value
+		}
+	}
+
+	return found, nil
+}
+
+func (loader *Loader[K, V]) Load(key K) (value V, found bool, err error) {
+
+	values, err := loader.LoadMany([]K{key})
+
+	if err != nil {
+
+		return value, false, err
+	}
+
+	value, found = values[key]
+
+	return value, found, nil
+}
+
+type contextKey struct{}
+
+type scope struct {
+	mu sync.Mutex
+
+	values map[interface{}]interface{}
+}
+
+// WithRequestScope returns a context that holds the loaders of one request
+func WithRequestScope(ctx context.Context) context.Context {
+
+	return context.WithValue(ctx, contextKey{}, &scope{values: make(map[interface{}]interface{})})
+}
+
+// Scoped returns the value stored under key for the current request, it is created on first use.
+// Outside of a request scope a new value is created on every call, which disables the caching but not the batching.
+func Scoped[T any](ctx context.Context, key interface{}, create func() T) T {
+
+	requestScope, ok := ctx.Value(contextKey{}).(*scope)
+
+	if !ok {
+
+		return create()
+	}
+
+	requestScope.mu.Lock()
+
+	defer requestScope.mu.Unlock()
+
+	if value, ok := requestScope.values[key].(T); ok {
+
+		return value
+	}
+
+	value := create()
+
+	requestScope.values[key] = value
+
+	return value
+}
@@ -0,0 +1,148 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeSource stands in for the database, every fetch counts as one query
+type fakeSource struct {
+	queries int
+}
+
+func (source *fakeSource) fetch(keys []int) (map[int]string, error) {
+
+	source.queries++
+
+	values := make(map[int]string, len(keys))
+
+	for _, key := range keys {
+
+		values[key] = fmt.Sprintf("value-%d", key)
+	}
+
+	return values, nil
+}
+
+func TestLoadManyBatchesAndCaches(t *testing.T) {
+
+	source := &fakeSource{}
+
+	loader := NewLoader(source.fetch)
+
+	values, err := loader.LoadMany([]int{1, 2, 2, 3})
+
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if source.queries != 1 || len(values) != 3 || values[2] != "value-2" {
+		t.Fatalf("expected one fetch for three keys, got %d fetches and %v", source.queries, values)
+	}
+
+	if _, err := loader.LoadMany([]int{2, 3}); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if source.queries != 1 {
+		t.Fatalf("cached keys were fetched again")
+	}
+
+	if value, found, _ := loader.Load(4); !found || value != "value-4" || source.queries != 2 {
+		t.Fatalf("expected a second fetch for a new key, got %q %v after %d fetches", value, found, source.queries)
+	}
+}
+
+func TestLoadManyCachesExtraValuesAndMisses(t *testing.T) {
+
+	queries := 0
+
+	// parents come back with the categories that were asked for, id 9 does not exist
+	loader := NewLoader(func(keys []int) (map[int]int, error) {
+
+		queries++
+
+		values := map[int]int{}
+
+		for _, key := range keys {
+
+			if key != 9 {
+
+				values[key] = key
+
+				values[key*10] = key * 10
+			}
+		}
+
+		return values, nil
+	})
+
+	if _, err := loader.LoadMany([]int{1, 9}); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if _, found, _ := loader.Load(10); !found {
+		t.Fatalf("extra value was not cached")
+	}
+
+	if _, found, _ := loader.Load(9); found {
+		t.Fatalf("expected no value for a missing key")
+	}
+
+	if queries != 1 {
+		t.Fatalf("expected a single fetch, got %d", queries)
+	}
+}
+
+func TestLoadManyRetriesAfterError(t *testing.T) {
+
+	fail := true
+
+	loader := NewLoader(func(keys []int) (map[int]int, error) {
+
+		if fail {
+
+			return nil, errors.New("connection reset")
+		}
+
+		return map[int]int{keys[0]: 1}, nil
+	})
+
+	if _, _, err := loader.Load(1); err == nil {
+		t.Fatalf("expected the fetch error")
+	}
+
+	fail = false
+
+	if _, found, err := loader.Load(1); err != nil || !found {
+		t.Fatalf("expected the key to be fetched again, got %v %v", found, err)
+	}
+}
+
+func TestScoped(t *testing.T) {
+
+	created := 0
+
+	create := func() *Loader[int, string] {
+
+		created++
+
+		return NewLoader((&fakeSource{}).fetch)
+	}
+
+	ctx := WithRequestScope(context.Background())
+
+	if Scoped(ctx, "authors", create) != Scoped(ctx, "authors", create) || created != 1 {
+		t.Fatalf("expected one loader per request scope")
+	}
+
+	Scoped(context.Background(), "authors", create)
+
+	Scoped(context.Background(), "authors", create)
+
+	if created != 3 {
+		t.Fatalf("expected a new loader outside of a request scope")
+	}
+}
@@ -0,0 +1,158 @@
+package entryviews
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// parts of the user agents of crawlers, link previews, monitors and command line clients, matched case insensitively.
+// The http libraries of javascript are left out, server rendered frontends call the api with them for their readers.
+var botMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "archiver", "facebookexternalhit", "embedly", "phantomjs", "lighthouse",
+	"pingdom", "uptime", "monitor", "curl/", "wget/", "python-requests", "python-urllib", "go-http-client", "java/",
+	"libwww", "httpclient", "postman",
+}
+
+// IsBot reports whether a view was made by a crawler or a script rather than a reader, a missing user agent counts as one
+func IsBot(userAgent string) bool {
+
+	userAgent = strings.ToLower(strings.TrimSpace(userAgent))
+
+	if userAgent == "" {
+
+		return true
+	}
+
+	for _, marker := range botMarkers {
+
+		if strings.Contains(userAgent, marker) {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fingerprint identifies a visitor by the given parts, e.g. the client ip and user agent, without keeping them
+func Fingerprint(parts ...string) string {
+
+	hash := sha256.New()
+
+	for _, part := range parts {
+
+		hash.Write([]byte(part))
+
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+type viewKey struct {
+	entryId     int
+	fingerprint string
+}
+
+type view struct {
+	key       viewKey
+	expiresAt time.Time
+}
+
+// Deduper remembers which visitor viewed which entry, so a visitor counts once per entry within the window. It keeps
+// at most size views, the oldest view is forgotten first when more visitors come in within one window.
+type Deduper struct {
+	mu sync.Mutex
+
+	window time.Duration
+	size   int
+
+	seen map[viewKey]*list.Element
+
+	// the views in the order they were seen, which is also the order they leave the window in, oldest at the back
+	order *list.List
+}
+
+func NewDeduper(window time.Duration, size int) *Deduper {
+
+	return &Deduper{window: window, size: size, seen: make(map[viewKey]*list.Element), order: list.New()}
+}
+
+// First reports whether the view is the first of the visitor for the entry within the window and remembers it
+func (deduper *Deduper) First(entryId int, fingerprint string, now time.Time) bool {
+
+	deduper.mu.Lock()
+
+	defer deduper.mu.Unlock()
+
+	for oldest := deduper.order.Back(); oldest != nil && !now.Before(oldest.Value.(*view).expiresAt); oldest = deduper.order.Back() {
+
+		deduper.remove(oldest)
+	}
+
+	key := viewKey{entryId: entryId, fingerprint: fingerprint}
+
+	if element, ok := deduper.seen[key]; ok {
+
+		if now.Before(element.Value.(*view).expiresAt) {
+
+			return false
+		}
+
+		deduper.remove(element)
+	}
+
+	deduper.seen[key] = deduper.order.PushFront(&view{key: key, expiresAt: now.Add(deduper.window)})
+
+	for deduper.order.Len() > deduper.size {
+
+		deduper.remove(deduper.order.Back())
+	}
+
+	return true
+}
+
+func (deduper *Deduper) remove(element *list.Element) {
+
+	deduper.order.Remove(element)
+
+	delete(deduper.seen, element.Value.(*view).key)
+}
+
+// Day returns the utc day a time falls on, views are counted per entry and day
+func Day(at time.Time) time.Time {
+
+	at = at.UTC()
+
+	return time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DayViews is the view count of an entry on one day
+type DayViews struct {
+	Day   time.Time
+	Views int
+}
+
+// Series returns a count for every day from the first to the last day, the days without views count zero
+func Series(counts []DayViews, from, to time.Time) []DayViews {
+
+	byDay := make(map[time.Time]int, len(counts))
+
+	for _, count := range counts {
+
+		byDay[Day(count.Day)] += count.Views
+	}
+
+	series := []DayViews{}
+
+	for day := Day(from); !day.After(Day(to)); day = day.AddDate(0, 0, 1) {
+
+		series = append(series, DayViews{Day: day, Views: byDay[day]})
+	}
+
+	return series
+}
@@ -0,0 +1,105 @@
+package entryviews
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsBot(t *testing.T) {
+
+	for userAgent, expected := range map[string]bool{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36": false,
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148":                   false,
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)":                                    true,
+		"facebookexternalhit/1.1": true,
+		"curl/8.4.0":              true,
+		"Go-http-client/1.1":      true,
+		"":                        true,
+		"axios/1.6.2":             false,
+		"node-fetch/1.0 (+https://github.com/bitinn/node-fetch)":                                                    false,
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0 Safari/537.36": false,
+		"Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 Chrome/120.0 Mobile Safari/537.36 Preview":              false,
+	} {
+
+		if IsBot(userAgent) != expected {
+			t.Fatalf("expected IsBot(%q) to be %v", userAgent, expected)
+		}
+	}
+}
+
+func TestDeduperCountsAVisitorOncePerWindow(t *testing.T) {
+
+	deduper := NewDeduper(30*time.Minute, 100)
+
+	at := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	visitor, other := Fingerprint("10.0.0.1", "browser"), Fingerprint("10.0.0.2", "browser")
+
+	if !deduper.First(1, visitor, at) {
+		t.Fatalf("expected the first view to count")
+	}
+
+	if deduper.First(1, visitor, at.Add(10*time.Minute)) {
+		t.Fatalf("expected a refresh within the window not to count")
+	}
+
+	if !deduper.First(2, visitor, at.Add(10*time.Minute)) || !deduper.First(1, other, at.Add(10*time.Minute)) {
+		t.Fatalf("expected views of other entries and visitors to count")
+	}
+
+	if !deduper.First(1, visitor, at.Add(31*time.Minute)) {
+		t.Fatalf("expected a view after the window to count again")
+	}
+
+	deduper.First(3, other, at.Add(2*time.Hour))
+
+	if len(deduper.seen) != 1 {
+		t.Fatalf("expected the views outside the window to be swept, got %v", deduper.seen)
+	}
+}
+
+func TestDeduperForgetsTheOldestViewWhenFull(t *testing.T) {
+
+	deduper := NewDeduper(30*time.Minute, 2)
+
+	at := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	visitor := Fingerprint("10.0.0.1", "browser")
+
+	for entryId := 1; entryId <= 3; entryId++ {
+
+		deduper.First(entryId, visitor, at)
+	}
+
+	if len(deduper.seen) != 2 || deduper.order.Len() != 2 {
+		t.Fatalf("expected the deduper to keep 2 views, got %d", len(deduper.seen))
+	}
+
+	if !deduper.First(1, visitor, at.Add(time.Minute)) {
+		t.Fatalf("expected the forgotten view to count again")
+	}
+
+	if deduper.First(3, visitor, at.Add(time.Minute)) {
+		t.Fatalf("expected the latest view to be remembered")
+	}
+}
+
+func TestSeriesFillsMissingDays(t *testing.T) {
+
+	from := time.Date(2024, 2, 28, 15, 0, 0, 0, time.UTC)
+
+	counts := []DayViews{
+		{Day: time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC), Views: 4},
+		{Day: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Views: 2},
+	}
+
+	series := Series(counts, from, time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC))
+
+	if len(series) != 3 || series[0].Views != 4 || series[1].Views != 0 || series[2].Views != 2 {
+		t.Fatalf("unexpected series %v", series)
+	}
+
+	if !series[1].Day.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the leap day in the series, got %v", series[1].Day)
+	}
+}
@@ -0,0 +1,357 @@
+package fieldfilter
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type Operator string
+
+const (
+	OpEq       Operator = "EQ"
+	OpNe       Operator = "NE"
+	OpIn       Operator = "IN"
+	OpLt       Operator = "LT"
+	OpGt       Operator = "GT"
+	OpContains Operator = "CONTAINS"
+	OpExists   Operator = "EXISTS"
+)
+
+// Kind decides how the values of a field are compared
+type Kind int
+
+const (
+	KindText Kind = iota
+	KindDate
+	KindSelect
+)
+
+// field type ids of tbl_field_types
+const (
+	dateTimeFieldType = 4
+	selectFieldType   = 5
+	dateFieldType     = 6
+	radioFieldType    = 9
+	checkboxFieldType = 10
+)
+
+var ErrInvalidValue = errors.New("invalid field predicate value")
+
+// layouts the admin panel and api clients store dates in
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02-01-2006 15:04",
+	"02-01-2006",
+	"01/02/2006",
+}
+
+func KindOf(fieldTypeId int) Kind {
+
+	switch fieldTypeId {
+
+	case dateTimeFieldType, dateFieldType:
+
+		return KindDate
+
+	case selectFieldType, radioFieldType, checkboxFieldType:
+
+		return KindSelect
+	}
+
+	return KindText
+}
+
+// NormalizeName lets a field be named by its label or a slug of it, "Start Date" and "start-date" are the same field
+func NormalizeName(name string) string {
+
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(words, "-")
+}
+
+type Predicate struct {
+	Op     Operator
+	Value  string
+	Values []string
+}
+
+// Match reports whether a field value satisfies the predicate, exists is false for an entry without the field. An
+// entry without the field only matches NE and a negated EXISTS. Dates stored without a zone are read in the location
+// of now, which is also the day "today" stands for.
+func (predicate Predicate) Match(kind Kind, value string, exists bool, now time.Time) (bool, error) {
+
+	exists = exists && strings.TrimSpace(value) != ""
+
+	if predicate.Op == OpExists {
+
+		want, err := strconv.ParseBool(defaultValue(predicate.Value, "true"))
+
+		if err != nil {
+
+			return false, ErrInvalidValue
+		}
+
+		return exists == want, nil
+	}
+
+	if !exists {
+
+		return predicate.Op == OpNe, nil
+	}
+
+	switch predicate.Op {
+
+	case OpEq, OpNe:
+
+		equal, err := equals(kind, value, predicate.Value, now)
+
+		if err != nil {
+
+			return false, err
+		}
+
+		return equal == (predicate.Op == OpEq), nil
+
+	case OpIn:
+
+		for _, candidate := range predicate.Values {
+
+			equal, err := equals(kind, value, candidate, now)
+
+			if err != nil {
+
+				return false, err
+			}
+
+			if equal {
+
+				return true, nil
+			}
+		}
+
+		return false, nil
+
+	case OpLt, OpGt:
+
+		comparison, err := compareWith(kind, value, predicate.Value, now)
+
+		if err != nil {
+
+			return false, err
+		}
+
+		if predicate.Op == OpLt {
+
+			return comparison < 0, nil
+		}
+
+		return comparison > 0, nil
+
+	case OpContains:
+
+		return strings.Contains(strings.ToLower(value), strings.ToLower(predicate.Value)), nil
+	}
+
+	return false, ErrInvalidValue
+}
+
+// Compare orders two field values, numbers by value and dates by time. Empty values sort after all others.
+func Compare(kind Kind, a, b string) int {
+
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+
+	switch {
+
+	case a == "" && b == "":
+
+		return 0
+
+	case a == "":
+
+		return 1
+
+	case b == "":
+
+		return -1
+	}
+
+	if kind == KindDate {
+
+		timeA, errA := ParseDate(a)
+
+		timeB, errB := ParseDate(b)
+
+		if errA == nil && errB == nil {
+
+			return compareTimes(timeA, timeB)
+		}
+	}
+
+	if numberA, errA := strconv.ParseFloat(a, 64); errA == nil {
+
+		if numberB, errB := strconv.ParseFloat(b, 64); errB == nil {
+
+			return compareNumbers(numberA, numberB)
+		}
+	}
+
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func equals(kind Kind, value, expected string, now time.Time) (bool, error) {
+
+	// a checkbox stores every checked option, one of them has to match
+	if kind == KindSelect {
+
+		for _, option := range strings.Split(value, ",") {
+
+			if strings.EqualFold(strings.TrimSpace(option), strings.TrimSpace(expected)) {
+
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	comparison, err := compareWith(kind, value, expected, now)
+
+	return comparison == 0, err
+}
+
+// compareWith compares a stored value with a predicate value. A date predicate has to be a date, "now" or "today".
+func compareWith(kind Kind, value, expected string, now time.Time) (int, error) {
+
+	value, expected = strings.TrimSpace(value), strings.TrimSpace(expected)
+
+	if kind == KindDate {
+
+		expectedTime, err := predicateDate(expected, now)
+
+		if err != nil {
+
+			return 0, err
+		}
+
+		valueTime, err := parseDateIn(value, now.Location())
+
+		// a stored value that is not a date never matches a date predicate
+		if err != nil {
+
+			return 1, nil
+		}
+
+		// a plain date is compared by day
+		if len(expected) == len("2006-01-02") || strings.EqualFold(expected, "today") {
+
+			valueTime = time.Date(valueTime.Year(), valueTime.Month(), valueTime.Day(), 0, 0, 0, 0, expectedTime.Location())
+		}
+
+		return compareTimes(valueTime, expectedTime), nil
+	}
+
+	if expectedNumber, err := strconv.ParseFloat(expected, 64); err == nil {
+
+		if valueNumber, err := strconv.ParseFloat(value, 64); err == nil {
+
+			return compareNumbers(valueNumber, expectedNumber), nil
+		}
+	}
+
+	return strings.Compare(strings.ToLower(value), strings.ToLower(expected)), nil
+}
+
+func predicateDate(value string, now time.Time) (time.Time, error) {
+
+	switch strings.ToLower(value) {
+
+	case "now":
+
+		return now, nil
+
+	case "today":
+
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), nil
+	}
+
+	parsed, err := parseDateIn(value, now.Location())
+
+	if err != nil {
+
+		return time.Time{}, ErrInvalidValue
+	}
+
+	return parsed, nil
+}
+
+// ParseDate reads a date field value in any of the layouts dates are stored in
+func ParseDate(value string) (time.Time, error) {
+
+	return parseDateIn(value, time.UTC)
+}
+
+func parseDateIn(value string, location *time.Location) (time.Time, error) {
+
+	for _, layout := range dateLayouts {
+
+		if parsed, err := time.ParseInLocation(layout, value, location); err == nil {
+
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, ErrInvalidValue
+}
+
+func compareTimes(a, b time.Time) int {
+
+	switch {
+
+	case a.Before(b):
+
+		return -1
+
+	case a.After(b):
+
+		return 1
+	}
+
+	return 0
+}
+
+func compareNumbers(a, b float64) int {
+
+	switch {
+
+	case a < b:
+
+		return -1
+
+	case a > b:
+
+		return 1
+	}
+
+	return 0
+}
+
+func defaultValue(value, fallback string) string {
+
+	if strings.TrimSpace(value) == "" {
+
+		return fallback
+	}
+
+	return strings.TrimSpace(value)
+}
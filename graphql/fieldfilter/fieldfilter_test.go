@@ -0,0 +1,94 @@
+package fieldfilter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+
+	now := time.Date(2024, 5, 10, 15, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		kind      Kind
+		value     string
+		exists    bool
+		predicate Predicate
+		expected  bool
+	}{
+		{"date after today", KindDate, "2024-05-11", true, Predicate{Op: OpGt, Value: "today"}, true},
+		{"same day is not after today", KindDate, "2024-05-10 18:00", true, Predicate{Op: OpGt, Value: "today"}, false},
+		{"date before now", KindDate, "2024-05-10T14:00:00Z", true, Predicate{Op: OpLt, Value: "now"}, true},
+		{"numbers by value", KindText, "9.5", true, Predicate{Op: OpLt, Value: "10"}, true},
+		{"text by value", KindText, "apple", true, Predicate{Op: OpLt, Value: "Banana"}, true},
+		{"text equal ignores case", KindText, "Draft", true, Predicate{Op: OpEq, Value: "draft"}, true},
+		{"checkbox option", KindSelect, "red, blue", true, Predicate{Op: OpEq, Value: "blue"}, true},
+		{"in", KindSelect, "green", true, Predicate{Op: OpIn, Values: []string{"red", "green"}}, true},
+		{"contains", KindText, "Open Air Concert", true, Predicate{Op: OpContains, Value: "air"}, true},
+		{"missing field fails eq", KindText, "", false, Predicate{Op: OpEq, Value: "x"}, false},
+		{"missing field passes ne", KindText, "", false, Predicate{Op: OpNe, Value: "x"}, true},
+		{"exists", KindText, "x", true, Predicate{Op: OpExists}, true},
+		{"empty value does not exist", KindText, " ", true, Predicate{Op: OpExists}, false},
+		{"not exists", KindText, "", false, Predicate{Op: OpExists, Value: "false"}, true},
+	}
+
+	for _, c := range cases {
+
+		matched, err := c.predicate.Match(c.kind, c.value, c.exists, now)
+
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+
+		if matched != c.expected {
+			t.Fatalf("%s: matched %v, expected %v", c.name, matched, c.expected)
+		}
+	}
+
+	if _, err := (Predicate{Op: OpGt, Value: "soon"}).Match(KindDate, "2024-05-11", true, now); err != ErrInvalidValue {
+		t.Fatalf("expected an invalid date predicate to fail, got %v", err)
+	}
+}
+
+func TestMatchInLocation(t *testing.T) {
+
+	location := time.FixedZone("UTC+5:30", 5*60*60+30*60)
+
+	// already the 11th in the tenant zone while it is still the 10th in UTC
+	now := time.Date(2024, 5, 10, 20, 0, 0, 0, time.UTC).In(location)
+
+	if matched, _ := (Predicate{Op: OpEq, Value: "today"}).Match(KindDate, "2024-05-11", true, now); !matched {
+		t.Fatalf("expected today to be the day of the tenant zone")
+	}
+
+	if matched, _ := (Predicate{Op: OpLt, Value: "now"}).Match(KindDate, "2024-05-11 01:00", true, now); !matched {
+		t.Fatalf("expected a stored time without a zone to be read in the tenant zone")
+	}
+}
+
+func TestCompare(t *testing.T) {
+
+	if Compare(KindText, "9", "10") >= 0 {
+		t.Fatalf("expected numbers to compare by value")
+	}
+
+	if Compare(KindDate, "2024-05-11", "2024-05-10 23:00") <= 0 {
+		t.Fatalf("expected dates to compare by time")
+	}
+
+	if Compare(KindText, "", "a") <= 0 || Compare(KindText, "a", "") >= 0 {
+		t.Fatalf("expected empty values to sort last")
+	}
+}
+
+func TestKindAndName(t *testing.T) {
+
+	if KindOf(6) != KindDate || KindOf(10) != KindSelect || KindOf(7) != KindText {
+		t.Fatalf("unexpected field kinds")
+	}
+
+	if NormalizeName("Start Date") != "start-date" || NormalizeName("start-date") != "start-date" {
+		t.Fatalf("expected labels and slugs to normalize alike")
+	}
+}
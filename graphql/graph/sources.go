@@ -0,0 +1,9 @@
+package graph
+
+import "github.com/vektah/gqlparser/v2/ast"
+
+// Sources returns the schema files the executable schema was generated from, so the schema can be extended at runtime
+func Sources() []*ast.Source {
+
+	return sources
+}
@@ -0,0 +1,365 @@
+package imagetransform
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/draw"
+	"math"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/nfnt/resize"
+)
+
+// how an image is fitted into the requested size
+const (
+	// scaled down to fit inside the size, the aspect ratio is kept
+	FitContain = "contain"
+
+	// scaled to cover the size and cropped to it from the center
+	FitCover = "cover"
+
+	// stretched to the size
+	FitFill = "fill"
+)
+
+const (
+	FormatJpeg = "jpeg"
+	FormatPng  = "png"
+)
+
+const (
+	DefaultQuality = 85
+
+	// largest width or height an image is transformed to
+	MaxDimension = 4096
+)
+
+var (
+	ErrInvalidTransform = errors.New("invalid image transform")
+	ErrInvalidSignature = errors.New("invalid image transform signature")
+)
+
+// Transform is the size, format and quality an image is served in, zero values keep the original
+type Transform struct {
+	Width   int
+	Height  int
+	Fit     string
+	Format  string
+	Quality int
+}
+
+// Preset is a size images may be transformed to, a zero width or height follows the aspect ratio and a preset
+// without a fit allows every fit
+type Preset struct {
+	Width  int
+	Height int
+	Fit    string
+}
+
+// DefaultPresets apply to the tenants that have not configured their own
+var DefaultPresets = []Preset{
+	{Width: 150, Height: 150},
+	{Width: 320, Height: 180},
+	{Width: 640, Height: 360},
+	{Width: 1280, Height: 720},
+	{Width: 320},
+	{Width: 640},
+	{Width: 1280},
+}
+
+// Normalize checks a transform and fills in the default fit
+func Normalize(transform Transform) (Transform, error) {
+
+	if transform.Width < 0 || transform.Height < 0 || transform.Width > MaxDimension || transform.Height > MaxDimension {
+
+		return transform, ErrInvalidTransform
+	}
+
+	if transform.Width == 0 && transform.Height == 0 {
+
+		return transform, ErrInvalidTransform
+	}
+
+	if transform.Fit == "" {
+
+		transform.Fit = FitContain
+	}
+
+	if !validFit(transform.Fit) {
+
+		return transform, ErrInvalidTransform
+	}
+
+	if transform.Format != "" && transform.Format != FormatJpeg && transform.Format != FormatPng {
+
+		return transform, ErrInvalidTransform
+	}
+
+	if transform.Quality < 0 || transform.Quality > 100 {
+
+		return transform, ErrInvalidTransform
+	}
+
+	return transform, nil
+}
+
+func validFit(fit string) bool {
+
+	return fit == FitContain || fit == FitCover || fit == FitFill
+}
+
+// Allowed reports whether the size and fit of a normalized transform is one of the presets
+func Allowed(presets []Preset, transform Transform) bool {
+
+	for _, preset := range presets {
+
+		if preset.Width == transform.Width && preset.Height == transform.Height && (preset.Fit == "" || preset.Fit == transform.Fit) {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParsePresets reads presets written one per line or comma separated as WIDTHxHEIGHT with an optional fit,
+// e.g. "150x150 cover, 640x0". Malformed presets are skipped.
+func ParsePresets(value string) []Preset {
+
+	var presets []Preset
+
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+
+		parts := strings.Fields(strings.ToLower(item))
+
+		if len(parts) == 0 || len(parts) > 2 {
+
+			continue
+		}
+
+		width, height, found := strings.Cut(parts[0], "x")
+
+		if !found {
+
+			continue
+		}
+
+		var (
+			preset Preset
+			err    error
+		)
+
+		if preset.Width, err = strconv.Atoi(width); err != nil {
+
+			continue
+		}
+
+		if preset.Height, err = strconv.Atoi(height); err != nil {
+
+			continue
+		}
+
+		if len(parts) == 2 {
+
+			preset.Fit = parts[1]
+		}
+
+		if _, err := Normalize(Transform{Width: preset.Width, Height: preset.Height, Fit: preset.Fit}); err != nil {
+
+			continue
+		}
+
+		if containsPreset(presets, preset) {
+
+			continue
+		}
+
+		presets = append(presets, preset)
+	}
+
+	return presets
+}
+
+func containsPreset(presets []Preset, preset Preset) bool {
+
+	for _, existing := range presets {
+
+		if existing == preset {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// FormatPresets writes presets back in the form ParsePresets reads, one per line
+func FormatPresets(presets []Preset) string {
+
+	lines := make([]string, len(presets))
+
+	for index, preset := range presets {
+
+		lines[index] = strconv.Itoa(preset.Width) + "x" + strconv.Itoa(preset.Height)
+
+		if preset.Fit != "" {
+
+			lines[index] += " " + preset.Fit
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// URL returns the signed image-resize url serving the image at the path with the transform applied
+func URL(secret, imagePath string, transform Transform) string {
+
+	query := url.Values{}
+
+	query.Set("name", path.Base(imagePath))
+
+	query.Set("path", path.Dir(imagePath))
+
+	query.Set("width", strconv.Itoa(transform.Width))
+
+	query.Set("height", strconv.Itoa(transform.Height))
+
+	query.Set("fit", transform.Fit)
+
+	if transform.Format != "" {
+
+		query.Set("format", transform.Format)
+	}
+
+	if transform.Quality > 0 {
+
+		query.Set("quality", strconv.Itoa(transform.Quality))
+	}
+
+	query.Set("sig", sign(secret, ObjectKey(query.Get("path"), query.Get("name")), transform))
+
+	return "/image-resize?" + query.Encode()
+}
+
+// ObjectKey joins the path and name parameters of an image-resize url into the key of the stored image
+func ObjectKey(dir, name string) string {
+
+	return strings.TrimSuffix(dir, "/") + "/" + name
+}
+
+// Verify reads the transform of an image-resize url and checks its signature. False when the url asks for no transform.
+func Verify(secret string, query url.Values) (Transform, bool, error) {
+
+	if query.Get("width") == "" && query.Get("height") == "" && query.Get("fit") == "" && query.Get("format") == "" && query.Get("quality") == "" {
+
+		return Transform{}, false, nil
+	}
+
+	var (
+		transform Transform
+		err       error
+	)
+
+	if transform.Width, err = optionalInt(query.Get("width")); err != nil {
+
+		return Transform{}, true, ErrInvalidTransform
+	}
+
+	if transform.Height, err = optionalInt(query.Get("height")); err != nil {
+
+		return Transform{}, true, ErrInvalidTransform
+	}
+
+	if transform.Quality, err = optionalInt(query.Get("quality")); err != nil {
+
+		return Transform{}, true, ErrInvalidTransform
+	}
+
+	transform.Fit, transform.Format = query.Get("fit"), query.Get("format")
+
+	expected := sign(secret, ObjectKey(query.Get("path"), query.Get("name")), transform)
+
+	if secret == "" || !hmac.Equal([]byte(expected), []byte(query.Get("sig"))) {
+
+		return Transform{}, true, ErrInvalidSignature
+	}
+
+	if transform, err = Normalize(transform); err != nil {
+
+		return Transform{}, true, err
+	}
+
+	return transform, true, nil
+}
+
+func optionalInt(value string) (int, error) {
+
+	if value == "" {
+
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+func sign(secret, key string, transform Transform) string {
+
+	mac := hmac.New(sha256.New, []byte(secret))
+
+	mac.Write([]byte(strings.Join([]string{key, strconv.Itoa(transform.Width), strconv.Itoa(transform.Height), transform.Fit, transform.Format, strconv.Itoa(transform.Quality)}, "|")))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Apply resizes an image as a normalized transform asks for
+func Apply(img image.Image, transform Transform) image.Image {
+
+	width, height := uint(transform.Width), uint(transform.Height)
+
+	// with one side left open the other follows the aspect ratio whatever the fit
+	if width == 0 || height == 0 {
+
+		return resize.Resize(width, height, img, resize.Lanczos3)
+	}
+
+	switch transform.Fit {
+
+	case FitFill:
+
+		return resize.Resize(width, height, img, resize.Lanczos3)
+
+	case FitCover:
+
+		return cover(img, transform.Width, transform.Height)
+	}
+
+	return resize.Thumbnail(width, height, img, resize.Lanczos3)
+}
+
+// cover scales the image to cover the size and crops the overflow evenly from both sides
+func cover(img image.Image, width, height int) image.Image {
+
+	bounds := img.Bounds()
+
+	scale := math.Max(float64(width)/float64(bounds.Dx()), float64(height)/float64(bounds.Dy()))
+
+	scaledWidth := int(math.Ceil(float64(bounds.Dx()) * scale))
+
+	scaledHeight := int(math.Ceil(float64(bounds.Dy()) * scale))
+
+	scaled := resize.Resize(uint(scaledWidth), uint(scaledHeight), img, resize.Lanczos3)
+
+	offset := image.Pt((scaledWidth-width)/2, (scaledHeight-height)/2).Add(scaled.Bounds().Min)
+
+	cropped := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	draw.Draw(cropped, cropped.Bounds(), scaled, offset, draw.Src)
+
+	return cropped
+}
@@ -0,0 +1,135 @@
+package imagetransform
+
+import (
+	"image"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePresets(t *testing.T) {
+
+	presets := ParsePresets("150x150 cover, 640x0\n 640X0 ,0x0, 10x10 zoom, 5000x10, abc, 320x180 fill extra")
+
+	expected := []Preset{{Width: 150, Height: 150, Fit: FitCover}, {Width: 640}}
+
+	if !reflect.DeepEqual(presets, expected) {
+		t.Fatalf("unexpected presets %v", presets)
+	}
+
+	if FormatPresets(presets) != "150x150 cover\n640x0" {
+		t.Fatalf("unexpected formatted presets %q", FormatPresets(presets))
+	}
+}
+
+func TestAllowed(t *testing.T) {
+
+	presets := []Preset{{Width: 150, Height: 150, Fit: FitCover}, {Width: 640}}
+
+	if !Allowed(presets, Transform{Width: 150, Height: 150, Fit: FitCover}) {
+		t.Fatalf("expected the preset size and fit to be allowed")
+	}
+
+	if Allowed(presets, Transform{Width: 150, Height: 150, Fit: FitFill}) {
+		t.Fatalf("expected another fit of a preset with a fit to be rejected")
+	}
+
+	if !Allowed(presets, Transform{Width: 640, Fit: FitFill}) {
+		t.Fatalf("expected every fit of a preset without a fit to be allowed")
+	}
+
+	if Allowed(presets, Transform{Width: 641, Fit: FitContain}) {
+		t.Fatalf("expected an arbitrary size to be rejected")
+	}
+}
+
+func TestSignedURLVerifies(t *testing.T) {
+
+	transform, err := Normalize(Transform{Width: 320, Height: 180, Format: FormatPng, Quality: 70})
+
+	if err != nil || transform.Fit != FitContain {
+		t.Fatalf("unexpected normalized transform %v %v", transform, err)
+	}
+
+	signed := URL("secret", "tenant/media/cover.jpg", transform)
+
+	parsed, err := url.Parse(signed)
+
+	if err != nil || parsed.Path != "/image-resize" {
+		t.Fatalf("unexpected url %q", signed)
+	}
+
+	query := parsed.Query()
+
+	if ObjectKey(query.Get("path"), query.Get("name")) != "tenant/media/cover.jpg" {
+		t.Fatalf("unexpected object key in %q", signed)
+	}
+
+	verified, ok, err := Verify("secret", query)
+
+	if err != nil || !ok || verified != transform {
+		t.Fatalf("expected the signed url to verify, got %v %v %v", verified, ok, err)
+	}
+
+	if _, _, err := Verify("other", query); err != ErrInvalidSignature {
+		t.Fatalf("expected another secret to be rejected, got %v", err)
+	}
+
+	query.Set("width", "3200")
+
+	if _, _, err := Verify("secret", query); err != ErrInvalidSignature {
+		t.Fatalf("expected a changed size to be rejected, got %v", err)
+	}
+
+	query.Set("width", "320")
+
+	query.Set("name", "other.jpg")
+
+	if _, _, err := Verify("secret", query); err != ErrInvalidSignature {
+		t.Fatalf("expected another image to be rejected, got %v", err)
+	}
+}
+
+func TestVerifyUnsigned(t *testing.T) {
+
+	if _, ok, err := Verify("secret", url.Values{"name": {"a.png"}, "path": {"media"}}); ok || err != nil {
+		t.Fatalf("expected a url without a transform to pass through, got %v %v", ok, err)
+	}
+
+	if _, ok, err := Verify("secret", url.Values{"name": {"a.png"}, "path": {"media"}, "width": {"100"}, "height": {"100"}}); !ok || err != ErrInvalidSignature {
+		t.Fatalf("expected an unsigned size to be rejected, got %v %v", ok, err)
+	}
+
+	if !strings.Contains(URL("", "media/a.png", Transform{Width: 10, Fit: FitContain}), "sig=") {
+		t.Fatalf("expected the url to carry a signature")
+	}
+
+	if _, _, err := Verify("", url.Values{"name": {"a.png"}, "path": {"media"}, "width": {"10"}, "fit": {FitContain}, "sig": {""}}); err != ErrInvalidSignature {
+		t.Fatalf("expected transforms to be rejected without a secret, got %v", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+
+	source := image.NewRGBA(image.Rect(0, 0, 400, 200))
+
+	cases := []struct {
+		transform     Transform
+		width, height int
+	}{
+		{Transform{Width: 100, Height: 100, Fit: FitContain}, 100, 50},
+		{Transform{Width: 100, Height: 100, Fit: FitCover}, 100, 100},
+		{Transform{Width: 100, Height: 100, Fit: FitFill}, 100, 100},
+		{Transform{Width: 200, Fit: FitCover}, 200, 100},
+	}
+
+	for _, c := range cases {
+
+		bounds := Apply(source, c.transform).Bounds()
+
+		if bounds.Dx() != c.width || bounds.Dy() != c.height {
+			t.Fatalf("%v: expected %vx%v, got %vx%v", c.transform, c.width, c.height, bounds.Dx(), bounds.Dy())
+		}
+	}
+}
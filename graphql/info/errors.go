@@ -20,4 +20,43 @@ var (
 	ErrNoRowsAffected       = errors.New("no rows affected")
 	ErrUpdateViewCount      = errors.New("failed to update view count")
 	ErrRecordNotFound       = errors.New("record not found")
+	ErrChannelNotFound      = errors.New("channel not found")
+	ErrInvalidEntryStatus   = errors.New("invalid entry status")
+	ErrCreateEntry          = errors.New("failed to create entry")
+	ErrUpdateEntry          = errors.New("failed to update entry")
+	ErrEntryField           = errors.New("field does not belong to the channel of the entry")
+	ErrEntryCategory        = errors.New("category not found")
+	ErrInvalidCursor        = errors.New("invalid cursor")
+	ErrInvalidSortKey       = errors.New("unsupported sort key")
+	ErrInvalidPageArgs      = errors.New("invalid pagination arguments")
+	ErrCursorSortMismatch   = errors.New("cursor does not match the requested sort")
+	ErrApiKeyScope          = errors.New("api key does not have the required scope")
+	ErrApiKeyChannel        = errors.New("api key has no access to the channel")
+	ErrFetchApiKeyScope     = errors.New("failed to get api key scope")
+	ErrRateLimit            = errors.New("api key rate limit exceeded")
+	ErrMemberCredentials    = errors.New("invalid member credentials")
+	ErrMemberInactive       = errors.New("member is inactive")
+	ErrMemberLoginPerm      = errors.New("member password login is disabled")
+	ErrMemberToken          = errors.New("invalid or expired member token")
+	ErrMemberRestricted     = errors.New("entry is restricted to other member groups")
+	ErrMemberExists         = errors.New("member already exists")
+	ErrMemberPassword       = errors.New("password is required")
+	ErrMemberGroup          = errors.New("no member group to register the member in")
+	ErrVerificationToken    = errors.New("invalid or expired verification token")
+	ErrVerificationMail     = errors.New("member is registered but the verification email could not be sent, request a new one")
+	ErrSearchQuery          = errors.New("search query has no words to search for")
+	ErrFieldPredicate       = errors.New("field predicate needs a field id or name and a valid value")
+	ErrViewStatsRange       = errors.New("view stats range must not start after it ends or span more than 366 days")
+	ErrMemberSignIn         = errors.New("member sign in required")
+	ErrProfileSlug          = errors.New("profile slug may only hold lowercase letters, digits and hyphens")
+	ErrProfileSlugTaken     = errors.New("profile slug is already taken")
+	ErrProfileClaimed       = errors.New("profile is already claimed")
+	ErrClaimToken           = errors.New("invalid or expired claim token")
+	ErrNoteContent          = errors.New("note content must not be empty or longer than 10000 characters")
+	ErrHighlightConfig      = errors.New("highlight configuration is required")
+	ErrImageTransform       = errors.New("image transform needs a width or height up to 4096 and a quality from 1 to 100")
+	ErrImagePreset          = errors.New("image size is not one of the allowed presets")
+	ErrImageSigningSecret   = errors.New("image signing secret is not configured")
+	ErrImageSignature       = errors.New("image transform url is not signed")
+	ErrPreviewToken         = errors.New("invalid or expired preview token")
 )
@@ -0,0 +1,348 @@
+package limits
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	DefaultMaxComplexity = 1000
+	DefaultMaxDepth      = 12
+	DefaultAPQCacheSize  = 1000
+
+	// cached query responses and the seconds they are kept, content changes drop them earlier
+	DefaultResponseCacheSize = 1000
+	DefaultResponseCacheTTL  = 300
+
+	// page size assumed for list fields queried without a limit
+	defaultListSize = 100
+
+	// largest page size taken into account, matches the connection page size cap
+	maxListSize = 100
+
+	errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+
+	// the typed channel queries of a tenant schema are charged as these fields
+	TypedEntriesField = "Query.typedEntries"
+	TypedEntryField   = "Query.typedEntry"
+)
+
+// default cost of the fields that fan out to extra queries, every other field costs 1
+var defaultFieldCosts = map[string]int{
+	"Query.ChannelList":                  2,
+	"Query.ChannelListConnection":        2,
+	"Query.ChannelEntriesList":           5,
+	"Query.ChannelEntriesListConnection": 5,
+	"Query.ChannelEntryDetail":           3,
+	"Query.CategoryList":                 2,
+	"Query.CategoryTree":                 3,
+	"Query.MembersList":                  2,
+	"Query.MembersListConnection":        2,
+	"Query.SearchEntries":                5,
+	"Query.PopularEntries":               4,
+	"Query.MemberNotes":                  2,
+	TypedEntriesField:                    5,
+	TypedEntryField:                      3,
+	"ChannelEntries.categories":          3,
+	"ChannelEntries.authorDetails":       2,
+	"ChannelEntries.memberProfile":       2,
+	"ChannelEntries.additionalFields":    5,
+	"ChannelEntries.related":             2,
+}
+
+// list fields, their cost and the cost of their selections are multiplied by the requested page size
+var listFields = map[string]bool{
+	"Query.ChannelList":                  true,
+	"Query.ChannelListConnection":        true,
+	"Query.ChannelEntriesList":           true,
+	"Query.ChannelEntriesListConnection": true,
+	"Query.CategoryList":                 true,
+	"Query.MembersList":                  true,
+	"Query.MembersListConnection":        true,
+	"Query.SearchEntries":                true,
+	"Query.PopularEntries":               true,
+	"Query.MemberNotes":                  true,
+	TypedEntriesField:                    true,
+	"CategoryNode.children":              true,
+	"ChannelEntries.related":             true,
+}
+
+// page size assumed for the list fields whose resolvers default to a smaller page than defaultListSize
+var defaultListSizes = map[string]int{
+	"Query.SearchEntries":  10,
+	"Query.PopularEntries": 10,
+	"Query.MemberNotes":    10,
+
+	// children assumed per category, every nested level of a tree multiplies the cost again
+	"CategoryNode.children": 5,
+
+	// related entries returned per entry when no limit is given
+	"ChannelEntries.related": 5,
+}
+
+type Config struct {
+	MaxComplexity     int
+	MaxDepth          int
+	APQCacheSize      int
+	ResponseCacheSize int
+	ResponseCacheTTL  int
+	FieldCosts        map[string]int
+}
+
+// LoadConfig reads the limits from the environment. GRAPHQL_FIELD_COSTS overrides single field costs,
+// e.g. GRAPHQL_FIELD_COSTS = 'ChannelEntries.categories=5,Query.CategoryList=3'
+func LoadConfig() Config {
+
+	config := Config{
+		MaxComplexity:     envInt("GRAPHQL_MAX_COMPLEXITY", DefaultMaxComplexity),
+		MaxDepth:          envInt("GRAPHQL_MAX_DEPTH", DefaultMaxDepth),
+		APQCacheSize:      envInt("GRAPHQL_APQ_CACHE_SIZE", DefaultAPQCacheSize),
+		ResponseCacheSize: envInt("GRAPHQL_RESPONSE_CACHE_SIZE", DefaultResponseCacheSize),
+		ResponseCacheTTL:  envInt("GRAPHQL_RESPONSE_CACHE_TTL", DefaultResponseCacheTTL),
+		FieldCosts:        make(map[string]int, len(defaultFieldCosts)),
+	}
+
+	for field, cost := range defaultFieldCosts {
+
+		config.FieldCosts[field] = cost
+	}
+
+	for field, cost := range ParseFieldCosts(os.Getenv("GRAPHQL_FIELD_COSTS")) {
+
+		config.FieldCosts[field] = cost
+	}
+
+	return config
+}
+
+// ParseFieldCosts parses a comma separated list of Type.field=cost pairs, malformed pairs are skipped
+func ParseFieldCosts(value string) map[string]int {
+
+	costs := make(map[string]int)
+
+	for _, pair := range strings.Split(value, ",") {
+
+		field, cost, found := strings.Cut(strings.TrimSpace(pair), "=")
+
+		if !found || !strings.Contains(field, ".") {
+
+			continue
+		}
+
+		costValue, err := strconv.Atoi(strings.TrimSpace(cost))
+
+		if err != nil || costValue < 0 {
+
+			continue
+		}
+
+		costs[strings.TrimSpace(field)] = costValue
+	}
+
+	return costs
+}
+
+func envInt(key string, fallback int) int {
+
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+
+	if err != nil || value <= 0 {
+
+		return fallback
+	}
+
+	return value
+}
+
+// CostFields is implemented by the schemas that add fields at runtime, it names the configured field a runtime field
+// is charged as
+type CostFields interface {
+	CostField(typeName, fieldName string) (string, bool)
+}
+
+// costSchema overrides the generated complexity functions with the configured field costs
+type costSchema struct {
+	graphql.ExecutableSchema
+
+	costs map[string]int
+}
+
+func WithFieldCosts(schema graphql.ExecutableSchema, costs map[string]int) graphql.ExecutableSchema {
+
+	return costSchema{ExecutableSchema: schema, costs: costs}
+}
+
+func (schema costSchema) Complexity(typeName, fieldName string, childComplexity int, args map[string]interface{}) (int, bool) {
+
+	field := typeName + "." + fieldName
+
+	if runtime, ok := schema.ExecutableSchema.(CostFields); ok {
+
+		if costField, ok := runtime.CostField(typeName, fieldName); ok {
+
+			field = costField
+		}
+	}
+
+	cost, ok := schema.costs[field]
+
+	if !ok {
+
+		cost = 1
+	}
+
+	if listFields[field] {
+
+		return (cost + childComplexity) * listSize(field, args), true
+	}
+
+	return cost + childComplexity, true
+}
+
+// listSize reads the page size of a list field from first/last, its limit or the limit of its filter argument
+func listSize(field string, args map[string]interface{}) int {
+
+	size := 0
+
+	for _, key := range []string{"first", "last", "limit"} {
+
+		if value, ok := args[key].(int); ok {
+
+			size = value
+		}
+	}
+
+	for _, key := range []string{"filter", "commonFilter"} {
+
+		if filter, ok := args[key].(map[string]interface{}); ok {
+
+			if value, ok := filter["limit"].(int); ok {
+
+				size = value
+			}
+		}
+	}
+
+	switch {
+
+	case size <= 0:
+
+		if defaultSize, ok := defaultListSizes[field]; ok {
+
+			return defaultSize
+		}
+
+		return defaultListSize
+
+	case size > maxListSize:
+
+		return maxListSize
+	}
+
+	return size
+}
+
+// DepthLimit rejects operations that nest selections deeper than Max, introspection fields are not counted
+type DepthLimit struct {
+	Max int
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = DepthLimit{}
+
+func (limit DepthLimit) ExtensionName() string {
+
+	return "DepthLimit"
+}
+
+func (limit DepthLimit) Validate(schema graphql.ExecutableSchema) error {
+
+	return nil
+}
+
+func (limit DepthLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+
+	if rc.Doc == nil {
+
+		return nil
+	}
+
+	op := rc.Doc.Operations.ForName(rc.OperationName)
+
+	if op == nil {
+
+		return nil
+	}
+
+	if depth := SelectionDepth(op.SelectionSet); depth > limit.Max {
+
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, limit.Max)
+
+		errcode.Set(err, errDepthLimit)
+
+		return err
+	}
+
+	return nil
+}
+
+func SelectionDepth(selectionSet ast.SelectionSet) int {
+
+	return selectionDepth(selectionSet, map[string]bool{})
+}
+
+func selectionDepth(selectionSet ast.SelectionSet, visiting map[string]bool) int {
+
+	maxDepth := 0
+
+	for _, selection := range selectionSet {
+
+		depth := 0
+
+		switch s := selection.(type) {
+
+		case *ast.Field:
+
+			if strings.HasPrefix(s.Name, "__") {
+
+				continue
+			}
+
+			depth = 1 + selectionDepth(s.SelectionSet, visiting)
+
+		case *ast.InlineFragment:
+
+			depth = selectionDepth(s.SelectionSet, visiting)
+
+		case *ast.FragmentSpread:
+
+			// validation already rejects fragment cycles, this only guards the walk
+			if s.Definition == nil || visiting[s.Name] {
+
+				continue
+			}
+
+			visiting[s.Name] = true
+
+			depth = selectionDepth(s.Definition.SelectionSet, visiting)
+
+			delete(visiting, s.Name)
+		}
+
+		if depth > maxDepth {
+
+			maxDepth = depth
+		}
+	}
+
+	return maxDepth
+}
@@ -0,0 +1,155 @@
+package limits
+
+import (
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestParseFieldCosts(t *testing.T) {
+
+	costs := ParseFieldCosts(" ChannelEntries.categories = 5,Query.CategoryList=3,broken=2,Query.MembersList=-1,Query.ChannelList=x ")
+
+	if len(costs) != 2 || costs["ChannelEntries.categories"] != 5 || costs["Query.CategoryList"] != 3 {
+		t.Fatalf("unexpected costs %v", costs)
+	}
+}
+
+func TestListFieldComplexity(t *testing.T) {
+
+	schema := costSchema{costs: map[string]int{"Query.ChannelEntriesListConnection": 5}}
+
+	complexity, _ := schema.Complexity("Query", "ChannelEntriesListConnection", 10, map[string]interface{}{"first": 20})
+
+	if complexity != (5+10)*20 {
+		t.Fatalf("expected %d, got %d", (5+10)*20, complexity)
+	}
+
+	complexity, _ = schema.Complexity("Query", "ChannelEntriesList", 1, map[string]interface{}{"commonFilter": map[string]interface{}{"limit": 1000}})
+
+	if complexity != 2*maxListSize {
+		t.Fatalf("expected page size to be capped, got %d", complexity)
+	}
+
+	complexity, _ = schema.Complexity("Query", "SearchEntries", 4, map[string]interface{}{"limit": 20})
+
+	if complexity != (1+4)*20 {
+		t.Fatalf("expected the limit argument to set the page size, got %d", complexity)
+	}
+
+	complexity, _ = schema.Complexity("Query", "SearchEntries", 4, nil)
+
+	if complexity != (1+4)*defaultListSizes["Query.SearchEntries"] {
+		t.Fatalf("expected the default page size of the field, got %d", complexity)
+	}
+
+	complexity, _ = costSchema{costs: defaultFieldCosts}.Complexity("Query", "PopularEntries", 2, nil)
+
+	if complexity != (defaultFieldCosts["Query.PopularEntries"]+2)*defaultListSizes["Query.PopularEntries"] {
+		t.Fatalf("expected the popular entries to be charged per entry, got %d", complexity)
+	}
+
+	complexity, _ = costSchema{costs: defaultFieldCosts}.Complexity("Query", "MemberNotes", 3, map[string]interface{}{"limit": 25})
+
+	if complexity != (defaultFieldCosts["Query.MemberNotes"]+3)*25 {
+		t.Fatalf("expected the member notes to be charged per note, got %d", complexity)
+	}
+
+	complexity, _ = schema.Complexity("CategoryNode", "children", 3, nil)
+
+	if complexity != (1+3)*defaultListSizes["CategoryNode.children"] {
+		t.Fatalf("expected the children of a category node to be multiplied, got %d", complexity)
+	}
+
+	complexity, _ = costSchema{costs: defaultFieldCosts}.Complexity("ChannelEntries", "related", 4, nil)
+
+	if complexity != (defaultFieldCosts["ChannelEntries.related"]+4)*defaultListSizes["ChannelEntries.related"] {
+		t.Fatalf("expected the related entries to be charged per entry, got %d", complexity)
+	}
+
+	complexity, _ = schema.Complexity("ChannelEntries", "title", 0, nil)
+
+	if complexity != 1 {
+		t.Fatalf("expected default cost 1, got %d", complexity)
+	}
+}
+
+// typedSchema charges its blogEntries query as the typed entry list
+type typedSchema struct {
+	graphql.ExecutableSchema
+}
+
+func (typedSchema) CostField(typeName, fieldName string) (string, bool) {
+
+	if typeName == "Query" && fieldName == "blogEntries" {
+
+		return TypedEntriesField, true
+	}
+
+	return "", false
+}
+
+func TestRuntimeFieldComplexity(t *testing.T) {
+
+	schema := costSchema{ExecutableSchema: typedSchema{}, costs: map[string]int{TypedEntriesField: 5}}
+
+	complexity, _ := schema.Complexity("Query", "blogEntries", 2, map[string]interface{}{"limit": 10})
+
+	if complexity != (5+2)*10 {
+		t.Fatalf("expected the typed query to be charged as %s, got %d", TypedEntriesField, complexity)
+	}
+}
+
+func TestSelectionDepth(t *testing.T) {
+
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		query {
+			ChannelEntriesList {
+				channelEntriesList { ...entry }
+			}
+			__schema { types { fields { name } } }
+		}
+		fragment entry on ChannelEntries {
+			id
+			categories { id }
+		}`})
+
+	if err != nil {
+		t.Fatalf("parse query: %v", err)
+	}
+
+	for _, fragment := range doc.Fragments {
+
+		for _, operation := range doc.Operations {
+
+			resolveSpreads(operation.SelectionSet, fragment)
+		}
+	}
+
+	if depth := SelectionDepth(doc.Operations[0].SelectionSet); depth != 4 {
+		t.Fatalf("expected depth 4, got %d", depth)
+	}
+}
+
+// the parser leaves fragment spreads unresolved, validation normally links them
+func resolveSpreads(selectionSet ast.SelectionSet, fragment *ast.FragmentDefinition) {
+
+	for _, selection := range selectionSet {
+
+		switch s := selection.(type) {
+
+		case *ast.Field:
+
+			resolveSpreads(s.SelectionSet, fragment)
+
+		case *ast.FragmentSpread:
+
+			if s.Name == fragment.Name {
+
+				s.Definition = fragment
+			}
+		}
+	}
+}
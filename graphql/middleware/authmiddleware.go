@@ -5,17 +5,60 @@ import (
 	"errors"
 	"net/http"
 	"spurt-cms/graphql/controller"
+	"spurt-cms/graphql/dataloader"
 	"spurt-cms/graphql/info"
 	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/ratelimit"
+	"spurt-cms/graphql/usage"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/gin-gonic/gin"
 	"github.com/spurtcms/team"
 	"gorm.io/gorm"
 )
 
-func AuthMiddleware(ctx context.Context, _ interface{}, next graphql.Resolver) (interface{}, error) {
+// how often the usage counters of the api keys are written to the database
+const usageFlushInterval = 30 * time.Second
+
+var (
+	apiKeyLimiter = ratelimit.NewLimiter()
+
+	apiKeyUsage = usage.NewMeter()
+)
+
+// apiKeyAuth is the result of authenticating the api key of an operation, it is shared by every @auth field of the operation
+type apiKeyAuth struct {
+	tokenId       int
+	tenantDetails team.TblUser
+	scope         controller.ApiKeyScope
+	status        int
+	retryAfter    int
+	err           error
+
+	// an operation is counted once as an error however many of its fields fail
+	failed sync.Once
+}
+
+type apiKeyAuthKey struct{}
+
+func (auth *apiKeyAuth) fail() {
+
+	if auth.tokenId == 0 {
+
+		return
+	}
+
+	auth.failed.Do(func() {
+
+		apiKeyUsage.Error(auth.tokenId, auth.tenantDetails.TenantId, time.Now().UTC())
+	})
+}
+
+func AuthMiddleware(ctx context.Context, _ interface{}, next graphql.Resolver, requires model.Scope) (interface{}, error) {
 
 	c, ok := ctx.Value(controller.GinContext).(*gin.Context)
 
@@ -28,43 +71,94 @@ func AuthMiddleware(ctx context.Context, _ interface{}, next graphql.Resolver) (
 		return nil, info.ErrGinCtx
 	}
 
+	auth := dataloader.Scoped(ctx, apiKeyAuthKey{}, func() *apiKeyAuth {
+
+		return authenticate(c)
+	})
+
+	if auth.err != nil {
+
+		controller.ErrorLog.Printf("%v", auth.err)
+
+		if auth.retryAfter > 0 {
+
+			c.Header("Retry-After", strconv.Itoa(auth.retryAfter))
+		}
+
+		c.AbortWithStatus(auth.status)
+
+		return nil, auth.err
+	}
+
+	c.Set("tenantDetails", auth.tenantDetails)
+
+	c.Set("apiKeyScope", auth.scope)
+
+	if !auth.scope.Allows(requires) {
+
+		auth.fail()
+
+		c.AbortWithStatus(http.StatusForbidden)
+
+		return nil, info.ErrApiKeyScope
+	}
+
+	res, err := next(ctx)
+
+	if err != nil {
+
+		auth.fail()
+	}
+
+	return res, err
+}
+
+func authenticate(c *gin.Context) *apiKeyAuth {
+
 	var graphqlSettings model.TblGraphqlSettings
 
 	err := AuthenticateApiKey(c, &graphqlSettings)
 
 	if err != nil {
 
-		controller.ErrorLog.Printf("%v", err)
+		return &apiKeyAuth{status: http.StatusBadRequest, err: err}
+	}
+
+	now := time.Now().UTC()
+
+	apiKeyUsage.Request(graphqlSettings.Id, graphqlSettings.TenantId, now)
 
-		c.AbortWithStatus(http.StatusBadRequest)
+	if allowed, wait := apiKeyLimiter.Allow(graphqlSettings.Id, graphqlSettings.RateLimit, graphqlSettings.RateBurst, now); !allowed {
 
-		return nil, err
+		apiKeyUsage.Error(graphqlSettings.Id, graphqlSettings.TenantId, now)
+
+		return &apiKeyAuth{status: http.StatusTooManyRequests, retryAfter: ratelimit.RetryAfter(wait), err: info.ErrRateLimit}
 	}
 
 	tenantDetails, err := controller.NewTeamWP.UserDetails(team.Team{TenantId: graphqlSettings.TenantId})
 
 	if err != nil {
 
-		if err == gorm.ErrRecordNotFound {
-
-			controller.ErrorLog.Printf("%v", info.ErrTenantId)
+		apiKeyUsage.Error(graphqlSettings.Id, graphqlSettings.TenantId, now)
 
-			c.AbortWithStatus(http.StatusBadRequest)
+		if err == gorm.ErrRecordNotFound {
 
-			return nil, err
+			return &apiKeyAuth{status: http.StatusBadRequest, err: info.ErrTenantId}
 		}
 
-		controller.ErrorLog.Printf("%v", err)
-
-		c.AbortWithStatus(http.StatusInternalServerError)
+		return &apiKeyAuth{status: http.StatusInternalServerError, err: err}
+	}
 
-		return nil, err
+	return &apiKeyAuth{tokenId: graphqlSettings.Id, tenantDetails: tenantDetails, scope: controller.NewApiKeyScope(graphqlSettings)}
+}
 
-	}
+// StartUsageFlush writes the usage counters of the api keys to the database in the background
+func StartUsageFlush() {
 
-	c.Set("tenantDetails", tenantDetails)
+	go apiKeyUsage.Flush(usageFlushInterval, model.Model.SaveApiKeyUsage, func(err error) {
 
-	return next(ctx)
+		controller.ErrorLog.Printf("%v", err)
+	})
 }
 
 func AuthenticateApiKey(c *gin.Context, graphqlSettings *model.TblGraphqlSettings) error {
@@ -101,6 +195,36 @@ func AuthenticateApiKey(c *gin.Context, graphqlSettings *model.TblGraphqlSetting
 
 }
 
+// WebsocketInit authenticates a subscription connection. Browsers can not send headers on the websocket
+// handshake, so the api key may also be passed in the connection_init payload.
+func WebsocketInit(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+
+	c, ok := ctx.Value(controller.GinContext).(*gin.Context)
+
+	if !ok {
+
+		controller.ErrorLog.Printf("%v", info.ErrGinCtx)
+
+		return ctx, nil, info.ErrGinCtx
+	}
+
+	if apiKey := initPayload.GetString("ApiKey"); apiKey != "" {
+
+		c.Request.Header.Set("ApiKey", apiKey)
+	}
+
+	var graphqlSettings model.TblGraphqlSettings
+
+	if err := AuthenticateApiKey(c, &graphqlSettings); err != nil {
+
+		controller.ErrorLog.Printf("%v", err)
+
+		return ctx, nil, err
+	}
+
+	return ctx, nil, nil
+}
+
 func LimitRequestBodySize(limit int64) gin.HandlerFunc {
 
 	return func(c *gin.Context) {
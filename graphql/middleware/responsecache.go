@@ -0,0 +1,277 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"spurt-cms/events"
+	"spurt-cms/graphql/controller"
+	"spurt-cms/graphql/dataloader"
+	"spurt-cms/graphql/model"
+	"spurt-cms/graphql/responsecache"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// ResponseCache serves repeated query operations of a tenant from memory. Responses carry an ETag, a conditional
+// request with a matching If-None-Match header gets a 304. The cached responses of a tenant are dropped when one of
+// its entries, channels, categories or members changes.
+//
+// The cache lives in the memory of each server and the change events are only raised in the process that made the
+// change. When several servers share a database, one of them keeps serving its cached responses until they expire,
+// so such deployments should keep GRAPHQL_RESPONSE_CACHE_TTL short or set GRAPHQL_RESPONSE_CACHE_SIZE to 0.
+type ResponseCache struct {
+	cache *responsecache.Cache
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = &ResponseCache{}
+
+func NewResponseCache(size int, ttl time.Duration) *ResponseCache {
+
+	return &ResponseCache{cache: responsecache.New(size, ttl)}
+}
+
+func (*ResponseCache) ExtensionName() string {
+
+	return "ResponseCache"
+}
+
+func (*ResponseCache) Validate(graphql.ExecutableSchema) error {
+
+	return nil
+}
+
+// InterceptOperation must run inside the request scope of the operation, the api key is then authenticated once
+// whether the response is served from the cache or not
+func (rc *ResponseCache) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+
+	opCtx := graphql.GetOperationContext(ctx)
+
+	c, ok := ctx.Value(controller.GinContext).(*gin.Context)
+
+	if !ok || opCtx.Operation == nil || opCtx.Operation.Operation != ast.Query || c.IsWebsocket() {
+
+		return next(ctx)
+	}
+
+	// the entries a signed in member sees depend on the member groups
+	if c.GetHeader("Authorization") != "" {
+
+		return next(ctx)
+	}
+
+	// a preview shows the current draft of an entry to the holder of its token only
+	if previewsEntry(opCtx) {
+
+		return next(ctx)
+	}
+
+	// no content change is raised when such a response goes out of date
+	if timeDependent(opCtx) {
+
+		return next(ctx)
+	}
+
+	// the @auth directive reports the failure when the operation runs
+	auth := dataloader.Scoped(ctx, apiKeyAuthKey{}, func() *apiKeyAuth {
+
+		return authenticate(c)
+	})
+
+	if auth.err != nil || !auth.scope.Allows(model.ScopeRead) {
+
+		return next(ctx)
+	}
+
+	var query strings.Builder
+
+	formatter.NewFormatter(&query).FormatQueryDocument(opCtx.Doc)
+
+	tenantId := auth.tenantDetails.TenantId
+
+	key, err := responsecache.Key(tenantId, auth.scope.CacheKey(), query.String(), opCtx.OperationName, opCtx.Variables)
+
+	if err != nil {
+
+		return next(ctx)
+	}
+
+	if body, etag, ok := rc.cache.Get(tenantId, key, time.Now()); ok {
+
+		// the @auth directive of the cached fields is not run, so the tenant is set the same way it would
+		c.Set("tenantDetails", auth.tenantDetails)
+
+		c.Set("apiKeyScope", auth.scope)
+
+		writeCacheHeaders(c, etag)
+
+		served := false
+
+		return func(ctx context.Context) *graphql.Response {
+
+			if served {
+
+				return nil
+			}
+
+			served = true
+
+			return &graphql.Response{Data: body}
+		}
+	}
+
+	// read before the operation runs, a change while it runs keeps its response out of the cache
+	generation := rc.cache.Generation(tenantId)
+
+	responses := next(ctx)
+
+	first := true
+
+	return func(ctx context.Context) *graphql.Response {
+
+		response := responses(ctx)
+
+		if !first || response == nil {
+
+			return response
+		}
+
+		first = false
+
+		// partial results and deferred responses are not cached
+		if len(response.Errors) > 0 || response.HasNext != nil || len(response.Data) == 0 || string(response.Data) == "null" {
+
+			return response
+		}
+
+		body := append([]byte(nil), response.Data...)
+
+		writeCacheHeaders(c, rc.cache.Set(tenantId, key, body, generation, time.Now()))
+
+		return response
+	}
+}
+
+func previewsEntry(opCtx *graphql.OperationContext) bool {
+
+	for _, field := range graphql.CollectFields(opCtx, opCtx.Operation.SelectionSet, []string{"Query"}) {
+
+		if field.Arguments.ForName("previewToken") != nil {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// the queries answered from the entry views, which are counted without raising a change event
+var viewCountQueries = map[string]bool{"PopularEntries": true, "EntryViewStats": true}
+
+// timeDependent reports whether a response of the operation changes with the clock rather than the content, view
+// counts and field predicates on "now" or "today"
+func timeDependent(opCtx *graphql.OperationContext) bool {
+
+	for _, field := range graphql.CollectFields(opCtx, opCtx.Operation.SelectionSet, []string{"Query"}) {
+
+		if viewCountQueries[field.Name] {
+
+			return true
+		}
+
+		predicates, _ := field.ArgumentMap(opCtx.Variables)["fieldFilter"].([]interface{})
+
+		for _, predicate := range predicates {
+
+			values, _ := predicate.(map[string]interface{})
+
+			if relativeDate(values["value"]) {
+
+				return true
+			}
+
+			list, _ := values["values"].([]interface{})
+
+			for _, value := range list {
+
+				if relativeDate(value) {
+
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func relativeDate(value interface{}) bool {
+
+	text, _ := value.(string)
+
+	text = strings.TrimSpace(text)
+
+	return strings.EqualFold(text, "now") || strings.EqualFold(text, "today")
+}
+
+// writeCacheHeaders lets clients keep the response but revalidate it on every use, the server drops it as soon as the
+// content changes
+func writeCacheHeaders(c *gin.Context, etag string) {
+
+	c.Header("ETag", etag)
+
+	c.Header("Cache-Control", "private, no-cache")
+
+	c.Header("Vary", "ApiKey, Authorization")
+
+	if responsecache.NotModified(c.GetHeader("If-None-Match"), etag) {
+
+		// the body written after a 304 status is discarded by net/http
+		c.Status(http.StatusNotModified)
+	}
+}
+
+// StartInvalidation drops the cached responses of a tenant whenever its content or its members change in the admin
+// panel or through a mutation of this server
+func (rc *ResponseCache) StartInvalidation() {
+
+	entryEvents, _ := events.SubscribeEntryEvents(-1)
+
+	channelEvents, _ := events.SubscribeChannelEvents(-1)
+
+	categoryEvents, _ := events.SubscribeCategoryEvents(-1)
+
+	memberEvents, _ := events.SubscribeMemberEvents(-1)
+
+	go func() {
+
+		for {
+
+			select {
+
+			case event := <-entryEvents:
+
+				rc.cache.Invalidate(event.TenantId)
+
+			case event := <-channelEvents:
+
+				rc.cache.Invalidate(event.TenantId)
+
+			case event := <-categoryEvents:
+
+				rc.cache.Invalidate(event.TenantId)
+
+			case event := <-memberEvents:
+
+				rc.cache.Invalidate(event.TenantId)
+			}
+		}
+	}()
+}
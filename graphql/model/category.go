@@ -0,0 +1,40 @@
+package model
+
+// CategoryIdBySlug returns the id of the category with the slug, group categories come first, 0 when there is none
+func (model ModelConfig) CategoryIdBySlug(slug string, tenantId int) (categoryId int, err error) {
+
+	var categoryIds []int
+
+	if err = model.DB.Debug().Table("tbl_categories").Where("category_slug = ? and tenant_id = ? and is_deleted = 0", slug, tenantId).Order("parent_id, id").Limit(1).Pluck("id", &categoryIds).Error; err != nil {
+
+		return 0, err
+	}
+
+	if len(categoryIds) == 0 {
+
+		return 0, nil
+	}
+
+	return categoryIds[0], nil
+}
+
+// PublishedEntryCategoryIds returns the category list of every published entry the request may read
+func (model ModelConfig) PublishedEntryCategoryIds(channelIds []int, memberGroupId int, tenantId int) (categoryIds []string, err error) {
+
+	query := model.DB.Debug().Table("tbl_channel_entries as en").Joins("inner join tbl_channels as tc on tc.id = en.channel_id").
+		Where("en.is_deleted = 0 and en.status = 1 and tc.is_deleted = 0 and en.tenant_id = ? and coalesce(en.categories_id, '') != ''", tenantId)
+
+	if len(channelIds) > 0 {
+
+		query = query.Where("en.channel_id in (?)", channelIds)
+	}
+
+	query = query.Where("en.id not in (?)", model.hiddenEntries(memberGroupId, tenantId))
+
+	if err = query.Pluck("en.categories_id", &categoryIds).Error; err != nil {
+
+		return []string{}, err
+	}
+
+	return categoryIds, nil
+}
@@ -0,0 +1,445 @@
+package model
+
+import (
+	"spurt-cms/graphql/pagination"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TblChannelEntryField struct {
+	Id             int
+	FieldName      string
+	FieldValue     string
+	ChannelEntryId int
+	FieldId        int
+	TenantId       int
+}
+
+type TblChannel struct {
+	Id                 int
+	ChannelName        string
+	ChannelDescription string
+	SlugName           string
+	FieldGroupId       int
+	IsActive           int
+	IsDeleted          int
+	CreatedOn          time.Time
+	CreatedBy          int
+	ModifiedOn         time.Time
+	ModifiedBy         int
+	TenantId           int
+}
+
+type ChannelsCursorReq struct {
+	Keyword    string
+	IsActive   bool
+	ChannelIds []int
+	TenantId   int
+	Window     pagination.Window
+}
+
+type EntriesCursorReq struct {
+	ChannelId              int
+	CategoryId             int
+	CategorySlug           string
+	SelectedCategoryFilter bool
+	Keyword                string
+	Status                 int
+	ActiveEntriesOnly      bool
+	ChannelIds             []int
+	MemberGroupId          int
+	FieldConditions        []FieldCondition
+	FieldOrder             *FieldOrder
+	TenantId               int
+	Window                 pagination.Window
+}
+
+type EntriesPageReq struct {
+	EntriesCursorReq
+	SortBy string
+	Order  int
+	Limit  int
+	Offset int
+}
+
+type entryCursorRow struct {
+	Id            int
+	CreatedOn     time.Time
+	PublishedSort time.Time
+	SortOrderSort int
+	FieldSort     string
+}
+
+var entrySortColumns = map[string]string{
+	pagination.SortById:            "en.id",
+	pagination.SortByCreatedOn:     "en.created_on",
+	pagination.SortByPublishedTime: "coalesce(en.published_time, en.created_on)",
+	pagination.SortBySortOrder:     "coalesce(en.sort_order, 0)",
+}
+
+// entryOrderColumns are the entry columns an offset list may be sorted by
+var entryOrderColumns = map[string]string{
+	"id":             "en.id",
+	"title":          "en.title",
+	"slug":           "en.slug",
+	"created_on":     "en.created_on",
+	"modified_on":    "en.modified_on",
+	"published_time": "en.published_time",
+	"sort_order":     "en.sort_order",
+	"view_count":     "en.view_count",
+	"channel_id":     "en.channel_id",
+	"status":         "en.status",
+	"order_index":    "en.order_index",
+}
+
+func (model ModelConfig) ChannelsCursorPage(inputs ChannelsCursorReq) (channels []TblChannel, count int64, err error) {
+
+	query := model.DB.Debug().Table("tbl_channels").Where("tbl_channels.is_deleted = 0")
+
+	if inputs.TenantId != -1 {
+
+		query = query.Where("tbl_channels.tenant_id=?", inputs.TenantId)
+	}
+
+	if inputs.Keyword != "" {
+
+		query = query.Where("LOWER(TRIM(channel_name)) LIKE LOWER(TRIM(?))", "%"+inputs.Keyword+"%")
+	}
+
+	if inputs.IsActive {
+
+		query = query.Where("tbl_channels.is_active=1")
+	}
+
+	if len(inputs.ChannelIds) > 0 {
+
+		query = query.Where("tbl_channels.id in (?)", inputs.ChannelIds)
+	}
+
+	if err = query.Count(&count).Error; err != nil {
+
+		return []TblChannel{}, 0, err
+	}
+
+	query, err = keysetPage(query, "tbl_channels.created_on", "tbl_channels.id", inputs.Window)
+
+	if err != nil {
+
+		return []TblChannel{}, 0, err
+	}
+
+	if err = query.Find(&channels).Error; err != nil {
+
+		return []TblChannel{}, 0, err
+	}
+
+	return channels, count, nil
+}
+
+// entriesQuery filters the entries of the cursor and the offset pages alike
+func (model ModelConfig) entriesQuery(inputs EntriesCursorReq) *gorm.DB {
+
+	query := model.DB.Debug().Table("tbl_channel_entries as en").Joins("inner join tbl_channels as tc on tc.id = en.channel_id").Where("en.is_deleted = 0 and tc.is_deleted = 0")
+
+	if inputs.TenantId != -1 {
+
+		query = query.Where("en.tenant_id = ?", inputs.TenantId)
+	}
+
+	if inputs.ChannelId != 0 {
+
+		query = query.Where("en.channel_id = ?", inputs.ChannelId)
+	}
+
+	if len(inputs.ChannelIds) > 0 {
+
+		query = query.Where("en.channel_id in (?)", inputs.ChannelIds)
+	}
+
+	query = query.Where("en.id not in (?)", model.hiddenEntries(inputs.MemberGroupId, inputs.TenantId))
+
+	if inputs.Status != -1 {
+
+		query = query.Where("en.status = ?", inputs.Status)
+	}
+
+	if inputs.ActiveEntriesOnly {
+
+		query = query.Where("en.is_active = ?", 1)
+	}
+
+	if inputs.Keyword != "" {
+
+		query = query.Where("TRIM(LOWER(en.title)) LIKE TRIM(LOWER(?))", "%"+inputs.Keyword+"%")
+	}
+
+	if inputs.CategoryId != 0 || inputs.CategorySlug != "" {
+
+		var categoryCondition string
+
+		if model.DB.Config.Dialector.Name() == "mysql" {
+
+			categoryCondition = `find_in_set(cat.id,en.categories_id) > 0`
+
+		} else {
+
+			categoryCondition = `cat.id = any(string_to_array(en.categories_id,',')::Integer[])`
+		}
+
+		categoryIds := model.DB.Table("tbl_categories as pcat").Select("pcat.id").Where("pcat.is_deleted = 0 and pcat.tenant_id = ?", inputs.TenantId)
+
+		if inputs.CategoryId != 0 {
+
+			categoryIds = categoryIds.Where("pcat.id = ?", inputs.CategoryId)
+
+		} else {
+
+			categoryIds = categoryIds.Where("pcat.category_slug = ?", inputs.CategorySlug)
+		}
+
+		matchQuery := model.DB.Table("tbl_categories as cat").Select("1").Where(categoryCondition).Where("cat.is_deleted = 0")
+
+		if inputs.SelectedCategoryFilter {
+
+			matchQuery = matchQuery.Where("cat.id in (?)", categoryIds)
+
+		} else {
+
+			matchQuery = matchQuery.Where("(cat.id in (?) or cat.parent_id in (?))", categoryIds, categoryIds)
+		}
+
+		query = query.Where("exists (?)", matchQuery)
+	}
+
+	for _, condition := range inputs.FieldConditions {
+
+		query = model.fieldCondition(query, condition)
+	}
+
+	return query
+}
+
+// EntriesCursorPage returns the cursors of one page of entries in the order they were read, the entry details are fetched separately by id
+func (model ModelConfig) EntriesCursorPage(inputs EntriesCursorReq) (cursors []pagination.Cursor, count int64, err error) {
+
+	query := model.entriesQuery(inputs)
+
+	if err = query.Count(&count).Error; err != nil {
+
+		return []pagination.Cursor{}, 0, err
+	}
+
+	selectSQL, selectVars := "en.id, en.created_on, "+entrySortColumns[pagination.SortByPublishedTime]+" as published_sort, "+entrySortColumns[pagination.SortBySortOrder]+" as sort_order_sort", []interface{}{}
+
+	if inputs.FieldOrder != nil {
+
+		query = fieldKeysetPage(query, fieldPosition(*inputs.FieldOrder), inputs.FieldOrder.CursorPosition, "en.id", inputs.Window)
+
+		selectSQL, selectVars = selectSQL+", ? as field_sort", append(selectVars, fieldValue(*inputs.FieldOrder))
+
+	} else {
+
+		query, err = keysetPage(query, entrySortColumns[inputs.Window.SortKey], "en.id", inputs.Window)
+
+		if err != nil {
+
+			return []pagination.Cursor{}, 0, err
+		}
+	}
+
+	var rows []entryCursorRow
+
+	if err = query.Select(selectSQL, selectVars...).Scan(&rows).Error; err != nil {
+
+		return []pagination.Cursor{}, 0, err
+	}
+
+	cursors = make([]pagination.Cursor, len(rows))
+
+	for index, row := range rows {
+
+		switch {
+
+		case inputs.FieldOrder != nil:
+
+			cursors[index] = pagination.Cursor{SortKey: inputs.Window.SortKey, Value: row.FieldSort, Id: row.Id}
+
+		case inputs.Window.SortKey == pagination.SortByCreatedOn:
+
+			cursors[index] = pagination.TimeCursor(inputs.Window.SortKey, row.CreatedOn, row.Id)
+
+		case inputs.Window.SortKey == pagination.SortByPublishedTime:
+
+			cursors[index] = pagination.TimeCursor(inputs.Window.SortKey, row.PublishedSort, row.Id)
+
+		case inputs.Window.SortKey == pagination.SortBySortOrder:
+
+			cursors[index] = pagination.IntCursor(inputs.Window.SortKey, row.SortOrderSort, row.Id)
+
+		default:
+
+			cursors[index] = pagination.IntCursor(inputs.Window.SortKey, row.Id, row.Id)
+		}
+	}
+
+	return cursors, count, nil
+}
+
+// entry field rows keyed by field id, used to decide between update and insert
+func (model ModelConfig) GetEntryFieldRows(entryId int, tenantId int) (fieldRows map[int]int, err error) {
+
+	var entryFields []TblChannelEntryField
+
+	if err = model.DB.Debug().Table("tbl_channel_entry_fields").Where("channel_entry_id = ? and tenant_id = ? and (deleted_by is null or deleted_by = 0)", entryId, tenantId).Find(&entryFields).Error; err != nil {
+
+		return map[int]int{}, err
+	}
+
+	fieldRows = make(map[int]int, len(entryFields))
+
+	for _, field := range entryFields {
+
+		fieldRows[field.FieldId] = field.Id
+	}
+
+	return fieldRows, nil
+}
+
+// update only the given columns, unlike the channels package update which rewrites every column of the entry
+func (model ModelConfig) UpdateEntryDetails(entryId int, columns map[string]interface{}, tenantId int) error {
+
+	if err := model.DB.Debug().Table("tbl_channel_entries").Where("id = ? and tenant_id = ? and is_deleted = 0", entryId, tenantId).UpdateColumns(columns).Error; err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+// ChannelFieldIds returns the ids of the fields in the field groups of a channel
+func (model ModelConfig) ChannelFieldIds(channelId int, tenantId int) (fieldIds []int, err error) {
+
+	if err = model.DB.Debug().Table("tbl_group_fields as tgf").Joins("inner join tbl_fields as tf on tf.id = tgf.field_id").Joins("inner join tbl_channels as tc on tc.id = tgf.channel_id").Where("tf.is_deleted = 0 and tgf.channel_id = ? and tc.tenant_id = ?", channelId, tenantId).Pluck("tf.id", &fieldIds).Error; err != nil {
+
+		return []int{}, err
+	}
+
+	return fieldIds, nil
+}
+
+// TenantCategoryIds returns the ids among categoryIds that are live categories of the tenant
+func (model ModelConfig) TenantCategoryIds(categoryIds []int, tenantId int) (tenantCategoryIds []int, err error) {
+
+	if err = model.DB.Debug().Table("tbl_categories").Where("id in (?) and tenant_id = ? and is_deleted = 0", categoryIds, tenantId).Pluck("id", &tenantCategoryIds).Error; err != nil {
+
+		return []int{}, err
+	}
+
+	return tenantCategoryIds, nil
+}
+
+func (model ModelConfig) UpdateEntryPublishedTime(entryId int, publishedTime time.Time, tenantId int) error {
+
+	if err := model.DB.Debug().Table("tbl_channel_entries").Where("id = ? and tenant_id = ? and published_time is null", entryId, tenantId).UpdateColumn("published_time", publishedTime).Error; err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+// the review workflow of a channel, empty when its entries are published directly
+func (model ModelConfig) GetChannelWorkflow(channelId int, tenantId int) (string, error) {
+
+	var workflow string
+
+	if err := model.DB.Table("tbl_channels").Select("coalesce(workflow, '')").Where("id = ? and tenant_id = ?", channelId, tenantId).Scan(&workflow).Error; err != nil {
+
+		return "", err
+	}
+
+	return workflow, nil
+}
+
+// push existing entries down so that a newly created entry is listed first, same as the admin panel
+func (model ModelConfig) ShiftEntriesOrderIndex(tenantId int) error {
+
+	if err := model.DB.Debug().Exec("update tbl_channel_entries set order_index = coalesce(order_index,0) + 1 where tenant_id = ? and is_deleted = 0", tenantId).Error; err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+// ActiveChannels returns every active channel of the tenant, oldest first
+func (model ModelConfig) ActiveChannels(tenantId int) (channels []TblChannel, err error) {
+
+	if err = model.DB.Debug().Table("tbl_channels").Where("is_deleted = 0 and is_active = 1 and tenant_id = ?", tenantId).Order("id").Find(&channels).Error; err != nil {
+
+		return []TblChannel{}, err
+	}
+
+	return channels, nil
+}
+
+// EntriesPage returns the ids of one offset page of entries in the order they were read, the entry details are fetched
+// separately by id
+func (model ModelConfig) EntriesPage(inputs EntriesPageReq) (entryIds []int, count int64, err error) {
+
+	query := model.entriesQuery(inputs.EntriesCursorReq)
+
+	if err = query.Count(&count).Error; err != nil {
+
+		return []int{}, 0, err
+	}
+
+	sortColumn, ok := entryOrderColumns[strings.TrimPrefix(inputs.SortBy, "en.")]
+
+	if !ok {
+
+		sortColumn = "en.id"
+	}
+
+	orderSQL := sortColumn
+
+	if inputs.Order > 0 || !ok {
+
+		orderSQL += " desc"
+	}
+
+	if sortColumn != "en.id" {
+
+		orderSQL += ", en.id desc"
+	}
+
+	// the field order comes first, the list order decides between equal values
+	if inputs.FieldOrder != nil {
+
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{SQL: "?, " + orderSQL, Vars: []interface{}{fieldPosition(*inputs.FieldOrder)}, WithoutParentheses: true}})
+
+	} else {
+
+		query = query.Order(orderSQL)
+	}
+
+	if inputs.Limit > 0 {
+
+		query = query.Limit(inputs.Limit)
+	}
+
+	if inputs.Offset > -1 {
+
+		query = query.Offset(inputs.Offset)
+	}
+
+	if err = query.Pluck("en.id", &entryIds).Error; err != nil {
+
+		return []int{}, 0, err
+	}
+
+	return entryIds, count, nil
+}
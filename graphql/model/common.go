@@ -17,21 +17,26 @@ type ModelConfig struct {
 }
 
 type TblGraphqlSettings struct {
-	Id          int `gorm:"primaryKey;auto_increment;"`
-	TokenName   string
-	Description string
-	Duration    string
-	CreatedBy   int
-	CreatedOn   time.Time
-	ModifiedBy  int
-	ModifiedOn  time.Time
-	DeletedBy   int
-	DeletedOn   time.Time
-	IsDeleted   int `gorm:"DEFAULT:0"`
-	Token       string
-	ExpiryTime  time.Time
-	IsDefault   int `gorm:"Default:NULl"`
-	TenantId    int
+	Id           int `gorm:"primaryKey;auto_increment;"`
+	TokenName    string
+	Description  string
+	Duration     string
+	CreatedBy    int
+	CreatedOn    time.Time
+	ModifiedBy   int
+	ModifiedOn   time.Time
+	DeletedBy    int
+	DeletedOn    time.Time
+	IsDeleted    int `gorm:"DEFAULT:0"`
+	Token        string
+	ExpiryTime   time.Time
+	IsDefault    int `gorm:"Default:NULl"`
+	TenantId     int
+	ChannelIds   string
+	WriteAccess  int
+	MemberAccess int
+	RateLimit    int
+	RateBurst    int
 }
 
 type TblMstrTenant struct {
@@ -48,8 +53,8 @@ type TblStorageTypes struct {
 	Aws          datatypes.JSONMap
 	Azure        datatypes.JSONMap
 	Drive        datatypes.JSONMap
-	SelectedType string 
-	TenantId     int   
+	SelectedType string
+	TenantId     int
 }
 
 func init() {
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"spurt-cms/graphql/pagination"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// keysetPage applies the cursor condition, ordering and limit of a page window to a list query.
+// One extra row is read so the caller can tell whether another page exists.
+func keysetPage(query *gorm.DB, sortColumn, idColumn string, window pagination.Window) (*gorm.DB, error) {
+
+	direction, comparison := "asc", ">"
+
+	if window.Descending() {
+
+		direction, comparison = "desc", "<"
+	}
+
+	if window.Cursor != nil {
+
+		switch window.SortKey {
+
+		case pagination.SortById:
+
+			query = query.Where(idColumn+" "+comparison+" ?", window.Cursor.Id)
+
+		default:
+
+			var (
+				value interface{}
+				err   error
+			)
+
+			if window.SortKey == pagination.SortBySortOrder {
+
+				value, err = window.Cursor.Int()
+
+			} else {
+
+				value, err = window.Cursor.Time()
+			}
+
+			if err != nil {
+
+				return query, err
+			}
+
+			query = query.Where("("+sortColumn+" "+comparison+" ? or ("+sortColumn+" = ? and "+idColumn+" "+comparison+" ?))", value, value, window.Cursor.Id)
+		}
+	}
+
+	if window.SortKey != pagination.SortById {
+
+		query = query.Order(sortColumn + " " + direction)
+	}
+
+	return query.Order(idColumn + " " + direction).Limit(window.Limit + 1), nil
+}
+
+// fieldKeysetPage pages a list sorted by a field order, the cursor is compared by the place of its value in the order
+func fieldKeysetPage(query *gorm.DB, position clause.Expr, cursorPosition int, idColumn string, window pagination.Window) *gorm.DB {
+
+	direction, comparison := "asc", ">"
+
+	if window.Descending() {
+
+		direction, comparison = "desc", "<"
+	}
+
+	if window.Cursor != nil {
+
+		query = query.Where("(? "+comparison+" ? or (? = ? and "+idColumn+" "+comparison+" ?))", position, cursorPosition, position, cursorPosition, window.Cursor.Id)
+	}
+
+	return query.Order(clause.OrderBy{Expression: clause.Expr{SQL: "? " + direction + ", " + idColumn + " " + direction, Vars: []interface{}{position}, WithoutParentheses: true}}).Limit(window.Limit + 1)
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"spurt-cms/graphql/entryviews"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TblGraphqlEntryViews is the number of counted views of an entry on one day
+type TblGraphqlEntryViews struct {
+	Id        int
+	EntryId   int
+	ChannelId int
+	ViewDate  time.Time
+	ViewCount int
+	TenantId  int
+}
+
+type PopularEntriesReq struct {
+	ChannelId     int
+	ChannelIds    []int
+	MemberGroupId int
+	Since         time.Time
+	Limit         int
+	TenantId      int
+}
+
+type EntryViews struct {
+	EntryId int
+	Views   int
+}
+
+// RecordEntryView adds a view to the row of the entry for the day. The row is created or counted up in one statement,
+// concurrent first views of a day meet on the unique index over entry and day.
+func (model ModelConfig) RecordEntryView(entryId, channelId int, day time.Time, tenantId int) error {
+
+	row := TblGraphqlEntryViews{EntryId: entryId, ChannelId: channelId, ViewDate: day, ViewCount: 1, TenantId: tenantId}
+
+	return model.DB.Debug().Table("tbl_graphql_entry_views").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entry_id"}, {Name: "view_date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"view_count": gorm.Expr("tbl_graphql_entry_views.view_count + 1")}),
+	}).Create(&row).Error
+}
+
+// PopularEntries returns the published entries with the most views since the given day, most viewed first. A zero
+// Since counts every recorded view.
+func (model ModelConfig) PopularEntries(inputs PopularEntriesReq) (popular []EntryViews, err error) {
+
+	query := model.DB.Debug().Table("tbl_graphql_entry_views as ev").
+		Joins("inner join tbl_channel_entries as en on en.id = ev.entry_id").
+		Joins("inner join tbl_channels as tc on tc.id = en.channel_id").
+		Where("ev.tenant_id = ? and en.is_deleted = 0 and en.status = 1 and tc.is_deleted = 0", inputs.TenantId)
+
+	if !inputs.Since.IsZero() {
+
+		query = query.Where("ev.view_date >= ?", inputs.Since)
+	}
+
+	if inputs.ChannelId != 0 {
+
+		query = query.Where("en.channel_id = ?", inputs.ChannelId)
+	}
+
+	if len(inputs.ChannelIds) > 0 {
+
+		query = query.Where("en.channel_id in (?)", inputs.ChannelIds)
+	}
+
+	query = query.Where("ev.entry_id not in (?)", model.hiddenEntries(inputs.MemberGroupId, inputs.TenantId))
+
+	err = query.Select("ev.entry_id, sum(ev.view_count) as views").
+		Group("ev.entry_id").
+		Order("views desc, ev.entry_id desc").
+		Limit(inputs.Limit).
+		Scan(&popular).Error
+
+	if err != nil {
+
+		return []EntryViews{}, err
+	}
+
+	return popular, nil
+}
+
+// EntryDayViews returns the recorded views of an entry per day within the range, the days without views are left out
+func (model ModelConfig) EntryDayViews(entryId int, from, to time.Time, tenantId int) (days []entryviews.DayViews, err error) {
+
+	var rows []TblGraphqlEntryViews
+
+	err = model.DB.Debug().Table("tbl_graphql_entry_views").Where("entry_id = ? and tenant_id = ? and view_date >= ? and view_date <= ?", entryId, tenantId, from, to).Order("view_date").Find(&rows).Error
+
+	if err != nil {
+
+		return []entryviews.DayViews{}, err
+	}
+
+	for _, row := range rows {
+
+		days = append(days, entryviews.DayViews{Day: row.ViewDate, Views: row.ViewCount})
+	}
+
+	return days, nil
+}
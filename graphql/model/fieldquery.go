@@ -0,0 +1,167 @@
+package model
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FieldCondition keeps the entries by their value of the fields of a predicate. Values lists the stored values of each
+// field the predicate matches, or the ones it does not match when Inverse is set. Entries without a value of the
+// fields match when Missing is set.
+type FieldCondition struct {
+	Values  map[int][]string
+	Inverse bool
+	Missing bool
+}
+
+// FieldOrder sorts the entries by their value of the fields. Positions places the stored values, values that compare
+// equal share a place and the entries without a placed value come last at Missing. CursorPosition is the place of the
+// value a cursor of the window points at.
+type FieldOrder struct {
+	FieldIds       []int
+	Positions      []FieldPosition
+	Missing        int
+	CursorPosition int
+}
+
+type FieldPosition struct {
+	Value    string
+	Position int
+}
+
+// the stored values of the fields of an entry, empty values count as no value
+const entryFieldRows = "tbl_channel_entry_fields as cef"
+
+const entryFieldRowsCondition = "cef.channel_entry_id = en.id and trim(coalesce(cef.field_value, '')) != ''"
+
+// DistinctFieldValues returns every value stored for the fields once per field, empty values are left out
+func (model ModelConfig) DistinctFieldValues(fieldIds []int, tenantId int) (values []EntryFieldValue, err error) {
+
+	if err = model.DB.Debug().Table("tbl_channel_entry_fields as cef").Distinct("cef.field_id, cef.field_value").Where("cef.field_id in (?) and cef.tenant_id = ? and trim(coalesce(cef.field_value, '')) != ''", fieldIds, tenantId).Find(&values).Error; err != nil {
+
+		return []EntryFieldValue{}, err
+	}
+
+	return values, nil
+}
+
+// TenantChannelIds returns the channels of a tenant, inactive ones included
+func (model ModelConfig) TenantChannelIds(tenantId int) (channelIds []int, err error) {
+
+	if err = model.DB.Debug().Table("tbl_channels").Where("is_deleted = 0 and tenant_id = ?", tenantId).Order("id").Pluck("id", &channelIds).Error; err != nil {
+
+		return []int{}, err
+	}
+
+	return channelIds, nil
+}
+
+// fieldCondition is the exists subquery of a field condition on the entry fields
+func (model ModelConfig) fieldCondition(query *gorm.DB, condition FieldCondition) *gorm.DB {
+
+	if condition.Missing {
+
+		return query.Where("not exists (?)", model.fieldRows(condition, condition.Inverse))
+	}
+
+	return query.Where("exists (?)", model.fieldRows(condition, !condition.Inverse))
+}
+
+// fieldRows reads the value rows of an entry whose values are listed in the condition, or the ones not listed
+func (model ModelConfig) fieldRows(condition FieldCondition, listed bool) *gorm.DB {
+
+	var (
+		fieldConditions []string
+		vars            []interface{}
+	)
+
+	for _, fieldId := range sortedFieldIds(condition.Values) {
+
+		values := condition.Values[fieldId]
+
+		switch {
+
+		case listed && len(values) == 0:
+
+			continue
+
+		case listed:
+
+			fieldConditions = append(fieldConditions, "(cef.field_id = ? and cef.field_value in (?))")
+
+			vars = append(vars, fieldId, values)
+
+		case len(values) == 0:
+
+			fieldConditions = append(fieldConditions, "cef.field_id = ?")
+
+			vars = append(vars, fieldId)
+
+		default:
+
+			fieldConditions = append(fieldConditions, "(cef.field_id = ? and cef.field_value not in (?))")
+
+			vars = append(vars, fieldId, values)
+		}
+	}
+
+	rows := model.DB.Table(entryFieldRows).Select("1").Where(entryFieldRowsCondition)
+
+	if len(fieldConditions) == 0 {
+
+		return rows.Where("1 = 0")
+	}
+
+	return rows.Where("("+strings.Join(fieldConditions, " or ")+")", vars...)
+}
+
+// fieldValue reads the value of the fields an entry is sorted by
+func fieldValue(order FieldOrder) clause.Expr {
+
+	return clause.Expr{SQL: "(select cef.field_value from " + entryFieldRows + " where " + entryFieldRowsCondition + " and cef.field_id in ? order by cef.id limit 1)", Vars: []interface{}{order.FieldIds}}
+}
+
+// fieldPosition places an entry in the field order by its value
+func fieldPosition(order FieldOrder) clause.Expr {
+
+	if len(order.Positions) == 0 {
+
+		return clause.Expr{SQL: strconv.Itoa(order.Missing)}
+	}
+
+	var (
+		positionSQL strings.Builder
+		vars        = []interface{}{fieldValue(order)}
+	)
+
+	positionSQL.WriteString("case ?")
+
+	for _, position := range order.Positions {
+
+		positionSQL.WriteString(" when ? then " + strconv.Itoa(position.Position))
+
+		vars = append(vars, position.Value)
+	}
+
+	positionSQL.WriteString(" else " + strconv.Itoa(order.Missing) + " end")
+
+	return clause.Expr{SQL: positionSQL.String(), Vars: vars}
+}
+
+func sortedFieldIds(values map[int][]string) []int {
+
+	fieldIds := make([]int, 0, len(values))
+
+	for fieldId := range values {
+
+		fieldIds = append(fieldIds, fieldId)
+	}
+
+	sort.Ints(fieldIds)
+
+	return fieldIds
+}
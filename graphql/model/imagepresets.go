@@ -0,0 +1,21 @@
+package model
+
+// ImagePresets returns the image transform presets a tenant configured in its general settings, empty when it has none
+func (model ModelConfig) ImagePresets(tenantId int) (presets string, err error) {
+
+	var settings []struct {
+		ImagePresets string
+	}
+
+	if err = model.DB.Debug().Table("tbl_general_settings").Select("image_presets").Where("tenant_id = ?", tenantId).Limit(1).Find(&settings).Error; err != nil {
+
+		return "", err
+	}
+
+	if len(settings) == 0 {
+
+		return "", nil
+	}
+
+	return settings[0].ImagePresets, nil
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"spurt-cms/graphql/pagination"
 	"spurt-cms/logger"
 )
 type MembersListReq struct {
@@ -58,3 +59,43 @@ func (model ModelConfig) MembersList(inputs MembersListReq) (members []Members,
 	logger.Info(fmt.Sprintf("%v", members, "mmembersss"))
 	return members, count, nil
 }
+
+type MembersCursorReq struct {
+	Keyword  string
+	TenantId int
+	Window   pagination.Window
+}
+
+func (model ModelConfig) MembersCursorPage(inputs MembersCursorReq) (members []Members, count int64, err error) {
+
+	query := model.DB.Debug().Table("tbl_members").Where("tbl_members.is_deleted = 0")
+
+	if inputs.TenantId != -1 {
+
+		query = query.Where("tbl_members.tenant_id=?", inputs.TenantId)
+	}
+
+	if inputs.Keyword != "" {
+
+		query = query.Where("LOWER(TRIM(first_name)) LIKE LOWER(TRIM(?))", "%"+inputs.Keyword+"%")
+	}
+
+	if err = query.Count(&count).Error; err != nil {
+
+		return []Members{}, 0, err
+	}
+
+	query, err = keysetPage(query, "tbl_members.created_on", "tbl_members.id", inputs.Window)
+
+	if err != nil {
+
+		return []Members{}, 0, err
+	}
+
+	if err = query.Find(&members).Error; err != nil {
+
+		return []Members{}, 0, err
+	}
+
+	return members, count, nil
+}
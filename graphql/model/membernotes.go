@@ -0,0 +1,116 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// TblMemberNotesHighlights is a note or highlight of a member on an entry, PageId holds the entry id
+type TblMemberNotesHighlights struct {
+	Id                      int
+	MemberId                int
+	PageId                  int
+	NotesHighlightsContent  string
+	NotesHighlightsType     string
+	HighlightsConfiguration datatypes.JSONMap
+	CreatedBy               int
+	CreatedOn               time.Time
+	ModifiedBy              int
+	ModifiedOn              *time.Time
+	DeletedBy               int
+	DeletedOn               *time.Time
+	IsDeleted               int
+	TenantId                int
+}
+
+type MemberNotesReq struct {
+	MemberId int
+	EntryId  int
+	Type     string
+	Limit    int
+	Offset   int
+	TenantId int
+}
+
+func (model ModelConfig) CreateMemberNote(note *TblMemberNotesHighlights) error {
+
+	if err := model.DB.Debug().Table("tbl_member_notes_highlights").Create(note).Error; err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+// MemberNotes returns a page of the notes of a member, newest first, with the count of all that match
+func (model ModelConfig) MemberNotes(inputs MemberNotesReq) (notes []TblMemberNotesHighlights, count int64, err error) {
+
+	query := model.DB.Debug().Table("tbl_member_notes_highlights").Where("member_id = ? and tenant_id = ? and is_deleted = 0", inputs.MemberId, inputs.TenantId)
+
+	if inputs.EntryId != 0 {
+
+		query = query.Where("page_id = ?", inputs.EntryId)
+	}
+
+	if inputs.Type != "" {
+
+		query = query.Where("notes_highlights_type = ?", inputs.Type)
+	}
+
+	if err = query.Count(&count).Error; err != nil {
+
+		return []TblMemberNotesHighlights{}, 0, err
+	}
+
+	if err = query.Order("created_on desc, id desc").Limit(inputs.Limit).Offset(inputs.Offset).Find(&notes).Error; err != nil {
+
+		return []TblMemberNotesHighlights{}, 0, err
+	}
+
+	return notes, count, nil
+}
+
+func (model ModelConfig) MemberNote(id, memberId, tenantId int) (note TblMemberNotesHighlights, err error) {
+
+	if err = model.DB.Debug().Table("tbl_member_notes_highlights").Where("id = ? and member_id = ? and tenant_id = ? and is_deleted = 0", id, memberId, tenantId).Take(&note).Error; err != nil {
+
+		return TblMemberNotesHighlights{}, err
+	}
+
+	return note, nil
+}
+
+func (model ModelConfig) UpdateMemberNote(note *TblMemberNotesHighlights) error {
+
+	err := model.DB.Debug().Table("tbl_member_notes_highlights").Where("id = ? and tenant_id = ? and is_deleted = 0", note.Id, note.TenantId).
+		UpdateColumns(map[string]interface{}{
+			"notes_highlights_content": note.NotesHighlightsContent,
+			"highlights_configuration": note.HighlightsConfiguration,
+			"modified_by":              note.ModifiedBy,
+			"modified_on":              note.ModifiedOn,
+		}).Error
+
+	if err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+// DeleteMemberNote soft deletes a note of the member, it reports false when the member has no such note
+func (model ModelConfig) DeleteMemberNote(id, memberId, tenantId int) (bool, error) {
+
+	currentTime := time.Now().UTC()
+
+	result := model.DB.Debug().Table("tbl_member_notes_highlights").Where("id = ? and member_id = ? and tenant_id = ? and is_deleted = 0", id, memberId, tenantId).
+		UpdateColumns(map[string]interface{}{"is_deleted": 1, "deleted_by": memberId, "deleted_on": currentTime})
+
+	if result.Error != nil {
+
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"time"
+
+	"github.com/spurtcms/member"
+	"gorm.io/gorm"
+)
+
+type TblMemberProfileClaims struct {
+	Id        int
+	ProfileId int
+	MemberId  int
+	TokenHash string
+	ExpiresOn time.Time
+	ClaimedOn *time.Time
+	CreatedOn time.Time
+	TenantId  int
+}
+
+// ClaimableProfile is a member profile with the member its claim email is sent to
+type ClaimableProfile struct {
+	ProfileId   int
+	MemberId    int
+	ProfileSlug string
+	CompanyName string
+	ClaimStatus int
+	FirstName   string
+	LastName    string
+	Email       string
+	IsActive    int
+}
+
+// MemberProfileBySlug returns the profile of an active member
+func (model ModelConfig) MemberProfileBySlug(slug string, tenantId int) (profile member.TblMemberProfile, err error) {
+
+	err = model.DB.Debug().Table("tbl_member_profiles as mp").Select("mp.*").
+		Joins("inner join tbl_members as tm on tm.id = mp.member_id").
+		Where("LOWER(mp.profile_slug) = LOWER(?) and mp.tenant_id = ? and mp.is_deleted = 0 and tm.is_deleted = 0 and tm.is_active = 1", slug, tenantId).
+		Take(&profile).Error
+
+	if err != nil {
+
+		return member.TblMemberProfile{}, err
+	}
+
+	return profile, nil
+}
+
+func (model ModelConfig) MemberProfileByMemberId(memberId int, tenantId int) (profile member.TblMemberProfile, err error) {
+
+	if err = model.DB.Debug().Table("tbl_member_profiles").Where("member_id = ? and tenant_id = ? and is_deleted = 0", memberId, tenantId).Take(&profile).Error; err != nil {
+
+		return member.TblMemberProfile{}, err
+	}
+
+	return profile, nil
+}
+
+// ProfileSlugTaken reports whether another profile of the tenant uses the slug
+func (model ModelConfig) ProfileSlugTaken(slug string, profileId int, tenantId int) (bool, error) {
+
+	var count int64
+
+	if err := model.DB.Debug().Table("tbl_member_profiles").Where("LOWER(profile_slug) = LOWER(?) and id <> ? and tenant_id = ? and is_deleted = 0", slug, profileId, tenantId).Count(&count).Error; err != nil {
+
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (model ModelConfig) ClaimableProfileBySlug(slug string, tenantId int) (profile ClaimableProfile, err error) {
+
+	err = model.DB.Debug().Table("tbl_member_profiles as mp").
+		Select("mp.id as profile_id, mp.member_id, mp.profile_slug, mp.company_name, mp.claim_status, tm.first_name, tm.last_name, tm.email, tm.is_active").
+		Joins("inner join tbl_members as tm on tm.id = mp.member_id").
+		Where("LOWER(mp.profile_slug) = LOWER(?) and mp.tenant_id = ? and mp.is_deleted = 0 and tm.is_deleted = 0", slug, tenantId).
+		Take(&profile).Error
+
+	if err != nil {
+
+		return ClaimableProfile{}, err
+	}
+
+	return profile, nil
+}
+
+func (model ModelConfig) CreateProfileClaim(claim *TblMemberProfileClaims) error {
+
+	if err := model.DB.Debug().Table("tbl_member_profile_claims").Create(claim).Error; err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+// LastProfileClaim returns the creation time of the latest claim email of a profile, zero when there is none
+func (model ModelConfig) LastProfileClaim(profileId, tenantId int) (createdOn time.Time, err error) {
+
+	var claim TblMemberProfileClaims
+
+	err = model.DB.Debug().Table("tbl_member_profile_claims").Where("profile_id = ? and tenant_id = ?", profileId, tenantId).Order("created_on desc").First(&claim).Error
+
+	if err == gorm.ErrRecordNotFound {
+
+		return time.Time{}, nil
+	}
+
+	return claim.CreatedOn, err
+}
+
+// ActiveProfileClaim returns the claim of a token that is neither used nor expired
+func (model ModelConfig) ActiveProfileClaim(tokenHash string, tenantId int) (claim TblMemberProfileClaims, err error) {
+
+	if err = model.DB.Debug().Table("tbl_member_profile_claims").Where("token_hash = ? and tenant_id = ? and claimed_on is null and expires_on > ?", tokenHash, tenantId, time.Now().UTC()).First(&claim).Error; err != nil {
+
+		return TblMemberProfileClaims{}, err
+	}
+
+	return claim, nil
+}
+
+// ClaimMemberProfile marks the token as used, voids the other open tokens of the profile and sets the profile claimed
+// the way the admin claim does
+func (model ModelConfig) ClaimMemberProfile(claim TblMemberProfileClaims) error {
+
+	currentTime := time.Now().UTC()
+
+	return model.DB.Transaction(func(tx *gorm.DB) error {
+
+		result := tx.Debug().Table("tbl_member_profile_claims").Where("id = ? and claimed_on is null", claim.Id).UpdateColumn("claimed_on", currentTime)
+
+		if result.Error != nil {
+
+			return result.Error
+		}
+
+		// the token was used by a concurrent request
+		if result.RowsAffected == 0 {
+
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Debug().Table("tbl_member_profile_claims").Where("profile_id = ? and tenant_id = ? and claimed_on is null", claim.ProfileId, claim.TenantId).UpdateColumn("expires_on", currentTime).Error; err != nil {
+
+			return err
+		}
+
+		result = tx.Debug().Table("tbl_member_profiles").Where("id = ? and member_id = ? and tenant_id = ? and is_deleted = 0", claim.ProfileId, claim.MemberId, claim.TenantId).UpdateColumns(map[string]interface{}{"claim_status": 1, "claim_date": currentTime, "modified_on": currentTime})
+
+		if result.Error != nil {
+
+			return result.Error
+		}
+
+		// the profile was deleted after the email was sent
+		if result.RowsAffected == 0 {
+
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TblGraphqlMemberSessions struct {
+	Id        int
+	MemberId  int
+	TokenHash string
+	ExpiresOn time.Time
+	RevokedOn *time.Time
+	CreatedOn time.Time
+	TenantId  int
+}
+
+// MemberAccess is the member an entry query is read for, entries restricted to other member groups are left out
+type MemberAccess struct {
+	MemberId      int
+	MemberGroupId int
+}
+
+func (model ModelConfig) CreateMemberSession(session *TblGraphqlMemberSessions) error {
+
+	if err := model.DB.Debug().Table("tbl_graphql_member_sessions").Create(session).Error; err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+// ActiveMemberSession returns the session of a refresh token that is neither revoked nor expired
+func (model ModelConfig) ActiveMemberSession(tokenHash string, tenantId int) (session TblGraphqlMemberSessions, err error) {
+
+	if err = model.DB.Debug().Table("tbl_graphql_member_sessions").Where("token_hash = ? and tenant_id = ? and revoked_on is null and expires_on > ?", tokenHash, tenantId, time.Now().UTC()).First(&session).Error; err != nil {
+
+		return TblGraphqlMemberSessions{}, err
+	}
+
+	return session, nil
+}
+
+// RotateMemberSession replaces the refresh token of a session, the old token can not be used again
+func (model ModelConfig) RotateMemberSession(sessionId int, oldHash, newHash string, expiresOn time.Time, tenantId int) error {
+
+	result := model.DB.Debug().Table("tbl_graphql_member_sessions").Where("id = ? and token_hash = ? and tenant_id = ? and revoked_on is null", sessionId, oldHash, tenantId).UpdateColumns(map[string]interface{}{"token_hash": newHash, "expires_on": expiresOn})
+
+	if result.Error != nil {
+
+		return result.Error
+	}
+
+	// the token was rotated or revoked by a concurrent request
+	if result.RowsAffected == 0 {
+
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (model ModelConfig) RevokeMemberSession(sessionId int, tenantId int) error {
+
+	if err := model.DB.Debug().Table("tbl_graphql_member_sessions").Where("id = ? and tenant_id = ? and revoked_on is null", sessionId, tenantId).UpdateColumn("revoked_on", time.Now().UTC()).Error; err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+// SessionMemberAccess checks that the session of an access token is still open and returns the current member group of its member
+func (model ModelConfig) SessionMemberAccess(sessionId, memberId, tenantId int) (access MemberAccess, err error) {
+
+	err = model.DB.Debug().Table("tbl_graphql_member_sessions as ms").Select("tm.id as member_id, tm.member_group_id").
+		Joins("inner join tbl_members as tm on tm.id = ms.member_id").
+		Where("ms.id = ? and ms.member_id = ? and ms.tenant_id = ? and ms.revoked_on is null and ms.expires_on > ?", sessionId, memberId, tenantId, time.Now().UTC()).
+		Where("tm.is_deleted = 0 and tm.is_active = 1").
+		Take(&access).Error
+
+	if err != nil {
+
+		return MemberAccess{}, err
+	}
+
+	return access, nil
+}
+
+// hiddenEntries selects the entries a member group may not read. An entry is restricted by the content access rights
+// it is listed in and by its own member group list, a group has to be granted by both to read it. The list queries
+// filter with it as a subquery so the ids never leave the database.
+func (model ModelConfig) hiddenEntries(memberGroupId int, tenantId int) *gorm.DB {
+
+	grants := func() *gorm.DB {
+
+		return model.DB.Table("tbl_access_control_pages as acp").Select("acp.entry_id").
+			Joins("inner join tbl_access_control_user_groups as acu on acu.id = acp.access_control_user_group_id").
+			Joins("inner join tbl_access_controls as ac on ac.id = acu.access_control_id").
+			Where("acp.is_deleted = 0 and acu.is_deleted = 0 and ac.is_deleted = 0 and acp.entry_id != 0 and acp.tenant_id = ?", tenantId)
+	}
+
+	var groupCondition string
+
+	if model.DB.Config.Dialector.Name() == "mysql" {
+
+		groupCondition = `find_in_set(?, he.membergroup_id) = 0`
+
+	} else {
+
+		groupCondition = `not (? = any(string_to_array(he.membergroup_id, ',')::Integer[]))`
+	}
+
+	restrictedByAccess := grants().Where("acp.entry_id not in (?)", grants().Where("acu.member_group_id = ?", memberGroupId))
+
+	return model.DB.Table("tbl_channel_entries as he").Select("he.id").Where("he.is_deleted = 0 and he.tenant_id = ?", tenantId).
+		Where("(he.id in (?) or (coalesce(he.membergroup_id, '') != '' and "+groupCondition+"))", restrictedByAccess, memberGroupId)
+}
+
+// HiddenEntryIds returns the entries among the ids a member group may not read
+func (model ModelConfig) HiddenEntryIds(entryIds []int, memberGroupId int, tenantId int) (hiddenIds []int, err error) {
+
+	if len(entryIds) == 0 {
+
+		return []int{}, nil
+	}
+
+	if err = model.hiddenEntries(memberGroupId, tenantId).Where("he.id in (?)", entryIds).Pluck("he.id", &hiddenIds).Error; err != nil {
+
+		return []int{}, err
+	}
+
+	return hiddenIds, nil
+}
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TblMemberVerifications struct {
+	Id         int
+	MemberId   int
+	TokenHash  string
+	ExpiresOn  time.Time
+	VerifiedOn *time.Time
+	CreatedOn  time.Time
+	TenantId   int
+}
+
+// UnverifiedMember is a registered member that has not opened a verification link yet
+type UnverifiedMember struct {
+	Id        int
+	FirstName string
+	Email     string
+}
+
+// MemberRegistrationSettings are the member settings the graphql registration reads
+type MemberRegistrationSettings struct {
+	AllowRegistration int
+	MemberLogin       string
+	DefaultGroupId    int
+	EmailVerification int
+	VerificationUrl   string
+}
+
+func (model ModelConfig) GetMemberRegistrationSettings(tenantId int) (settings MemberRegistrationSettings, err error) {
+
+	if err = model.DB.Debug().Table("tbl_member_settings").Select("allow_registration, member_login, default_group_id, email_verification, verification_url").Where("tenant_id = ?", tenantId).Take(&settings).Error; err != nil {
+
+		return MemberRegistrationSettings{}, err
+	}
+
+	return settings, nil
+}
+
+// MemberUsernames returns the lower cased usernames of the members of a tenant that start with the prefix
+func (model ModelConfig) MemberUsernames(prefix string, tenantId int) (usernames []string, err error) {
+
+	if err = model.DB.Debug().Table("tbl_members").Where("LOWER(TRIM(username)) like ? and tenant_id = ? and is_deleted = 0", strings.ToLower(prefix)+"%", tenantId).Pluck("LOWER(TRIM(username))", &usernames).Error; err != nil {
+
+		return []string{}, err
+	}
+
+	return usernames, nil
+}
+
+// DefaultMemberGroupId returns the group new members join, the configured group or else the default group of the tenant
+func (model ModelConfig) DefaultMemberGroupId(configuredId, tenantId int) (int, error) {
+
+	activeGroups := func() *gorm.DB {
+
+		return model.DB.Debug().Table("tbl_member_groups").Where("is_deleted = 0 and is_active = 1 and tenant_id = ?", tenantId)
+	}
+
+	var groupIds []int
+
+	if configuredId != 0 {
+
+		if err := activeGroups().Where("id = ?", configuredId).Pluck("id", &groupIds).Error; err != nil {
+
+			return 0, err
+		}
+
+		if len(groupIds) > 0 {
+
+			return groupIds[0], nil
+		}
+	}
+
+	if err := activeGroups().Order("case when slug = 'default-group' then 0 else 1 end, id").Limit(1).Pluck("id", &groupIds).Error; err != nil {
+
+		return 0, err
+	}
+
+	if len(groupIds) == 0 {
+
+		return 0, nil
+	}
+
+	return groupIds[0], nil
+}
+
+func (model ModelConfig) CreateMemberVerification(verification *TblMemberVerifications) error {
+
+	if err := model.DB.Debug().Table("tbl_member_verifications").Create(verification).Error; err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+// ActiveMemberVerification returns the verification of a token that is neither used nor expired
+func (model ModelConfig) ActiveMemberVerification(tokenHash string, tenantId int) (verification TblMemberVerifications, err error) {
+
+	if err = model.DB.Debug().Table("tbl_member_verifications").Where("token_hash = ? and tenant_id = ? and verified_on is null and expires_on > ?", tokenHash, tenantId, time.Now().UTC()).First(&verification).Error; err != nil {
+
+		return TblMemberVerifications{}, err
+	}
+
+	return verification, nil
+}
+
+// UnverifiedMemberByEmail returns the member of an email that was sent a verification and never verified it. Members
+// deactivated by an admin are not returned, so a verification can not activate them again.
+func (model ModelConfig) UnverifiedMemberByEmail(email string, tenantId int) (member UnverifiedMember, err error) {
+
+	err = model.DB.Debug().Table("tbl_members as tm").Select("tm.id, tm.first_name, tm.email").
+		Where("LOWER(TRIM(tm.email)) = LOWER(TRIM(?)) and tm.tenant_id = ? and tm.is_deleted = 0 and tm.is_active = 0", email, tenantId).
+		Where("exists (select 1 from tbl_member_verifications as mv where mv.member_id = tm.id)").
+		Where("not exists (select 1 from tbl_member_verifications as mv where mv.member_id = tm.id and mv.verified_on is not null)").
+		Take(&member).Error
+
+	if err != nil {
+
+		return UnverifiedMember{}, err
+	}
+
+	return member, nil
+}
+
+// LastMemberVerification returns the creation time of the latest verification sent to a member, zero when there is none
+func (model ModelConfig) LastMemberVerification(memberId, tenantId int) (createdOn time.Time, err error) {
+
+	var verification TblMemberVerifications
+
+	err = model.DB.Debug().Table("tbl_member_verifications").Where("member_id = ? and tenant_id = ?", memberId, tenantId).Order("created_on desc").First(&verification).Error
+
+	if err == gorm.ErrRecordNotFound {
+
+		return time.Time{}, nil
+	}
+
+	return verification.CreatedOn, err
+}
+
+// VerifyMember marks the token as used, voids the other open tokens of the member and activates the member
+func (model ModelConfig) VerifyMember(verification TblMemberVerifications) error {
+
+	currentTime := time.Now().UTC()
+
+	return model.DB.Transaction(func(tx *gorm.DB) error {
+
+		result := tx.Debug().Table("tbl_member_verifications").Where("id = ? and verified_on is null", verification.Id).UpdateColumn("verified_on", currentTime)
+
+		if result.Error != nil {
+
+			return result.Error
+		}
+
+		// the token was used by a concurrent request
+		if result.RowsAffected == 0 {
+
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Debug().Table("tbl_member_verifications").Where("member_id = ? and tenant_id = ? and verified_on is null", verification.MemberId, verification.TenantId).UpdateColumn("expires_on", currentTime).Error; err != nil {
+
+			return err
+		}
+
+		return tx.Debug().Table("tbl_members").Where("id = ? and tenant_id = ? and is_deleted = 0", verification.MemberId, verification.TenantId).UpdateColumns(map[string]interface{}{"is_active": 1, "modified_on": currentTime}).Error
+	})
+}
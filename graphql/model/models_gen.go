@@ -3,7 +3,10 @@
 package model
 
 import (
+	"fmt"
+	"io"
 	"spurt-cms/graphql/scalars"
+	"strconv"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql"
@@ -59,6 +62,14 @@ type CategoryFilter struct {
 	ChannelSlug          graphql.Omittable[*string] `json:"channelSlug,omitempty"`
 }
 
+type CategoryNode struct {
+	Category   *Category      `json:"category"`
+	Path       string         `json:"path"`
+	Depth      int            `json:"depth"`
+	EntryCount int            `json:"entryCount"`
+	Children   []CategoryNode `json:"children"`
+}
+
 type Channel struct {
 	ID                 int        `json:"id"`
 	ChannelName        string     `json:"channelName"`
@@ -74,11 +85,22 @@ type Channel struct {
 	TenantID           int        `json:"tenantId"`
 }
 
+type ChannelConnection struct {
+	Edges      []ChannelEdge `json:"edges"`
+	PageInfo   *PageInfo     `json:"pageInfo"`
+	TotalCount int           `json:"totalCount"`
+}
+
 type ChannelDetails struct {
 	Channellist []Channel `json:"channellist"`
 	Count       int       `json:"count"`
 }
 
+type ChannelEdge struct {
+	Node   *Channel `json:"node"`
+	Cursor string   `json:"cursor"`
+}
+
 type ChannelEntries struct {
 	ID               int                  `json:"id"`
 	Title            string               `json:"title"`
@@ -115,6 +137,19 @@ type ChannelEntries struct {
 	MemberProfile    *MemberProfile       `json:"memberProfile,omitempty"`
 	TenantID         int                  `json:"tenantId"`
 	ContentChunk     *Chunk               `json:"contentChunk,omitempty"`
+	Breadcrumbs      []Category           `json:"breadcrumbs"`
+	Related          []ChannelEntries     `json:"related"`
+}
+
+type ChannelEntriesConnection struct {
+	Edges      []ChannelEntriesEdge `json:"edges"`
+	PageInfo   *PageInfo            `json:"pageInfo"`
+	TotalCount int                  `json:"totalCount"`
+}
+
+type ChannelEntriesEdge struct {
+	Node   *ChannelEntries `json:"node"`
+	Cursor string          `json:"cursor"`
 }
 
 type ChannelEntryDetails struct {
@@ -132,6 +167,32 @@ type CountUpdate struct {
 	Status bool `json:"status"`
 }
 
+type CreateEntryInput struct {
+	ChannelID        int                                  `json:"channelId"`
+	Title            string                               `json:"title"`
+	Slug             graphql.Omittable[*string]           `json:"slug,omitempty"`
+	Description      graphql.Omittable[*string]           `json:"description,omitempty"`
+	CoverImage       graphql.Omittable[*string]           `json:"coverImage,omitempty"`
+	Author           graphql.Omittable[*string]           `json:"author,omitempty"`
+	CreateTime       graphql.Omittable[*time.Time]        `json:"createTime,omitempty"`
+	PublishedTime    graphql.Omittable[*time.Time]        `json:"publishedTime,omitempty"`
+	ReadingTime      graphql.Omittable[*int]              `json:"readingTime,omitempty"`
+	SortOrder        graphql.Omittable[*int]              `json:"sortOrder,omitempty"`
+	Tags             graphql.Omittable[*string]           `json:"tags,omitempty"`
+	Excerpt          graphql.Omittable[*string]           `json:"excerpt,omitempty"`
+	Status           graphql.Omittable[*int]              `json:"status,omitempty"`
+	CategoryIds      graphql.Omittable[[]int]             `json:"categoryIds,omitempty"`
+	Seo              graphql.Omittable[*EntrySeoInput]    `json:"seo,omitempty"`
+	AdditionalFields graphql.Omittable[[]EntryFieldInput] `json:"additionalFields,omitempty"`
+}
+
+type DeletedEntry struct {
+	ID        int       `json:"id"`
+	ChannelID int       `json:"channelId"`
+	TenantID  int       `json:"tenantId"`
+	DeletedOn time.Time `json:"deletedOn"`
+}
+
 type EntriesAdditionalData struct {
 	AuthorDetails    graphql.Omittable[*bool] `json:"authorDetails,omitempty"`
 	MemberProfile    graphql.Omittable[*bool] `json:"memberProfile,omitempty"`
@@ -147,6 +208,30 @@ type EntriesFilter struct {
 	Status             graphql.Omittable[*string] `json:"Status,omitempty"`
 }
 
+type EntryDayViews struct {
+	Day   time.Time `json:"day"`
+	Views int       `json:"views"`
+}
+
+type EntryFieldInput struct {
+	FieldID    int                        `json:"fieldId"`
+	FieldName  graphql.Omittable[*string] `json:"fieldName,omitempty"`
+	FieldValue string                     `json:"fieldValue"`
+}
+
+type EntrySeoInput struct {
+	MetaTitle       graphql.Omittable[*string] `json:"metaTitle,omitempty"`
+	MetaDescription graphql.Omittable[*string] `json:"metaDescription,omitempty"`
+	MetaKeywords    graphql.Omittable[*string] `json:"metaKeywords,omitempty"`
+	ImageAltTag     graphql.Omittable[*string] `json:"imageAltTag,omitempty"`
+}
+
+type EntryViewStats struct {
+	EntryID int             `json:"entryId"`
+	Total   int             `json:"total"`
+	Days    []EntryDayViews `json:"days"`
+}
+
 type Field struct {
 	ID               int            `json:"id"`
 	FieldName        string         `json:"fieldName"`
@@ -181,6 +266,20 @@ type FieldOptions struct {
 	TenantID    int        `json:"tenantId"`
 }
 
+type FieldPredicate struct {
+	FieldID   graphql.Omittable[*int]     `json:"fieldId,omitempty"`
+	FieldName graphql.Omittable[*string]  `json:"fieldName,omitempty"`
+	Op        FieldOperator               `json:"op"`
+	Value     graphql.Omittable[*string]  `json:"value,omitempty"`
+	Values    graphql.Omittable[[]string] `json:"values,omitempty"`
+}
+
+type FieldSort struct {
+	FieldID   graphql.Omittable[*int]    `json:"fieldId,omitempty"`
+	FieldName graphql.Omittable[*string] `json:"fieldName,omitempty"`
+	Order     graphql.Omittable[*int]    `json:"order,omitempty"`
+}
+
 type FieldValue struct {
 	ID         int        `json:"id"`
 	FieldValue string     `json:"fieldValue"`
@@ -203,6 +302,13 @@ type MemberArguments struct {
 	TenantID graphql.Omittable[*int] `json:"tenantId,omitempty"`
 }
 
+type MemberAuth struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	Member       *Members  `json:"member"`
+}
+
 type MemberDetails struct {
 	FirstName        string                     `json:"firstName"`
 	LastName         graphql.Omittable[*string] `json:"lastName,omitempty"`
@@ -216,6 +322,34 @@ type MemberDetails struct {
 	GroupID          graphql.Omittable[*int]    `json:"groupId,omitempty"`
 }
 
+type MemberLoginInput struct {
+	Email    graphql.Omittable[*string] `json:"email,omitempty"`
+	Username graphql.Omittable[*string] `json:"username,omitempty"`
+	Password string                     `json:"password"`
+}
+
+type MemberNote struct {
+	ID            int                    `json:"id"`
+	EntryID       int                    `json:"entryId"`
+	Type          MemberNoteType         `json:"type"`
+	Content       string                 `json:"content"`
+	Configuration map[string]interface{} `json:"configuration,omitempty"`
+	CreatedOn     time.Time              `json:"createdOn"`
+	ModifiedOn    *time.Time             `json:"modifiedOn,omitempty"`
+}
+
+type MemberNoteInput struct {
+	EntryID       int                                       `json:"entryId"`
+	Type          MemberNoteType                            `json:"type"`
+	Content       string                                    `json:"content"`
+	Configuration graphql.Omittable[map[string]interface{}] `json:"configuration,omitempty"`
+}
+
+type MemberNoteList struct {
+	Notes []MemberNote `json:"notes"`
+	Count int          `json:"count"`
+}
+
 type MemberProfile struct {
 	ID              int        `json:"id"`
 	MemberID        int        `json:"memberId"`
@@ -243,6 +377,22 @@ type MemberProfile struct {
 	ClaimDate       *time.Time `json:"claimDate,omitempty"`
 }
 
+type MemberProfileInput struct {
+	ProfileName     graphql.Omittable[*string] `json:"profileName,omitempty"`
+	ProfileSlug     graphql.Omittable[*string] `json:"profileSlug,omitempty"`
+	ProfilePage     graphql.Omittable[*string] `json:"profilePage,omitempty"`
+	CompanyName     graphql.Omittable[*string] `json:"companyName,omitempty"`
+	CompanyLocation graphql.Omittable[*string] `json:"companyLocation,omitempty"`
+	CompanyLogo     graphql.Omittable[*string] `json:"companyLogo,omitempty"`
+	About           graphql.Omittable[*string] `json:"about,omitempty"`
+	SeoTitle        graphql.Omittable[*string] `json:"seoTitle,omitempty"`
+	SeoDescription  graphql.Omittable[*string] `json:"seoDescription,omitempty"`
+	SeoKeyword      graphql.Omittable[*string] `json:"seoKeyword,omitempty"`
+	Linkedin        graphql.Omittable[*string] `json:"linkedin,omitempty"`
+	Twitter         graphql.Omittable[*string] `json:"twitter,omitempty"`
+	Website         graphql.Omittable[*string] `json:"website,omitempty"`
+}
+
 type MemberSettings struct {
 	ID                int        `json:"id"`
 	AllowRegistration int        `json:"allowRegistration"`
@@ -273,17 +423,65 @@ type Members struct {
 	IsDeleted        int        `json:"isDeleted"`
 }
 
+type MembersConnection struct {
+	Edges      []MembersEdge `json:"edges"`
+	PageInfo   *PageInfo     `json:"pageInfo"`
+	TotalCount int           `json:"totalCount"`
+}
+
 type MembersDetails struct {
 	MembersList []Members `json:"membersList"`
 	Count       int       `json:"count"`
 }
 
+type MembersEdge struct {
+	Node   *Members `json:"node"`
+	Cursor string   `json:"cursor"`
+}
+
 type Mutation struct {
 }
 
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
+type PopularEntry struct {
+	Entry *ChannelEntries `json:"entry"`
+	Views int             `json:"views"`
+}
+
 type Query struct {
 }
 
+type SearchFacetCount struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type SearchFacets struct {
+	Channels   []SearchFacetCount `json:"channels,omitempty"`
+	Categories []SearchFacetCount `json:"categories,omitempty"`
+	Tags       []SearchFacetCount `json:"tags,omitempty"`
+}
+
+type SearchHit struct {
+	Entry        *ChannelEntries `json:"entry"`
+	Score        float64         `json:"score"`
+	TitleSnippet string          `json:"titleSnippet"`
+	Snippet      string          `json:"snippet"`
+}
+
+type SearchResults struct {
+	Hits   []SearchHit   `json:"hits"`
+	Count  int           `json:"count"`
+	Facets *SearchFacets `json:"facets"`
+}
+
 type Section struct {
 	ID            int        `json:"id"`
 	SectionName   string     `json:"sectionName"`
@@ -300,3 +498,339 @@ type Sort struct {
 	SortBy graphql.Omittable[*string] `json:"sortBy,omitempty"`
 	Order  graphql.Omittable[*int]    `json:"order,omitempty"`
 }
+
+type Subscription struct {
+}
+
+type UpdateEntryInput struct {
+	ChannelID        graphql.Omittable[*int]              `json:"channelId,omitempty"`
+	Title            graphql.Omittable[*string]           `json:"title,omitempty"`
+	Slug             graphql.Omittable[*string]           `json:"slug,omitempty"`
+	Description      graphql.Omittable[*string]           `json:"description,omitempty"`
+	CoverImage       graphql.Omittable[*string]           `json:"coverImage,omitempty"`
+	Author           graphql.Omittable[*string]           `json:"author,omitempty"`
+	CreateTime       graphql.Omittable[*time.Time]        `json:"createTime,omitempty"`
+	PublishedTime    graphql.Omittable[*time.Time]        `json:"publishedTime,omitempty"`
+	ReadingTime      graphql.Omittable[*int]              `json:"readingTime,omitempty"`
+	SortOrder        graphql.Omittable[*int]              `json:"sortOrder,omitempty"`
+	Tags             graphql.Omittable[*string]           `json:"tags,omitempty"`
+	Excerpt          graphql.Omittable[*string]           `json:"excerpt,omitempty"`
+	Status           graphql.Omittable[*int]              `json:"status,omitempty"`
+	CategoryIds      graphql.Omittable[[]int]             `json:"categoryIds,omitempty"`
+	Seo              graphql.Omittable[*EntrySeoInput]    `json:"seo,omitempty"`
+	AdditionalFields graphql.Omittable[[]EntryFieldInput] `json:"additionalFields,omitempty"`
+}
+
+type UpdateMemberNoteInput struct {
+	Content       graphql.Omittable[*string]                `json:"content,omitempty"`
+	Configuration graphql.Omittable[map[string]interface{}] `json:"configuration,omitempty"`
+}
+
+type FieldOperator string
+
+const (
+	FieldOperatorEq       FieldOperator = "EQ"
+	FieldOperatorNe       FieldOperator = "NE"
+	FieldOperatorIn       FieldOperator = "IN"
+	FieldOperatorLt       FieldOperator = "LT"
+	FieldOperatorGt       FieldOperator = "GT"
+	FieldOperatorContains FieldOperator = "CONTAINS"
+	FieldOperatorExists   FieldOperator = "EXISTS"
+)
+
+var AllFieldOperator = []FieldOperator{
+	FieldOperatorEq,
+	FieldOperatorNe,
+	FieldOperatorIn,
+	FieldOperatorLt,
+	FieldOperatorGt,
+	FieldOperatorContains,
+	FieldOperatorExists,
+}
+
+func (e FieldOperator) IsValid() bool {
+	switch e {
+	case FieldOperatorEq, FieldOperatorNe, FieldOperatorIn, FieldOperatorLt, FieldOperatorGt, FieldOperatorContains, FieldOperatorExists:
+		return true
+	}
+	return false
+}
+
+func (e FieldOperator) String() string {
+	return string(e)
+}
+
+func (e *FieldOperator) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FieldOperator(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FieldOperator", str)
+	}
+	return nil
+}
+
+func (e FieldOperator) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImageFit string
+
+const (
+	ImageFitContain ImageFit = "CONTAIN"
+	ImageFitCover   ImageFit = "COVER"
+	ImageFitFill    ImageFit = "FILL"
+)
+
+var AllImageFit = []ImageFit{
+	ImageFitContain,
+	ImageFitCover,
+	ImageFitFill,
+}
+
+func (e ImageFit) IsValid() bool {
+	switch e {
+	case ImageFitContain, ImageFitCover, ImageFitFill:
+		return true
+	}
+	return false
+}
+
+func (e ImageFit) String() string {
+	return string(e)
+}
+
+func (e *ImageFit) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImageFit(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImageFit", str)
+	}
+	return nil
+}
+
+func (e ImageFit) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImageFormat string
+
+const (
+	ImageFormatJpeg ImageFormat = "JPEG"
+	ImageFormatPng  ImageFormat = "PNG"
+)
+
+var AllImageFormat = []ImageFormat{
+	ImageFormatJpeg,
+	ImageFormatPng,
+}
+
+func (e ImageFormat) IsValid() bool {
+	switch e {
+	case ImageFormatJpeg, ImageFormatPng:
+		return true
+	}
+	return false
+}
+
+func (e ImageFormat) String() string {
+	return string(e)
+}
+
+func (e *ImageFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImageFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImageFormat", str)
+	}
+	return nil
+}
+
+func (e ImageFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type MemberNoteType string
+
+const (
+	MemberNoteTypeNote      MemberNoteType = "NOTE"
+	MemberNoteTypeHighlight MemberNoteType = "HIGHLIGHT"
+)
+
+var AllMemberNoteType = []MemberNoteType{
+	MemberNoteTypeNote,
+	MemberNoteTypeHighlight,
+}
+
+func (e MemberNoteType) IsValid() bool {
+	switch e {
+	case MemberNoteTypeNote, MemberNoteTypeHighlight:
+		return true
+	}
+	return false
+}
+
+func (e MemberNoteType) String() string {
+	return string(e)
+}
+
+func (e *MemberNoteType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MemberNoteType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MemberNoteType", str)
+	}
+	return nil
+}
+
+func (e MemberNoteType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Scope string
+
+const (
+	ScopeRead       Scope = "READ"
+	ScopeWrite      Scope = "WRITE"
+	ScopeMemberData Scope = "MEMBER_DATA"
+)
+
+var AllScope = []Scope{
+	ScopeRead,
+	ScopeWrite,
+	ScopeMemberData,
+}
+
+func (e Scope) IsValid() bool {
+	switch e {
+	case ScopeRead, ScopeWrite, ScopeMemberData:
+		return true
+	}
+	return false
+}
+
+func (e Scope) String() string {
+	return string(e)
+}
+
+func (e *Scope) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Scope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Scope", str)
+	}
+	return nil
+}
+
+func (e Scope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SearchFacet string
+
+const (
+	SearchFacetChannel  SearchFacet = "CHANNEL"
+	SearchFacetCategory SearchFacet = "CATEGORY"
+	SearchFacetTag      SearchFacet = "TAG"
+)
+
+var AllSearchFacet = []SearchFacet{
+	SearchFacetChannel,
+	SearchFacetCategory,
+	SearchFacetTag,
+}
+
+func (e SearchFacet) IsValid() bool {
+	switch e {
+	case SearchFacetChannel, SearchFacetCategory, SearchFacetTag:
+		return true
+	}
+	return false
+}
+
+func (e SearchFacet) String() string {
+	return string(e)
+}
+
+func (e *SearchFacet) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchFacet(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchFacet", str)
+	}
+	return nil
+}
+
+func (e SearchFacet) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ViewPeriod string
+
+const (
+	ViewPeriodDay     ViewPeriod = "DAY"
+	ViewPeriodWeek    ViewPeriod = "WEEK"
+	ViewPeriodMonth   ViewPeriod = "MONTH"
+	ViewPeriodYear    ViewPeriod = "YEAR"
+	ViewPeriodAllTime ViewPeriod = "ALL_TIME"
+)
+
+var AllViewPeriod = []ViewPeriod{
+	ViewPeriodDay,
+	ViewPeriodWeek,
+	ViewPeriodMonth,
+	ViewPeriodYear,
+	ViewPeriodAllTime,
+}
+
+func (e ViewPeriod) IsValid() bool {
+	switch e {
+	case ViewPeriodDay, ViewPeriodWeek, ViewPeriodMonth, ViewPeriodYear, ViewPeriodAllTime:
+		return true
+	}
+	return false
+}
+
+func (e ViewPeriod) String() string {
+	return string(e)
+}
+
+func (e *ViewPeriod) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ViewPeriod(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ViewPeriod", str)
+	}
+	return nil
+}
+
+func (e ViewPeriod) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/spurtcms/channels"
+	"gorm.io/gorm"
+)
+
+type RelatedEntriesReq struct {
+	EntryId       int
+	CategoryIds   []int
+	Tags          []string
+	ChannelIds    []int
+	MemberGroupId int
+	Limit         int
+	TenantId      int
+}
+
+// PublishedEntriesByIds returns the published entries of live channels among the ids
+func (model ModelConfig) PublishedEntriesByIds(entryIds []int, tenantId int) (entries map[int]channels.Tblchannelentries, err error) {
+
+	var entryList []channels.Tblchannelentries
+
+	err = model.DB.Debug().Table("tbl_channel_entries as en").Select("en.*").
+		Joins("inner join tbl_channels as tc on tc.id = en.channel_id").
+		Where("en.id in (?) and en.tenant_id = ? and en.is_deleted = 0 and en.status = 1 and tc.is_deleted = 0", entryIds, tenantId).
+		Find(&entryList).Error
+
+	if err != nil {
+
+		return map[int]channels.Tblchannelentries{}, err
+	}
+
+	entries = make(map[int]channels.Tblchannelentries, len(entryList))
+
+	for _, entry := range entryList {
+
+		entries[entry.Id] = entry
+	}
+
+	return entries, nil
+}
+
+// relatedCandidate is a candidate with the position of the entry it was read for
+type relatedCandidate struct {
+	channels.Tblchannelentries `gorm:"embedded"`
+	CandidateSet               int `gorm:"column:candidate_set"`
+}
+
+// RelatedEntryCandidates returns the latest published entries that look like they share a category or a tag with each
+// of the entries, most recent first and in the order of the inputs. The candidates of every entry are read with one
+// query. Tags are matched loosely here, the caller compares them exactly.
+func (model ModelConfig) RelatedEntryCandidates(inputs []RelatedEntriesReq) (candidates [][]channels.Tblchannelentries, err error) {
+
+	candidates = make([][]channels.Tblchannelentries, len(inputs))
+
+	var (
+		sets    []string
+		queries []interface{}
+	)
+
+	for index, input := range inputs {
+
+		if query := model.relatedCandidatesQuery(input, index); query != nil {
+
+			sets = append(sets, "(?)")
+
+			queries = append(queries, query)
+		}
+	}
+
+	if len(queries) == 0 {
+
+		return candidates, nil
+	}
+
+	var rows []relatedCandidate
+
+	if err = model.DB.Debug().Raw("select * from ("+strings.Join(sets, " union all ")+") as candidates order by candidate_set, coalesce(published_time, created_on) desc, id desc", queries...).Scan(&rows).Error; err != nil {
+
+		return make([][]channels.Tblchannelentries, len(inputs)), err
+	}
+
+	for _, row := range rows {
+
+		candidates[row.CandidateSet] = append(candidates[row.CandidateSet], row.Tblchannelentries)
+	}
+
+	return candidates, nil
+}
+
+// relatedCandidatesQuery reads the candidates of one entry, nil when the entry has neither a category nor a tag
+func (model ModelConfig) relatedCandidatesQuery(inputs RelatedEntriesReq, set int) *gorm.DB {
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	for _, categoryId := range inputs.CategoryIds {
+
+		if model.DB.Config.Dialector.Name() == "mysql" {
+
+			conditions = append(conditions, "find_in_set(?, en.categories_id) > 0")
+
+		} else {
+
+			conditions = append(conditions, "? = any(string_to_array(en.categories_id, ',')::Integer[])")
+		}
+
+		args = append(args, categoryId)
+	}
+
+	for _, tag := range inputs.Tags {
+
+		conditions = append(conditions, "LOWER(en.tags) LIKE ?")
+
+		args = append(args, "%"+tag+"%")
+	}
+
+	if len(conditions) == 0 {
+
+		return nil
+	}
+
+	query := model.DB.Table("tbl_channel_entries as en").Select("en.*, "+strconv.Itoa(set)+" as candidate_set").
+		Joins("inner join tbl_channels as tc on tc.id = en.channel_id").
+		Where("en.id <> ? and en.tenant_id = ? and en.is_deleted = 0 and en.status = 1 and tc.is_deleted = 0", inputs.EntryId, inputs.TenantId).
+		Where("("+strings.Join(conditions, " or ")+")", args...)
+
+	if len(inputs.ChannelIds) > 0 {
+
+		query = query.Where("en.channel_id in (?)", inputs.ChannelIds)
+	}
+
+	query = query.Where("en.id not in (?)", model.hiddenEntries(inputs.MemberGroupId, inputs.TenantId))
+
+	return query.Order("coalesce(en.published_time, en.created_on) desc, en.id desc").Limit(inputs.Limit)
+}
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/spurtcms/categories"
+	"github.com/spurtcms/member"
+	"github.com/spurtcms/team"
+)
+
+// batch queries behind the entry list loaders, each one reads a relation for a whole page of entries
+
+type ChannelField struct {
+	Id               int
+	ChannelId        int
+	FieldName        string
+	FieldDesc        string
+	FieldTypeId      int
+	MandatoryField   int
+	OptionExist      int
+	CreatedOn        time.Time
+	CreatedBy        int
+	ModifiedOn       time.Time
+	ModifiedBy       int
+	OrderIndex       int
+	ImagePath        string
+	DatetimeFormat   string
+	TimeFormat       string
+	SectionParentId  int
+	CharacterAllowed int
+	TypeName         string
+	TenantId         int
+	FieldOptions     []TblFieldOption `gorm:"-"`
+}
+
+type TblFieldOption struct {
+	Id          int
+	OptionName  string
+	OptionValue string
+	FieldId     int
+	CreatedOn   time.Time
+	CreatedBy   int
+	ModifiedOn  time.Time
+	ModifiedBy  int
+	TenantId    int
+}
+
+type EntryFieldValue struct {
+	Id             int
+	FieldId        int
+	FieldTypeId    int
+	FieldValue     string
+	ChannelEntryId int
+	CreatedOn      time.Time
+	CreatedBy      int
+	ModifiedOn     time.Time
+	ModifiedBy     int
+	TenantId       int
+}
+
+func (model ModelConfig) AuthorsByIds(userIds []int) (authors map[int]team.TblUser, err error) {
+
+	var users []team.TblUser
+
+	if err = model.DB.Debug().Table("tbl_users").Where("id in (?) and is_deleted = 0", userIds).Find(&users).Error; err != nil {
+
+		return map[int]team.TblUser{}, err
+	}
+
+	authors = make(map[int]team.TblUser, len(users))
+
+	for _, user := range users {
+
+		authors[user.Id] = user
+	}
+
+	return authors, nil
+}
+
+// CategoriesWithParents reads the given categories together with all of their parents in one recursive query
+func (model ModelConfig) CategoriesWithParents(categoryIds []int) (categoriesById map[int]categories.TblCategories, err error) {
+
+	ids := make([]string, len(categoryIds))
+
+	for index, id := range categoryIds {
+
+		ids[index] = strconv.Itoa(id)
+	}
+
+	var categoryList []categories.TblCategories
+
+	if err = categories.Categorymodel.GetHierarchicalCategoriesMappedInEntries(ids, &categoryList, model.DB, model.DB.Dialector.Name()); err != nil {
+
+		return map[int]categories.TblCategories{}, err
+	}
+
+	categoriesById = make(map[int]categories.TblCategories, len(categoryList))
+
+	for _, category := range categoryList {
+
+		categoriesById[category.Id] = category
+	}
+
+	return categoriesById, nil
+}
+
+func (model ModelConfig) MemberProfilesByMemberIds(memberIds []int, tenantId int) (profiles map[int]member.TblMemberProfile, err error) {
+
+	var profileList []member.TblMemberProfile
+
+	if err = model.DB.Debug().Table("tbl_member_profiles as tmp").Select("tmp.*").Joins("inner join tbl_members as tm on tm.id = tmp.member_id").Where("tmp.member_id in (?) and tmp.is_deleted = 0 and tm.is_deleted = 0 and tmp.tenant_id = ?", memberIds, tenantId).Find(&profileList).Error; err != nil {
+
+		return map[int]member.TblMemberProfile{}, err
+	}
+
+	profiles = make(map[int]member.TblMemberProfile, len(profileList))
+
+	for _, profile := range profileList {
+
+		profiles[profile.MemberId] = profile
+	}
+
+	return profiles, nil
+}
+
+// ChannelFieldsByChannelIds reads the additional field definitions of the channels, options included
+func (model ModelConfig) ChannelFieldsByChannelIds(channelIds []int, tenantId int) (channelFields map[int][]ChannelField, err error) {
+
+	var fields []ChannelField
+
+	if err = model.DB.Debug().Table("tbl_group_fields as tgf").Select("tf.*, tgf.channel_id, tft.type_name").Joins("inner join tbl_fields as tf on tf.id = tgf.field_id").Joins("left join tbl_field_types as tft on tft.id = tf.field_type_id").Where("tf.is_deleted = 0 and tgf.channel_id in (?)", channelIds).Order("tf.order_index, tf.id").Find(&fields).Error; err != nil {
+
+		return map[int][]ChannelField{}, err
+	}
+
+	var optionFieldIds []int
+
+	for _, field := range fields {
+
+		if field.OptionExist == 1 {
+
+			optionFieldIds = append(optionFieldIds, field.Id)
+		}
+	}
+
+	fieldOptions := make(map[int][]TblFieldOption)
+
+	if len(optionFieldIds) > 0 {
+
+		var options []TblFieldOption
+
+		if err = model.DB.Debug().Table("tbl_field_options").Where("is_deleted = 0 and field_id in (?) and tenant_id = ?", optionFieldIds, tenantId).Order("id").Find(&options).Error; err != nil {
+
+			return map[int][]ChannelField{}, err
+		}
+
+		for _, option := range options {
+
+			fieldOptions[option.FieldId] = append(fieldOptions[option.FieldId], option)
+		}
+	}
+
+	channelFields = make(map[int][]ChannelField, len(channelIds))
+
+	for _, field := range fields {
+
+		field.FieldOptions = fieldOptions[field.Id]
+
+		channelFields[field.ChannelId] = append(channelFields[field.ChannelId], field)
+	}
+
+	return channelFields, nil
+}
+
+func (model ModelConfig) EntryFieldValuesByEntryIds(entryIds []int, tenantId int) (entryFields map[int][]EntryFieldValue, err error) {
+
+	var values []EntryFieldValue
+
+	if err = model.DB.Debug().Table("tbl_channel_entry_fields as cef").Select("cef.*, tf.field_type_id").Joins("inner join tbl_fields as tf on tf.id = cef.field_id").Where("cef.channel_entry_id in (?) and cef.tenant_id = ? and tf.is_deleted = 0", entryIds, tenantId).Order("cef.id").Find(&values).Error; err != nil {
+
+		return map[int][]EntryFieldValue{}, err
+	}
+
+	entryFields = make(map[int][]EntryFieldValue, len(entryIds))
+
+	for _, value := range values {
+
+		entryFields[value.ChannelEntryId] = append(entryFields[value.ChannelEntryId], value)
+	}
+
+	return entryFields, nil
+}
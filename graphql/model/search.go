@@ -0,0 +1,257 @@
+package model
+
+import (
+	"spurt-cms/graphql/search"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// field types whose values are indexed with the entry text
+var searchFieldTypes = []string{"text", "textbox", "textarea", "texteditor"}
+
+// the matches of a search are ranked and counted in memory, so a query reads at most this many of them
+const MaxSearchMatches = 1000
+
+// TblGraphqlSearchEntries is the search document of a published entry. Postgres keeps the weighted tsvector of the
+// document in search_vector, mysql matches the fulltext index over title, tags and content.
+type TblGraphqlSearchEntries struct {
+	Id            int
+	EntryId       int
+	ChannelId     int
+	Title         string
+	Tags          string
+	Content       string
+	CategoriesId  string
+	PublishedTime time.Time
+	TenantId      int
+}
+
+// SearchEntrySource is the entry text a search document is built from
+type SearchEntrySource struct {
+	Id            int
+	ChannelId     int
+	Title         string
+	Excerpt       string
+	Description   string
+	Tags          string
+	CategoriesId  string
+	PublishedTime time.Time
+	TenantId      int
+	FieldValues   []string `gorm:"-"`
+}
+
+type SearchEntriesReq struct {
+	Terms         []string
+	ChannelId     int
+	CategorySlug  string
+	ChannelIds    []int
+	MemberGroupId int
+	TenantId      int
+}
+
+type SearchMatch struct {
+	EntryId      int
+	ChannelId    int
+	CategoriesId string
+	Tags         string
+	Score        float64
+}
+
+// SearchEntrySource returns the text of a published entry, gorm.ErrRecordNotFound when the entry should not be searchable
+func (model ModelConfig) SearchEntrySource(entryId int) (source SearchEntrySource, err error) {
+
+	err = model.DB.Debug().Table("tbl_channel_entries as en").
+		Select("en.id, en.channel_id, en.title, en.excerpt, en.description, en.tags, en.categories_id, coalesce(en.published_time, en.created_on) as published_time, en.tenant_id").
+		Joins("inner join tbl_channels as tc on tc.id = en.channel_id").
+		Where("en.id = ? and en.is_deleted = 0 and en.status = 1 and tc.is_deleted = 0", entryId).
+		Take(&source).Error
+
+	if err != nil {
+
+		return SearchEntrySource{}, err
+	}
+
+	err = model.DB.Debug().Table("tbl_channel_entry_fields as ef").
+		Joins("inner join tbl_fields as tf on tf.id = ef.field_id").
+		Joins("inner join tbl_field_types as ft on ft.id = tf.field_type_id").
+		Where("ef.channel_entry_id = ? and (ef.deleted_by is null or ef.deleted_by = 0) and tf.is_deleted = 0 and ft.type_slug in (?)", entryId, searchFieldTypes).
+		Order("ef.id").
+		Pluck("ef.field_value", &source.FieldValues).Error
+
+	if err != nil {
+
+		return SearchEntrySource{}, err
+	}
+
+	return source, nil
+}
+
+// IndexSearchEntry replaces the search document of an entry
+func (model ModelConfig) IndexSearchEntry(document *TblGraphqlSearchEntries) error {
+
+	return model.DB.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Debug().Table("tbl_graphql_search_entries").Where("entry_id = ?", document.EntryId).Delete(&TblGraphqlSearchEntries{}).Error; err != nil {
+
+			return err
+		}
+
+		if err := tx.Debug().Table("tbl_graphql_search_entries").Create(document).Error; err != nil {
+
+			return err
+		}
+
+		if model.DB.Config.Dialector.Name() == "mysql" {
+
+			return nil
+		}
+
+		// the title weighs more than the tags, which weigh more than the body
+		return tx.Debug().Exec(`update tbl_graphql_search_entries set search_vector = setweight(to_tsvector('simple', coalesce(title, '')), 'A') || setweight(to_tsvector('simple', coalesce(tags, '')), 'B') || setweight(to_tsvector('simple', coalesce(content, '')), 'C') where id = ?`, document.Id).Error
+	})
+}
+
+func (model ModelConfig) RemoveSearchEntry(entryId int) error {
+
+	if err := model.DB.Debug().Table("tbl_graphql_search_entries").Where("entry_id = ?", entryId).Delete(&TblGraphqlSearchEntries{}).Error; err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+// UnsyncedSearchEntryIds returns the published entries missing from the search index and the indexed entries that are
+// no longer published, e.g. after changes made while the server was down
+func (model ModelConfig) UnsyncedSearchEntryIds() (entryIds []int, err error) {
+
+	var missing, stale []int
+
+	err = model.DB.Debug().Table("tbl_channel_entries as en").
+		Joins("inner join tbl_channels as tc on tc.id = en.channel_id").
+		Where("en.is_deleted = 0 and en.status = 1 and tc.is_deleted = 0").
+		Where("not exists (select 1 from tbl_graphql_search_entries as se where se.entry_id = en.id)").
+		Pluck("en.id", &missing).Error
+
+	if err != nil {
+
+		return []int{}, err
+	}
+
+	err = model.DB.Debug().Table("tbl_graphql_search_entries as se").
+		Where("not exists (select 1 from tbl_channel_entries as en inner join tbl_channels as tc on tc.id = en.channel_id where en.id = se.entry_id and en.is_deleted = 0 and en.status = 1 and tc.is_deleted = 0)").
+		Pluck("se.entry_id", &stale).Error
+
+	if err != nil {
+
+		return []int{}, err
+	}
+
+	return append(missing, stale...), nil
+}
+
+// SearchEntryMatches returns the entries matching every term of the query, best match first
+func (model ModelConfig) SearchEntryMatches(inputs SearchEntriesReq) (matches []SearchMatch, err error) {
+
+	query := model.DB.Debug().Table("tbl_graphql_search_entries as se").Where("se.tenant_id = ?", inputs.TenantId)
+
+	var (
+		matchQuery        string
+		categoryCondition string
+		scoreSelect       string
+	)
+
+	if model.DB.Config.Dialector.Name() == "mysql" {
+
+		matchQuery = search.MysqlQuery(inputs.Terms)
+
+		query = query.Where("match(se.title, se.tags, se.content) against (? in boolean mode)", matchQuery)
+
+		scoreSelect = "match(se.title, se.tags, se.content) against (? in boolean mode) as score"
+
+		categoryCondition = `find_in_set(cat.id,se.categories_id) > 0`
+
+	} else {
+
+		matchQuery = search.PostgresQuery(inputs.Terms)
+
+		query = query.Where("se.search_vector @@ to_tsquery('simple', ?)", matchQuery)
+
+		scoreSelect = "ts_rank(se.search_vector, to_tsquery('simple', ?)) as score"
+
+		categoryCondition = `cat.id = any(string_to_array(se.categories_id,',')::Integer[])`
+	}
+
+	if inputs.ChannelId != 0 {
+
+		query = query.Where("se.channel_id = ?", inputs.ChannelId)
+	}
+
+	if len(inputs.ChannelIds) > 0 {
+
+		query = query.Where("se.channel_id in (?)", inputs.ChannelIds)
+	}
+
+	query = query.Where("se.entry_id not in (?)", model.hiddenEntries(inputs.MemberGroupId, inputs.TenantId))
+
+	// like the entry list, a category matches the entries of its child categories too
+	if inputs.CategorySlug != "" {
+
+		categoryIds := model.DB.Table("tbl_categories as pcat").Select("pcat.id").Where("pcat.is_deleted = 0 and pcat.tenant_id = ? and pcat.category_slug = ?", inputs.TenantId, inputs.CategorySlug)
+
+		categoryMatch := model.DB.Table("tbl_categories as cat").Select("1").Where(categoryCondition).Where("cat.is_deleted = 0").Where("(cat.id in (?) or cat.parent_id in (?))", categoryIds, categoryIds)
+
+		query = query.Where("exists (?)", categoryMatch)
+	}
+
+	err = query.Select("se.entry_id, se.channel_id, se.categories_id, se.tags, "+scoreSelect, matchQuery).
+		Order("score desc, se.published_time desc, se.entry_id desc").
+		Limit(MaxSearchMatches).
+		Scan(&matches).Error
+
+	if err != nil {
+
+		return []SearchMatch{}, err
+	}
+
+	return matches, nil
+}
+
+func (model ModelConfig) SearchEntriesByIds(entryIds []int) (documents map[int]TblGraphqlSearchEntries, err error) {
+
+	var rows []TblGraphqlSearchEntries
+
+	if err = model.DB.Debug().Table("tbl_graphql_search_entries").Where("entry_id in (?)", entryIds).Find(&rows).Error; err != nil {
+
+		return map[int]TblGraphqlSearchEntries{}, err
+	}
+
+	documents = make(map[int]TblGraphqlSearchEntries, len(rows))
+
+	for _, row := range rows {
+
+		documents[row.EntryId] = row
+	}
+
+	return documents, nil
+}
+
+func (model ModelConfig) ChannelsByIds(channelIds []int, tenantId int) (channelsById map[int]TblChannel, err error) {
+
+	var channelList []TblChannel
+
+	if err = model.DB.Debug().Table("tbl_channels").Where("id in (?) and tenant_id = ? and is_deleted = 0", channelIds, tenantId).Find(&channelList).Error; err != nil {
+
+		return map[int]TblChannel{}, err
+	}
+
+	channelsById = make(map[int]TblChannel, len(channelList))
+
+	for _, channel := range channelList {
+
+		channelsById[channel.Id] = channel
+	}
+
+	return channelsById, nil
+}
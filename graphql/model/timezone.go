@@ -0,0 +1,21 @@
+package model
+
+// TimeZone returns the time zone a tenant set in its general settings, empty when it has none
+func (model ModelConfig) TimeZone(tenantId int) (timeZone string, err error) {
+
+	var settings []struct {
+		TimeZone string
+	}
+
+	if err = model.DB.Debug().Table("tbl_general_settings").Select("time_zone").Where("tenant_id = ?", tenantId).Limit(1).Find(&settings).Error; err != nil {
+
+		return "", err
+	}
+
+	if len(settings) == 0 {
+
+		return "", nil
+	}
+
+	return settings[0].TimeZone, nil
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"spurt-cms/graphql/usage"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TblGraphqlUsages struct {
+	Id           int
+	TokenId      int
+	UsageDate    time.Time
+	RequestCount int
+	ErrorCount   int
+	TenantId     int
+}
+
+// SaveApiKeyUsage adds the counters to the daily usage rows of the api keys and moves their last used time forward
+func (model ModelConfig) SaveApiKeyUsage(counters []usage.Counter) error {
+
+	return model.DB.Transaction(func(tx *gorm.DB) error {
+
+		for _, counter := range counters {
+
+			result := tx.Debug().Table("tbl_graphql_usages").Where("token_id = ? and usage_date = ?", counter.TokenId, counter.Day).UpdateColumns(map[string]interface{}{
+				"request_count": gorm.Expr("request_count + ?", counter.Requests),
+				"error_count":   gorm.Expr("error_count + ?", counter.Errors),
+			})
+
+			if result.Error != nil {
+
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+
+				row := TblGraphqlUsages{TokenId: counter.TokenId, UsageDate: counter.Day, RequestCount: counter.Requests, ErrorCount: counter.Errors, TenantId: counter.TenantId}
+
+				if err := tx.Debug().Table("tbl_graphql_usages").Create(&row).Error; err != nil {
+
+					return err
+				}
+			}
+
+			if counter.LastUsedOn.IsZero() {
+
+				continue
+			}
+
+			if err := tx.Debug().Table("tbl_graphql_settings").Where("id = ? and (last_used_on is null or last_used_on < ?)", counter.TokenId, counter.LastUsedOn).UpdateColumn("last_used_on", counter.LastUsedOn).Error; err != nil {
+
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,271 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"spurt-cms/graphql/info"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// sort keys accepted by the connection queries, id is always used as the tie breaker
+const (
+	SortById            = "id"
+	SortByCreatedOn     = "createdOn"
+	SortByPublishedTime = "publishedTime"
+	SortBySortOrder     = "sortOrder"
+)
+
+// Cursor points at a single row of a sorted list, it holds the sort key it was made for so that a cursor
+// taken with one sort can not be replayed against another
+type Cursor struct {
+	SortKey string `json:"k"`
+	Value   string `json:"v"`
+	Id      int    `json:"i"`
+}
+
+type Args struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+// Window is the resolved page request handed to the model layer
+type Window struct {
+	SortKey  string
+	Desc     bool
+	Limit    int
+	Backward bool
+	Cursor   *Cursor
+}
+
+// FieldSortKey is the sort key of a list sorted by an additional field, a cursor of one field order can not be replayed
+// against another
+func FieldSortKey(field string, desc bool) string {
+
+	if desc {
+
+		return "field:" + field + ":desc"
+	}
+
+	return "field:" + field
+}
+
+func TimeCursor(sortKey string, value time.Time, id int) Cursor {
+
+	return Cursor{SortKey: sortKey, Value: value.UTC().Format(time.RFC3339Nano), Id: id}
+}
+
+func IntCursor(sortKey string, value int, id int) Cursor {
+
+	return Cursor{SortKey: sortKey, Value: strconv.Itoa(value), Id: id}
+}
+
+func (cursor Cursor) Encode() string {
+
+	data, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (cursor Cursor) Time() (time.Time, error) {
+
+	value, err := time.Parse(time.RFC3339Nano, cursor.Value)
+
+	if err != nil {
+
+		return time.Time{}, info.ErrInvalidCursor
+	}
+
+	return value, nil
+}
+
+func (cursor Cursor) Int() (int, error) {
+
+	value, err := strconv.Atoi(cursor.Value)
+
+	if err != nil {
+
+		return 0, info.ErrInvalidCursor
+	}
+
+	return value, nil
+}
+
+func DecodeCursor(encoded string) (Cursor, error) {
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+
+	if err != nil {
+
+		return Cursor{}, info.ErrInvalidCursor
+	}
+
+	var cursor Cursor
+
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.SortKey == "" || cursor.Id <= 0 {
+
+		return Cursor{}, info.ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// NormalizeSortKey maps the sortBy argument to one of the supported sort keys, snake case column names are accepted as well
+func NormalizeSortKey(sortBy string, allowed ...string) (string, error) {
+
+	var key string
+
+	switch sortBy {
+
+	case "", "id":
+
+		key = SortById
+
+	case SortByCreatedOn, "created_on":
+
+		key = SortByCreatedOn
+
+	case SortByPublishedTime, "published_time":
+
+		key = SortByPublishedTime
+
+	case SortBySortOrder, "sort_order":
+
+		key = SortBySortOrder
+
+	default:
+
+		return "", info.ErrInvalidSortKey
+	}
+
+	for _, allowedKey := range allowed {
+
+		if allowedKey == key {
+
+			return key, nil
+		}
+	}
+
+	return "", info.ErrInvalidSortKey
+}
+
+// NewWindow validates first/after/last/before. Forward paging reads after the cursor, backward paging reads before it.
+func NewWindow(args Args, sortKey string, desc bool) (Window, error) {
+
+	window := Window{SortKey: sortKey, Desc: desc, Limit: DefaultPageSize}
+
+	if args.First != nil && args.Last != nil {
+
+		return Window{}, info.ErrInvalidPageArgs
+	}
+
+	if args.After != nil && args.Before != nil {
+
+		return Window{}, info.ErrInvalidPageArgs
+	}
+
+	if (args.First != nil && args.Before != nil) || (args.Last != nil && args.After != nil) {
+
+		return Window{}, info.ErrInvalidPageArgs
+	}
+
+	switch {
+
+	case args.First != nil:
+
+		window.Limit = *args.First
+
+	case args.Last != nil:
+
+		window.Limit = *args.Last
+
+		window.Backward = true
+	}
+
+	if args.Before != nil {
+
+		window.Backward = true
+	}
+
+	if window.Limit < 0 {
+
+		return Window{}, info.ErrInvalidPageArgs
+	}
+
+	if window.Limit > MaxPageSize {
+
+		window.Limit = MaxPageSize
+	}
+
+	var encoded *string
+
+	if args.After != nil {
+
+		encoded = args.After
+
+	} else if args.Before != nil {
+
+		encoded = args.Before
+	}
+
+	if encoded != nil && *encoded != "" {
+
+		cursor, err := DecodeCursor(*encoded)
+
+		if err != nil {
+
+			return Window{}, err
+		}
+
+		if cursor.SortKey != sortKey {
+
+			return Window{}, info.ErrCursorSortMismatch
+		}
+
+		window.Cursor = &cursor
+	}
+
+	return window, nil
+}
+
+// Descending reports the order the rows have to be read in, backward pages are read in reverse and flipped afterwards
+func (window Window) Descending() bool {
+
+	return window.Desc != window.Backward
+}
+
+// Page trims the extra row fetched to detect more results and returns the page info flags
+func (window Window) Page(fetched int) (size int, hasNextPage bool, hasPreviousPage bool) {
+
+	size = fetched
+
+	hasMore := fetched > window.Limit
+
+	if hasMore {
+
+		size = window.Limit
+	}
+
+	if window.Backward {
+
+		return size, window.Cursor != nil, hasMore
+	}
+
+	return size, hasMore, window.Cursor != nil
+}
+
+// Reverse flips a backward page back into the requested sort order
+func Reverse[T any](rows []T) {
+
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+}
@@ -0,0 +1,132 @@
+package pagination
+
+import (
+	"spurt-cms/graphql/info"
+	"testing"
+	"time"
+)
+
+func intPtr(value int) *int { return &value }
+
+func stringPtr(value string) *string { return &value }
+
+func TestCursorRoundTrip(t *testing.T) {
+
+	createdOn := time.Date(2024, 5, 10, 8, 30, 15, 123456000, time.UTC)
+
+	cursor := TimeCursor(SortByCreatedOn, createdOn, 42)
+
+	decoded, err := DecodeCursor(cursor.Encode())
+
+	if err != nil {
+		t.Fatalf("decode cursor: %v", err)
+	}
+
+	if decoded != cursor {
+		t.Fatalf("expected %+v, got %+v", cursor, decoded)
+	}
+
+	value, err := decoded.Time()
+
+	if err != nil || !value.Equal(createdOn) {
+		t.Fatalf("expected %v, got %v (%v)", createdOn, value, err)
+	}
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+
+	for _, encoded := range []string{"", "not-base64!", "e30"} {
+
+		if _, err := DecodeCursor(encoded); err != info.ErrInvalidCursor {
+			t.Errorf("%q: expected invalid cursor error, got %v", encoded, err)
+		}
+	}
+}
+
+func TestNewWindow(t *testing.T) {
+
+	after := IntCursor(SortById, 10, 10).Encode()
+
+	t.Run("defaults to the first page", func(t *testing.T) {
+
+		window, err := NewWindow(Args{}, SortById, true)
+
+		if err != nil || window.Limit != DefaultPageSize || window.Backward || window.Cursor != nil {
+			t.Fatalf("unexpected window %+v (%v)", window, err)
+		}
+	})
+
+	t.Run("caps the page size", func(t *testing.T) {
+
+		window, _ := NewWindow(Args{First: intPtr(MaxPageSize + 50)}, SortById, true)
+
+		if window.Limit != MaxPageSize {
+			t.Fatalf("expected limit %d, got %d", MaxPageSize, window.Limit)
+		}
+	})
+
+	t.Run("last reads backward", func(t *testing.T) {
+
+		window, err := NewWindow(Args{Last: intPtr(5), Before: stringPtr(after)}, SortById, true)
+
+		if err != nil || !window.Backward || window.Descending() || window.Cursor.Id != 10 {
+			t.Fatalf("unexpected window %+v (%v)", window, err)
+		}
+	})
+
+	t.Run("rejects mixed directions", func(t *testing.T) {
+
+		invalid := []Args{
+			{First: intPtr(1), Last: intPtr(1)},
+			{After: stringPtr(after), Before: stringPtr(after)},
+			{First: intPtr(1), Before: stringPtr(after)},
+			{First: intPtr(-1)},
+		}
+
+		for _, args := range invalid {
+
+			if _, err := NewWindow(args, SortById, true); err != info.ErrInvalidPageArgs {
+				t.Errorf("%+v: expected invalid page args, got %v", args, err)
+			}
+		}
+	})
+
+	t.Run("rejects a cursor from another sort", func(t *testing.T) {
+
+		if _, err := NewWindow(Args{After: stringPtr(after)}, SortByCreatedOn, true); err != info.ErrCursorSortMismatch {
+			t.Fatalf("expected sort mismatch, got %v", err)
+		}
+	})
+}
+
+func TestWindowPage(t *testing.T) {
+
+	cursor := IntCursor(SortById, 3, 3)
+
+	forward := Window{Limit: 2, Cursor: &cursor}
+
+	if size, hasNext, hasPrevious := forward.Page(3); size != 2 || !hasNext || !hasPrevious {
+		t.Fatalf("forward page: got %d %v %v", size, hasNext, hasPrevious)
+	}
+
+	backward := Window{Limit: 2, Backward: true}
+
+	if size, hasNext, hasPrevious := backward.Page(2); size != 2 || hasNext || hasPrevious {
+		t.Fatalf("backward page: got %d %v %v", size, hasNext, hasPrevious)
+	}
+}
+
+func TestNormalizeSortKey(t *testing.T) {
+
+	if key, err := NormalizeSortKey("published_time", SortById, SortByPublishedTime); err != nil || key != SortByPublishedTime {
+		t.Fatalf("expected publishedTime, got %q (%v)", key, err)
+	}
+
+	if _, err := NormalizeSortKey("sortOrder", SortById, SortByCreatedOn); err != info.ErrInvalidSortKey {
+		t.Fatalf("expected unsupported sort key, got %v", err)
+	}
+
+	if _, err := NormalizeSortKey("title; drop table", SortById); err != info.ErrInvalidSortKey {
+		t.Fatalf("expected unsupported sort key, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter keeps a token bucket per api key. The limits are passed on every call, so a changed limit on the token
+// record applies from the next request without resetting the bucket.
+type Limiter struct {
+	mu sync.Mutex
+
+	buckets map[int]*bucket
+}
+
+type bucket struct {
+	tokens float64
+
+	updated time.Time
+}
+
+func NewLimiter() *Limiter {
+
+	return &Limiter{buckets: make(map[int]*bucket)}
+}
+
+// Allow takes a token from the bucket of key. rate is the number of requests allowed per minute and burst the size
+// of the bucket, a burst of 0 uses the rate. A rate of 0 is unlimited. When the bucket is empty it returns how long
+// the caller has to wait for the next token.
+func (limiter *Limiter) Allow(key int, rate int, burst int, now time.Time) (bool, time.Duration) {
+
+	if rate <= 0 {
+
+		return true, 0
+	}
+
+	if burst <= 0 {
+
+		burst = rate
+	}
+
+	limiter.mu.Lock()
+
+	defer limiter.mu.Unlock()
+
+	perSecond := float64(rate) / 60
+
+	keyBucket, ok := limiter.buckets[key]
+
+	if !ok {
+
+		keyBucket = &bucket{tokens: float64(burst), updated: now}
+
+		limiter.buckets[key] = keyBucket
+	}
+
+	if elapsed := now.Sub(keyBucket.updated).Seconds(); elapsed > 0 {
+
+		keyBucket.tokens += elapsed * perSecond
+
+		keyBucket.updated = now
+	}
+
+	keyBucket.tokens = math.Min(keyBucket.tokens, float64(burst))
+
+	if keyBucket.tokens >= 1 {
+
+		keyBucket.tokens--
+
+		return true, 0
+	}
+
+	wait := (1 - keyBucket.tokens) / perSecond
+
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// RetryAfter formats a wait as the whole number of seconds sent in the Retry-After header
+func RetryAfter(wait time.Duration) int {
+
+	seconds := int(math.Ceil(wait.Seconds()))
+
+	if seconds < 1 {
+
+		return 1
+	}
+
+	return seconds
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAllowBurstThenRefill(t *testing.T) {
+
+	limiter := NewLimiter()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+
+		if ok, _ := limiter.Allow(1, 60, 3, now); !ok {
+			t.Fatalf("request %d of the burst was limited", i+1)
+		}
+	}
+
+	ok, wait := limiter.Allow(1, 60, 3, now)
+
+	if ok || wait != time.Second {
+		t.Fatalf("expected to wait a second for the next token, got %v %v", ok, wait)
+	}
+
+	if ok, _ := limiter.Allow(1, 60, 3, now.Add(time.Second)); !ok {
+		t.Fatalf("expected a token after a second")
+	}
+
+	// other keys have their own bucket
+	if ok, _ := limiter.Allow(2, 60, 3, now); !ok {
+		t.Fatalf("expected a separate bucket per key")
+	}
+}
+
+func TestAllowUnlimitedAndDefaultBurst(t *testing.T) {
+
+	limiter := NewLimiter()
+
+	now := time.Now()
+
+	for i := 0; i < 1000; i++ {
+
+		if ok, _ := limiter.Allow(1, 0, 0, now); !ok {
+			t.Fatalf("a rate of 0 should not limit")
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+
+		if ok, _ := limiter.Allow(2, 5, 0, now); !ok {
+			t.Fatalf("expected the burst to default to the rate")
+		}
+	}
+
+	if ok, _ := limiter.Allow(2, 5, 0, now); ok {
+		t.Fatalf("expected the sixth request to be limited")
+	}
+}
+
+func TestAllowLoweredBurst(t *testing.T) {
+
+	limiter := NewLimiter()
+
+	now := time.Now()
+
+	limiter.Allow(1, 100, 100, now)
+
+	// the limit was lowered on the token record, the bucket is capped to the new burst
+	for i := 0; i < 2; i++ {
+
+		if ok, _ := limiter.Allow(1, 100, 2, now); !ok {
+			t.Fatalf("request %d was limited", i+1)
+		}
+	}
+
+	if ok, _ := limiter.Allow(1, 100, 2, now); ok {
+		t.Fatalf("expected the lowered burst to apply")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+
+	for wait, expected := range map[time.Duration]int{0: 1, 200 * time.Millisecond: 1, time.Second: 1, 1500 * time.Millisecond: 2} {
+
+		if seconds := RetryAfter(wait); seconds != expected {
+			t.Fatalf("RetryAfter(%v) = %d, expected %d", wait, seconds, expected)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package related
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Picked parses the related_articles column of an entry, keeping the order the editor picked the entries in. Invalid
+// and repeated ids are skipped, and so is the entry itself.
+func Picked(value string, entryId int) []int {
+
+	var ids []int
+
+	seen := make(map[int]bool)
+
+	for _, part := range strings.Split(value, ",") {
+
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+
+		if err != nil || id <= 0 || id == entryId || seen[id] {
+
+			continue
+		}
+
+		seen[id] = true
+
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// Tags splits the comma separated tags of an entry, lower cased and without repeats
+func Tags(value string) []string {
+
+	var tags []string
+
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ",") {
+
+		tag := strings.ToLower(strings.TrimSpace(part))
+
+		if tag == "" || seen[tag] {
+
+			continue
+		}
+
+		seen[tag] = true
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// Shares reports whether two entries have a category or a tag in common
+func Shares(categoryIds []int, tags []string, otherCategoryIds []int, otherTags []string) bool {
+
+	for _, categoryId := range categoryIds {
+
+		for _, otherId := range otherCategoryIds {
+
+			if categoryId == otherId {
+
+				return true
+			}
+		}
+	}
+
+	for _, tag := range tags {
+
+		for _, otherTag := range otherTags {
+
+			if tag == otherTag {
+
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,40 @@
+package related
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPickedKeepsTheEditorOrder(t *testing.T) {
+
+	picked := Picked(" 12,4, 12,,x,7,3 ,-1", 7)
+
+	if !reflect.DeepEqual(picked, []int{12, 4, 3}) {
+		t.Fatalf("unexpected picked ids %v", picked)
+	}
+
+	if Picked("", 1) != nil {
+		t.Fatalf("expected no ids for an empty column")
+	}
+}
+
+func TestShares(t *testing.T) {
+
+	tags := Tags("Go, CMS ,go")
+
+	if !reflect.DeepEqual(tags, []string{"go", "cms"}) {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+
+	if !Shares([]int{1, 2}, nil, []int{3, 2}, nil) {
+		t.Fatalf("expected a shared category to count")
+	}
+
+	if !Shares(nil, tags, []int{5}, Tags("news, cms")) {
+		t.Fatalf("expected a shared tag to count")
+	}
+
+	if Shares([]int{1}, tags, []int{2}, Tags("gopher, cmsx")) {
+		t.Fatalf("expected partial tag matches not to count")
+	}
+}
@@ -0,0 +1,28 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.49
+
+import (
+	"context"
+	"spurt-cms/graphql/controller"
+	"spurt-cms/graphql/graph"
+	"spurt-cms/graphql/model"
+	"time"
+)
+
+// PopularEntries is the resolver for the PopularEntries field.
+func (r *queryResolver) PopularEntries(ctx context.Context, channelSlug *string, period model.ViewPeriod, limit *int, additionalData *model.EntriesAdditionalData) ([]model.PopularEntry, error) {
+	return controller.PopularEntries(ctx, channelSlug, period, limit, additionalData)
+}
+
+// EntryViewStats is the resolver for the EntryViewStats field.
+func (r *queryResolver) EntryViewStats(ctx context.Context, id int, from *time.Time, to *time.Time) (*model.EntryViewStats, error) {
+	return controller.EntryViewStats(ctx, id, from, to)
+}
+
+// Query returns graph.QueryResolver implementation.
+func (r *Resolver) Query() graph.QueryResolver { return &queryResolver{r} }
+
+type queryResolver struct{ *Resolver }
@@ -7,7 +7,6 @@ package resolvers
 import (
 	"context"
 	"spurt-cms/graphql/controller"
-	"spurt-cms/graphql/graph"
 	"spurt-cms/graphql/model"
 )
 
@@ -16,7 +15,7 @@ func (r *queryResolver) CategoryList(ctx context.Context, categoryFilter *model.
 	return controller.CategoryList(ctx, categoryFilter, commonFilter)
 }
 
-// Query returns graph.QueryResolver implementation.
-func (r *Resolver) Query() graph.QueryResolver { return &queryResolver{r} }
-
-type queryResolver struct{ *Resolver }
+// CategoryTree is the resolver for the CategoryTree field.
+func (r *queryResolver) CategoryTree(ctx context.Context, categoryGroupSlug string, depth *int) (*model.CategoryNode, error) {
+	return controller.CategoryTree(ctx, categoryGroupSlug, depth)
+}
@@ -11,9 +11,59 @@ import (
 	"spurt-cms/graphql/model"
 )
 
+// ProfileImagePath is the resolver for the profileImagePath field.
+func (r *authorResolver) ProfileImagePath(ctx context.Context, obj *model.Author, width *int, height *int, fit *model.ImageFit, format *model.ImageFormat, quality *int) (*string, error) {
+	return controller.TransformOptionalImage(ctx, obj.ProfileImagePath, width, height, fit, format, quality)
+}
+
+// CoverImage is the resolver for the coverImage field.
+func (r *channelEntriesResolver) CoverImage(ctx context.Context, obj *model.ChannelEntries, width *int, height *int, fit *model.ImageFit, format *model.ImageFormat, quality *int) (string, error) {
+	return controller.TransformImage(ctx, obj.CoverImage, width, height, fit, format, quality)
+}
+
+// ThumbnailImage is the resolver for the thumbnailImage field.
+func (r *channelEntriesResolver) ThumbnailImage(ctx context.Context, obj *model.ChannelEntries, width *int, height *int, fit *model.ImageFit, format *model.ImageFormat, quality *int) (string, error) {
+	return controller.TransformImage(ctx, obj.ThumbnailImage, width, height, fit, format, quality)
+}
+
+// Breadcrumbs is the resolver for the breadcrumbs field.
+func (r *channelEntriesResolver) Breadcrumbs(ctx context.Context, obj *model.ChannelEntries) ([]model.Category, error) {
+	return controller.EntryBreadcrumbs(ctx, obj)
+}
+
+// Related is the resolver for the related field.
+func (r *channelEntriesResolver) Related(ctx context.Context, obj *model.ChannelEntries, limit *int, additionalData *model.EntriesAdditionalData) ([]model.ChannelEntries, error) {
+	return controller.EntryRelated(ctx, obj, limit, additionalData)
+}
+
 // UpdateEntryViewCount is the resolver for the UpdateEntryViewCount field.
-func (r *mutationResolver) UpdateEntryViewCount(ctx context.Context, id *int, slug *string) (*model.CountUpdate, error) {
-	return controller.UpdateEntryViewCount(ctx, id, slug)
+func (r *mutationResolver) UpdateEntryViewCount(ctx context.Context, id *int, slug *string, visitorIP *string, visitorAgent *string, visitorID *string) (*model.CountUpdate, error) {
+	return controller.UpdateEntryViewCount(ctx, id, slug, visitorIP, visitorAgent, visitorID)
+}
+
+// CreateEntry is the resolver for the createEntry field.
+func (r *mutationResolver) CreateEntry(ctx context.Context, input model.CreateEntryInput) (*model.ChannelEntries, error) {
+	return controller.CreateEntry(ctx, input)
+}
+
+// UpdateEntry is the resolver for the updateEntry field.
+func (r *mutationResolver) UpdateEntry(ctx context.Context, id int, input model.UpdateEntryInput) (*model.ChannelEntries, error) {
+	return controller.UpdateEntry(ctx, id, input)
+}
+
+// PublishEntry is the resolver for the publishEntry field.
+func (r *mutationResolver) PublishEntry(ctx context.Context, id int) (*model.ChannelEntries, error) {
+	return controller.PublishEntry(ctx, id)
+}
+
+// UnpublishEntry is the resolver for the unpublishEntry field.
+func (r *mutationResolver) UnpublishEntry(ctx context.Context, id int) (*model.ChannelEntries, error) {
+	return controller.UnpublishEntry(ctx, id)
+}
+
+// DeleteEntry is the resolver for the deleteEntry field.
+func (r *mutationResolver) DeleteEntry(ctx context.Context, id int) (bool, error) {
+	return controller.DeleteEntry(ctx, id)
 }
 
 // ChannelList is the resolver for the ChannelList field.
@@ -27,16 +77,53 @@ func (r *queryResolver) ChannelDetail(ctx context.Context, channelID *int, chann
 }
 
 // ChannelEntriesList is the resolver for the ChannelEntriesList field.
-func (r *queryResolver) ChannelEntriesList(ctx context.Context, commonFilter *model.Filter, sort *model.Sort, entryFilter *model.EntriesFilter, additionalData *model.EntriesAdditionalData) (*model.ChannelEntryDetails, error) {
-	return controller.ChannelEntriesList(ctx, commonFilter, sort, entryFilter, additionalData)
+func (r *queryResolver) ChannelEntriesList(ctx context.Context, commonFilter *model.Filter, sort *model.Sort, entryFilter *model.EntriesFilter, additionalData *model.EntriesAdditionalData, fieldFilter []model.FieldPredicate, fieldSort *model.FieldSort) (*model.ChannelEntryDetails, error) {
+	return controller.ChannelEntriesList(ctx, commonFilter, sort, entryFilter, additionalData, fieldFilter, fieldSort)
 }
 
 // ChannelEntryDetail is the resolver for the ChannelEntryDetail field.
-func (r *queryResolver) ChannelEntryDetail(ctx context.Context, id *int, slug *string, additionalData *model.EntriesAdditionalData, channelID *int) (*model.ChannelEntries, error) {
-	return controller.ChannelEntryDetail(ctx, id, slug, additionalData, channelID)
+func (r *queryResolver) ChannelEntryDetail(ctx context.Context, id *int, slug *string, additionalData *model.EntriesAdditionalData, channelID *int, previewToken *string) (*model.ChannelEntries, error) {
+	return controller.ChannelEntryDetail(ctx, id, slug, additionalData, channelID, previewToken)
+}
+
+// ChannelListConnection is the resolver for the ChannelListConnection field.
+func (r *queryResolver) ChannelListConnection(ctx context.Context, first *int, after *string, last *int, before *string, filter *model.Filter, sort *model.Sort) (*model.ChannelConnection, error) {
+	return controller.ChannelListConnection(ctx, first, after, last, before, filter, sort)
+}
+
+// ChannelEntriesListConnection is the resolver for the ChannelEntriesListConnection field.
+func (r *queryResolver) ChannelEntriesListConnection(ctx context.Context, first *int, after *string, last *int, before *string, commonFilter *model.Filter, sort *model.Sort, entryFilter *model.EntriesFilter, additionalData *model.EntriesAdditionalData, fieldFilter []model.FieldPredicate, fieldSort *model.FieldSort) (*model.ChannelEntriesConnection, error) {
+	return controller.ChannelEntriesListConnection(ctx, first, after, last, before, commonFilter, sort, entryFilter, additionalData, fieldFilter, fieldSort)
+}
+
+// EntryPublished is the resolver for the entryPublished field.
+func (r *subscriptionResolver) EntryPublished(ctx context.Context, channelSlug *string) (<-chan *model.ChannelEntries, error) {
+	return controller.EntryPublished(ctx, channelSlug)
+}
+
+// EntryUpdated is the resolver for the entryUpdated field.
+func (r *subscriptionResolver) EntryUpdated(ctx context.Context, channelSlug *string) (<-chan *model.ChannelEntries, error) {
+	return controller.EntryUpdated(ctx, channelSlug)
+}
+
+// EntryDeleted is the resolver for the entryDeleted field.
+func (r *subscriptionResolver) EntryDeleted(ctx context.Context, channelSlug *string) (<-chan *model.DeletedEntry, error) {
+	return controller.EntryDeleted(ctx, channelSlug)
 }
 
+// Author returns graph.AuthorResolver implementation.
+func (r *Resolver) Author() graph.AuthorResolver { return &authorResolver{r} }
+
+// ChannelEntries returns graph.ChannelEntriesResolver implementation.
+func (r *Resolver) ChannelEntries() graph.ChannelEntriesResolver { return &channelEntriesResolver{r} }
+
 // Mutation returns graph.MutationResolver implementation.
 func (r *Resolver) Mutation() graph.MutationResolver { return &mutationResolver{r} }
 
+// Subscription returns graph.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() graph.SubscriptionResolver { return &subscriptionResolver{r} }
+
+type authorResolver struct{ *Resolver }
+type channelEntriesResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
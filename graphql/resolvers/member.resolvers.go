@@ -7,15 +7,85 @@ package resolvers
 import (
 	"context"
 	"spurt-cms/graphql/controller"
+	"spurt-cms/graphql/graph"
 	"spurt-cms/graphql/model"
 )
 
+// CompanyLogo is the resolver for the companyLogo field.
+func (r *memberProfileResolver) CompanyLogo(ctx context.Context, obj *model.MemberProfile, width *int, height *int, fit *model.ImageFit, format *model.ImageFormat, quality *int) (*string, error) {
+	return controller.TransformOptionalImage(ctx, obj.CompanyLogo, width, height, fit, format, quality)
+}
+
+// ProfileImagePath is the resolver for the profileImagePath field.
+func (r *membersResolver) ProfileImagePath(ctx context.Context, obj *model.Members, width *int, height *int, fit *model.ImageFit, format *model.ImageFormat, quality *int) (*string, error) {
+	return controller.TransformOptionalImage(ctx, obj.ProfileImagePath, width, height, fit, format, quality)
+}
+
 // MemberRegister is the resolver for the memberRegister field.
 func (r *mutationResolver) MemberRegister(ctx context.Context, input model.MemberDetails, arguments *model.MemberArguments) (bool, error) {
 	return controller.MemberRegister(ctx, &input, arguments)
 }
 
+// VerifyMemberEmail is the resolver for the verifyMemberEmail field.
+func (r *mutationResolver) VerifyMemberEmail(ctx context.Context, token string) (bool, error) {
+	return controller.VerifyMemberEmail(ctx, token)
+}
+
+// ResendMemberVerification is the resolver for the resendMemberVerification field.
+func (r *mutationResolver) ResendMemberVerification(ctx context.Context, email string) (bool, error) {
+	return controller.ResendMemberVerification(ctx, email)
+}
+
+// MemberLogin is the resolver for the memberLogin field.
+func (r *mutationResolver) MemberLogin(ctx context.Context, input model.MemberLoginInput) (*model.MemberAuth, error) {
+	return controller.MemberLogin(ctx, input)
+}
+
+// MemberRefreshToken is the resolver for the memberRefreshToken field.
+func (r *mutationResolver) MemberRefreshToken(ctx context.Context, refreshToken string) (*model.MemberAuth, error) {
+	return controller.MemberRefreshToken(ctx, refreshToken)
+}
+
+// MemberLogout is the resolver for the memberLogout field.
+func (r *mutationResolver) MemberLogout(ctx context.Context, refreshToken string) (bool, error) {
+	return controller.MemberLogout(ctx, refreshToken)
+}
+
+// UpdateMyProfile is the resolver for the updateMyProfile field.
+func (r *mutationResolver) UpdateMyProfile(ctx context.Context, input model.MemberProfileInput) (*model.MemberProfile, error) {
+	return controller.UpdateMyProfile(ctx, input)
+}
+
+// RequestProfileClaim is the resolver for the requestProfileClaim field.
+func (r *mutationResolver) RequestProfileClaim(ctx context.Context, slug string) (bool, error) {
+	return controller.RequestProfileClaim(ctx, slug)
+}
+
+// ConfirmProfileClaim is the resolver for the confirmProfileClaim field.
+func (r *mutationResolver) ConfirmProfileClaim(ctx context.Context, token string) (*model.MemberProfile, error) {
+	return controller.ConfirmProfileClaim(ctx, token)
+}
+
 // MembersList is the resolver for the MembersList field.
 func (r *queryResolver) MembersList(ctx context.Context, filter *model.Filter) (*model.MembersDetails, error) {
 	return controller.MembersList(ctx, filter)
 }
+
+// MembersListConnection is the resolver for the MembersListConnection field.
+func (r *queryResolver) MembersListConnection(ctx context.Context, first *int, after *string, last *int, before *string, filter *model.Filter, sort *model.Sort) (*model.MembersConnection, error) {
+	return controller.MembersListConnection(ctx, first, after, last, before, filter, sort)
+}
+
+// MemberProfile is the resolver for the MemberProfile field.
+func (r *queryResolver) MemberProfile(ctx context.Context, slug string) (*model.MemberProfile, error) {
+	return controller.MemberProfile(ctx, slug)
+}
+
+// MemberProfile returns graph.MemberProfileResolver implementation.
+func (r *Resolver) MemberProfile() graph.MemberProfileResolver { return &memberProfileResolver{r} }
+
+// Members returns graph.MembersResolver implementation.
+func (r *Resolver) Members() graph.MembersResolver { return &membersResolver{r} }
+
+type memberProfileResolver struct{ *Resolver }
+type membersResolver struct{ *Resolver }
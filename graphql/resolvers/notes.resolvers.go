@@ -0,0 +1,31 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.49
+
+import (
+	"context"
+	"spurt-cms/graphql/controller"
+	"spurt-cms/graphql/model"
+)
+
+// CreateNote is the resolver for the createNote field.
+func (r *mutationResolver) CreateNote(ctx context.Context, input model.MemberNoteInput) (*model.MemberNote, error) {
+	return controller.CreateNote(ctx, input)
+}
+
+// UpdateNote is the resolver for the updateNote field.
+func (r *mutationResolver) UpdateNote(ctx context.Context, id int, input model.UpdateMemberNoteInput) (*model.MemberNote, error) {
+	return controller.UpdateNote(ctx, id, input)
+}
+
+// DeleteNote is the resolver for the deleteNote field.
+func (r *mutationResolver) DeleteNote(ctx context.Context, id int) (bool, error) {
+	return controller.DeleteNote(ctx, id)
+}
+
+// MemberNotes is the resolver for the MemberNotes field.
+func (r *queryResolver) MemberNotes(ctx context.Context, entryID *int, typeArg *model.MemberNoteType, limit *int, offset *int) (*model.MemberNoteList, error) {
+	return controller.MemberNotes(ctx, entryID, typeArg, limit, offset)
+}
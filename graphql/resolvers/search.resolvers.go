@@ -0,0 +1,16 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.49
+
+import (
+	"context"
+	"spurt-cms/graphql/controller"
+	"spurt-cms/graphql/model"
+)
+
+// SearchEntries is the resolver for the SearchEntries field.
+func (r *queryResolver) SearchEntries(ctx context.Context, query string, channelSlug *string, categorySlug *string, facets []model.SearchFacet, limit *int, offset *int, additionalData *model.EntriesAdditionalData) (*model.SearchResults, error) {
+	return controller.SearchEntries(ctx, query, channelSlug, categorySlug, facets, limit, offset, additionalData)
+}
@@ -0,0 +1,184 @@
+package responsecache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache keeps the response bodies of query operations in memory, least recently used first out. The responses of a
+// tenant are dropped together when its content changes.
+type Cache struct {
+	mu sync.Mutex
+
+	size int
+	ttl  time.Duration
+
+	entries map[string]*list.Element
+	order   *list.List
+
+	// a response is only served while its tenant is still at the generation it was stored at
+	generations map[int]int
+}
+
+type entry struct {
+	key        string
+	tenantId   int
+	generation int
+	body       []byte
+	etag       string
+	expiresAt  time.Time
+}
+
+func New(size int, ttl time.Duration) *Cache {
+
+	return &Cache{size: size, ttl: ttl, entries: make(map[string]*list.Element), order: list.New(), generations: make(map[int]int)}
+}
+
+// Get returns the body and etag stored under the key, false when there is none or it went stale
+func (cache *Cache) Get(tenantId int, key string, now time.Time) ([]byte, string, bool) {
+
+	cache.mu.Lock()
+
+	defer cache.mu.Unlock()
+
+	element, ok := cache.entries[key]
+
+	if !ok {
+
+		return nil, "", false
+	}
+
+	stored := element.Value.(*entry)
+
+	if stored.tenantId != tenantId || stored.generation != cache.generations[tenantId] || !now.Before(stored.expiresAt) {
+
+		cache.remove(element)
+
+		return nil, "", false
+	}
+
+	cache.order.MoveToFront(element)
+
+	return stored.body, stored.etag, true
+}
+
+// Generation returns the generation the tenant is at, read it before running an operation and store the response at it
+func (cache *Cache) Generation(tenantId int) int {
+
+	cache.mu.Lock()
+
+	defer cache.mu.Unlock()
+
+	return cache.generations[tenantId]
+}
+
+// Set stores the body under the key and returns its etag. The body is not stored when the content of the tenant changed
+// since the generation the operation was run at, it may have read the content from before the change.
+func (cache *Cache) Set(tenantId int, key string, body []byte, generation int, now time.Time) string {
+
+	etag := ETag(body)
+
+	cache.mu.Lock()
+
+	defer cache.mu.Unlock()
+
+	if generation != cache.generations[tenantId] {
+
+		return etag
+	}
+
+	if element, ok := cache.entries[key]; ok {
+
+		cache.remove(element)
+	}
+
+	stored := &entry{key: key, tenantId: tenantId, generation: generation, body: body, etag: etag, expiresAt: now.Add(cache.ttl)}
+
+	cache.entries[key] = cache.order.PushFront(stored)
+
+	for cache.order.Len() > cache.size {
+
+		cache.remove(cache.order.Back())
+	}
+
+	return etag
+}
+
+// Invalidate drops every response of the tenant, they are removed as they are next read or pushed out
+func (cache *Cache) Invalidate(tenantId int) {
+
+	cache.mu.Lock()
+
+	defer cache.mu.Unlock()
+
+	cache.generations[tenantId]++
+}
+
+func (cache *Cache) Len() int {
+
+	cache.mu.Lock()
+
+	defer cache.mu.Unlock()
+
+	return cache.order.Len()
+}
+
+func (cache *Cache) remove(element *list.Element) {
+
+	cache.order.Remove(element)
+
+	delete(cache.entries, element.Value.(*entry).key)
+}
+
+// Key identifies a response by everything it depends on. Variables are encoded with sorted keys, so the order a
+// client sends them in does not matter.
+func Key(tenantId int, scope string, query string, operationName string, variables map[string]interface{}) (string, error) {
+
+	encoded, err := json.Marshal(variables)
+
+	if err != nil {
+
+		return "", err
+	}
+
+	hash := sha256.New()
+
+	for _, part := range []string{scope, query, operationName, string(encoded)} {
+
+		hash.Write([]byte(part))
+
+		hash.Write([]byte{0})
+	}
+
+	return strconv.Itoa(tenantId) + ":" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ETag returns a strong entity tag of the body
+func ETag(body []byte) string {
+
+	sum := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// NotModified reports whether an If-None-Match header matches the etag, weak tags compare equal to strong ones
+func NotModified(ifNoneMatch string, etag string) bool {
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+
+		if candidate == "*" || candidate == etag {
+
+			return true
+		}
+	}
+
+	return false
+}
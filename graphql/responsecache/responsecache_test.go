@@ -0,0 +1,154 @@
+package responsecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetReturnsStoredResponse(t *testing.T) {
+
+	cache := New(10, time.Minute)
+
+	now := time.Now()
+
+	etag := cache.Set(1, "1:a", []byte(`{"data":{}}`), cache.Generation(1), now)
+
+	body, storedEtag, ok := cache.Get(1, "1:a", now.Add(time.Second))
+
+	if !ok || string(body) != `{"data":{}}` || storedEtag != etag {
+		t.Fatalf("unexpected cache hit %q %q %v", body, storedEtag, ok)
+	}
+
+	if _, _, ok := cache.Get(1, "1:a", now.Add(time.Minute)); ok {
+		t.Fatal("expected the response to expire")
+	}
+
+	if cache.Len() != 0 {
+		t.Fatalf("expected the expired response to be removed, %d left", cache.Len())
+	}
+}
+
+func TestInvalidateDropsOnlyTheTenant(t *testing.T) {
+
+	cache := New(10, time.Minute)
+
+	now := time.Now()
+
+	cache.Set(1, "1:a", []byte("one"), cache.Generation(1), now)
+
+	cache.Set(2, "2:a", []byte("two"), cache.Generation(2), now)
+
+	cache.Invalidate(1)
+
+	if _, _, ok := cache.Get(1, "1:a", now); ok {
+		t.Fatal("expected the tenant response to be invalidated")
+	}
+
+	if _, _, ok := cache.Get(2, "2:a", now); !ok {
+		t.Fatal("expected the response of the other tenant to stay")
+	}
+
+	cache.Set(1, "1:a", []byte("one again"), cache.Generation(1), now)
+
+	if body, _, ok := cache.Get(1, "1:a", now); !ok || string(body) != "one again" {
+		t.Fatalf("expected a response stored after the invalidation, got %q %v", body, ok)
+	}
+}
+
+func TestSetSkipsResponsesOfAnEarlierGeneration(t *testing.T) {
+
+	cache := New(10, time.Minute)
+
+	now := time.Now()
+
+	// the content changes while the operation runs
+	generation := cache.Generation(1)
+
+	cache.Invalidate(1)
+
+	cache.Set(1, "1:a", []byte("before the change"), generation, now)
+
+	if _, _, ok := cache.Get(1, "1:a", now); ok {
+		t.Fatal("expected the response read before the change not to be stored")
+	}
+}
+
+func TestLeastRecentlyUsedIsEvicted(t *testing.T) {
+
+	cache := New(2, time.Minute)
+
+	now := time.Now()
+
+	cache.Set(1, "a", []byte("a"), cache.Generation(1), now)
+
+	cache.Set(1, "b", []byte("b"), cache.Generation(1), now)
+
+	cache.Get(1, "a", now)
+
+	cache.Set(1, "c", []byte("c"), cache.Generation(1), now)
+
+	if _, _, ok := cache.Get(1, "b", now); ok {
+		t.Fatal("expected b to be evicted")
+	}
+
+	if _, _, ok := cache.Get(1, "a", now); !ok {
+		t.Fatal("expected a to stay")
+	}
+}
+
+func TestKey(t *testing.T) {
+
+	first, err := Key(1, "read", "{ a }", "", map[string]interface{}{"x": 1, "y": "z"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, _ := Key(1, "read", "{ a }", "", map[string]interface{}{"y": "z", "x": 1})
+
+	if first != second {
+		t.Fatal("expected the variable order not to change the key")
+	}
+
+	for _, other := range []string{
+		mustKey(t, 2, "read", "{ a }", ""),
+		mustKey(t, 1, "read,write", "{ a }", ""),
+		mustKey(t, 1, "read", "{ b }", ""),
+		mustKey(t, 1, "read", "{ a }", "Named"),
+	} {
+
+		if other == first {
+			t.Fatal("expected a different key")
+		}
+	}
+}
+
+func mustKey(t *testing.T, tenantId int, scope, query, operationName string) string {
+
+	key, err := Key(tenantId, scope, query, operationName, map[string]interface{}{"x": 1, "y": "z"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestNotModified(t *testing.T) {
+
+	etag := ETag([]byte("body"))
+
+	for header, want := range map[string]bool{
+		etag:               true,
+		"W/" + etag:        true,
+		`"other", ` + etag: true,
+		"*":                true,
+		`"other"`:          false,
+		"":                 false,
+	} {
+
+		if got := NotModified(header, etag); got != want {
+			t.Errorf("NotModified(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
@@ -3,17 +3,26 @@ package routes
 import (
 	"context"
 	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
 	"spurt-cms/graphql/controller"
+	"spurt-cms/graphql/dataloader"
 	"spurt-cms/graphql/graph"
+	"spurt-cms/graphql/limits"
 	"spurt-cms/graphql/middleware"
 	"spurt-cms/graphql/resolvers"
 	"strings"
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 )
 
 func GetEndpointHandlers(r *gin.Engine) *gin.Engine {
@@ -48,16 +57,18 @@ func GetEndpointHandlers(r *gin.Engine) *gin.Engine {
 
 	})
 
-	r.POST("/query", func(c *gin.Context) {
+	servers := newTenantServers(limits.LoadConfig())
 
-		execSchema := graph.NewExecutableSchema(graph.Config{Resolvers: &resolvers.Resolver{}, Directives: graph.DirectiveRoot{Auth: middleware.AuthMiddleware}})
+	middleware.StartUsageFlush()
 
-		srv := handler.NewDefaultServer(execSchema)
+	controller.StartSearchIndexer()
 
-		ctx := context.WithValue(c.Request.Context(), controller.GinContext, c)
+	servers.startInvalidation()
 
-		srv.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
-	})
+	r.POST("/query", servers.handler())
+
+	// persisted queries over GET and the websocket upgrade for subscriptions
+	r.GET("/query", servers.handler())
 
 	r.GET("/apidocs", func(c *gin.Context) {
 
@@ -66,3 +77,67 @@ func GetEndpointHandlers(r *gin.Engine) *gin.Engine {
 
 	return r
 }
+
+func graphConfig() graph.Config {
+
+	return graph.Config{Resolvers: &resolvers.Resolver{}, Directives: graph.DirectiveRoot{Auth: middleware.AuthMiddleware}}
+}
+
+// NewGraphqlServer serves the executable schema, the gin context is passed in per request
+func NewGraphqlServer(config limits.Config, execSchema graphql.ExecutableSchema, responseCache *middleware.ResponseCache) *handler.Server {
+
+	srv := handler.New(limits.WithFieldCosts(execSchema, config.FieldCosts))
+
+	// serves both graphql-transport-ws and the older graphql-ws subprotocol
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return true
+			},
+		},
+		InitFunc: middleware.WebsocketInit,
+	})
+
+	srv.AddTransport(transport.Options{})
+
+	srv.AddTransport(transport.GET{})
+
+	srv.AddTransport(transport.POST{})
+
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New(1000))
+
+	srv.Use(extension.Introspection{})
+
+	// clients send the sha256 hash of the query and only fall back to the full text when the hash is unknown
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New(config.APQCacheSize),
+	})
+
+	srv.Use(extension.FixedComplexityLimit(config.MaxComplexity))
+
+	srv.Use(limits.DepthLimit{Max: config.MaxDepth})
+
+	// every operation gets its own loaders, so batched relations are never cached across operations
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+
+		return next(dataloader.WithRequestScope(ctx))
+	})
+
+	// added after the request scope, the cache authenticates the api key within it
+	srv.Use(responseCache)
+
+	return srv
+}
+
+func GraphqlHandler(srv *handler.Server) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx := context.WithValue(c.Request.Context(), controller.GinContext, c)
+
+		srv.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+	}
+}
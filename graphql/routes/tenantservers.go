@@ -0,0 +1,186 @@
+package routes
+
+import (
+	"spurt-cms/events"
+	"spurt-cms/graphql/channelschema"
+	"spurt-cms/graphql/controller"
+	"spurt-cms/graphql/graph"
+	"spurt-cms/graphql/limits"
+	"spurt-cms/graphql/middleware"
+	"spurt-cms/graphql/model"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/singleflight"
+)
+
+// tenantServers keeps a graphql server per tenant, the schema of each one carries the typed queries of the tenant
+// channels. Requests without a known api key and websocket subscriptions are served by the base server.
+type tenantServers struct {
+	config limits.Config
+	base   *handler.Server
+
+	// shared by the servers of every tenant
+	responseCache *middleware.ResponseCache
+
+	mu      sync.Mutex
+	servers map[int]*handler.Server
+	tenants map[string]int
+
+	// bumped when the channels of a tenant change, a schema built before that is not kept
+	generations map[int]int
+
+	// concurrent requests of a tenant without a server share one schema build
+	builds singleflight.Group
+}
+
+func newTenantServers(config limits.Config) *tenantServers {
+
+	responseCache := middleware.NewResponseCache(config.ResponseCacheSize, time.Duration(config.ResponseCacheTTL)*time.Second)
+
+	return &tenantServers{
+		config:        config,
+		base:          NewGraphqlServer(config, graph.NewExecutableSchema(graphConfig()), responseCache),
+		responseCache: responseCache,
+		servers:       make(map[int]*handler.Server),
+		tenants:       make(map[string]int),
+		generations:   make(map[int]int),
+	}
+}
+
+func (s *tenantServers) handler() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		GraphqlHandler(s.serverFor(c))(c)
+	}
+}
+
+func (s *tenantServers) serverFor(c *gin.Context) *handler.Server {
+
+	if c.IsWebsocket() {
+
+		return s.base
+	}
+
+	tenantId := s.tenantOf(c.GetHeader("ApiKey"))
+
+	if tenantId == 0 {
+
+		return s.base
+	}
+
+	s.mu.Lock()
+
+	srv, ok := s.servers[tenantId]
+
+	s.mu.Unlock()
+
+	if ok {
+
+		return srv
+	}
+
+	// the schema is built outside the lock, so a slow build holds up no other tenant
+	built, err, _ := s.builds.Do(strconv.Itoa(tenantId), func() (interface{}, error) {
+
+		s.mu.Lock()
+
+		generation := s.generations[tenantId]
+
+		s.mu.Unlock()
+
+		execSchema, err := channelschema.Load(graphConfig(), tenantId)
+
+		if err != nil {
+
+			return nil, err
+		}
+
+		srv := NewGraphqlServer(s.config, execSchema, s.responseCache)
+
+		s.mu.Lock()
+
+		if s.generations[tenantId] == generation {
+
+			s.servers[tenantId] = srv
+		}
+
+		s.mu.Unlock()
+
+		return srv, nil
+	})
+
+	if err != nil {
+
+		// the base schema still answers the request, the typed schema is tried again on the next one
+		controller.ErrorLog.Printf("%v", err)
+
+		return s.base
+	}
+
+	return built.(*handler.Server)
+}
+
+// tenantOf returns the tenant of the api key, the key itself is checked by the @auth directive of every field
+func (s *tenantServers) tenantOf(apiKey string) int {
+
+	if apiKey == "" {
+
+		return 0
+	}
+
+	s.mu.Lock()
+
+	tenantId, ok := s.tenants[apiKey]
+
+	s.mu.Unlock()
+
+	if ok {
+
+		return tenantId
+	}
+
+	var graphqlSettings model.TblGraphqlSettings
+
+	if err := model.Model.GetApiSettings(apiKey, &graphqlSettings); err != nil {
+
+		return 0
+	}
+
+	s.mu.Lock()
+
+	s.tenants[apiKey] = graphqlSettings.TenantId
+
+	s.mu.Unlock()
+
+	return graphqlSettings.TenantId
+}
+
+// startInvalidation drops the server of a tenant when one of its channels changes, the next request rebuilds the schema
+func (s *tenantServers) startInvalidation() {
+
+	s.responseCache.StartInvalidation()
+
+	channelEvents, _ := events.SubscribeChannelEvents(-1)
+
+	go func() {
+
+		for event := range channelEvents {
+
+			s.mu.Lock()
+
+			delete(s.servers, event.TenantId)
+
+			s.generations[event.TenantId]++
+
+			s.mu.Unlock()
+
+			// requests after the change do not wait for a build that started before it
+			s.builds.Forget(strconv.Itoa(event.TenantId))
+		}
+	}()
+}
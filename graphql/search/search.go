@@ -0,0 +1,274 @@
+package search
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// longer queries are cut, every term adds a condition to the full text match
+	MaxTerms = 10
+
+	DefaultSnippetLength = 160
+
+	highlightStart = "<mark>"
+
+	highlightEnd = "</mark>"
+)
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// Terms splits a search query into lower case words, punctuation and full text operators are dropped
+func Terms(query string) []string {
+
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var terms []string
+
+	seen := make(map[string]bool, len(words))
+
+	for _, word := range words {
+
+		if seen[word] {
+
+			continue
+		}
+
+		seen[word] = true
+
+		terms = append(terms, word)
+
+		if len(terms) == MaxTerms {
+
+			break
+		}
+	}
+
+	return terms
+}
+
+// PostgresQuery builds a to_tsquery expression that matches every term as a prefix
+func PostgresQuery(terms []string) string {
+
+	parts := make([]string, len(terms))
+
+	for index, term := range terms {
+
+		parts[index] = term + ":*"
+	}
+
+	return strings.Join(parts, " & ")
+}
+
+// MysqlQuery builds a boolean mode match expression that requires every term as a prefix
+func MysqlQuery(terms []string) string {
+
+	parts := make([]string, len(terms))
+
+	for index, term := range terms {
+
+		parts[index] = "+" + term + "*"
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// PlainText strips the markup of an editor field so only the words are indexed
+func PlainText(value string) string {
+
+	value = tagPattern.ReplaceAllString(value, " ")
+
+	return strings.Join(strings.Fields(html.UnescapeString(value)), " ")
+}
+
+// Snippet returns the part of the text around the first matching term, html escaped with every term match wrapped in
+// a mark element. The start of the text is returned when no term matches.
+func Snippet(text string, terms []string, length int) string {
+
+	if length <= 0 {
+
+		length = DefaultSnippetLength
+	}
+
+	runes := []rune(text)
+
+	lower := []rune(strings.ToLower(text))
+
+	// lower casing can change the length of some runes, matching then falls back to the start of the text
+	if len(lower) != len(runes) {
+
+		lower = runes
+	}
+
+	first := -1
+
+	for _, term := range terms {
+
+		if at := indexRunes(lower, []rune(term), 0); at != -1 && (first == -1 || at < first) {
+
+			first = at
+		}
+	}
+
+	start := 0
+
+	if first > length/3 {
+
+		start = first - length/3
+
+		// start the snippet on a word
+		for start < first && !unicode.IsSpace(runes[start-1]) {
+
+			start++
+		}
+	}
+
+	end := start + length
+
+	if end > len(runes) {
+
+		end = len(runes)
+	}
+
+	var snippet strings.Builder
+
+	if start > 0 {
+
+		snippet.WriteString("…")
+	}
+
+	for index := start; index < end; {
+
+		matched := 0
+
+		for _, term := range terms {
+
+			termRunes := []rune(term)
+
+			if len(termRunes) > matched && index+len(termRunes) <= end && indexRunes(lower[index:index+len(termRunes)], termRunes, 0) == 0 {
+
+				matched = len(termRunes)
+			}
+		}
+
+		if matched == 0 {
+
+			snippet.WriteString(html.EscapeString(string(runes[index])))
+
+			index++
+
+			continue
+		}
+
+		snippet.WriteString(highlightStart + html.EscapeString(string(runes[index:index+matched])) + highlightEnd)
+
+		index += matched
+	}
+
+	if end < len(runes) {
+
+		snippet.WriteString("…")
+	}
+
+	return snippet.String()
+}
+
+func indexRunes(text, term []rune, from int) int {
+
+	if len(term) == 0 {
+
+		return -1
+	}
+
+	for index := from; index+len(term) <= len(text); index++ {
+
+		matched := true
+
+		for offset, r := range term {
+
+			if text[index+offset] != r {
+
+				matched = false
+
+				break
+			}
+		}
+
+		if matched {
+
+			return index
+		}
+	}
+
+	return -1
+}
+
+type FacetCount struct {
+	Key   string
+	Count int
+}
+
+// Facet counts the documents of every key, a document is counted once per key. The keys are sorted by count and then
+// by key.
+type Facet map[string]int
+
+func (facet Facet) Add(keys ...string) {
+
+	seen := make(map[string]bool, len(keys))
+
+	for _, key := range keys {
+
+		if key == "" || seen[key] {
+
+			continue
+		}
+
+		seen[key] = true
+
+		facet[key]++
+	}
+}
+
+func (facet Facet) Counts() []FacetCount {
+
+	counts := make([]FacetCount, 0, len(facet))
+
+	for key, count := range facet {
+
+		counts = append(counts, FacetCount{Key: key, Count: count})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+
+		if counts[i].Count != counts[j].Count {
+
+			return counts[i].Count > counts[j].Count
+		}
+
+		return counts[i].Key < counts[j].Key
+	})
+
+	return counts
+}
+
+// SplitList returns the trimmed values of a comma separated column like the entry tags and categories
+func SplitList(value string) []string {
+
+	var values []string
+
+	for _, item := range strings.Split(value, ",") {
+
+		if item = strings.TrimSpace(item); item != "" {
+
+			values = append(values, item)
+		}
+	}
+
+	return values
+}
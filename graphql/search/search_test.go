@@ -0,0 +1,75 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+
+	terms := Terms(`Go "Generics" & go:* -tips, café`)
+
+	if expected := []string{"go", "generics", "tips", "café"}; !reflect.DeepEqual(terms, expected) {
+		t.Fatalf("Terms = %q, expected %q", terms, expected)
+	}
+
+	if PostgresQuery(terms) != "go:* & generics:* & tips:* & café:*" {
+		t.Fatalf("unexpected postgres query %q", PostgresQuery(terms))
+	}
+
+	if MysqlQuery(terms) != "+go* +generics* +tips* +café*" {
+		t.Fatalf("unexpected mysql query %q", MysqlQuery(terms))
+	}
+
+	if len(Terms(strings.Repeat("a b c d e f g h i j k l ", 2))) != MaxTerms {
+		t.Fatalf("expected the terms to be capped at %d", MaxTerms)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+
+	if text := PlainText("<p>Fish &amp; <b>chips</b></p>\n<p>today</p>"); text != "Fish & chips today" {
+		t.Fatalf("PlainText = %q", text)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+
+	text := strings.Repeat("filler words ", 30) + "the Search <engine> ranks results " + strings.Repeat("more words ", 30)
+
+	snippet := Snippet(text, []string{"search", "ranks"}, 80)
+
+	if !strings.HasPrefix(snippet, "…") || !strings.HasSuffix(snippet, "…") {
+		t.Fatalf("expected a cut snippet, got %q", snippet)
+	}
+
+	if !strings.Contains(snippet, "<mark>Search</mark> &lt;engine&gt; <mark>ranks</mark>") {
+		t.Fatalf("expected escaped text with marked terms, got %q", snippet)
+	}
+
+	if snippet := Snippet("short text", []string{"missing"}, 80); snippet != "short text" {
+		t.Fatalf("expected the whole text without a match, got %q", snippet)
+	}
+}
+
+func TestFacet(t *testing.T) {
+
+	facet := Facet{}
+
+	facet.Add("go", "news", "go")
+
+	facet.Add("news")
+
+	facet.Add("", "api")
+
+	expected := []FacetCount{{Key: "news", Count: 2}, {Key: "api", Count: 1}, {Key: "go", Count: 1}}
+
+	if counts := facet.Counts(); !reflect.DeepEqual(counts, expected) {
+		t.Fatalf("Counts = %v, expected %v", counts, expected)
+	}
+
+	if values := SplitList(" a, ,b,"); !reflect.DeepEqual(values, []string{"a", "b"}) {
+		t.Fatalf("SplitList = %q", values)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"spurt-cms/graphql/routes"
+	"strings"
 	"sync"
 
 	"github.com/gin-gonic/gin"
@@ -17,6 +18,13 @@ func RunGraphqlServer(wg *sync.WaitGroup) {
 
 	r := gin.Default()
 
+	// the client ip counts entry views and is only taken from X-Forwarded-For when the request came through one of
+	// these proxies, without any the address of the connection is used
+	if err := r.SetTrustedProxies(trustedProxies()); err != nil {
+
+		log.Fatal(err)
+	}
+
 	REngine := routes.GetEndpointHandlers(r)
 
 	if err := REngine.Run(":" + port); err != nil{
@@ -25,3 +33,19 @@ func RunGraphqlServer(wg *sync.WaitGroup) {
 	}
 
 }
+
+// trustedProxies reads the comma separated ips and cidr ranges of GRAPHQL_TRUSTED_PROXIES
+func trustedProxies() []string {
+
+	var proxies []string
+
+	for _, proxy := range strings.Split(os.Getenv("GRAPHQL_TRUSTED_PROXIES"), ",") {
+
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	return proxies
+}
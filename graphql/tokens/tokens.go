@@ -0,0 +1,178 @@
+package tokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Audiences of the graphql tokens. The admin and website tokens are signed with the same secret, the audience keeps
+// a token from being accepted where it was not meant to be.
+const (
+	MemberAudience = "graphql-member"
+
+	PreviewAudience = "graphql-preview"
+)
+
+const (
+	AccessTokenTTL = 15 * time.Minute
+
+	RefreshTokenTTL = 30 * 24 * time.Hour
+
+	DefaultPreviewTTL = time.Hour
+
+	// longest time a preview link stays valid
+	MaxPreviewTTL = 7 * 24 * time.Hour
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims of a token issued by this package
+type Claims interface {
+	jwt.Claims
+
+	registered() *jwt.RegisteredClaims
+
+	// complete reports whether the claims identify what the token was issued for
+	complete() bool
+}
+
+// MemberClaims of a member access token. The session is checked on every request, so a logout applies before the token expires.
+type MemberClaims struct {
+	MemberId  int `json:"member_id"`
+	TenantId  int `json:"tenant_id"`
+	SessionId int `json:"session_id"`
+	jwt.RegisteredClaims
+}
+
+func (c *MemberClaims) registered() *jwt.RegisteredClaims {
+
+	return &c.RegisteredClaims
+}
+
+func (c *MemberClaims) complete() bool {
+
+	return c.MemberId != 0 && c.SessionId != 0
+}
+
+// PreviewClaims of a preview token, it lets whoever holds it read the current version of one entry whatever its status
+type PreviewClaims struct {
+	EntryId  int `json:"entry_id"`
+	TenantId int `json:"tenant_id"`
+	jwt.RegisteredClaims
+}
+
+func (c *PreviewClaims) registered() *jwt.RegisteredClaims {
+
+	return &c.RegisteredClaims
+}
+
+func (c *PreviewClaims) complete() bool {
+
+	return c.EntryId != 0
+}
+
+// PreviewTTL returns the validity of a preview token asked for ttl, the default when none is given and at most MaxPreviewTTL
+func PreviewTTL(ttl time.Duration) time.Duration {
+
+	if ttl <= 0 {
+
+		return DefaultPreviewTTL
+	}
+
+	if ttl > MaxPreviewTTL {
+
+		return MaxPreviewTTL
+	}
+
+	return ttl
+}
+
+// Issue signs the claims for the audience and returns the token with its expiry
+func Issue(secret, audience string, claims Claims, ttl time.Duration, now time.Time) (string, time.Time, error) {
+
+	expiresAt := now.Add(ttl)
+
+	registered := claims.registered()
+
+	registered.Audience = jwt.ClaimStrings{audience}
+
+	registered.IssuedAt = jwt.NewNumericDate(now)
+
+	registered.ExpiresAt = jwt.NewNumericDate(expiresAt)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+
+	if err != nil {
+
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// Parse verifies the signature, audience and expiry of a token and decodes it into claims
+func Parse(secret, audience, token string, claims Claims, now time.Time) error {
+
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+
+		if t.Method != jwt.SigningMethodHS256 {
+
+			return nil, ErrInvalidToken
+		}
+
+		return []byte(secret), nil
+	})
+
+	if err != nil || !parsed.Valid {
+
+		return ErrInvalidToken
+	}
+
+	registered := claims.registered()
+
+	if !registered.VerifyAudience(audience, true) || !registered.VerifyExpiresAt(now, true) || !claims.complete() {
+
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+// NewRefreshToken returns a random opaque token, only its hash is stored
+func NewRefreshToken() (string, error) {
+
+	buf := make([]byte, 32)
+
+	if _, err := rand.Read(buf); err != nil {
+
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+func HashRefreshToken(token string) string {
+
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// BearerToken returns the token of an Authorization header, or an empty string when there is none
+func BearerToken(header string) string {
+
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
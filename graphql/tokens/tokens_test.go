@@ -0,0 +1,133 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestMemberTokenRoundTrip(t *testing.T) {
+
+	now := time.Now()
+
+	token, expiresAt, err := Issue("secret", MemberAudience, &MemberClaims{MemberId: 7, TenantId: 3, SessionId: 11}, AccessTokenTTL, now)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !expiresAt.Equal(now.Add(AccessTokenTTL)) {
+		t.Fatalf("unexpected expiry %v", expiresAt)
+	}
+
+	var claims MemberClaims
+
+	if err := Parse("secret", MemberAudience, token, &claims, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.MemberId != 7 || claims.TenantId != 3 || claims.SessionId != 11 {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+}
+
+func TestPreviewTokenRoundTrip(t *testing.T) {
+
+	now := time.Now()
+
+	token, expiresAt, err := Issue("secret", PreviewAudience, &PreviewClaims{EntryId: 42, TenantId: 3}, PreviewTTL(0), now)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !expiresAt.Equal(now.Add(DefaultPreviewTTL)) {
+		t.Fatalf("unexpected expiry %v", expiresAt)
+	}
+
+	var claims PreviewClaims
+
+	if err := Parse("secret", PreviewAudience, token, &claims, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.EntryId != 42 || claims.TenantId != 3 {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+}
+
+func TestPreviewTTLIsCapped(t *testing.T) {
+
+	if ttl := PreviewTTL(30 * 24 * time.Hour); ttl != MaxPreviewTTL {
+		t.Fatalf("expected the ttl to be capped, got %v", ttl)
+	}
+
+	if ttl := PreviewTTL(2 * time.Hour); ttl != 2*time.Hour {
+		t.Fatalf("expected the ttl to be kept, got %v", ttl)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+
+	now := time.Now()
+
+	token, _, _ := Issue("secret", MemberAudience, &MemberClaims{MemberId: 7, TenantId: 3, SessionId: 11}, AccessTokenTTL, now)
+
+	if err := Parse("other", MemberAudience, token, &MemberClaims{}, now); err != ErrInvalidToken {
+		t.Fatalf("expected a token signed with another secret to be rejected, got %v", err)
+	}
+
+	if err := Parse("secret", MemberAudience, token, &MemberClaims{}, now.Add(AccessTokenTTL+time.Second)); err != ErrInvalidToken {
+		t.Fatalf("expected an expired token to be rejected, got %v", err)
+	}
+
+	if err := Parse("secret", MemberAudience, token[:len(token)-2], &MemberClaims{}, now); err != ErrInvalidToken {
+		t.Fatalf("expected a tampered token to be rejected, got %v", err)
+	}
+
+	// member and preview tokens are signed with the same secret but are only accepted for their own audience
+	if err := Parse("secret", PreviewAudience, token, &PreviewClaims{}, now); err != ErrInvalidToken {
+		t.Fatalf("expected a member token to be rejected as a preview token, got %v", err)
+	}
+
+	// a website member token carries a member id but is not meant for the graphql api
+	other, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"member_id": 7, "session_id": 11}).SignedString([]byte("secret"))
+
+	if err := Parse("secret", MemberAudience, other, &MemberClaims{}, now); err != ErrInvalidToken {
+		t.Fatalf("expected a token without the audience to be rejected, got %v", err)
+	}
+}
+
+func TestParseRejectsIncompleteClaims(t *testing.T) {
+
+	now := time.Now()
+
+	token, _, _ := Issue("secret", PreviewAudience, &PreviewClaims{TenantId: 3}, time.Hour, now)
+
+	if err := Parse("secret", PreviewAudience, token, &PreviewClaims{}, now); err != ErrInvalidToken {
+		t.Fatalf("expected a preview token without an entry to be rejected, got %v", err)
+	}
+}
+
+func TestRefreshTokenAndBearer(t *testing.T) {
+
+	first, _ := NewRefreshToken()
+
+	second, _ := NewRefreshToken()
+
+	if len(first) != 64 || first == second {
+		t.Fatalf("expected random 32 byte tokens, got %q %q", first, second)
+	}
+
+	if HashRefreshToken(first) == first || HashRefreshToken(first) != HashRefreshToken(first) {
+		t.Fatalf("expected a stable hash different from the token")
+	}
+
+	for header, expected := range map[string]string{"Bearer abc": "abc", "bearer  abc ": "abc", "Basic abc": "", "": "", "abc": ""} {
+
+		if token := BearerToken(header); token != expected {
+			t.Fatalf("BearerToken(%q) = %q, expected %q", header, token, expected)
+		}
+	}
+}
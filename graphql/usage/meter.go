@@ -0,0 +1,139 @@
+package usage
+
+import (
+	"sync"
+	"time"
+)
+
+// Counter is the usage of one api key on one day
+type Counter struct {
+	TokenId    int
+	TenantId   int
+	Day        time.Time
+	Requests   int
+	Errors     int
+	LastUsedOn time.Time
+}
+
+type counterKey struct {
+	tokenId int
+	day     time.Time
+}
+
+// Meter counts the requests of the api keys in memory, the counters are written to the database in batches by Flush
+// instead of on every request.
+type Meter struct {
+	mu sync.Mutex
+
+	counters map[counterKey]*Counter
+}
+
+func NewMeter() *Meter {
+
+	return &Meter{counters: make(map[counterKey]*Counter)}
+}
+
+func (meter *Meter) counter(tokenId int, tenantId int, at time.Time) *Counter {
+
+	at = at.UTC()
+
+	key := counterKey{tokenId: tokenId, day: time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)}
+
+	counter, ok := meter.counters[key]
+
+	if !ok {
+
+		counter = &Counter{TokenId: tokenId, TenantId: tenantId, Day: key.day}
+
+		meter.counters[key] = counter
+	}
+
+	return counter
+}
+
+func (meter *Meter) Request(tokenId int, tenantId int, at time.Time) {
+
+	meter.mu.Lock()
+
+	defer meter.mu.Unlock()
+
+	counter := meter.counter(tokenId, tenantId, at)
+
+	counter.Requests++
+
+	if at.After(counter.LastUsedOn) {
+
+		counter.LastUsedOn = at
+	}
+}
+
+func (meter *Meter) Error(tokenId int, tenantId int, at time.Time) {
+
+	meter.mu.Lock()
+
+	defer meter.mu.Unlock()
+
+	meter.counter(tokenId, tenantId, at).Errors++
+}
+
+// Take returns the counters collected since the last call and resets them
+func (meter *Meter) Take() []Counter {
+
+	meter.mu.Lock()
+
+	defer meter.mu.Unlock()
+
+	counters := make([]Counter, 0, len(meter.counters))
+
+	for _, counter := range meter.counters {
+
+		counters = append(counters, *counter)
+	}
+
+	meter.counters = make(map[counterKey]*Counter)
+
+	return counters
+}
+
+// Restore puts back counters that could not be saved, they are added to whatever was counted in the meantime
+func (meter *Meter) Restore(counters []Counter) {
+
+	meter.mu.Lock()
+
+	defer meter.mu.Unlock()
+
+	for _, restored := range counters {
+
+		counter := meter.counter(restored.TokenId, restored.TenantId, restored.Day)
+
+		counter.Requests += restored.Requests
+
+		counter.Errors += restored.Errors
+
+		if restored.LastUsedOn.After(counter.LastUsedOn) {
+
+			counter.LastUsedOn = restored.LastUsedOn
+		}
+	}
+}
+
+// Flush saves the collected counters every interval, failed saves are retried on the next tick
+func (meter *Meter) Flush(interval time.Duration, save func([]Counter) error, onError func(error)) {
+
+	for range time.Tick(interval) {
+
+		counters := meter.Take()
+
+		if len(counters) == 0 {
+
+			continue
+		}
+
+		if err := save(counters); err != nil {
+
+			meter.Restore(counters)
+
+			onError(err)
+		}
+	}
+}
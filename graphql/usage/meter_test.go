@@ -0,0 +1,64 @@
+package usage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMeterCountsPerTokenAndDay(t *testing.T) {
+
+	meter := NewMeter()
+
+	morning := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	meter.Request(1, 10, morning)
+
+	meter.Request(1, 10, morning.Add(time.Hour))
+
+	meter.Error(1, 10, morning.Add(time.Hour))
+
+	meter.Request(1, 10, morning.Add(24*time.Hour))
+
+	meter.Request(2, 10, morning)
+
+	counters := meter.Take()
+
+	if len(counters) != 3 {
+		t.Fatalf("expected a counter per token and day, got %v", counters)
+	}
+
+	for _, counter := range counters {
+
+		if counter.TokenId == 1 && counter.Day.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+
+			if counter.Requests != 2 || counter.Errors != 1 || !counter.LastUsedOn.Equal(morning.Add(time.Hour)) {
+				t.Fatalf("unexpected counter %+v", counter)
+			}
+		}
+	}
+
+	if len(meter.Take()) != 0 {
+		t.Fatalf("expected Take to reset the counters")
+	}
+}
+
+func TestMeterRestore(t *testing.T) {
+
+	meter := NewMeter()
+
+	at := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	meter.Request(1, 10, at)
+
+	failed := meter.Take()
+
+	meter.Request(1, 10, at.Add(time.Minute))
+
+	meter.Restore(failed)
+
+	counters := meter.Take()
+
+	if len(counters) != 1 || counters[0].Requests != 2 || !counters[0].LastUsedOn.Equal(at.Add(time.Minute)) {
+		t.Fatalf("expected the failed counters to be merged back, got %+v", counters)
+	}
+}
@@ -248,6 +248,11 @@ type Translation struct {
 		Smtp                  string `json:"smtp"`
 		Environment           string `json:"environment"`
 		Imagetypeerror        string `json:"imagetypeerror"`
+		Imagepresets          string `json:"imagepresets"`
+		Chooseimagepresets    string `json:"chooseimagepresets"`
+		Imagesizes            string `json:"imagesizes"`
+		Recyclebin            string `json:"recyclebin"`
+		Recyclebincontent     string `json:"recyclebincontent"`
 	} `json:"Setting"`
 
 	Emailtemplate struct {
@@ -438,6 +443,15 @@ type Translation struct {
 		AddFields              string `json:"addfields"`
 		ThisContainsEntries    string `json:"thischannelcontainsentries"`
 		Duplicate              string `json:"duplicate"`
+		Revisionlimit          string `json:"revisionlimit"`
+		Revisionlimitdesc      string `json:"revisionlimitdesc"`
+		Workflow               string `json:"workflow"`
+		Workflownone           string `json:"workflownone"`
+		Workflowreview         string `json:"workflowreview"`
+		Workflowapproval       string `json:"workflowapproval"`
+		Workflowdesc           string `json:"workflowdesc"`
+		Inreview               string `json:"inreview"`
+		Approved               string `json:"approved"`
 	} `json:"Channell"`
 
 	Userss struct {
@@ -562,6 +576,22 @@ type Translation struct {
 		Deactive        string `json:"deactive"`
 		Membersavilable string `json:"membersavilable"`
 		Memberavilable  string `json:"memberavilable"`
+		Defaultgroup    string `json:"defaultgroup"`
+		Defaultgrpcont  string `json:"defaultgrpcont"`
+		Emailverify     string `json:"emailverify"`
+		Emailverifycont string `json:"emailverifycont"`
+		Verifyurl       string `json:"verifyurl"`
+		Verifyurlcont   string `json:"verifyurlcont"`
+		Notes           string `json:"notes"`
+		Notesavilable   string `json:"notesavilable"`
+		Noteavilable    string `json:"noteavilable"`
+		Entry           string `json:"entry"`
+		Notetype        string `json:"notetype"`
+		Note            string `json:"note"`
+		Highlight       string `json:"highlight"`
+		Alltypes        string `json:"alltypes"`
+		Content         string `json:"content"`
+		Configuration   string `json:"configuration"`
 	} `json:"Memberss"`
 
 	MembersGroup struct {
@@ -1140,6 +1170,27 @@ type Translation struct {
 		Copyheading             string `json:"copyheading"`
 		Copydetails             string `json:"copydetails"`
 		Notes                   string `json:"notes"`
+		ChannelsFieldHeading    string `json:"channelsfieldheading"`
+		AllChannels             string `json:"allchannels"`
+		ChannelsDescription     string `json:"channelsdescription"`
+		WriteAccessHeading      string `json:"writeaccessheading"`
+		WriteAccessDescription  string `json:"writeaccessdescription"`
+		MemberAccessHeading     string `json:"memberaccessheading"`
+		MemberAccessDescription string `json:"memberaccessdescription"`
+		TableScopeHeading       string `json:"tablescopeheading"`
+		ScopeRead               string `json:"scoperead"`
+		ScopeWrite              string `json:"scopewrite"`
+		RateLimitHeading        string `json:"ratelimitheading"`
+		RateLimitDescription    string `json:"ratelimitdescription"`
+		RateBurstHeading        string `json:"rateburstheading"`
+		RateBurstDescription    string `json:"rateburstdescription"`
+		TableUsageHeading       string `json:"tableusageheading"`
+		TableLastUsedHeading    string `json:"tablelastusedheading"`
+		UsageToday              string `json:"usagetoday"`
+		UsageWeek               string `json:"usageweek"`
+		UsageRequests           string `json:"usagerequests"`
+		UsageErrors             string `json:"usageerrors"`
+		NeverUsed               string `json:"neverused"`
 	} `json:"Graphql"`
 
 	Webhooks struct {
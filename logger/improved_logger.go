@@ -62,7 +62,7 @@ func DefaultConfig() Config {
 type ImprovedLogger struct {
 	config Config
 	writer io.Writer
-	file   *os.File
+	file   *logFile
 }
 
 // LogEntry represents a structured log entry
@@ -77,24 +77,12 @@ type LogEntry struct {
 
 // NewLogger creates a new ImprovedLogger with the given configuration
 func NewLogger(config Config) (*ImprovedLogger, error) {
-	// Create logs directory if it doesn't exist
-	if config.OutputPath != "" {
-		dir := filepath.Dir(config.OutputPath)
-		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-			return nil, fmt.Errorf("failed to create log directory: %v", err)
-		}
-	}
-
 	var writers []io.Writer
-	var file *os.File
+	var file *logFile
 
-	// Add file writer if output path is specified
+	// Add file writer if output path is specified; the file is created on the first write
 	if config.OutputPath != "" {
-		var err error
-		file, err = os.OpenFile(config.OutputPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
-		if err != nil {
-			return nil, fmt.Errorf("failed to open log file: %v", err)
-		}
+		file = &logFile{path: config.OutputPath}
 		writers = append(writers, file)
 	}
 
@@ -286,6 +274,8 @@ func init() {
 	if err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
+
+	outputFile = globalLogger.file
 }
 
 // GetLogger returns the global logger instance
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// logFile is a log file that is created on the first write, so importing the
+// package leaves no files behind until something is actually logged.
+type logFile struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+var (
+	errorFile = &logFile{path: LogFile}
+
+	outputFile *logFile
+)
+
+// Write appends p to the log file, creating the file and its directory first if needed.
+func (l *logFile) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		if err := os.MkdirAll(filepath.Dir(l.path), os.ModePerm); err != nil {
+			return 0, err
+		}
+
+		file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+		if err != nil {
+			return 0, err
+		}
+
+		l.file = file
+	}
+
+	return l.file.Write(p)
+}
+
+// Sync flushes the log file if it is open.
+func (l *logFile) Sync() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	return l.file.Sync()
+}
+
+// Close closes the log file; the next write opens it again.
+func (l *logFile) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	err := l.file.Close()
+	l.file = nil
+
+	return err
+}
+
+func (l *logFile) setDir(dir string) {
+	l.Close()
+
+	l.mu.Lock()
+	l.path = filepath.Join(dir, filepath.Base(l.path))
+	l.mu.Unlock()
+}
+
+// SetLogDir moves the log files of the package into dir. Tests point it at a
+// temporary directory so their logs stay out of the source tree.
+func SetLogDir(dir string) {
+	errorFile.setDir(dir)
+
+	if outputFile != nil {
+		outputFile.setDir(dir)
+	}
+}
@@ -1,9 +1,7 @@
 package logger
 
 import (
-	"fmt"
 	"log"
-	"os"
 )
 
 var LogFile = "logs/error.log"
@@ -25,38 +23,11 @@ type Logger struct {
 var logger *Logger
 
 func init() {
-	makedir := os.Mkdir("logs", os.ModePerm)
-
-	if makedir != nil {
-		log.Printf("Error creating logs directory: %v", makedir)
-	}
-
-	_, err := os.Stat(LogFile)
-
-	var file *os.File
-
-	if os.IsNotExist(err) {
-		// Create the file since it does not exist
-		_, err = os.Create(LogFile)
-
-		if err != nil {
-			fmt.Printf("Failed to create log file: %v", err)
-		}
-
-		log.Println("Created new log file.")
-	}
-
-	file, err = os.OpenFile(LogFile, os.O_APPEND|os.O_WRONLY, 0666)
-
-	if err != nil {
-		fmt.Printf("Failed to open log file: %v", err)
-	}
-
-	log.SetOutput(file)
+	log.SetOutput(errorFile)
 
 	logger = &Logger{
-		InfoLogger:  log.New(file, "INFO ", log.LstdFlags|log.Lshortfile),
-		WarnLogger:  log.New(file, "WARN ", log.LstdFlags|log.Lshortfile),
-		ErrorLogger: log.New(file, "ERROR ", log.LstdFlags|log.Lshortfile),
+		InfoLogger:  log.New(errorFile, "INFO ", log.LstdFlags|log.Lshortfile),
+		WarnLogger:  log.New(errorFile, "WARN ", log.LstdFlags|log.Lshortfile),
+		ErrorLogger: log.New(errorFile, "ERROR ", log.LstdFlags|log.Lshortfile),
 	}
 }
@@ -17,6 +17,10 @@ func RunAdminPanel(wg *sync.WaitGroup) {
 
 	r := routes.SetupRoutes() // setup routes
 
+	go controllers.RunEntryScheduler() // publish and unpublish the scheduled entries
+
+	go controllers.RunRecycleBinPurge() // delete the records kept in the recycle bin longer than the tenant allows
+
 	err := r.Run(":" + os.Getenv("PORT"))
 
 	if err != nil {
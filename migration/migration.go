@@ -12,10 +12,14 @@ import (
 	"strings"
 	"time"
 	"spurt-cms/logger"
+
+	"gorm.io/gorm"
 )
 
 func TableMigration() {
 
+	mergeEntryViewRows(controllers.DB)
+
 	if os.Getenv("DATABASE_TYPE") == "postgres" {
 
 		postgres.MigrationTables() //auto migrate table
@@ -473,6 +477,85 @@ func InsertDefaultValues() {
 
 	}
 
+	workflowPermissions(db)
+
 	// S3folderCreation()
 	logger.Info("SQL file loaded successfully.")
 }
+
+// workflowPermissions adds the permissions of the entry review workflows to the Entries module of the roles. They
+// are added by name, the ids of the module permissions differ between installs.
+func workflowPermissions(db *gorm.DB) {
+
+	permissions := []map[string]interface{}{
+		{"route_name": "/channel/transition", "display_name": "Review", "slug_name": "review", "description": "Approve or reject the entries submitted for review", "order_index": 1},
+		{"route_name": "/channel/transition", "display_name": "Publish", "slug_name": "publish", "description": "Publish and unpublish the entries of channels with a review workflow", "order_index": 2},
+	}
+
+	for _, permission := range permissions {
+
+		var count int64
+
+		if err := db.Table("tbl_module_permissions").Where("module_id = 8 and display_name = ?", permission["display_name"]).Count(&count).Error; err != nil || count > 0 {
+
+			continue
+		}
+
+		permission["module_id"] = 8
+		permission["created_by"] = 1
+		permission["created_on"] = time.Now().UTC()
+		permission["full_access_permission"] = 0
+		permission["parent_id"] = 0
+		permission["assign_permission"] = 0
+
+		if err := db.Table("tbl_module_permissions").Create(permission).Error; err != nil {
+
+			log.Println(err)
+		}
+	}
+}
+
+// mergeEntryViewRows adds up the view rows an entry has for the same day, so the unique index over entry and day can
+// be created on tables written before it existed
+func mergeEntryViewRows(db *gorm.DB) {
+
+	if db == nil || !db.Migrator().HasTable("tbl_graphql_entry_views") {
+
+		return
+	}
+
+	var duplicates []struct {
+		EntryId   int
+		ViewDate  time.Time
+		Id        int
+		ViewCount int
+	}
+
+	err := db.Table("tbl_graphql_entry_views").Select("entry_id, view_date, min(id) as id, sum(view_count) as view_count").
+		Group("entry_id, view_date").Having("count(*) > 1").Scan(&duplicates).Error
+
+	if err != nil {
+
+		log.Println(err)
+
+		return
+	}
+
+	for _, duplicate := range duplicates {
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+
+			if err := tx.Table("tbl_graphql_entry_views").Where("id = ?", duplicate.Id).UpdateColumn("view_count", duplicate.ViewCount).Error; err != nil {
+
+				return err
+			}
+
+			return tx.Exec("delete from tbl_graphql_entry_views where entry_id = ? and view_date = ? and id <> ?", duplicate.EntryId, duplicate.ViewDate, duplicate.Id).Error
+		})
+
+		if err != nil {
+
+			log.Println(err)
+		}
+	}
+}
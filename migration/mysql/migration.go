@@ -82,6 +82,10 @@ type TblChannels struct {
 	ModifiedBy         int       `gorm:"DEFAULT:NULL;type:int"`
 	TenantId           int       `gorm:"type:int;"`
 	ChannelType        string    `gorm:"type:varchar(255)"`
+	RevisionLimit      int       `gorm:"type:int;DEFAULT:NULL"`
+	Workflow           string    `gorm:"type:varchar(255);DEFAULT:''"`
+	DeletedOn          time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+	DeletedBy          int       `gorm:"DEFAULT:NULL;type:int"`
 }
 
 type TblMemberGroups struct {
@@ -405,6 +409,7 @@ type TblChannelEntries struct {
 	ParentId        int       `gorm:"type:int;"`
 	OrderIndex      int       `gorm:"type:int;"`
 	MembergroupId   string    `gorm:"type:varchar(255)"`
+	Version         int       `gorm:"type:int;DEFAULT:1"`
 }
 
 type TblChannelEntryFields struct {
@@ -422,6 +427,65 @@ type TblChannelEntryFields struct {
 	TenantId       int       `gorm:"type:int;"`
 }
 
+type TblChannelEntryLocks struct {
+	Id          int       `gorm:"primaryKey;auto_increment"`
+	EntryId     int       `gorm:"type:int;uniqueIndex"`
+	UserId      int       `gorm:"type:int"`
+	LockedOn    time.Time `gorm:"type:datetime"`
+	HeartbeatOn time.Time `gorm:"type:datetime"`
+	TenantId    int       `gorm:"type:int"`
+}
+
+type TblChannelEntryRevisions struct {
+	Id        int       `gorm:"primaryKey;auto_increment"`
+	EntryId   int       `gorm:"type:int;index"`
+	ChannelId int       `gorm:"type:int"`
+	Title     string    `gorm:"type:varchar(255)"`
+	Snapshot  string    `gorm:"type:LONGTEXT"`
+	CreatedBy int       `gorm:"type:int"`
+	CreatedOn time.Time `gorm:"type:datetime"`
+	TenantId  int       `gorm:"type:int;"`
+}
+
+type TblChannelEntrySchedules struct {
+	Id        int       `gorm:"primaryKey;auto_increment"`
+	EntryId   int       `gorm:"type:int;index"`
+	Action    string    `gorm:"type:varchar(255)"`
+	RunAt     time.Time `gorm:"type:datetime;index"`
+	Status    string    `gorm:"type:varchar(255);index"`
+	ClaimedBy string    `gorm:"type:varchar(255)"`
+	ClaimedOn time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+	RunOn     time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+	Message   string    `gorm:"type:varchar(255)"`
+	CreatedBy int       `gorm:"type:int"`
+	CreatedOn time.Time `gorm:"type:datetime"`
+	TenantId  int       `gorm:"type:int;"`
+}
+
+type TblChannelEntryWorkflowLogs struct {
+	Id         int       `gorm:"primaryKey;auto_increment"`
+	EntryId    int       `gorm:"type:int;index"`
+	ChannelId  int       `gorm:"type:int"`
+	Action     string    `gorm:"type:varchar(255)"`
+	FromStatus int       `gorm:"type:int"`
+	ToStatus   int       `gorm:"type:int"`
+	Comment    string    `gorm:"type:LONGTEXT"`
+	CreatedBy  int       `gorm:"type:int"`
+	CreatedOn  time.Time `gorm:"type:datetime"`
+	TenantId   int       `gorm:"type:int"`
+}
+
+type TblRecycleBinLinks struct {
+	Id         int       `gorm:"primaryKey;auto_increment"`
+	Module     string    `gorm:"type:varchar(255)"`
+	RecordId   int       `gorm:"type:int;index"`
+	LinkType   string    `gorm:"type:varchar(255)"`
+	LinkId     int       `gorm:"type:int"`
+	CategoryId string    `gorm:"type:text"`
+	DeletedOn  time.Time `gorm:"type:datetime"`
+	TenantId   int       `gorm:"type:int"`
+}
+
 type TblMemberProfiles struct {
 	Id              int               `gorm:"primaryKey;auto_increment"`
 	MemberId        int               `gorm:"type:int"`
@@ -456,7 +520,7 @@ type TblMemberNotesHighlights struct {
 	Id                      int               `gorm:"primaryKey;auto_increment"`
 	MemberId                int               `gorm:"type:int"`
 	PageId                  int               `gorm:"type:int"`
-	NotesHighlightsContent  string            `gorm:"type:varchar(255)"`
+	NotesHighlightsContent  string            `gorm:"type:text"`
 	NotesHighlightsType     string            `gorm:"type:varchar(255)"`
 	HighlightsConfiguration datatypes.JSONMap `gorm:"type:jsonb"`
 	CreatedBy               int               `gorm:"type:int"`
@@ -499,6 +563,8 @@ type TblGeneralSettings struct {
 	ModifiedOn     time.Time `gorm:"type:datetime;DEFAULT:NULL"`
 	TenantId       int       `gorm:"type:int;"`
 	StorageType    string    `gorm:"type:varchar(255)"`
+	ImagePresets   string    `gorm:"type:text"`
+	RecycleBinDays int       `gorm:"type:int;DEFAULT:0"`
 }
 
 type TblMemberSettings struct {
@@ -508,25 +574,95 @@ type TblMemberSettings struct {
 	ModifiedBy        int       `gorm:"type:integer"`
 	ModifiedOn        time.Time `gorm:"type:datetime;DEFAULT:NULL"`
 	NotificationUsers string    `gorm:"type:varchar(255)"`
+	DefaultGroupId    int       `gorm:"type:int;DEFAULT:0"`
+	EmailVerification int       `gorm:"type:int;DEFAULT:0"`
+	VerificationUrl   string    `gorm:"type:varchar(255)"`
 	TenantId          int       `gorm:"type:int;"`
 }
 
+type TblMemberVerifications struct {
+	Id         int       `gorm:"primaryKey;auto_increment"`
+	MemberId   int       `gorm:"type:int"`
+	TokenHash  string    `gorm:"type:varchar(64);index"`
+	ExpiresOn  time.Time `gorm:"type:datetime"`
+	VerifiedOn time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+	CreatedOn  time.Time `gorm:"type:datetime"`
+	TenantId   int       `gorm:"type:int"`
+}
+
+type TblMemberProfileClaims struct {
+	Id        int       `gorm:"primaryKey;auto_increment"`
+	ProfileId int       `gorm:"type:int"`
+	MemberId  int       `gorm:"type:int"`
+	TokenHash string    `gorm:"type:varchar(64);index"`
+	ExpiresOn time.Time `gorm:"type:datetime"`
+	ClaimedOn time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+	CreatedOn time.Time `gorm:"type:datetime"`
+	TenantId  int       `gorm:"type:int"`
+}
+
 type TblGraphqlSettings struct {
-	Id          int       `gorm:"primaryKey;auto_increment;type:serial"`
-	TokenName   string    `gorm:"type:varchar(255)"`
-	Description string    `gorm:"type:LONGTEXT"`
-	Duration    string    `gorm:"type:varchar(255)"`
-	CreatedBy   int       `gorm:"type:int"`
-	CreatedOn   time.Time `gorm:"type:datetime;DEFAULT:NULL"`
-	ModifiedBy  int       `gorm:"type:integer;DEFAULT:NULL"`
-	ModifiedOn  time.Time `gorm:"type:datetime;DEFAULT:NULL"`
-	DeletedBy   int       `gorm:"type:integer;DEFAULT:NULL"`
-	DeletedOn   time.Time `gorm:"type:datetime;DEFAULT:NULL"`
-	IsDeleted   int       `gorm:"type:int;DEFAULT:0"`
-	Token       string    `gorm:"type:varchar(255)"`
-	IsDefault   int       `gorm:"type:int;DEFAULT:0"`
-	ExpiryTime  time.Time `gorm:"type:datetime;DEFAULT:NULL"`
-	TenantId    int       `gorm:"type:int;"`
+	Id           int       `gorm:"primaryKey;auto_increment;type:serial"`
+	TokenName    string    `gorm:"type:varchar(255)"`
+	Description  string    `gorm:"type:LONGTEXT"`
+	Duration     string    `gorm:"type:varchar(255)"`
+	CreatedBy    int       `gorm:"type:int"`
+	CreatedOn    time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+	ModifiedBy   int       `gorm:"type:integer;DEFAULT:NULL"`
+	ModifiedOn   time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+	DeletedBy    int       `gorm:"type:integer;DEFAULT:NULL"`
+	DeletedOn    time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+	IsDeleted    int       `gorm:"type:int;DEFAULT:0"`
+	Token        string    `gorm:"type:varchar(255)"`
+	IsDefault    int       `gorm:"type:int;DEFAULT:0"`
+	ExpiryTime   time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+	TenantId     int       `gorm:"type:int;"`
+	ChannelIds   string    `gorm:"type:varchar(255);DEFAULT:''"`
+	WriteAccess  int       `gorm:"type:int;DEFAULT:0"`
+	MemberAccess int       `gorm:"type:int;DEFAULT:1"`
+	RateLimit    int       `gorm:"type:int;DEFAULT:0"`
+	RateBurst    int       `gorm:"type:int;DEFAULT:0"`
+	LastUsedOn   time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+}
+
+type TblGraphqlUsages struct {
+	Id           int       `gorm:"primaryKey;auto_increment"`
+	TokenId      int       `gorm:"type:int"`
+	UsageDate    time.Time `gorm:"type:date"`
+	RequestCount int       `gorm:"type:int;DEFAULT:0"`
+	ErrorCount   int       `gorm:"type:int;DEFAULT:0"`
+	TenantId     int       `gorm:"type:int;"`
+}
+
+type TblGraphqlEntryViews struct {
+	Id        int       `gorm:"primaryKey;auto_increment"`
+	EntryId   int       `gorm:"type:int;uniqueIndex:idx_graphql_entry_views_entry_day"`
+	ChannelId int       `gorm:"type:int"`
+	ViewDate  time.Time `gorm:"type:date;uniqueIndex:idx_graphql_entry_views_entry_day;index"`
+	ViewCount int       `gorm:"type:int;DEFAULT:0"`
+	TenantId  int       `gorm:"type:int;index"`
+}
+
+type TblGraphqlMemberSessions struct {
+	Id        int       `gorm:"primaryKey;auto_increment"`
+	MemberId  int       `gorm:"type:int"`
+	TokenHash string    `gorm:"type:varchar(64);index"`
+	ExpiresOn time.Time `gorm:"type:datetime"`
+	RevokedOn time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+	CreatedOn time.Time `gorm:"type:datetime"`
+	TenantId  int       `gorm:"type:int"`
+}
+
+type TblGraphqlSearchEntries struct {
+	Id            int       `gorm:"primaryKey;auto_increment"`
+	EntryId       int       `gorm:"type:int;uniqueIndex"`
+	ChannelId     int       `gorm:"type:int"`
+	Title         string    `gorm:"type:text;index:idx_search_text,class:FULLTEXT"`
+	Tags          string    `gorm:"type:text;index:idx_search_text,class:FULLTEXT"`
+	Content       string    `gorm:"type:longtext;index:idx_search_text,class:FULLTEXT"`
+	CategoriesId  string    `gorm:"type:varchar(255)"`
+	PublishedTime time.Time `gorm:"type:datetime;DEFAULT:NULL"`
+	TenantId      int       `gorm:"type:int;index"`
 }
 
 type TblTimezones struct {
@@ -605,6 +741,10 @@ func MigrationTables() {
 		TblChannelCategories{},
 		TblChannelEntries{},
 		TblChannelEntryFields{},
+		TblChannelEntryLocks{},
+		TblChannelEntryRevisions{},
+		TblChannelEntrySchedules{},
+		TblChannelEntryWorkflowLogs{},
 		TblChannels{},
 		TblEmailTemplates{},
 		TblFieldGroups{},
@@ -618,6 +758,7 @@ func MigrationTables() {
 		TblMembers{},
 		TblModulePermissions{},
 		TblModules{},
+		TblRecycleBinLinks{},
 		TblRoles{},
 		TblRolePermissions{},
 		TblRoleUsers{},
@@ -628,7 +769,13 @@ func MigrationTables() {
 		TblEmailConfigurations{},
 		TblGeneralSettings{},
 		TblMemberSettings{},
+		TblMemberVerifications{},
+		TblMemberProfileClaims{},
 		TblGraphqlSettings{},
+		TblGraphqlUsages{},
+		TblGraphqlEntryViews{},
+		TblGraphqlMemberSessions{},
+		TblGraphqlSearchEntries{},
 		TblTimezones{},
 		TblPageTypes{},
 		TblTemplates{},
@@ -82,6 +82,10 @@ type TblChannels struct {
 	ModifiedBy         int       `gorm:"DEFAULT:NULL"`
 	ChannelType        string    `gorm:"type:character varying"`
 	TenantId           int       `gorm:"type:integer"`
+	RevisionLimit      int       `gorm:"type:integer;DEFAULT:NULL"`
+	Workflow           string    `gorm:"type:character varying;DEFAULT:''"`
+	DeletedOn          time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+	DeletedBy          int       `gorm:"DEFAULT:NULL"`
 }
 
 type TblMemberGroups struct {
@@ -405,6 +409,7 @@ type TblChannelEntries struct {
 	ParentId        int       `gorm:"type:integer"`
 	OrderIndex      int       `gorm:"type:integer"`
 	MembergroupId   string    `gorm:"type:character varying"`
+	Version         int       `gorm:"type:integer;DEFAULT:1"`
 }
 
 type TblChannelEntryFields struct {
@@ -422,6 +427,65 @@ type TblChannelEntryFields struct {
 	TenantId       int       `gorm:"type:integer"`
 }
 
+type TblChannelEntryLocks struct {
+	Id          int       `gorm:"primaryKey;auto_increment;type:serial"`
+	EntryId     int       `gorm:"type:integer;uniqueIndex"`
+	UserId      int       `gorm:"type:integer"`
+	LockedOn    time.Time `gorm:"type:timestamp without time zone"`
+	HeartbeatOn time.Time `gorm:"type:timestamp without time zone"`
+	TenantId    int       `gorm:"type:integer"`
+}
+
+type TblChannelEntryRevisions struct {
+	Id        int       `gorm:"primaryKey;auto_increment;type:serial"`
+	EntryId   int       `gorm:"type:integer;index"`
+	ChannelId int       `gorm:"type:integer"`
+	Title     string    `gorm:"type:character varying"`
+	Snapshot  string    `gorm:"type:text"`
+	CreatedBy int       `gorm:"type:integer"`
+	CreatedOn time.Time `gorm:"type:timestamp without time zone"`
+	TenantId  int       `gorm:"type:integer"`
+}
+
+type TblChannelEntrySchedules struct {
+	Id        int       `gorm:"primaryKey;auto_increment;type:serial"`
+	EntryId   int       `gorm:"type:integer;index"`
+	Action    string    `gorm:"type:character varying"`
+	RunAt     time.Time `gorm:"type:timestamp without time zone;index"`
+	Status    string    `gorm:"type:character varying;index"`
+	ClaimedBy string    `gorm:"type:character varying"`
+	ClaimedOn time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+	RunOn     time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+	Message   string    `gorm:"type:character varying"`
+	CreatedBy int       `gorm:"type:integer"`
+	CreatedOn time.Time `gorm:"type:timestamp without time zone"`
+	TenantId  int       `gorm:"type:integer"`
+}
+
+type TblChannelEntryWorkflowLogs struct {
+	Id         int       `gorm:"primaryKey;auto_increment;type:serial"`
+	EntryId    int       `gorm:"type:integer;index"`
+	ChannelId  int       `gorm:"type:integer"`
+	Action     string    `gorm:"type:character varying"`
+	FromStatus int       `gorm:"type:integer"`
+	ToStatus   int       `gorm:"type:integer"`
+	Comment    string    `gorm:"type:text"`
+	CreatedBy  int       `gorm:"type:integer"`
+	CreatedOn  time.Time `gorm:"type:timestamp without time zone"`
+	TenantId   int       `gorm:"type:integer"`
+}
+
+type TblRecycleBinLinks struct {
+	Id         int       `gorm:"primaryKey;auto_increment;type:serial"`
+	Module     string    `gorm:"type:character varying"`
+	RecordId   int       `gorm:"type:integer;index"`
+	LinkType   string    `gorm:"type:character varying"`
+	LinkId     int       `gorm:"type:integer"`
+	CategoryId string    `gorm:"type:text"`
+	DeletedOn  time.Time `gorm:"type:timestamp without time zone"`
+	TenantId   int       `gorm:"type:integer"`
+}
+
 type TblMemberProfiles struct {
 	Id              int               `gorm:"primaryKey;auto_increment;type:serial"`
 	MemberId        int               `gorm:"type:integer"`
@@ -499,6 +563,8 @@ type TblGeneralSettings struct {
 	ModifiedOn     time.Time `gorm:"type:timestamp with time zone;DEFAULT:NULL"`
 	TenantId       int       `gorm:"type:integer"`
 	StorageType    string    `gorm:"type:character varying"`
+	ImagePresets   string    `gorm:"type:text"`
+	RecycleBinDays int       `gorm:"type:integer;DEFAULT:0"`
 }
 type TblMemberSettings struct {
 	Id                int       `gorm:"primaryKey;auto_increment;type:serial"`
@@ -507,25 +573,96 @@ type TblMemberSettings struct {
 	ModifiedBy        int       `gorm:"type:integer"`
 	ModifiedOn        time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
 	NotificationUsers string    `gorm:"type:character varying"`
+	DefaultGroupId    int       `gorm:"type:integer;DEFAULT:0"`
+	EmailVerification int       `gorm:"type:integer;DEFAULT:0"`
+	VerificationUrl   string    `gorm:"type:character varying"`
 	TenantId          int       `gorm:"type:integer"`
 }
 
+type TblMemberVerifications struct {
+	Id         int       `gorm:"primaryKey;auto_increment;type:serial"`
+	MemberId   int       `gorm:"type:integer"`
+	TokenHash  string    `gorm:"type:character varying;index"`
+	ExpiresOn  time.Time `gorm:"type:timestamp without time zone"`
+	VerifiedOn time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+	CreatedOn  time.Time `gorm:"type:timestamp without time zone"`
+	TenantId   int       `gorm:"type:integer"`
+}
+
+type TblMemberProfileClaims struct {
+	Id        int       `gorm:"primaryKey;auto_increment;type:serial"`
+	ProfileId int       `gorm:"type:integer"`
+	MemberId  int       `gorm:"type:integer"`
+	TokenHash string    `gorm:"type:character varying;index"`
+	ExpiresOn time.Time `gorm:"type:timestamp without time zone"`
+	ClaimedOn time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+	CreatedOn time.Time `gorm:"type:timestamp without time zone"`
+	TenantId  int       `gorm:"type:integer"`
+}
+
 type TblGraphqlSettings struct {
-	Id          int       `gorm:"primaryKey;auto_increment;type:serial"`
-	TokenName   string    `gorm:"type:character varying"`
-	Description string    `gorm:"type:character varying"`
-	Duration    string    `gorm:"type:character varying"`
-	CreatedBy   int       `gorm:"type:integer"`
-	CreatedOn   time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
-	ModifiedBy  int       `gorm:"type:integer;DEFAULT:NULL"`
-	ModifiedOn  time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
-	DeletedBy   int       `gorm:"type:integer;DEFAULT:NULL"`
-	DeletedOn   time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
-	IsDeleted   int       `gorm:"type:integer;DEFAULT:0"`
-	Token       string    `gorm:"type:character varying"`
-	IsDefault   int       `gorm:"type:integer;DEFAULT:0"`
-	ExpiryTime  time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
-	TenantId    int       `gorm:"type:integer"`
+	Id           int       `gorm:"primaryKey;auto_increment;type:serial"`
+	TokenName    string    `gorm:"type:character varying"`
+	Description  string    `gorm:"type:character varying"`
+	Duration     string    `gorm:"type:character varying"`
+	CreatedBy    int       `gorm:"type:integer"`
+	CreatedOn    time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+	ModifiedBy   int       `gorm:"type:integer;DEFAULT:NULL"`
+	ModifiedOn   time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+	DeletedBy    int       `gorm:"type:integer;DEFAULT:NULL"`
+	DeletedOn    time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+	IsDeleted    int       `gorm:"type:integer;DEFAULT:0"`
+	Token        string    `gorm:"type:character varying"`
+	IsDefault    int       `gorm:"type:integer;DEFAULT:0"`
+	ExpiryTime   time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+	TenantId     int       `gorm:"type:integer"`
+	ChannelIds   string    `gorm:"type:character varying;DEFAULT:''"`
+	WriteAccess  int       `gorm:"type:integer;DEFAULT:0"`
+	MemberAccess int       `gorm:"type:integer;DEFAULT:1"`
+	RateLimit    int       `gorm:"type:integer;DEFAULT:0"`
+	RateBurst    int       `gorm:"type:integer;DEFAULT:0"`
+	LastUsedOn   time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+}
+
+type TblGraphqlUsages struct {
+	Id           int       `gorm:"primaryKey;auto_increment;type:serial"`
+	TokenId      int       `gorm:"type:integer"`
+	UsageDate    time.Time `gorm:"type:date"`
+	RequestCount int       `gorm:"type:integer;DEFAULT:0"`
+	ErrorCount   int       `gorm:"type:integer;DEFAULT:0"`
+	TenantId     int       `gorm:"type:integer"`
+}
+
+type TblGraphqlEntryViews struct {
+	Id        int       `gorm:"primaryKey;auto_increment;type:serial"`
+	EntryId   int       `gorm:"type:integer;uniqueIndex:idx_graphql_entry_views_entry_day"`
+	ChannelId int       `gorm:"type:integer"`
+	ViewDate  time.Time `gorm:"type:date;uniqueIndex:idx_graphql_entry_views_entry_day;index"`
+	ViewCount int       `gorm:"type:integer;DEFAULT:0"`
+	TenantId  int       `gorm:"type:integer;index"`
+}
+
+type TblGraphqlMemberSessions struct {
+	Id        int       `gorm:"primaryKey;auto_increment;type:serial"`
+	MemberId  int       `gorm:"type:integer"`
+	TokenHash string    `gorm:"type:character varying;index"`
+	ExpiresOn time.Time `gorm:"type:timestamp without time zone"`
+	RevokedOn time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+	CreatedOn time.Time `gorm:"type:timestamp without time zone"`
+	TenantId  int       `gorm:"type:integer"`
+}
+
+type TblGraphqlSearchEntries struct {
+	Id            int       `gorm:"primaryKey;auto_increment;type:serial"`
+	EntryId       int       `gorm:"type:integer;uniqueIndex"`
+	ChannelId     int       `gorm:"type:integer"`
+	Title         string    `gorm:"type:character varying"`
+	Tags          string    `gorm:"type:character varying"`
+	Content       string    `gorm:"type:text"`
+	CategoriesId  string    `gorm:"type:character varying"`
+	SearchVector  string    `gorm:"type:tsvector;index:,type:gin"`
+	PublishedTime time.Time `gorm:"type:timestamp without time zone;DEFAULT:NULL"`
+	TenantId      int       `gorm:"type:integer;index"`
 }
 
 type TblTimezones struct {
@@ -603,6 +740,10 @@ func MigrationTables() {
 		TblChannelCategories{},
 		TblChannelEntries{},
 		TblChannelEntryFields{},
+		TblChannelEntryLocks{},
+		TblChannelEntryRevisions{},
+		TblChannelEntrySchedules{},
+		TblChannelEntryWorkflowLogs{},
 		TblChannels{},
 		TblEmailTemplates{},
 		TblFieldGroups{},
@@ -616,6 +757,7 @@ func MigrationTables() {
 		TblMembers{},
 		TblModulePermissions{},
 		TblModules{},
+		TblRecycleBinLinks{},
 		TblRoles{},
 		TblRolePermissions{},
 		TblRoleUsers{},
@@ -626,7 +768,13 @@ func MigrationTables() {
 		TblEmailConfigurations{},
 		TblGeneralSettings{},
 		TblMemberSettings{},
+		TblMemberVerifications{},
+		TblMemberProfileClaims{},
 		TblGraphqlSettings{},
+		TblGraphqlUsages{},
+		TblGraphqlEntryViews{},
+		TblGraphqlMemberSessions{},
+		TblGraphqlSearchEntries{},
 		TblTimezones{},
 		TblPageTypes{},
 		TblTemplates{},
@@ -132,3 +132,60 @@ func Entryid(entry string, tenantid int) (Id int, err error) {
 
 	return id, nil
 }
+
+type RelatedEntry struct {
+	Id          int    `json:"id"`
+	Title       string `json:"title"`
+	ChannelName string `json:"channelName"`
+	Status      int    `json:"status"`
+}
+
+// search entries of every channel for the related articles picker
+func SearchRelatedEntries(keyword string, excludeid int, limit int, tenantid int) (entries []RelatedEntry, err error) {
+
+	query := DB.Table("tbl_channel_entries as en").Select("en.id, en.title, tc.channel_name, en.status").Joins("inner join tbl_channels as tc on tc.id = en.channel_id").Where("en.is_deleted = 0 and tc.is_deleted = 0 and en.id <> ? and en.tenant_id = ?", excludeid, tenantid)
+
+	if keyword != "" {
+		query = query.Where("LOWER(TRIM(en.title)) LIKE LOWER(TRIM(?))", "%"+keyword+"%")
+	}
+
+	if err := query.Order("en.id desc").Limit(limit).Find(&entries).Error; err != nil {
+		return []RelatedEntry{}, err
+	}
+
+	return entries, nil
+}
+
+func GetRelatedEntriesByIds(ids []int, tenantid int) (entries []RelatedEntry, err error) {
+
+	if err := DB.Table("tbl_channel_entries as en").Select("en.id, en.title, tc.channel_name, en.status").Joins("inner join tbl_channels as tc on tc.id = en.channel_id").Where("en.is_deleted = 0 and tc.is_deleted = 0 and en.id in (?) and en.tenant_id = ?", ids, tenantid).Find(&entries).Error; err != nil {
+		return []RelatedEntry{}, err
+	}
+
+	return entries, nil
+}
+
+func UpdateRelatedArticles(entryid int, relatedarticles string, tenantid int) error {
+
+	if err := DB.Table("tbl_channel_entries").Where("id = ? and tenant_id = ?", entryid, tenantid).UpdateColumn("related_articles", relatedarticles).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type PreviewEntry struct {
+	Id     int
+	Slug   string
+	Status int
+}
+
+// GetPreviewEntry returns the entry a preview token is issued for
+func GetPreviewEntry(entryid int, tenantid int) (entry PreviewEntry, err error) {
+
+	if err := DB.Table("tbl_channel_entries").Select("id, slug, status").Where("id = ? and tenant_id = ? and is_deleted = 0", entryid, tenantid).First(&entry).Error; err != nil {
+		return PreviewEntry{}, err
+	}
+
+	return entry, nil
+}
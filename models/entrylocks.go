@@ -0,0 +1,94 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TblChannelEntryLock struct {
+	Id          int
+	EntryId     int
+	UserId      int
+	LockedOn    time.Time
+	HeartbeatOn time.Time
+	TenantId    int
+	Username    string `gorm:"<-:false"`
+}
+
+// EntryVersion is the saved version of an entry with who saved it last
+type EntryVersion struct {
+	Version    int
+	ModifiedBy int
+	ModifiedOn time.Time
+	Username   string
+}
+
+// GetEntryVersion reads the version of an entry and the user of its last save
+func GetEntryVersion(entryid int, tenantid int) (version EntryVersion, err error) {
+
+	if err := DB.Table("tbl_channel_entries as en").Select("coalesce(en.version, 1) as version, coalesce(nullif(en.modified_by, 0), en.created_by) as modified_by, coalesce(en.modified_on, en.created_on) as modified_on, tu.username").Joins("left join tbl_users as tu on tu.id = coalesce(nullif(en.modified_by, 0), en.created_by)").Where("en.id = ? and en.tenant_id = ? and en.is_deleted = 0", entryid, tenantid).Take(&version).Error; err != nil {
+
+		return EntryVersion{}, err
+	}
+
+	return version, nil
+}
+
+// ClaimEntryVersion moves an entry past the version a save started from. Only one of two saves started from the
+// same version gets it, the other one is stale. Pass the transaction of the save so a failed save keeps the version.
+func ClaimEntryVersion(db *gorm.DB, entryid int, version int, tenantid int) (bool, error) {
+
+	result := db.Table("tbl_channel_entries").Where("id = ? and tenant_id = ? and coalesce(version, 1) = ?", entryid, tenantid, version).UpdateColumn("version", gorm.Expr("coalesce(version, 1) + 1"))
+
+	if result.Error != nil {
+
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// BumpEntryVersion moves an entry to a new version after a save that did not start from one
+func BumpEntryVersion(entryid int, tenantid int) error {
+
+	return DB.Table("tbl_channel_entries").Where("id = ? and tenant_id = ?", entryid, tenantid).UpdateColumn("version", gorm.Expr("coalesce(version, 1) + 1")).Error
+}
+
+// GetEntryLock reads the edit lock of an entry with the name of its holder, an entry nobody locked has an empty one
+func GetEntryLock(entryid int, tenantid int) (lock TblChannelEntryLock, err error) {
+
+	err = DB.Table("tbl_channel_entry_locks as el").Select("el.*, tu.username").Joins("left join tbl_users as tu on tu.id = el.user_id").Where("el.entry_id = ? and el.tenant_id = ?", entryid, tenantid).Take(&lock).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+
+		return TblChannelEntryLock{}, nil
+	}
+
+	if err != nil {
+
+		return TblChannelEntryLock{}, err
+	}
+
+	return lock, nil
+}
+
+// SaveEntryLock replaces the lock read as current. The row only changes while it is still the one that was read,
+// so of two editors taking a free lock at once one keeps it; read the lock again to see who holds it.
+func SaveEntryLock(current TblChannelEntryLock, lock TblChannelEntryLock) error {
+
+	if current.Id == 0 {
+
+		// the unique entry id turns the second of two inserts into an error
+		return DB.Table("tbl_channel_entry_locks").Create(&lock).Error
+	}
+
+	return DB.Table("tbl_channel_entry_locks").Where("id = ? and user_id = ? and heartbeat_on = ?", current.Id, current.UserId, current.HeartbeatOn).UpdateColumns(map[string]interface{}{"user_id": lock.UserId, "locked_on": lock.LockedOn, "heartbeat_on": lock.HeartbeatOn}).Error
+}
+
+// ReleaseEntryLock drops the lock of an entry when the user closing the editor still holds it
+func ReleaseEntryLock(entryid int, userid int, tenantid int) error {
+
+	return DB.Table("tbl_channel_entry_locks").Where("entry_id = ? and user_id = ? and tenant_id = ?", entryid, userid, tenantid).Delete(&TblChannelEntryLock{}).Error
+}
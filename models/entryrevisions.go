@@ -0,0 +1,331 @@
+package models
+
+import (
+	"encoding/json"
+	"spurt-cms/revisions"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TblChannelEntryRevision struct {
+	Id          int
+	EntryId     int
+	ChannelId   int
+	Title       string
+	Snapshot    string
+	CreatedBy   int
+	CreatedOn   time.Time
+	TenantId    int
+	Username    string `gorm:"<-:false"`
+	CreatedDate string `gorm:"-"`
+}
+
+type entrySnapshotRow struct {
+	ChannelId       int
+	Title           string
+	Description     string
+	CoverImage      string
+	Excerpt         string
+	Author          string
+	Tags            string
+	ReadingTime     int
+	CategoriesId    string
+	MetaTitle       string
+	MetaDescription string
+	Keyword         string
+	ImageAltTag     string
+}
+
+// GetEntrySnapshot reads the saved state of an entry with its additional field values and category names. The
+// additional fields are not always saved with the tenant, the entry they belong to is.
+func GetEntrySnapshot(entryid int, tenantid int) (snapshot revisions.Snapshot, channelid int, err error) {
+
+	return entrySnapshot(DB, entryid, tenantid)
+}
+
+func entrySnapshot(db *gorm.DB, entryid int, tenantid int) (snapshot revisions.Snapshot, channelid int, err error) {
+
+	var entry entrySnapshotRow
+
+	if err := db.Table("tbl_channel_entries").Select("channel_id, title, description, cover_image, excerpt, author, tags, reading_time, categories_id, meta_title, meta_description, keyword, image_alt_tag").Where("id = ? and tenant_id = ? and is_deleted = 0", entryid, tenantid).First(&entry).Error; err != nil {
+
+		return revisions.Snapshot{}, 0, err
+	}
+
+	snapshot = revisions.Snapshot{
+		Title:           entry.Title,
+		Description:     entry.Description,
+		CoverImage:      entry.CoverImage,
+		Excerpt:         entry.Excerpt,
+		Author:          entry.Author,
+		Tags:            entry.Tags,
+		ReadingTime:     entry.ReadingTime,
+		CategoryIds:     entry.CategoriesId,
+		MetaTitle:       entry.MetaTitle,
+		MetaDescription: entry.MetaDescription,
+		Keyword:         entry.Keyword,
+		ImageAltTag:     entry.ImageAltTag,
+		Fields:          []revisions.Field{},
+	}
+
+	if err := db.Table("tbl_channel_entry_fields").Select("field_id, field_name as name, field_value as value").Where("channel_entry_id = ?", entryid).Order("id").Find(&snapshot.Fields).Error; err != nil {
+
+		return revisions.Snapshot{}, 0, err
+	}
+
+	if snapshot.Categories, err = categoryNames(db, entry.CategoriesId, tenantid); err != nil {
+
+		return revisions.Snapshot{}, 0, err
+	}
+
+	return snapshot, entry.ChannelId, nil
+}
+
+// GetCategoryNames lists the names of the categories of an entry in the order of their ids
+func GetCategoryNames(categoryids string, tenantid int) (string, error) {
+
+	return categoryNames(DB, categoryids, tenantid)
+}
+
+func categoryNames(db *gorm.DB, categoryids string, tenantid int) (string, error) {
+
+	var ids []int
+
+	for _, part := range strings.Split(categoryids, ",") {
+
+		if id, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && !containsInt(ids, id) {
+
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+
+		return "", nil
+	}
+
+	var categories []TblCategory
+
+	if err := db.Table("tbl_categories").Select("id, category_name").Where("id in (?) and (tenant_id is NULL or tenant_id = ?)", ids, tenantid).Find(&categories).Error; err != nil {
+
+		return "", err
+	}
+
+	namesbyid := make(map[int]string, len(categories))
+
+	for _, category := range categories {
+
+		namesbyid[category.Id] = category.CategoryName
+	}
+
+	var names []string
+
+	for _, id := range ids {
+
+		if name, ok := namesbyid[id]; ok {
+
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, ", "), nil
+}
+
+func containsInt(ids []int, id int) bool {
+
+	for _, existing := range ids {
+
+		if existing == id {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// SaveEntryRevision adds the saved state of an entry to its history and drops the revisions past its channel's limit.
+// Every save of an entry goes through it, pass the transaction of the save so the revision is kept or dropped with it.
+func SaveEntryRevision(db *gorm.DB, entryid int, userid int, tenantid int) error {
+
+	snapshot, channelid, err := entrySnapshot(db, entryid, tenantid)
+
+	if err != nil {
+
+		return err
+	}
+
+	data, err := json.Marshal(snapshot)
+
+	if err != nil {
+
+		return err
+	}
+
+	revision := TblChannelEntryRevision{
+		EntryId:   entryid,
+		ChannelId: channelid,
+		Title:     snapshot.Title,
+		Snapshot:  string(data),
+		CreatedBy: userid,
+		CreatedOn: time.Now().UTC(),
+		TenantId:  tenantid,
+	}
+
+	if err := db.Table("tbl_channel_entry_revisions").Create(&revision).Error; err != nil {
+
+		return err
+	}
+
+	limit, err := channelRevisionLimit(db, channelid, tenantid)
+
+	if err != nil {
+
+		return err
+	}
+
+	var expired []int
+
+	if err := db.Table("tbl_channel_entry_revisions").Where("entry_id = ? and tenant_id = ?", entryid, tenantid).Order("id desc").Offset(revisions.Limit(limit)).Limit(-1).Pluck("id", &expired).Error; err != nil {
+
+		return err
+	}
+
+	if len(expired) == 0 {
+
+		return nil
+	}
+
+	return db.Table("tbl_channel_entry_revisions").Where("id in (?) and tenant_id = ?", expired, tenantid).Delete(&TblChannelEntryRevision{}).Error
+}
+
+// GetEntryRevisions lists the revisions of an entry with their authors, newest first
+func GetEntryRevisions(entryid int, tenantid int) (entryrevisions []TblChannelEntryRevision, err error) {
+
+	if err := DB.Table("tbl_channel_entry_revisions as rv").Select("rv.id, rv.entry_id, rv.channel_id, rv.title, rv.created_by, rv.created_on, rv.tenant_id, tu.username").Joins("left join tbl_users as tu on tu.id = rv.created_by").Where("rv.entry_id = ? and rv.tenant_id = ?", entryid, tenantid).Order("rv.id desc").Find(&entryrevisions).Error; err != nil {
+
+		return []TblChannelEntryRevision{}, err
+	}
+
+	return entryrevisions, nil
+}
+
+// GetEntryRevisionSnapshot reads the snapshot stored in a revision of an entry
+func GetEntryRevisionSnapshot(revisionid, entryid int, tenantid int) (snapshot revisions.Snapshot, err error) {
+
+	var revision TblChannelEntryRevision
+
+	if err := DB.Table("tbl_channel_entry_revisions").Where("id = ? and entry_id = ? and tenant_id = ?", revisionid, entryid, tenantid).First(&revision).Error; err != nil {
+
+		return revisions.Snapshot{}, err
+	}
+
+	if err := json.Unmarshal([]byte(revision.Snapshot), &snapshot); err != nil {
+
+		return revisions.Snapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+// RestoreEntrySnapshot writes a snapshot back to an entry and returns its channel. The slug, the status and the
+// publishing details stay as they are, additional fields the snapshot has no value for are cleared.
+func RestoreEntrySnapshot(entryid int, snapshot revisions.Snapshot, userid int, tenantid int) (channelid int, err error) {
+
+	now := time.Now().UTC()
+
+	err = DB.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Table("tbl_channel_entries").Select("channel_id").Where("id = ? and tenant_id = ? and is_deleted = 0", entryid, tenantid).Take(&channelid).Error; err != nil {
+
+			return err
+		}
+
+		if err := tx.Table("tbl_channel_entries").Where("id = ? and tenant_id = ?", entryid, tenantid).UpdateColumns(map[string]interface{}{
+			"title":            snapshot.Title,
+			"description":      snapshot.Description,
+			"cover_image":      snapshot.CoverImage,
+			"excerpt":          snapshot.Excerpt,
+			"author":           snapshot.Author,
+			"tags":             snapshot.Tags,
+			"reading_time":     snapshot.ReadingTime,
+			"categories_id":    snapshot.CategoryIds,
+			"meta_title":       snapshot.MetaTitle,
+			"meta_description": snapshot.MetaDescription,
+			"keyword":          snapshot.Keyword,
+			"image_alt_tag":    snapshot.ImageAltTag,
+			"modified_by":      userid,
+			"modified_on":      now,
+		}).Error; err != nil {
+
+			return err
+		}
+
+		fieldids := []int{0}
+
+		for _, field := range snapshot.Fields {
+
+			fieldids = append(fieldids, field.FieldId)
+
+			var existing int64
+
+			if err := tx.Table("tbl_channel_entry_fields").Where("channel_entry_id = ? and field_id = ?", entryid, field.FieldId).Count(&existing).Error; err != nil {
+
+				return err
+			}
+
+			if existing > 0 {
+
+				if err := tx.Table("tbl_channel_entry_fields").Where("channel_entry_id = ? and field_id = ?", entryid, field.FieldId).UpdateColumns(map[string]interface{}{"field_name": field.Name, "field_value": field.Value, "modified_by": userid, "modified_on": now}).Error; err != nil {
+
+					return err
+				}
+
+				continue
+			}
+
+			if err := tx.Table("tbl_channel_entry_fields").Create(map[string]interface{}{"field_name": field.Name, "field_value": field.Value, "channel_entry_id": entryid, "field_id": field.FieldId, "created_on": now, "created_by": userid, "tenant_id": tenantid}).Error; err != nil {
+
+				return err
+			}
+		}
+
+		return tx.Table("tbl_channel_entry_fields").Where("channel_entry_id = ? and field_id not in (?)", entryid, fieldids).UpdateColumns(map[string]interface{}{"field_value": "", "modified_by": userid, "modified_on": now}).Error
+	})
+
+	if err != nil {
+
+		return 0, err
+	}
+
+	return channelid, nil
+}
+
+// GetChannelRevisionLimit returns the revisions a channel keeps per entry, 0 when it has not configured a limit
+func GetChannelRevisionLimit(channelid int, tenantid int) (limit int, err error) {
+
+	return channelRevisionLimit(DB, channelid, tenantid)
+}
+
+func channelRevisionLimit(db *gorm.DB, channelid int, tenantid int) (limit int, err error) {
+
+	if err := db.Table("tbl_channels").Select("coalesce(revision_limit, 0)").Where("id = ? and tenant_id = ?", channelid, tenantid).Scan(&limit).Error; err != nil {
+
+		return 0, err
+	}
+
+	return limit, nil
+}
+
+func UpdateChannelRevisionLimit(channelid int, limit int, tenantid int) error {
+
+	if err := DB.Table("tbl_channels").Where("id = ? and tenant_id = ?", channelid, tenantid).UpdateColumn("revision_limit", limit).Error; err != nil {
+
+		return err
+	}
+
+	return nil
+}
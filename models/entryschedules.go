@@ -0,0 +1,191 @@
+package models
+
+import (
+	"errors"
+	"spurt-cms/schedule"
+	"spurt-cms/workflow"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TblChannelEntrySchedule struct {
+	Id        int
+	EntryId   int
+	Action    string
+	RunAt     time.Time
+	Status    string
+	ClaimedBy string
+	ClaimedOn *time.Time
+	RunOn     *time.Time
+	Message   string
+	CreatedBy int
+	CreatedOn time.Time
+	TenantId  int
+	RunDate   string `gorm:"-"`
+	RunOnDate string `gorm:"-"`
+}
+
+var ErrScheduledEntryNotFound = errors.New("the entry no longer exists")
+
+// EntryScheduleStore keeps the scheduled publishes and unpublishes of all tenants for the scheduler
+type EntryScheduleStore struct{}
+
+// Claim takes the due jobs for an instance. A job is only handed out when the conditional update of its status
+// goes through, so two instances claiming at once never both get it.
+func (EntryScheduleStore) Claim(instance string, now time.Time, staleBefore time.Time, limit int) ([]schedule.Job, error) {
+
+	var candidates []TblChannelEntrySchedule
+
+	if err := DB.Table("tbl_channel_entry_schedules").Select("id, entry_id, action, run_at, tenant_id").Where("run_at <= ? and (status = ? or (status = ? and claimed_on < ?))", now, schedule.StatusPending, schedule.StatusRunning, staleBefore).Order("run_at, id").Limit(limit).Find(&candidates).Error; err != nil {
+
+		return nil, err
+	}
+
+	var jobs []schedule.Job
+
+	for _, candidate := range candidates {
+
+		result := DB.Table("tbl_channel_entry_schedules").Where("id = ? and (status = ? or (status = ? and claimed_on < ?))", candidate.Id, schedule.StatusPending, schedule.StatusRunning, staleBefore).UpdateColumns(map[string]interface{}{"status": schedule.StatusRunning, "claimed_by": instance, "claimed_on": now})
+
+		if result.Error != nil {
+
+			return jobs, result.Error
+		}
+
+		if result.RowsAffected != 1 {
+
+			continue
+		}
+
+		jobs = append(jobs, schedule.Job{Id: candidate.Id, EntryId: candidate.EntryId, Action: candidate.Action, RunAt: candidate.RunAt, TenantId: candidate.TenantId})
+	}
+
+	return jobs, nil
+}
+
+// Apply sets the status of the job's entry. An entry already in that status is left alone, and on a channel with a
+// review workflow only an approved entry is published, a channel of 0 tells the scheduler nothing changed. The change
+// moves the entry to a new version, so an editor who had it open is told about it when they save.
+func (EntryScheduleStore) Apply(job schedule.Job, now time.Time) (channelId int, message string, err error) {
+
+	var entry struct {
+		ChannelId int
+		Status    int
+		Workflow  string
+	}
+
+	if err := DB.Table("tbl_channel_entries as en").Select("en.channel_id, en.status, coalesce(ch.workflow, '') as workflow").Joins("inner join tbl_channels as ch on ch.id = en.channel_id").Where("en.id = ? and en.tenant_id = ? and en.is_deleted = 0", job.EntryId, job.TenantId).Take(&entry).Error; err != nil {
+
+		return 0, "", ErrScheduledEntryNotFound
+	}
+
+	status := schedule.Status(job.Action)
+
+	if entry.Status == status {
+
+		return 0, "already " + job.Action + "ed", nil
+	}
+
+	if job.Action == schedule.ActionUnpublish && entry.Status != 1 {
+
+		return 0, "not published, left as it is", nil
+	}
+
+	if _, err := workflow.Find(entry.Workflow, entry.Status, job.Action); err != nil {
+
+		return 0, "waiting for review, " + workflow.Label(entry.Status) + " entries can not be " + job.Action + "ed", nil
+	}
+
+	var createdby int
+
+	if err := DB.Table("tbl_channel_entry_schedules").Select("created_by").Where("id = ?", job.Id).Scan(&createdby).Error; err != nil {
+
+		return 0, "", err
+	}
+
+	err = DB.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Table("tbl_channel_entries").Where("id = ? and tenant_id = ?", job.EntryId, job.TenantId).UpdateColumns(map[string]interface{}{"status": status, "modified_by": createdby, "modified_on": now, "version": gorm.Expr("coalesce(version, 1) + 1")}).Error; err != nil {
+
+			return err
+		}
+
+		return SaveEntryRevision(tx, job.EntryId, createdby, job.TenantId)
+	})
+
+	if err != nil {
+
+		return 0, "", err
+	}
+
+	return entry.ChannelId, job.Action + "ed", nil
+}
+
+// Finish records the outcome of a job, unless another instance took over the claim in the meantime
+func (EntryScheduleStore) Finish(job schedule.Job, instance string, now time.Time, message string, failed bool) error {
+
+	status := schedule.StatusDone
+
+	if failed {
+
+		status = schedule.StatusFailed
+	}
+
+	return DB.Table("tbl_channel_entry_schedules").Where("id = ? and claimed_by = ? and status = ?", job.Id, instance, schedule.StatusRunning).UpdateColumns(map[string]interface{}{"status": status, "run_on": now, "message": message}).Error
+}
+
+// ScheduleEntry replaces the pending job of an action on an entry, a zero time only cancels it
+func ScheduleEntry(entryid int, action string, runat time.Time, userid int, tenantid int) error {
+
+	var pending []TblChannelEntrySchedule
+
+	if err := DB.Table("tbl_channel_entry_schedules").Where("entry_id = ? and action = ? and status = ? and tenant_id = ?", entryid, action, schedule.StatusPending, tenantid).Find(&pending).Error; err != nil {
+
+		return err
+	}
+
+	// saving the entry again keeps an unchanged schedule as it is
+	if len(pending) == 1 && !runat.IsZero() && pending[0].RunAt.Equal(runat) {
+
+		return nil
+	}
+
+	now := time.Now().UTC()
+
+	if len(pending) > 0 {
+
+		if err := DB.Table("tbl_channel_entry_schedules").Where("entry_id = ? and action = ? and status = ? and tenant_id = ?", entryid, action, schedule.StatusPending, tenantid).UpdateColumns(map[string]interface{}{"status": schedule.StatusCancelled, "run_on": now, "message": "changed in the editor"}).Error; err != nil {
+
+			return err
+		}
+	}
+
+	if runat.IsZero() {
+
+		return nil
+	}
+
+	job := TblChannelEntrySchedule{
+		EntryId:   entryid,
+		Action:    action,
+		RunAt:     runat,
+		Status:    schedule.StatusPending,
+		CreatedBy: userid,
+		CreatedOn: now,
+		TenantId:  tenantid,
+	}
+
+	return DB.Table("tbl_channel_entry_schedules").Create(&job).Error
+}
+
+// GetEntrySchedules lists the scheduled jobs of an entry, newest first
+func GetEntrySchedules(entryid int, limit int, tenantid int) (schedules []TblChannelEntrySchedule, err error) {
+
+	if err := DB.Table("tbl_channel_entry_schedules").Where("entry_id = ? and tenant_id = ?", entryid, tenantid).Order("id desc").Limit(limit).Find(&schedules).Error; err != nil {
+
+		return []TblChannelEntrySchedule{}, err
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TblChannelEntryWorkflowLog struct {
+	Id          int
+	EntryId     int
+	ChannelId   int
+	Action      string
+	FromStatus  int
+	ToStatus    int
+	Comment     string
+	CreatedBy   int
+	CreatedOn   time.Time
+	TenantId    int
+	Username    string `gorm:"<-:false"`
+	CreatedDate string `gorm:"-"`
+	FromLabel   string `gorm:"-"`
+	ToLabel     string `gorm:"-"`
+}
+
+// EntryWorkflowState is an entry with the workflow of its channel
+type EntryWorkflowState struct {
+	EntryId     int
+	ChannelId   int
+	ChannelName string
+	Title       string
+	Status      int
+	CreatedBy   int
+	Workflow    string
+}
+
+// WorkflowReviewer is a user notified about the entries waiting on them
+type WorkflowReviewer struct {
+	Id        int
+	FirstName string
+	Email     string
+}
+
+var ErrEntryStatusChanged = errors.New("the entry was moved by someone else, reload it and try again")
+
+// GetEntryWorkflowState reads the status of an entry and the workflow of its channel
+func GetEntryWorkflowState(entryid int, tenantid int) (state EntryWorkflowState, err error) {
+
+	if err := DB.Table("tbl_channel_entries as en").Select("en.id as entry_id, en.channel_id, ch.channel_name, en.title, en.status, en.created_by, coalesce(ch.workflow, '') as workflow").Joins("inner join tbl_channels as ch on ch.id = en.channel_id").Where("en.id = ? and en.tenant_id = ? and en.is_deleted = 0", entryid, tenantid).Take(&state).Error; err != nil {
+
+		return EntryWorkflowState{}, err
+	}
+
+	return state, nil
+}
+
+// GetChannelWorkflow returns the workflow of a channel, empty when its entries are published directly
+func GetChannelWorkflow(channelid int, tenantid int) (workflow string, err error) {
+
+	if err := DB.Table("tbl_channels").Select("coalesce(workflow, '')").Where("id = ? and tenant_id = ?", channelid, tenantid).Scan(&workflow).Error; err != nil {
+
+		return "", err
+	}
+
+	return workflow, nil
+}
+
+func UpdateChannelWorkflow(channelid int, workflow string, tenantid int) error {
+
+	if err := DB.Table("tbl_channels").Where("id = ? and tenant_id = ?", channelid, tenantid).UpdateColumn("workflow", workflow).Error; err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+// TransitionEntry moves an entry from one status to another and logs it. The status only changes when the entry is
+// still in the one it was read in, so two reviewers acting at once do not both move it.
+func TransitionEntry(entryworkflowlog TblChannelEntryWorkflowLog) error {
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+
+		result := tx.Table("tbl_channel_entries").Where("id = ? and tenant_id = ? and status = ? and is_deleted = 0", entryworkflowlog.EntryId, entryworkflowlog.TenantId, entryworkflowlog.FromStatus).UpdateColumns(map[string]interface{}{"status": entryworkflowlog.ToStatus, "modified_by": entryworkflowlog.CreatedBy, "modified_on": entryworkflowlog.CreatedOn})
+
+		if result.Error != nil {
+
+			return result.Error
+		}
+
+		if result.RowsAffected != 1 {
+
+			return ErrEntryStatusChanged
+		}
+
+		return tx.Table("tbl_channel_entry_workflow_logs").Create(&entryworkflowlog).Error
+	})
+}
+
+// CreateEntryWorkflowLog records a status change made by an editor save
+func CreateEntryWorkflowLog(entryworkflowlog TblChannelEntryWorkflowLog) error {
+
+	return DB.Table("tbl_channel_entry_workflow_logs").Create(&entryworkflowlog).Error
+}
+
+// GetEntryWorkflowLogs lists the status changes of an entry with who made them, newest first
+func GetEntryWorkflowLogs(entryid int, tenantid int) (entryworkflowlogs []TblChannelEntryWorkflowLog, err error) {
+
+	if err := DB.Table("tbl_channel_entry_workflow_logs as wl").Select("wl.*, tu.username").Joins("left join tbl_users as tu on tu.id = wl.created_by").Where("wl.entry_id = ? and wl.tenant_id = ?", entryid, tenantid).Order("wl.id desc").Find(&entryworkflowlogs).Error; err != nil {
+
+		return []TblChannelEntryWorkflowLog{}, err
+	}
+
+	return entryworkflowlogs, nil
+}
+
+// GetWorkflowReviewers lists the active users with an email whose role has a permission of the Entries module. The
+// admin roles have every permission without it being assigned, they are notified when no other role has it.
+func GetWorkflowReviewers(permission string, tenantid int) (reviewers []WorkflowReviewer, err error) {
+
+	if err := DB.Table("tbl_users as tu").Select("distinct tu.id, tu.first_name, tu.email").Joins("inner join tbl_role_permissions as rp on rp.role_id = tu.role_id").Joins("inner join tbl_module_permissions as mp on mp.id = rp.permission_id").Where("mp.module_id = 8 and mp.display_name = ? and tu.is_deleted = 0 and tu.is_active = 1 and tu.email != '' and tu.tenant_id = ? and (rp.tenant_id is NULL or rp.tenant_id = ?)", permission, tenantid, tenantid).Find(&reviewers).Error; err != nil {
+
+		return []WorkflowReviewer{}, err
+	}
+
+	if len(reviewers) > 0 {
+
+		return reviewers, nil
+	}
+
+	if err := DB.Table("tbl_users").Select("id, first_name, email").Where("role_id in (1, 2) and is_deleted = 0 and is_active = 1 and email != '' and tenant_id = ?", tenantid).Find(&reviewers).Error; err != nil {
+
+		return []WorkflowReviewer{}, err
+	}
+
+	return reviewers, nil
+}
+
+// GetWorkflowUser reads the user an entry goes back to when it is rejected
+func GetWorkflowUser(userid int, tenantid int) (reviewer WorkflowReviewer, err error) {
+
+	if err := DB.Table("tbl_users").Select("id, first_name, email").Where("id = ? and is_deleted = 0 and tenant_id = ?", userid, tenantid).Take(&reviewer).Error; err != nil {
+
+		return WorkflowReviewer{}, err
+	}
+
+	return reviewer, nil
+}
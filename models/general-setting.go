@@ -14,6 +14,8 @@ type TblGeneralSetting struct {
 	ModifiedBy     int
 	ModifiedOn     time.Time
 	StorageType    string
+	ImagePresets   string
+	RecycleBinDays int
 }
 
 type TblTimeZone struct {
@@ -34,7 +36,7 @@ func GetGeneralSettings(tenantid int) (gensetting TblGeneralSetting, err error)
 
 func UpdateGeneralSettings(gensetting TblGeneralSetting, tenantid int) error {
 
-	if err := DB.Debug().Table("tbl_general_settings").Where("tenant_id = ?", tenantid).UpdateColumns(map[string]interface{}{"company_name": gensetting.CompanyName, "logo_path": gensetting.LogoPath, "expand_logo_path": gensetting.ExpandLogoPath, "date_format": gensetting.DateFormat, "time_format": gensetting.TimeFormat, "time_zone": gensetting.TimeZone, "language_id": gensetting.LanguageId, "modified_by": gensetting.ModifiedBy, "modified_on": gensetting.ModifiedOn, "storage_type": gensetting.StorageType}).Error; err != nil {
+	if err := DB.Debug().Table("tbl_general_settings").Where("tenant_id = ?", tenantid).UpdateColumns(map[string]interface{}{"company_name": gensetting.CompanyName, "logo_path": gensetting.LogoPath, "expand_logo_path": gensetting.ExpandLogoPath, "date_format": gensetting.DateFormat, "time_format": gensetting.TimeFormat, "time_zone": gensetting.TimeZone, "language_id": gensetting.LanguageId, "modified_by": gensetting.ModifiedBy, "modified_on": gensetting.ModifiedOn, "storage_type": gensetting.StorageType, "image_presets": gensetting.ImagePresets}).Error; err != nil {
 
 		return err
 
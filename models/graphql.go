@@ -23,6 +23,24 @@ type TblGraphqlSettings struct {
 	DateString  string `gorm:"-"`
 	ExpiryTime  time.Time
 	TenantId    int
+	ChannelIds   string
+	WriteAccess  int
+	MemberAccess int
+	RateLimit    int
+	RateBurst    int
+	LastUsedOn   time.Time  `gorm:"DEFAULT:NULL"`
+	ChannelNames []string   `gorm:"-"`
+	LastUsed     string     `gorm:"-"`
+	Usage        TokenUsage `gorm:"-"`
+}
+
+// TokenUsage is the usage of a token read from tbl_graphql_usages
+type TokenUsage struct {
+	TokenId       int
+	RequestsToday int
+	ErrorsToday   int
+	RequestsWeek  int
+	ErrorsWeek    int
 }
 
 func GetListOfTokens(limit int, offset int, keyword string, tenantid int) (grpahqlsett []TblGraphqlSettings, count int64, err error) {
@@ -125,3 +143,25 @@ func MultiDeleteGraphqltoken(graph *TblGraphqlSettings, ids []int, tenantid int)
 
 	return nil
 }
+
+// GetApiTokenUsage sums the daily usage of the tokens for today and the last seven days
+func GetApiTokenUsage(ids []int, today time.Time, tenantid int) (usage map[int]TokenUsage, err error) {
+
+	var usageList []TokenUsage
+
+	weekStart := today.AddDate(0, 0, -6)
+
+	if err := DB.Table("tbl_graphql_usages").Select("token_id, sum(case when usage_date = ? then request_count else 0 end) as requests_today, sum(case when usage_date = ? then error_count else 0 end) as errors_today, sum(request_count) as requests_week, sum(error_count) as errors_week", today, today).Where("token_id in (?) and tenant_id = ? and usage_date >= ?", ids, tenantid, weekStart).Group("token_id").Find(&usageList).Error; err != nil {
+
+		return map[int]TokenUsage{}, err
+	}
+
+	usage = make(map[int]TokenUsage, len(usageList))
+
+	for _, tokenUsage := range usageList {
+
+		usage[tokenUsage.TokenId] = tokenUsage
+	}
+
+	return usage, nil
+}
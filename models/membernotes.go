@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type MemberNote struct {
+	Id                      int
+	PageId                  int
+	EntryTitle              string
+	NotesHighlightsContent  string
+	NotesHighlightsType     string
+	HighlightsConfiguration datatypes.JSONMap
+	CreatedOn               time.Time
+	ModifiedOn              time.Time `gorm:"DEFAULT:NULL"`
+	Configuration           string    `gorm:"-"`
+	CreatedDate             string    `gorm:"-"`
+	ModifiedDate            string    `gorm:"-"`
+}
+
+// GetMemberNotes returns a page of the notes and highlights of a member with the titles of their entries, newest first
+func GetMemberNotes(memberid int, notetype string, limit, offset int, tenantid int) (notes []MemberNote, count int64, err error) {
+
+	query := DB.Table("tbl_member_notes_highlights as mn").
+		Joins("left join tbl_channel_entries as en on en.id = mn.page_id").
+		Where("mn.member_id = ? and mn.tenant_id = ? and mn.is_deleted = 0", memberid, tenantid)
+
+	if notetype != "" {
+
+		query = query.Where("mn.notes_highlights_type = ?", notetype)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+
+		return []MemberNote{}, 0, err
+	}
+
+	if err := query.Select("mn.id, mn.page_id, en.title as entry_title, mn.notes_highlights_content, mn.notes_highlights_type, mn.highlights_configuration, mn.created_on, mn.modified_on").Order("mn.created_on desc, mn.id desc").Limit(limit).Offset(offset).Find(&notes).Error; err != nil {
+
+		return []MemberNote{}, 0, err
+	}
+
+	return notes, count, nil
+}
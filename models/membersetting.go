@@ -11,6 +11,9 @@ type TblMemberSetting struct {
 	ModifiedOn        time.Time `gorm:"DEFAULT:NULL"`
 	ModifiedBy        int       `gorm:"DEFAULT:NULL"`
 	NotificationUsers string
+	DefaultGroupId    int
+	EmailVerification int
+	VerificationUrl   string
 }
 
 func GetMemberSettings(tenantid int) (membersetting *[]TblMemberSetting, error bool) {
@@ -25,7 +28,7 @@ func GetMemberSettings(tenantid int) (membersetting *[]TblMemberSetting, error b
 
 func UpdateMemberSetting(membersetting *TblMemberSetting, tenantid int) error {
 
-	if err := DB.Model(TblMemberSetting{}).Where("id=? and tenant_id = ?", membersetting.Id, tenantid).UpdateColumns(map[string]interface{}{"allow_registration": membersetting.AllowRegistration, "member_login": membersetting.MemberLogin, "notification_users": membersetting.NotificationUsers, "modified_on": membersetting.ModifiedOn, "modified_by": membersetting.ModifiedBy}).Error; err != nil {
+	if err := DB.Model(TblMemberSetting{}).Where("id=? and tenant_id = ?", membersetting.Id, tenantid).UpdateColumns(map[string]interface{}{"allow_registration": membersetting.AllowRegistration, "member_login": membersetting.MemberLogin, "notification_users": membersetting.NotificationUsers, "default_group_id": membersetting.DefaultGroupId, "email_verification": membersetting.EmailVerification, "verification_url": membersetting.VerificationUrl, "modified_on": membersetting.ModifiedOn, "modified_by": membersetting.ModifiedBy}).Error; err != nil {
 
 		return err
 	}
@@ -0,0 +1,849 @@
+package models
+
+import (
+	"errors"
+	"spurt-cms/recyclebin"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRestoreChannelFirst = errors.New("the channel of this entry is deleted, restore the channel first")
+	ErrRestoreParentFirst  = errors.New("the parent category is deleted, restore it first")
+	ErrRestoreTaken        = errors.New("an active record with the same name, email or username exists")
+)
+
+// RecycleBinItem is a soft-deleted record with who deleted it and when
+type RecycleBinItem struct {
+	Id          int
+	Name        string
+	LastName    string
+	Detail      string
+	DeletedOn   time.Time
+	DeletedBy   int
+	Username    string
+	DeletedDate string `gorm:"-"`
+}
+
+type TblRecycleBinLink struct {
+	Id         int
+	Module     string
+	RecordId   int
+	LinkType   string
+	LinkId     int
+	CategoryId string
+	DeletedOn  time.Time
+	TenantId   int
+}
+
+type recycleBinTable struct {
+	from    string
+	columns string
+	joins   string
+	where   string
+	search  []string
+}
+
+// the records of a module listed in the recycle bin, records deleted along with another one come back with it
+var recycleBinTables = map[string]recycleBinTable{
+	recyclebin.Entries: {
+		from:    "tbl_channel_entries as rb",
+		columns: "rb.title as name, ch.channel_name as detail",
+		joins:   "inner join tbl_channels as ch on ch.id = rb.channel_id",
+		where:   "ch.is_deleted = 0",
+		search:  []string{"rb.title"},
+	},
+	recyclebin.Channels: {
+		from:    "tbl_channels as rb",
+		columns: "rb.channel_name as name, rb.channel_description as detail",
+		search:  []string{"rb.channel_name"},
+	},
+	recyclebin.Categories: {
+		from:    "tbl_categories as rb",
+		columns: "rb.category_name as name, coalesce(pc.category_name, '') as detail",
+		joins:   "left join tbl_categories as pc on pc.id = rb.parent_id",
+		where:   "(pc.id is null or pc.is_deleted = 0 or pc.deleted_on <> rb.deleted_on)",
+		search:  []string{"rb.category_name"},
+	},
+	recyclebin.Members: {
+		from:    "tbl_members as rb",
+		columns: "rb.first_name as name, rb.last_name, rb.email as detail",
+		search:  []string{"rb.first_name", "rb.last_name", "rb.email"},
+	},
+	recyclebin.MemberGroups: {
+		from:    "tbl_member_groups as rb",
+		columns: "rb.name, rb.description as detail",
+		search:  []string{"rb.name"},
+	},
+	recyclebin.Users: {
+		from:    "tbl_users as rb",
+		columns: "rb.first_name as name, rb.last_name, rb.email as detail",
+		search:  []string{"rb.first_name", "rb.last_name", "rb.email", "rb.username"},
+	},
+}
+
+func recycleBinQuery(module string, keyword string, tenantid int) *gorm.DB {
+
+	table := recycleBinTables[module]
+
+	query := DB.Table(table.from).Where("rb.is_deleted = 1 and rb.tenant_id = ?", tenantid)
+
+	if table.joins != "" {
+
+		query = query.Joins(table.joins)
+	}
+
+	if table.where != "" {
+
+		query = query.Where(table.where)
+	}
+
+	if keyword = strings.TrimSpace(keyword); keyword != "" {
+
+		var conditions []string
+
+		var values []interface{}
+
+		for _, column := range table.search {
+
+			conditions = append(conditions, "LOWER(TRIM("+column+")) LIKE LOWER(TRIM(?))")
+
+			values = append(values, "%"+keyword+"%")
+		}
+
+		query = query.Where("("+strings.Join(conditions, " or ")+")", values...)
+	}
+
+	return query
+}
+
+// GetRecycleBin lists the deleted records of a module, the last deleted first
+func GetRecycleBin(module string, limit int, offset int, keyword string, tenantid int) (items []RecycleBinItem, count int64, err error) {
+
+	if err := recycleBinQuery(module, keyword, tenantid).Count(&count).Error; err != nil {
+
+		return nil, 0, err
+	}
+
+	query := recycleBinQuery(module, keyword, tenantid).Select("rb.id, " + recycleBinTables[module].columns + ", rb.deleted_on, rb.deleted_by, tu.username").Joins("left join tbl_users as tu on tu.id = rb.deleted_by").Order("rb.deleted_on desc, rb.id desc")
+
+	if limit > 0 {
+
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	if err := query.Find(&items).Error; err != nil {
+
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+// GetRecycleBinCounts counts the deleted records of every module
+func GetRecycleBinCounts(tenantid int) (map[string]int64, error) {
+
+	counts := make(map[string]int64, len(recyclebin.Modules))
+
+	for _, module := range recyclebin.Modules {
+
+		var count int64
+
+		if err := recycleBinQuery(module.Key, "", tenantid).Count(&count).Error; err != nil {
+
+			return nil, err
+		}
+
+		counts[module.Key] = count
+	}
+
+	return counts, nil
+}
+
+// RestoreRecycleBin brings a deleted record back with what was deleted along with it
+func RestoreRecycleBin(module string, id int, tenantid int) error {
+
+	var entriesmodule int
+
+	if module == recyclebin.Channels {
+
+		// the entries menu of a channel is a permission of the entries module
+		var err error
+
+		if entriesmodule, err = Entryid("Entries", tenantid); err != nil {
+
+			return err
+		}
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+
+		switch module {
+
+		case recyclebin.Entries:
+
+			return restoreEntry(tx, id, tenantid)
+
+		case recyclebin.Channels:
+
+			return restoreChannel(tx, id, entriesmodule, tenantid)
+
+		case recyclebin.Categories:
+
+			return restoreCategory(tx, id, tenantid)
+
+		case recyclebin.Members:
+
+			return restoreMember(tx, id, tenantid)
+
+		case recyclebin.MemberGroups:
+
+			return restoreMemberGroup(tx, id, tenantid)
+
+		case recyclebin.Users:
+
+			return restoreUser(tx, id, tenantid)
+		}
+
+		return gorm.ErrRecordNotFound
+	})
+}
+
+var restored = map[string]interface{}{"is_deleted": 0, "deleted_on": nil, "deleted_by": nil}
+
+func restoreEntry(tx *gorm.DB, id int, tenantid int) error {
+
+	var entry struct {
+		ChannelDeleted int
+	}
+
+	if err := tx.Table("tbl_channel_entries as en").Select("coalesce(ch.is_deleted, 0) as channel_deleted").Joins("left join tbl_channels as ch on ch.id = en.channel_id").Where("en.id = ? and en.tenant_id = ? and en.is_deleted = 1", id, tenantid).Take(&entry).Error; err != nil {
+
+		return err
+	}
+
+	if entry.ChannelDeleted == 1 {
+
+		return ErrRestoreChannelFirst
+	}
+
+	if err := tx.Table("tbl_channel_entries").Where("id = ? and tenant_id = ?", id, tenantid).UpdateColumns(restored).Error; err != nil {
+
+		return err
+	}
+
+	return tx.Table("tbl_channel_entry_fields").Where("channel_entry_id = ? and tenant_id = ?", id, tenantid).UpdateColumns(map[string]interface{}{"deleted_on": nil, "deleted_by": nil}).Error
+}
+
+func restoreChannel(tx *gorm.DB, id int, entriesmodule int, tenantid int) error {
+
+	var channel struct {
+		ChannelName  string
+		FieldGroupId int
+		CreatedBy    int
+		DeletedOn    time.Time
+	}
+
+	if err := tx.Table("tbl_channels").Select("channel_name, field_group_id, created_by, deleted_on").Where("id = ? and tenant_id = ? and is_deleted = 1", id, tenantid).Take(&channel).Error; err != nil {
+
+		return err
+	}
+
+	var taken int64
+
+	if err := tx.Table("tbl_channels").Where("LOWER(channel_name) = LOWER(?) and is_deleted = 0 and tenant_id = ?", channel.ChannelName, tenantid).Count(&taken).Error; err != nil {
+
+		return err
+	}
+
+	if taken > 0 {
+
+		return ErrRestoreTaken
+	}
+
+	if err := tx.Table("tbl_channels").Where("id = ? and tenant_id = ?", id, tenantid).UpdateColumns(restored).Error; err != nil {
+
+		return err
+	}
+
+	entries := tx.Table("tbl_channel_entries").Where("channel_id = ? and tenant_id = ? and is_deleted = 1", id, tenantid)
+
+	if channel.DeletedOn.IsZero() {
+
+		entries = entries.Where("deleted_on is null")
+
+	} else {
+
+		entries = entries.Where("deleted_on = ?", channel.DeletedOn)
+	}
+
+	if err := entries.UpdateColumns(restored).Error; err != nil {
+
+		return err
+	}
+
+	if err := tx.Table("tbl_field_groups").Where("id = ? and tenant_id = ?", channel.FieldGroupId, tenantid).UpdateColumns(restored).Error; err != nil {
+
+		return err
+	}
+
+	// deleting a channel drops its entries permission, the roles it was granted to have to be given it again
+	routename := "/channel/entrylist/" + strconv.Itoa(id)
+
+	var permissions int64
+
+	if err := tx.Table("tbl_module_permissions").Where("route_name = ?", routename).Count(&permissions).Error; err != nil {
+
+		return err
+	}
+
+	if permissions > 0 {
+
+		return nil
+	}
+
+	return tx.Table("tbl_module_permissions").Create(map[string]interface{}{
+		"display_name":           channel.ChannelName,
+		"route_name":             routename,
+		"slug_name":              strings.ReplaceAll(strings.ToLower(channel.ChannelName), " ", "_"),
+		"module_id":              entriesmodule,
+		"assign_permission":      1,
+		"order_index":            2,
+		"full_access_permission": 1,
+		"created_by":             channel.CreatedBy,
+		"created_on":             time.Now().UTC(),
+		"tenant_id":              tenantid,
+	}).Error
+}
+
+func restoreCategory(tx *gorm.DB, id int, tenantid int) error {
+
+	var category struct {
+		DeletedOn     time.Time
+		ParentDeleted int
+	}
+
+	if err := tx.Table("tbl_categories as ca").Select("ca.deleted_on, coalesce(pc.is_deleted, 0) as parent_deleted").Joins("left join tbl_categories as pc on pc.id = ca.parent_id").Where("ca.id = ? and ca.tenant_id = ? and ca.is_deleted = 1", id, tenantid).Take(&category).Error; err != nil {
+
+		return err
+	}
+
+	if category.ParentDeleted == 1 {
+
+		return ErrRestoreParentFirst
+	}
+
+	ids, err := categoryTree(tx, id)
+
+	if err != nil {
+
+		return err
+	}
+
+	// the sub categories deleted with the category come back with it
+	categories := tx.Table("tbl_categories").Where("id in (?) and tenant_id = ? and is_deleted = 1", ids, tenantid)
+
+	if category.DeletedOn.IsZero() {
+
+		categories = categories.Where("deleted_on is null or id = ?", id)
+
+	} else {
+
+		categories = categories.Where("deleted_on = ? or id = ?", category.DeletedOn, id)
+	}
+
+	if err := categories.UpdateColumns(restored).Error; err != nil {
+
+		return err
+	}
+
+	var links []TblRecycleBinLink
+
+	if err := tx.Table("tbl_recycle_bin_links").Where("module = ? and record_id = ? and tenant_id = ?", recyclebin.Categories, id, tenantid).Find(&links).Error; err != nil {
+
+		return err
+	}
+
+	for _, link := range links {
+
+		if link.LinkType == recyclebin.Channels {
+
+			var linked int64
+
+			if err := tx.Table("tbl_channel_categories").Where("channel_id = ? and category_id = ? and tenant_id = ?", link.LinkId, link.CategoryId, tenantid).Count(&linked).Error; err != nil {
+
+				return err
+			}
+
+			if linked > 0 {
+
+				continue
+			}
+
+			if err := tx.Table("tbl_channel_categories").Create(map[string]interface{}{"channel_id": link.LinkId, "category_id": link.CategoryId, "created_on": time.Now().UTC(), "tenant_id": tenantid}).Error; err != nil {
+
+				return err
+			}
+
+			continue
+		}
+
+		var current string
+
+		if err := tx.Table("tbl_channel_entries").Select("coalesce(categories_id, '')").Where("id = ? and tenant_id = ?", link.LinkId, tenantid).Scan(&current).Error; err != nil {
+
+			return err
+		}
+
+		if err := tx.Table("tbl_channel_entries").Where("id = ? and tenant_id = ?", link.LinkId, tenantid).UpdateColumn("categories_id", recyclebin.Relink(current, link.CategoryId, ids)).Error; err != nil {
+
+			return err
+		}
+	}
+
+	return tx.Table("tbl_recycle_bin_links").Where("module = ? and record_id = ? and tenant_id = ?", recyclebin.Categories, id, tenantid).Delete(&TblRecycleBinLink{}).Error
+}
+
+func restoreMember(tx *gorm.DB, id int, tenantid int) error {
+
+	var member struct {
+		Email    string
+		Username string
+	}
+
+	if err := tx.Table("tbl_members").Select("email, coalesce(username, '') as username").Where("id = ? and tenant_id = ? and is_deleted = 1", id, tenantid).Take(&member).Error; err != nil {
+
+		return err
+	}
+
+	var taken int64
+
+	if err := tx.Table("tbl_members").Where("(LOWER(email) = LOWER(?) or (username <> '' and username = ?)) and is_deleted = 0 and tenant_id = ?", member.Email, member.Username, tenantid).Count(&taken).Error; err != nil {
+
+		return err
+	}
+
+	if taken > 0 {
+
+		return ErrRestoreTaken
+	}
+
+	if err := tx.Table("tbl_members").Where("id = ? and tenant_id = ?", id, tenantid).UpdateColumns(restored).Error; err != nil {
+
+		return err
+	}
+
+	return tx.Table("tbl_member_profiles").Where("member_id = ? and tenant_id = ? and is_deleted = 1", id, tenantid).UpdateColumns(restored).Error
+}
+
+func restoreMemberGroup(tx *gorm.DB, id int, tenantid int) error {
+
+	var name string
+
+	if err := tx.Table("tbl_member_groups").Select("name").Where("id = ? and tenant_id = ? and is_deleted = 1", id, tenantid).Scan(&name).Error; err != nil {
+
+		return err
+	}
+
+	var taken int64
+
+	if err := tx.Table("tbl_member_groups").Where("LOWER(name) = LOWER(?) and is_deleted = 0 and tenant_id = ?", name, tenantid).Count(&taken).Error; err != nil {
+
+		return err
+	}
+
+	if taken > 0 {
+
+		return ErrRestoreTaken
+	}
+
+	result := tx.Table("tbl_member_groups").Where("id = ? and tenant_id = ? and is_deleted = 1", id, tenantid).UpdateColumns(restored)
+
+	if result.Error == nil && result.RowsAffected == 0 {
+
+		return gorm.ErrRecordNotFound
+	}
+
+	return result.Error
+}
+
+func restoreUser(tx *gorm.DB, id int, tenantid int) error {
+
+	var user struct {
+		Email    string
+		Username string
+	}
+
+	if err := tx.Table("tbl_users").Select("email, username").Where("id = ? and tenant_id = ? and is_deleted = 1", id, tenantid).Take(&user).Error; err != nil {
+
+		return err
+	}
+
+	var taken int64
+
+	if err := tx.Table("tbl_users").Where("(LOWER(email) = LOWER(?) or LOWER(username) = LOWER(?)) and is_deleted = 0", user.Email, user.Username).Count(&taken).Error; err != nil {
+
+		return err
+	}
+
+	if taken > 0 {
+
+		return ErrRestoreTaken
+	}
+
+	return tx.Table("tbl_users").Where("id = ? and tenant_id = ?", id, tenantid).UpdateColumns(restored).Error
+}
+
+// categoryTree returns a category with all the categories under it
+func categoryTree(tx *gorm.DB, id int) (ids []int, err error) {
+
+	if err := tx.Raw(`WITH RECURSIVE cat_tree AS (
+		SELECT id FROM tbl_categories WHERE id = ?
+		UNION ALL
+		SELECT tc.id FROM tbl_categories AS tc INNER JOIN cat_tree AS ct ON tc.parent_id = ct.id
+	) SELECT id FROM cat_tree`, id).Scan(&ids).Error; err != nil {
+
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// SaveCategoryLinks keeps the channels and entries a category is linked to before deleting it. Deleting a category
+// drops its channel links and takes it out of the entries, restoring it puts them back.
+func SaveCategoryLinks(categoryids []int, tenantid int) error {
+
+	var channelcategories []TblRecycleBinLink
+
+	if err := DB.Table("tbl_channel_categories").Select("channel_id as link_id, category_id").Where("tenant_id = ?", tenantid).Find(&channelcategories).Error; err != nil {
+
+		return err
+	}
+
+	var entrycategories []TblRecycleBinLink
+
+	if err := DB.Table("tbl_channel_entries").Select("id as link_id, categories_id as category_id").Where("tenant_id = ? and categories_id is not null and categories_id <> ''", tenantid).Find(&entrycategories).Error; err != nil {
+
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+
+		for _, categoryid := range categoryids {
+
+			ids, err := categoryTree(tx, categoryid)
+
+			if err != nil {
+
+				return err
+			}
+
+			if err := tx.Table("tbl_recycle_bin_links").Where("module = ? and record_id = ? and tenant_id = ?", recyclebin.Categories, categoryid, tenantid).Delete(&TblRecycleBinLink{}).Error; err != nil {
+
+				return err
+			}
+
+			var links []TblRecycleBinLink
+
+			for _, link := range channelcategories {
+
+				if recyclebin.Linked(link.CategoryId, ids) {
+
+					links = append(links, TblRecycleBinLink{Module: recyclebin.Categories, RecordId: categoryid, LinkType: recyclebin.Channels, LinkId: link.LinkId, CategoryId: link.CategoryId, DeletedOn: now, TenantId: tenantid})
+				}
+			}
+
+			for _, link := range entrycategories {
+
+				if recyclebin.Linked(link.CategoryId, ids) {
+
+					links = append(links, TblRecycleBinLink{Module: recyclebin.Categories, RecordId: categoryid, LinkType: recyclebin.Entries, LinkId: link.LinkId, CategoryId: link.CategoryId, DeletedOn: now, TenantId: tenantid})
+				}
+			}
+
+			if len(links) == 0 {
+
+				continue
+			}
+
+			if err := tx.Table("tbl_recycle_bin_links").Create(&links).Error; err != nil {
+
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// MarkChannelDeleted records who deleted a channel and when, the entries deleted with it get the same time so that
+// restoring the channel brings back only them
+func MarkChannelDeleted(channelid int, userid int, tenantid int) error {
+
+	now, _ := time.Parse("2006-01-02 15:04:05", time.Now().UTC().Format("2006-01-02 15:04:05"))
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Table("tbl_channels").Where("id = ? and tenant_id = ? and is_deleted = 1", channelid, tenantid).UpdateColumns(map[string]interface{}{"deleted_on": now, "deleted_by": userid}).Error; err != nil {
+
+			return err
+		}
+
+		return tx.Table("tbl_channel_entries").Where("channel_id = ? and tenant_id = ? and is_deleted = 1 and deleted_on is null", channelid, tenantid).UpdateColumns(map[string]interface{}{"deleted_on": now, "deleted_by": userid}).Error
+	})
+}
+
+// MarkMemberGroupDeleted records who deleted a member group and when
+func MarkMemberGroupDeleted(membergroupid int, userid int, tenantid int) error {
+
+	now, _ := time.Parse("2006-01-02 15:04:05", time.Now().UTC().Format("2006-01-02 15:04:05"))
+
+	return DB.Table("tbl_member_groups").Where("id = ? and tenant_id = ? and is_deleted = 1", membergroupid, tenantid).UpdateColumns(map[string]interface{}{"deleted_on": now, "deleted_by": userid}).Error
+}
+
+// DeleteRecycleBin deletes records of a module permanently with what belongs only to them, records that are not
+// deleted are left alone
+func DeleteRecycleBin(module string, ids []int, tenantid int) error {
+
+	recyclebintable, ok := recycleBinTables[module]
+
+	if !ok || len(ids) == 0 {
+
+		return nil
+	}
+
+	table := strings.Fields(recyclebintable.from)[0]
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+
+		var deleted []int
+
+		if err := tx.Table(table).Where("id in (?) and tenant_id = ? and is_deleted = 1", ids, tenantid).Pluck("id", &deleted).Error; err != nil {
+
+			return err
+		}
+
+		if len(deleted) == 0 {
+
+			return nil
+		}
+
+		switch module {
+
+		case recyclebin.Entries:
+
+			return purgeEntries(tx, deleted)
+
+		case recyclebin.Channels:
+
+			return purgeChannels(tx, deleted, tenantid)
+
+		case recyclebin.Categories:
+
+			return purgeCategories(tx, deleted, tenantid)
+
+		case recyclebin.Members:
+
+			return purgeMembers(tx, deleted)
+
+		case recyclebin.MemberGroups:
+
+			if err := tx.Table("tbl_access_control_user_groups").Where("member_group_id in (?)", deleted).Delete(nil).Error; err != nil {
+
+				return err
+			}
+
+			return tx.Table("tbl_member_groups").Where("id in (?)", deleted).Delete(nil).Error
+
+		case recyclebin.Users:
+
+			for _, table := range []string{"tbl_role_users", "tbl_user_personalizes"} {
+
+				if err := tx.Table(table).Where("user_id in (?)", deleted).Delete(nil).Error; err != nil {
+
+					return err
+				}
+			}
+
+			return tx.Table("tbl_users").Where("id in (?)", deleted).Delete(nil).Error
+		}
+
+		return nil
+	})
+}
+
+func purgeEntries(tx *gorm.DB, ids []int) error {
+
+	if len(ids) == 0 {
+
+		return nil
+	}
+
+	if err := tx.Table("tbl_channel_entry_fields").Where("channel_entry_id in (?)", ids).Delete(nil).Error; err != nil {
+
+		return err
+	}
+
+	for _, table := range []string{"tbl_channel_entry_revisions", "tbl_channel_entry_schedules", "tbl_channel_entry_workflow_logs", "tbl_channel_entry_locks", "tbl_graphql_entry_views", "tbl_graphql_search_entries"} {
+
+		if err := tx.Table(table).Where("entry_id in (?)", ids).Delete(nil).Error; err != nil {
+
+			return err
+		}
+	}
+
+	return tx.Table("tbl_channel_entries").Where("id in (?) and is_deleted = 1", ids).Delete(nil).Error
+}
+
+func purgeChannels(tx *gorm.DB, ids []int, tenantid int) error {
+
+	var entries []int
+
+	if err := tx.Table("tbl_channel_entries").Where("channel_id in (?) and tenant_id = ?", ids, tenantid).Pluck("id", &entries).Error; err != nil {
+
+		return err
+	}
+
+	if err := purgeEntries(tx, entries); err != nil {
+
+		return err
+	}
+
+	var fields, fieldgroups []int
+
+	if err := tx.Table("tbl_group_fields").Where("channel_id in (?)", ids).Pluck("field_id", &fields).Error; err != nil {
+
+		return err
+	}
+
+	if err := tx.Table("tbl_channels").Where("id in (?)", ids).Pluck("field_group_id", &fieldgroups).Error; err != nil {
+
+		return err
+	}
+
+	if len(fields) > 0 {
+
+		if err := tx.Table("tbl_field_options").Where("field_id in (?)", fields).Delete(nil).Error; err != nil {
+
+			return err
+		}
+
+		if err := tx.Table("tbl_fields").Where("id in (?)", fields).Delete(nil).Error; err != nil {
+
+			return err
+		}
+	}
+
+	if len(fieldgroups) > 0 {
+
+		if err := tx.Table("tbl_field_groups").Where("id in (?) and tenant_id = ?", fieldgroups, tenantid).Delete(nil).Error; err != nil {
+
+			return err
+		}
+	}
+
+	for _, table := range []string{"tbl_group_fields", "tbl_channel_categories"} {
+
+		if err := tx.Table(table).Where("channel_id in (?)", ids).Delete(nil).Error; err != nil {
+
+			return err
+		}
+	}
+
+	return tx.Table("tbl_channels").Where("id in (?)", ids).Delete(nil).Error
+}
+
+func purgeCategories(tx *gorm.DB, ids []int, tenantid int) error {
+
+	for _, id := range ids {
+
+		tree, err := categoryTree(tx, id)
+
+		if err != nil {
+
+			return err
+		}
+
+		// sub categories still in use stay, only the deleted ones go
+		if err := tx.Table("tbl_categories").Where("id in (?) and tenant_id = ? and is_deleted = 1", tree, tenantid).Delete(nil).Error; err != nil {
+
+			return err
+		}
+	}
+
+	return tx.Table("tbl_recycle_bin_links").Where("module = ? and record_id in (?) and tenant_id = ?", recyclebin.Categories, ids, tenantid).Delete(&TblRecycleBinLink{}).Error
+}
+
+func purgeMembers(tx *gorm.DB, ids []int) error {
+
+	for _, table := range []string{"tbl_member_profiles", "tbl_member_notes_highlights", "tbl_member_verifications", "tbl_member_profile_claims", "tbl_graphql_member_sessions"} {
+
+		if err := tx.Table(table).Where("member_id in (?)", ids).Delete(nil).Error; err != nil {
+
+			return err
+		}
+	}
+
+	return tx.Table("tbl_members").Where("id in (?)", ids).Delete(nil).Error
+}
+
+// GetRecycleBinPurges returns the tenants that purge their recycle bin with the days they keep deleted records
+func GetRecycleBinPurges() (purges map[int]int, err error) {
+
+	var settings []struct {
+		TenantId       int
+		RecycleBinDays int
+	}
+
+	if err := DB.Table("tbl_general_settings").Select("tenant_id, recycle_bin_days").Where("recycle_bin_days > 0").Find(&settings).Error; err != nil {
+
+		return nil, err
+	}
+
+	purges = make(map[int]int, len(settings))
+
+	for _, setting := range settings {
+
+		purges[setting.TenantId] = setting.RecycleBinDays
+	}
+
+	return purges, nil
+}
+
+// PurgeRecycleBin permanently deletes the records of a tenant deleted before a time, returning how many went
+func PurgeRecycleBin(before time.Time, tenantid int) (int, error) {
+
+	purged := 0
+
+	for _, module := range recyclebin.Modules {
+
+		table := strings.Fields(recycleBinTables[module.Key].from)[0]
+
+		var ids []int
+
+		if err := DB.Table(table).Where("is_deleted = 1 and tenant_id = ? and deleted_on < ?", tenantid, before).Pluck("id", &ids).Error; err != nil {
+
+			return purged, err
+		}
+
+		if err := DeleteRecycleBin(module.Key, ids, tenantid); err != nil {
+
+			return purged, err
+		}
+
+		purged += len(ids)
+	}
+
+	return purged, nil
+}
+
+// UpdateRecycleBinDays sets the days deleted records of a tenant are kept, 0 keeps them until they are deleted
+func UpdateRecycleBinDays(days int, tenantid int) error {
+
+	return DB.Table("tbl_general_settings").Where("tenant_id = ?", tenantid).UpdateColumn("recycle_bin_days", days).Error
+}
@@ -578,6 +578,8 @@ $(document).on('click', '.channelsave', function () {
       data: {
         "channelname": name,
         "channeldesc": desc,
+        "revisionlimit": $('#revisionlimit').val(),
+        "workflow": $('#workflow').val(),
         "sections": JSON.stringify({ sections }),
         "fiedlvalue": JSON.stringify({ fiedlvalue }),
         "categoryvalue": SelectedCategoryValue,
@@ -617,6 +619,8 @@ $(document).on('click', '.channelsave', function () {
         "id": $("#channelid").val(),
         "channelname": name,
         "channeldesc": desc,
+        "revisionlimit": $('#revisionlimit').val(),
+        "workflow": $('#workflow').val(),
         "sections": JSON.stringify({ sections }),
         "deletesections": JSON.stringify({ deletesecion }),
         "deletefields": JSON.stringify({ deletefields }),
@@ -1393,7 +1393,10 @@ $(document).ready(function () {
 //settings menu active menu highlighting code
 $(document).ready(function () {
 
-    if (window.location.href.indexOf('myprofile') != -1) {
+    if (window.location.href.indexOf('recyclebin') != -1) {
+        $('#recycleBinLink').addClass('active')
+
+    } else if (window.location.href.indexOf('myprofile') != -1) {
         $('#myProfileLink').addClass('active')
 
     } else if (window.location.href.indexOf('changepassword') != -1) {
@@ -1,4 +1,4 @@
-var languagedata, editTknName, editTknDrn, editTknDesc
+var languagedata, editTknName, editTknDrn, editTknDesc, editTknAccess
 
 $(document).ready(async function () {
     var languagepath = $('.language-group>button').attr('data-path')
@@ -55,6 +55,8 @@ $(document).on('click', '#genTokenBtn[data-status=0]', function (e) {
         }
     })
 
+    appendTokenAccess(formData)
+
     let validData = fieldVal.every(d => d != "" && d != "Select Duration" && d != "Seleccionar duración" && d != "Sélectionnez la durée")
     if (validData) {
         isCalled = true
@@ -157,6 +159,15 @@ $(document).on('click', '#key-desc', function () {
             $('#tokeninsideContent').text(result.data.Token)
             $('#tokeninsideContent').attr('data-value', result.data.Token)
             $("#slctdurn").text(result.data.Duration);
+            var channelIds = result.data.ChannelIds ? result.data.ChannelIds.split(',') : []
+            $('#createToken .token-channel').each(function () {
+                $(this).prop('checked', channelIds.includes($(this).val()))
+            })
+            $('#createToken #tokenWriteAccess').prop('checked', result.data.WriteAccess == 1)
+            $('#createToken #tokenMemberAccess').prop('checked', result.data.MemberAccess == 1)
+            $('#createToken #tokenRateLimit').val(result.data.RateLimit)
+            $('#createToken #tokenRateBurst').val(result.data.RateBurst)
+            editTknAccess = TokenAccessString()
             $('#apikey').removeClass('hidden')
             if (result.data.IsDefault == 1) {
                 $('.dropdown.tokenInputGrp').addClass('hidden')
@@ -199,13 +210,48 @@ function CheckIsEditDataUnchanged() {
     var desc = $("#createToken #tokenDesc").val()
     var durn = $("#slctdurn").text();
     var name = $("#createToken #tokenName").val()
-    if ((desc == editTknDesc && durn == editTknDrn && name == editTknName) || ($("#createToken #genTokenBtn").attr('default-update') == "1" && durn == editTknDrn && name == editTknName)) {
+    var scopes = TokenAccessString()
+    if ((desc == editTknDesc && durn == editTknDrn && name == editTknName && scopes == editTknAccess) || ($("#createToken #genTokenBtn").attr('default-update') == "1" && durn == editTknDrn && name == editTknName && scopes == editTknAccess)) {
         $("#createToken #genTokenBtn").addClass('bg-[#D1D1D1]')
         $("#createToken #genTokenBtn").removeClass('hover:bg-[#148569]')
         $("#createToken #genTokenBtn").prop('disabled', true).addClass('cursor-not-allowed')
     }
 }
 
+// channels, write access, member data access and rate limits granted to the token
+function TokenAccessString() {
+    var channelIds = []
+    $('#createToken .token-channel:checked').each(function () {
+        channelIds.push($(this).val())
+    })
+    var writeAccess = $('#createToken #tokenWriteAccess').is(':checked') ? "1" : "0"
+    var memberAccess = $('#createToken #tokenMemberAccess').is(':checked') ? "1" : "0"
+    var rateLimit = $.trim($('#createToken #tokenRateLimit').val()) || "0"
+    var rateBurst = $.trim($('#createToken #tokenRateBurst').val()) || "0"
+    return [channelIds.join(','), writeAccess, memberAccess, rateLimit, rateBurst].join('|')
+}
+
+function appendTokenAccess(formData) {
+    var access = TokenAccessString().split('|')
+    formData.append("channelIds", access[0])
+    formData.append("writeAccess", access[1])
+    formData.append("memberAccess", access[2])
+    formData.append("rateLimit", access[3])
+    formData.append("rateBurst", access[4])
+}
+
+$(document).on('change input', '#createToken .token-channel, #createToken #tokenWriteAccess, #createToken #tokenMemberAccess, #createToken #tokenRateLimit, #createToken #tokenRateBurst', function () {
+    if ($("#createToken #genTokenBtn").attr('data-status') == "1") {
+        if (TokenAccessString() != editTknAccess) {
+            $("#createToken #genTokenBtn").removeClass('bg-[#D1D1D1]')
+            $("#createToken #genTokenBtn").addClass('hover:bg-[#148569]').addClass('bg-[#10A37F]')
+            $("#createToken #genTokenBtn").prop('disabled', false).removeClass('cursor-not-allowed')
+        } else {
+            CheckIsEditDataUnchanged()
+        }
+    }
+})
+
 $(document).on('click', '#genTokenBtn[data-status=1]', function (e) {
 
     var formData = new FormData()
@@ -240,6 +286,8 @@ $(document).on('click', '#genTokenBtn[data-status=1]', function (e) {
         }
     })
 
+    appendTokenAccess(formData)
+
     let validData = fieldVal.every(d => d != "" && d != "Select Duration" && d != "Seleccionar duración" && d != "Sélectionnez la durée")
     if (validData) {
         $.ajax({
@@ -119,9 +119,12 @@ $(document).ready(async function () {
                     url: "/channel/draftentry/" + eid,
                     type: "POST",
                     dataType: "json",
-                    data: { "id": $("#slchannel").attr('data-id'), "cname": channelname, "image": spurtdata.image, "title": spurtdata.title, "status": 0, "text": spurtdata.html, "categoryids": categoryIds, "channeldata": JSON.stringify(channeldata), "seodetails": JSON.stringify(seodetails), csrf: $("input[name='csrf']").val(), "author": authername, "createtime": createtime, "publishtime": publishtime, "readingtime": readingtime, "sortorder": "", "tagname": tagname, "extxt": extxt, "orderindex": orderindex },
+                    data: { "id": $("#slchannel").attr('data-id'), "cname": channelname, "image": spurtdata.image, "title": spurtdata.title, "status": 0, "text": spurtdata.html, "categoryids": categoryIds, "channeldata": JSON.stringify(channeldata), "seodetails": JSON.stringify(seodetails), csrf: $("input[name='csrf']").val(), "author": authername, "createtime": createtime, "publishtime": publishtime, "readingtime": readingtime, "sortorder": "", "tagname": tagname, "extxt": extxt, "orderindex": orderindex, "relatedarticles": $("#relatedarticles").val(), "publishat": $("#publishat").val(), "unpublishat": $("#unpublishat").val(), "version": $("#entryversion").val() },
                     success: function (result) {
                         window.location.href = homeurl;
+                    },
+                    error: function (xhr) {
+                        showScheduleError(xhr)
                     }
                 })
             }
@@ -200,9 +203,12 @@ $(document).ready(async function () {
                     url: "/channel/publishentry/" + eid,
                     type: "POST",
                     dataType: "json",
-                    data: { "id": $("#slchannel").attr('data-id'), "cname": channelname, "image": spurtdata.image, "title": spurtdata.title, "status": 1, "text": spurtdata.html, "categoryids": categoryIds, "channeldata": JSON.stringify(channeldata), "seodetails": JSON.stringify(seodetails), csrf: $("input[name='csrf']").val(), "author": authername, "createtime": createtime, "publishtime": publishtime, "readingtime": readingtime, "sortorder": "", "tagname": tagname, "extxt": extxt, "orderindex": orderindex },
+                    data: { "id": $("#slchannel").attr('data-id'), "cname": channelname, "image": spurtdata.image, "title": spurtdata.title, "status": 1, "text": spurtdata.html, "categoryids": categoryIds, "channeldata": JSON.stringify(channeldata), "seodetails": JSON.stringify(seodetails), csrf: $("input[name='csrf']").val(), "author": authername, "createtime": createtime, "publishtime": publishtime, "readingtime": readingtime, "sortorder": "", "tagname": tagname, "extxt": extxt, "orderindex": orderindex, "relatedarticles": $("#relatedarticles").val(), "publishat": $("#publishat").val(), "unpublishat": $("#unpublishat").val(), "version": $("#entryversion").val() },
                     success: function (result) {
                         window.location.href = homeurl;
+                    },
+                    error: function (xhr) {
+                        showScheduleError(xhr)
                     }
                 })
             }
@@ -296,6 +302,11 @@ $(document).ready(async function () {
                     $("#publishtime").val(formattedDate)
 
                 }
+                $("#publishat").val(result.Publishat)
+                $("#unpublishat").val(result.Unpublishat)
+                $("#entryversion").val(result.Version)
+                renderEntrySchedules(result.Schedules)
+
                 if (result.Entries.ReadingTime != 0) {
 
                     $("#readingtime").val(result.Entries.ReadingTime)
@@ -1049,7 +1060,8 @@ $(document).on("click", '#field-update', function () {
                 "deleteoptions": JSON.stringify({ deleteoption }),
                 "fiedlvalue": JSON.stringify({ fiedlvalue }),
                 csrf: $("input[name='csrf']").val(),
-                "entryid":$('#eid').val()
+                "entryid":$('#eid').val(),
+                "version": $("#entryversion").val()
             },
             success: function (result) {
 
@@ -1073,6 +1085,13 @@ $(document).on("click", '#field-update', function () {
                 $("#field-section").addClass("hidden")
                 $("#ed-section").removeClass("hidden")
             
+            },
+            error: function (xhr) {
+
+                // the entry was saved by someone else since it was opened
+                if (xhr.responseJSON && xhr.responseJSON.conflict) {
+                    showEntryConflict(xhr.responseJSON)
+                }
             }
         })
 
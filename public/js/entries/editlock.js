@@ -0,0 +1,128 @@
+// soft edit lock of the entry editor and the conflict screen of a stale save
+
+var entryLockTimer
+
+function entryLock(takeover) {
+
+    var entryid = $("#eid").val()
+
+    if (entryid == "" || entryid == "0") {
+        return
+    }
+
+    $.ajax({
+        url: "/channel/lock/" + entryid,
+        type: "POST",
+        dataType: "json",
+        data: { "takeover": takeover ? 1 : 0, csrf: $("input[name='csrf']").val() },
+        success: function (result) {
+
+            var banner = $("#entry-lock-banner")
+
+            if (result.locked) {
+                banner.addClass("hidden")
+            } else {
+                $("#entry-lock-text").text(result.holder + " is editing this entry since " + result.since + ", your changes may overwrite theirs")
+                $("#entry-lock-takeover").toggleClass("hidden", !result.takeover)
+                banner.removeClass("hidden")
+            }
+
+            clearTimeout(entryLockTimer)
+
+            entryLockTimer = setTimeout(function () {
+                entryLock(false)
+            }, result.heartbeat * 1000)
+        },
+        error: function (xhr) {
+
+            if (xhr.responseJSON && xhr.responseJSON.error) {
+                $("#entry-lock-text").text(xhr.responseJSON.error)
+            }
+        }
+    })
+}
+
+function showEntryConflict(conflict) {
+
+    var author = conflict.savedby != "" ? conflict.savedby : "Another user"
+
+    $("#entry-conflict-saved").text(author + " saved this entry on " + conflict.savedon + ".")
+
+    var diff = $("#entry-conflict-diff").empty()
+
+    $.each(conflict.changes || [], function (index, change) {
+
+        if (!change.changed) {
+            return
+        }
+
+        var rows = ""
+
+        $.each(change.rows || [], function (rowIndex, row) {
+
+            var saved = row.kind == "same" ? "" : "bg-[#FFF1ED]"
+            var yours = row.kind == "same" ? "" : "bg-[#E7F6F2]"
+
+            rows += '<tr>' +
+                '<td class="align-top w-1/2 p-[6px_8px] text-[12px] text-[#262626] break-all border-r border-[#EDEDED] ' + saved + '">' + escapeRevisionText(row.old) + '</td>' +
+                '<td class="align-top w-1/2 p-[6px_8px] text-[12px] text-[#262626] break-all ' + yours + '">' + escapeRevisionText(row.new) + '</td>' +
+                '</tr>'
+        })
+
+        diff.append(
+            '<div class="border border-[#EDEDED] rounded-[4px] mb-[12px]">' +
+            '<p class="text-sm font-medium text-bold-black bg-[#F7F7F5] p-[6px_8px] mb-0">' + escapeRevisionText(change.label) + '</p>' +
+            '<table class="w-full table-fixed">' + rows + '</table>' +
+            '</div>')
+    })
+
+    if (diff.children().length == 0) {
+        diff.append('<p class="text-sm text-[#717171]">Your changes are the same as the saved version</p>')
+    }
+
+    $("#entry-conflict-keep").attr("data-version", conflict.version)
+
+    $("#entryconflictmodal").modal("show")
+}
+
+$(document).ready(function () {
+
+    entryLock(false)
+})
+
+$(document).on("click", "#entry-lock-takeover", function () {
+
+    if (!confirm("Take over editing this entry? The other editor is warned that you are editing it now.")) {
+        return
+    }
+
+    entryLock(true)
+})
+
+$(document).on("click", "#entry-conflict-reload", function () {
+
+    window.location.reload()
+})
+
+// the next save starts from the saved version and overwrites it
+$(document).on("click", "#entry-conflict-keep", function () {
+
+    $("#entryversion").val($(this).attr("data-version"))
+
+    $("#entryconflictmodal").modal("hide")
+})
+
+window.addEventListener("pagehide", function () {
+
+    var entryid = $("#eid").val()
+
+    if (entryid == "" || entryid == "0" || !navigator.sendBeacon) {
+        return
+    }
+
+    var data = new FormData()
+
+    data.append("csrf", $("input[name='csrf']").val())
+
+    navigator.sendBeacon("/channel/unlock/" + entryid, data)
+})
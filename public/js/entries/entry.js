@@ -62,6 +62,14 @@ $(document).on('click', '#removecategory', function () {
 
         window.location.href = "/channel/draftentries"
 
+    } else if (currentLocation.includes("reviewentrieslist")) {
+
+        window.location.href = "/channel/reviewentries"
+
+    } else if (currentLocation.includes("approvedentrieslist")) {
+
+        window.location.href = "/channel/approvedentries"
+
     } else if (currentLocation.includes("entrylist")) {
 
         window.location.href = "/channel/entrylist"
@@ -833,6 +841,14 @@ $(document).on('keyup', '#searchkey', function () {
 
                 window.location.href = "/channel/draftentries"
 
+            } else if (currentLocation.includes("reviewentries")) {
+
+                window.location.href = "/channel/reviewentries"
+
+            } else if (currentLocation.includes("approvedentries")) {
+
+                window.location.href = "/channel/approvedentries"
+
             } else if (currentLocation.includes("entrylist")) {
 
                 window.location.href = "/channel/entrylist"
@@ -889,6 +905,14 @@ $(document).on('click', '.deleteentry', function () {
 
         pname = "draft"
 
+    } else if (currentLocation.includes("reviewentries")) {
+
+        pname = "review"
+
+    } else if (currentLocation.includes("approvedentries")) {
+
+        pname = "approved"
+
     } else if (currentLocation.includes("entrylist")) {
 
         pname = "publish"
@@ -1130,6 +1154,16 @@ $(document).on("click", ".entrystatuschange", function () {
 
 
 
+            }
+        },
+        error: function (xhr) {
+
+            // channels with a review workflow only publish approved entries
+            if (xhr.status == 403) {
+
+                setCookie("Alert-msg", "Entry Review Required")
+
+                window.location.reload()
             }
         }
     })
@@ -1907,6 +1941,11 @@ $(document).on('click', '.selectedunpublish', function () {
                 setCookie("get-toast", "Entry " + datastatus + " Successfully")
 
                 window.location.href = data.url
+            } else if (data.error) {
+
+                setCookie("Alert-msg", "Entry Review Required")
+
+                window.location.reload()
             } else {
 
                 setCookie("Alert-msg", "Internal Server Error")
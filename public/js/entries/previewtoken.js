@@ -0,0 +1,33 @@
+// preview links of the entry editor, the headless frontend reads the draft through graphql with the signed token
+
+$(document).on("click", "#headless-preview-generate", function () {
+
+    $.ajax({
+        url: "/channel/previewtoken/" + $("#eid").val(),
+        type: "POST",
+        dataType: "json",
+        data: { "hours": $("#headless-preview-hours").val(), csrf: $("input[name='csrf']").val() },
+        success: function (result) {
+
+            if (result.status != 1) {
+                return
+            }
+
+            // without a frontend preview route configured the token itself is handed out
+            $("#headless-preview-link").val(result.url != "" ? result.url : result.token)
+            $("#headless-preview-expiry").text("Expires on " + result.expiresat)
+            $("#headless-preview-copy").text("Copy")
+            $("#headless-preview-result").removeClass("hidden")
+        }
+    })
+})
+
+$(document).on("click", "#headless-preview-copy", function () {
+
+    var link = $("#headless-preview-link").val()
+
+    if (link != "" && navigator.clipboard) {
+        navigator.clipboard.writeText(link)
+        $(this).text("Copied")
+    }
+})
@@ -0,0 +1,154 @@
+// related articles picker of the entry editor, the picked entries are kept in their order in #relatedarticles
+
+var relatedentries = []
+var relatedsearchtimer
+
+function RelatedEntryExcludeId() {
+
+    var eid = $("#eid").val()
+
+    if (eid == undefined || eid == "" || window.location.href.includes("copyentry")) {
+        return 0
+    }
+
+    return eid
+}
+
+function RenderRelatedEntries() {
+
+    var list = ""
+
+    for (let entry of relatedentries) {
+
+        list += `<li class="rounded-[4px] p-[8px_12px] border border-[#EDEDED] flex space-x-[6px] justify-between items-center mb-[8px] last-of-type:mb-[0]" data-id="` + entry.id + `">
+        <div class="flex flex-col">
+            <span class="text-[14px] font-normal leading-[17.5px] text-[#262626] line-clamp-1 related-title"></span>
+            <span class="text-[12px] font-normal leading-none text-[#717171] related-channel"></span>
+        </div>
+        <div class="flex space-x-[4px]">
+            <a href="javascript:void(0);" class="bg-[#F7F7F5] rounded-[3px] w-[16px] h-[16px] grid place-items-center hover:bg-[#F0F0F0] text-[10px] related-up" title="Move up">&#9650;</a>
+            <a href="javascript:void(0);" class="bg-[#F7F7F5] p-[3px] rounded-[3px] min-w-[16px] w-[16px] h-[16px] grid place-items-center hover:bg-[#F0F0F0] related-remove">
+                <img src="/public/img/remove-categories.svg" alt="remove">
+            </a>
+        </div>
+        </li>`
+    }
+
+    $("#related-list").html(list)
+
+    // titles are set as text so an entry title can not inject markup
+    $("#related-list li").each(function (index) {
+        $(this).find(".related-title").text(relatedentries[index].title)
+        $(this).find(".related-channel").text(relatedentries[index].channelName)
+    })
+
+    $("#relatedarticles").val(relatedentries.map(entry => entry.id).join(","))
+}
+
+function SearchRelatedEntries(keyword) {
+
+    $.ajax({
+        url: "/channel/relatedentries",
+        type: "GET",
+        dataType: "json",
+        data: { "keyword": keyword, "entryid": RelatedEntryExcludeId() },
+        success: function (result) {
+
+            var picked = relatedentries.map(entry => entry.id)
+
+            var entries = result.entries.filter(entry => !picked.includes(entry.id))
+
+            $("#related-results").empty()
+
+            if (entries.length == 0) {
+                $("#related-results").append(`<li class="p-[8px_12px] text-[12px] text-[#717171]">No entries found</li>`)
+            }
+
+            for (let entry of entries) {
+
+                var item = $(`<li class="cursor-pointer p-[8px_12px] hover:bg-[#F5F5F5] flex flex-col related-option">
+                    <span class="text-[14px] text-[#262626] line-clamp-1 related-title"></span>
+                    <span class="text-[12px] text-[#717171] related-channel"></span>
+                </li>`)
+
+                item.data("entry", entry)
+                item.find(".related-title").text(entry.title)
+                item.find(".related-channel").text(entry.channelName + (entry.status == 1 ? "" : " (not published)"))
+
+                $("#related-results").append(item)
+            }
+
+            $("#related-results").removeClass("hidden")
+        }
+    })
+}
+
+$(document).ready(function () {
+
+    var ids = $("#relatedarticles").val()
+
+    if (ids == undefined || ids == "") {
+        return
+    }
+
+    $.ajax({
+        url: "/channel/relatedentries",
+        type: "GET",
+        dataType: "json",
+        data: { "ids": ids, "entryid": RelatedEntryExcludeId() },
+        success: function (result) {
+
+            relatedentries = result.entries
+
+            RenderRelatedEntries()
+        }
+    })
+})
+
+$(document).on("keyup focus", "#related-search", function () {
+
+    var keyword = $(this).val().trim()
+
+    clearTimeout(relatedsearchtimer)
+
+    relatedsearchtimer = setTimeout(function () {
+        SearchRelatedEntries(keyword)
+    }, 300)
+})
+
+$(document).on("click", ".related-option", function () {
+
+    relatedentries.push($(this).data("entry"))
+
+    RenderRelatedEntries()
+
+    $("#related-search").val("")
+    $("#related-results").addClass("hidden")
+})
+
+$(document).on("click", ".related-remove", function () {
+
+    var index = $(this).closest("li").index()
+
+    relatedentries.splice(index, 1)
+
+    RenderRelatedEntries()
+})
+
+$(document).on("click", ".related-up", function () {
+
+    var index = $(this).closest("li").index()
+
+    if (index > 0) {
+        relatedentries.splice(index - 1, 0, relatedentries.splice(index, 1)[0])
+    }
+
+    RenderRelatedEntries()
+})
+
+$(document).on("click", function (event) {
+
+    if (!$(event.target).closest("#related-picker").length) {
+        $("#related-results").addClass("hidden")
+    }
+})
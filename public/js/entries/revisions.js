@@ -0,0 +1,156 @@
+// revision history of the entry editor, every save of the entry is kept and can be compared or restored
+
+var entryRevisionChanges = []
+
+function escapeRevisionText(value) {
+
+    return $("<div>").text(value).html()
+}
+
+function loadEntryRevisions() {
+
+    $.ajax({
+        url: "/channel/revisions/" + $("#eid").val(),
+        type: "GET",
+        dataType: "json",
+        success: function (result) {
+
+            var list = $("#entry-revisions").empty()
+            var from = $("#entry-revision-from").empty()
+            var to = $("#entry-revision-to").empty()
+
+            $("#entry-revision-diff").empty()
+
+            to.append('<option value="0">Current entry</option>')
+
+            if (result.revisions.length == 0) {
+                $("#entry-revisions-empty").removeClass("hidden")
+                return
+            }
+
+            $("#entry-revisions-empty").addClass("hidden")
+
+            $.each(result.revisions, function (index, revision) {
+
+                var author = revision.Username != "" ? revision.Username : "Unknown user"
+                var label = "#" + revision.Id + " " + revision.CreatedDate
+
+                list.append(
+                    '<li class="border border-[#EDEDED] rounded-[4px] p-[8px_12px] list-none">' +
+                    '<p class="text-sm text-bold-black font-normal mb-[2px] line-clamp-1">' + escapeRevisionText(revision.Title) + '</p>' +
+                    '<p class="text-[12px] text-[#717171] mb-[6px]">' + escapeRevisionText(author) + " · " + escapeRevisionText(revision.CreatedDate) + '</p>' +
+                    '<a href="javascript:void(0);" class="entry-revision-restore text-[12px] text-[#10A37F] no-underline" data-id="' + revision.Id + '">Restore</a>' +
+                    '</li>')
+
+                from.append('<option value="' + revision.Id + '">' + escapeRevisionText(label) + '</option>')
+                to.append('<option value="' + revision.Id + '">' + escapeRevisionText(label) + '</option>')
+            })
+
+            // the previous save against the entry as it is now
+            from.val(result.revisions[result.revisions.length > 1 ? 1 : 0].Id)
+            to.val("0")
+
+            compareEntryRevisions()
+        }
+    })
+}
+
+function compareEntryRevisions() {
+
+    $.ajax({
+        url: "/channel/revisiondiff/" + $("#eid").val(),
+        type: "GET",
+        dataType: "json",
+        data: { "from": $("#entry-revision-from").val(), "to": $("#entry-revision-to").val() },
+        success: function (result) {
+
+            entryRevisionChanges = result.changes
+
+            renderEntryRevisionDiff()
+        }
+    })
+}
+
+function renderEntryRevisionDiff() {
+
+    var showUnchanged = $("#entry-revision-unchanged").is(":checked")
+    var diff = $("#entry-revision-diff").empty()
+    var changed = 0
+
+    var cellClass = {
+        "same": ["", ""],
+        "changed": ["bg-[#FFF1ED]", "bg-[#E7F6F2]"],
+        "removed": ["bg-[#FFF1ED]", ""],
+        "added": ["", "bg-[#E7F6F2]"]
+    }
+
+    $.each(entryRevisionChanges, function (index, change) {
+
+        if (change.changed) {
+            changed++
+        }
+
+        if (!change.changed && !showUnchanged) {
+            return
+        }
+
+        var rows = ""
+
+        $.each(change.rows || [], function (rowIndex, row) {
+
+            rows += '<tr>' +
+                '<td class="align-top w-1/2 p-[6px_8px] text-[12px] text-[#262626] break-all border-r border-[#EDEDED] ' + cellClass[row.kind][0] + '">' + escapeRevisionText(row.old) + '</td>' +
+                '<td class="align-top w-1/2 p-[6px_8px] text-[12px] text-[#262626] break-all ' + cellClass[row.kind][1] + '">' + escapeRevisionText(row.new) + '</td>' +
+                '</tr>'
+        })
+
+        if (rows == "") {
+            rows = '<tr><td colspan="2" class="p-[6px_8px] text-[12px] text-[#B2B2B2]">Empty</td></tr>'
+        }
+
+        diff.append(
+            '<div class="border border-[#EDEDED] rounded-[4px] mb-[12px]">' +
+            '<p class="text-sm font-medium text-bold-black bg-[#F7F7F5] p-[6px_8px] mb-0">' + escapeRevisionText(change.label) + '</p>' +
+            '<table class="w-full table-fixed">' + rows + '</table>' +
+            '</div>')
+    })
+
+    if (changed == 0 && !showUnchanged) {
+        diff.append('<p class="text-sm text-[#717171]">The revisions are the same</p>')
+    }
+}
+
+$(document).on("show.bs.modal", "#entryhistorymodal", function () {
+
+    loadEntryRevisions()
+})
+
+$(document).on("click", "#entry-revision-compare", function () {
+
+    compareEntryRevisions()
+})
+
+$(document).on("change", "#entry-revision-unchanged", function () {
+
+    renderEntryRevisionDiff()
+})
+
+$(document).on("click", ".entry-revision-restore", function () {
+
+    if (!confirm("Restore revision #" + $(this).attr("data-id") + "? Unsaved changes in the editor are lost.")) {
+        return
+    }
+
+    $.ajax({
+        url: "/channel/restorerevision/" + $("#eid").val(),
+        type: "POST",
+        dataType: "json",
+        data: { "revisionid": $(this).attr("data-id"), csrf: $("input[name='csrf']").val() },
+        success: function (result) {
+
+            if (result.status == 1) {
+                window.location.reload()
+            }
+        }
+    })
+})
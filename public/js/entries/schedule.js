@@ -0,0 +1,56 @@
+// publish and unpublish times of the entry editor, the admin scheduler flips the entry status when they are due
+
+function renderEntrySchedules(schedules) {
+
+    var log = $("#entry-schedule-log").empty()
+
+    if (!schedules || schedules.length == 0) {
+        $("#entry-schedules").addClass("hidden")
+        return
+    }
+
+    $("#entry-schedules").removeClass("hidden")
+
+    $.each(schedules, function (index, job) {
+
+        var text = (job.Action == "publish" ? "Publish" : "Unpublish") + " at " + job.RunDate + " · " + job.Status
+
+        if (job.Message != "") {
+            text += " (" + job.Message + ")"
+        }
+
+        if (job.RunOnDate != "") {
+            text += " · " + job.RunOnDate
+        }
+
+        log.append('<li class="text-[12px] text-[#717171] list-none">' + $("<div>").text(text).html() + '</li>')
+    })
+}
+
+function showScheduleError(xhr) {
+
+    if (!xhr.responseJSON || !xhr.responseJSON.error) {
+        return
+    }
+
+    // someone else saved the entry since it was opened
+    if (xhr.responseJSON.conflict) {
+        showEntryConflict(xhr.responseJSON)
+        return
+    }
+
+    // refused by the review workflow of the channel, not by the schedule
+    if (xhr.responseJSON.workflow) {
+        showEntryWorkflowError(xhr.responseJSON.error)
+        return
+    }
+
+    $("#schedule-error").text(xhr.responseJSON.error).removeClass("hidden")
+    $('.editor-tabs').removeClass('translate-x-[100%]');
+    $('#editingArea').addClass('mr-[387px] w-full ');
+}
+
+$(document).on("change", "#publishat, #unpublishat", function () {
+
+    $("#schedule-error").addClass("hidden")
+})
@@ -0,0 +1,133 @@
+// review workflow of the entry editor, shown for the channels that require a review before publishing
+
+var entryWorkflowActions = {
+    "submit": "Submit for Review",
+    "approve": "Approve",
+    "reject": "Reject",
+    "publish": "Publish",
+    "unpublish": "Unpublish"
+}
+
+function loadEntryWorkflow() {
+
+    var entryid = $("#eid").val()
+
+    if (entryid == "" || entryid == "0") {
+        return
+    }
+
+    $.ajax({
+        url: "/channel/workflow/" + entryid,
+        type: "GET",
+        dataType: "json",
+        success: function (result) {
+
+            if (result.workflow == "") {
+                $("#entry-workflow").addClass("hidden")
+                return
+            }
+
+            $("#entry-workflow").removeClass("hidden")
+            $("#entry-workflow-state").text(result.label)
+            $("#entry-workflow-label").text(result.label)
+
+            var actions = $("#entry-workflow-actions").empty()
+
+            $.each(result.actions, function (index, transition) {
+
+                var color = transition.action == "reject" ? "text-[#F26674] border border-[#F26674] bg-white hover:bg-[#FFF1ED]" : "text-white bg-[#10A37F] hover:bg-[#148569] hover:text-white"
+
+                actions.append('<a href="javascript:void(0);" class="entry-workflow-action h-8 flex items-center justify-center px-3 text-sm font-normal rounded-[4px] no-underline ' + color + '" data-action="' + transition.action + '">' + entryWorkflowActions[transition.action] + '</a>')
+            })
+
+            var history = $("#entry-workflow-history").empty()
+
+            if (result.history.length == 0) {
+                $("#entry-workflow-empty").removeClass("hidden")
+                return
+            }
+
+            $("#entry-workflow-empty").addClass("hidden")
+
+            $.each(result.history, function (index, log) {
+
+                var author = log.Username != "" ? log.Username : "Unknown user"
+
+                var item = $('<li class="border border-[#EDEDED] rounded-[4px] p-[8px_12px] list-none"></li>')
+
+                item.append($('<p class="text-sm text-bold-black font-normal mb-[2px]"></p>').text(log.FromLabel + " → " + log.ToLabel))
+                item.append($('<p class="text-[12px] text-[#717171] mb-0"></p>').text(author + " · " + log.CreatedDate))
+
+                if (log.Comment != "") {
+                    item.append($('<p class="text-[12px] text-[#262626] mt-[4px] mb-0 whitespace-pre-line"></p>').text(log.Comment))
+                }
+
+                history.append(item)
+            })
+        }
+    })
+}
+
+// opens the review panel with the reason a save was refused
+function showEntryWorkflowError(message) {
+
+    // new entries have no review panel yet
+    if ($("#entry-workflow").length == 0) {
+        $("#schedule-error").text(message).removeClass("hidden")
+        $('.editor-tabs').removeClass('translate-x-[100%]');
+        $('#editingArea').addClass('mr-[387px] w-full ');
+        return
+    }
+
+    if ($("#entry-workflow-label").text() == "") {
+        $("#entry-workflow-label").text("Draft")
+        $("#entry-workflow-empty").removeClass("hidden")
+    }
+
+    $("#entry-workflow").trigger("click")
+
+    $("#entry-workflow-error").text(message).removeClass("hidden")
+}
+
+$(document).ready(function () {
+
+    loadEntryWorkflow()
+})
+
+$(document).on("show.bs.modal", "#entryworkflowmodal", function () {
+
+    $("#entry-workflow-error").addClass("hidden")
+
+    loadEntryWorkflow()
+})
+
+$(document).on("click", ".entry-workflow-action", function () {
+
+    $("#entry-workflow-error").addClass("hidden")
+
+    $.ajax({
+        url: "/channel/transition/" + $("#eid").val(),
+        type: "POST",
+        dataType: "json",
+        data: {
+            "action": $(this).attr("data-action"),
+            "comment": $("#entry-workflow-comment").val(),
+            csrf: $("input[name='csrf']").val()
+        },
+        success: function () {
+
+            window.location.reload()
+        },
+        error: function (xhr) {
+
+            var message = xhr.responseJSON && xhr.responseJSON.error ? xhr.responseJSON.error : "Internal server error"
+
+            $("#entry-workflow-error").text(message).removeClass("hidden")
+
+            // someone else moved the entry, show where it is now
+            if (xhr.status == 409) {
+                loadEntryWorkflow()
+            }
+        }
+    })
+})
@@ -284,6 +284,17 @@ $(document).on('click','#update',function(){
 
 })
 
+$(document).on('click','.emailverify',function(){
+
+    if ($(this).prop('checked')){
+
+        $('.emailverification').val('1')
+    }else{
+
+        $('.emailverification').val('0')
+    }
+})
+
 $(document).on('click','.allowregis',function(){
 
 
@@ -119,6 +119,8 @@ $(document).on('click', '#settingsUpdate[data-status=1]', function () {
     timeZones = $.trim($('#timezoneText').text())
     formData.append('timezon', timeZones)
 
+    formData.append('imagepresets', $('#imagePresets').val())
+
     $.ajax({
         url: "/settings/general-settings/update",
         type: "POST",
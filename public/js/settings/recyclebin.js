@@ -0,0 +1,86 @@
+// restore and permanent delete of the records in the recycle bin
+
+function recycleBinRequest(url, data) {
+
+    data.csrf = $("input[name='csrf']").val()
+
+    $.ajax({
+        url: url,
+        type: "POST",
+        dataType: "json",
+        data: data,
+        success: function () {
+            window.location.reload()
+        },
+        error: function (xhr) {
+
+            var error = xhr.responseJSON && xhr.responseJSON.error ? xhr.responseJSON.error : "Something went wrong, please try again"
+
+            $("#recyclebin-error").text(error).removeClass("hidden")
+        }
+    })
+}
+
+function recycleBinSelected() {
+
+    return $(".recyclebin-check:checked").map(function () {
+        return $(this).val()
+    }).get()
+}
+
+$(document).on("click", ".recyclebin-restore", function () {
+
+    recycleBinRequest("/settings/recyclebin/restore", { module: $(this).attr("data-module"), id: $(this).attr("data-id") })
+})
+
+$(document).on("click", ".recyclebin-delete", function () {
+
+    if (!confirm("Delete this record permanently? It can not be restored afterwards.")) {
+        return
+    }
+
+    recycleBinRequest("/settings/recyclebin/delete", { module: $(this).attr("data-module"), ids: [$(this).attr("data-id")] })
+})
+
+$(document).on("click", "#recyclebin-delete-selected", function () {
+
+    var ids = recycleBinSelected()
+
+    if (ids.length == 0 || !confirm("Delete the " + ids.length + " selected records permanently? They can not be restored afterwards.")) {
+        return
+    }
+
+    recycleBinRequest("/settings/recyclebin/delete", { module: $(this).attr("data-module"), ids: ids })
+})
+
+$(document).on("change", "#recyclebin-check-all", function () {
+
+    $(".recyclebin-check").prop("checked", $(this).prop("checked"))
+
+    $("#recyclebin-delete-selected").toggleClass("hidden", recycleBinSelected().length == 0)
+})
+
+$(document).on("change", ".recyclebin-check", function () {
+
+    $("#recyclebin-check-all").prop("checked", $(".recyclebin-check").length == recycleBinSelected().length)
+
+    $("#recyclebin-delete-selected").toggleClass("hidden", recycleBinSelected().length == 0)
+})
+
+$(document).on("click", ".searchClosebtn", function () {
+
+    window.location.href = "/settings/recyclebin/?module=" + $("input[name='module']").val()
+})
+
+$(document).on("click", ".Closebtn", function () {
+
+    $(".search").val('')
+    $(".Closebtn").addClass("hidden")
+    $(".srchBtn-togg").removeClass("pointer-events-none")
+})
+
+$(document).on("input", ".search", function () {
+
+    $(".Closebtn").toggleClass("hidden", $(this).val().length == 0)
+    $(".srchBtn-togg").toggleClass("pointer-events-none", $(this).val().length != 0)
+})
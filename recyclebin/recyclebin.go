@@ -0,0 +1,165 @@
+package recyclebin
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// modules with soft-deleted records shown in the recycle bin
+const (
+	Entries      = "entries"
+	Channels     = "channels"
+	Categories   = "categories"
+	Members      = "members"
+	MemberGroups = "membergroups"
+	Users        = "users"
+)
+
+const (
+	// deleted records are kept until they are deleted permanently when the tenant sets no purge
+	DefaultPurgeDays = 0
+
+	MaxPurgeDays = 3650
+
+	DefaultInterval = time.Hour
+)
+
+var ErrPurgeDays = errors.New("the days before purging have to be a number from 0 to 3650")
+
+// Module is a tab of the recycle bin
+type Module struct {
+	Key   string
+	Label string
+}
+
+var Modules = []Module{
+	{Key: Entries, Label: "Entries"},
+	{Key: Channels, Label: "Channels"},
+	{Key: Categories, Label: "Categories"},
+	{Key: Members, Label: "Members"},
+	{Key: MemberGroups, Label: "Member Groups"},
+	{Key: Users, Label: "Users"},
+}
+
+// Find returns the module of a key, the first module when the key is unknown
+func Find(key string) (Module, bool) {
+
+	for _, module := range Modules {
+
+		if module.Key == key {
+
+			return module, true
+		}
+	}
+
+	return Modules[0], false
+}
+
+// ParsePurgeDays reads the days deleted records are kept before they are purged, 0 keeps them
+func ParsePurgeDays(value string) (int, error) {
+
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+
+		return DefaultPurgeDays, nil
+	}
+
+	days, err := strconv.Atoi(value)
+
+	if err != nil || days < 0 || days > MaxPurgeDays {
+
+		return 0, ErrPurgeDays
+	}
+
+	return days, nil
+}
+
+// PurgeBefore returns the time records deleted before are purged, false when the tenant keeps them
+func PurgeBefore(now time.Time, days int) (time.Time, bool) {
+
+	if days <= 0 {
+
+		return time.Time{}, false
+	}
+
+	return now.AddDate(0, 0, -days), true
+}
+
+// Relink puts the categories a deletion stripped from an entry back into its current categories. The ids keep the
+// order they had before the deletion and the ids added since stay at the end.
+func Relink(current string, original string, removed []int) string {
+
+	currentIds, originalIds := Ids(current), Ids(original)
+
+	var ids []string
+
+	seen := make(map[int]bool)
+
+	for _, id := range originalIds {
+
+		if !seen[id] && (contains(removed, id) || contains(currentIds, id)) {
+
+			seen[id] = true
+
+			ids = append(ids, strconv.Itoa(id))
+		}
+	}
+
+	for _, id := range currentIds {
+
+		if !seen[id] {
+
+			seen[id] = true
+
+			ids = append(ids, strconv.Itoa(id))
+		}
+	}
+
+	return strings.Join(ids, ",")
+}
+
+// Ids reads a comma separated list of category ids, anything else in it is skipped
+func Ids(value string) []int {
+
+	var ids []int
+
+	for _, part := range strings.Split(value, ",") {
+
+		if id, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// Linked reports whether a comma separated list of category ids has any of the ids
+func Linked(value string, ids []int) bool {
+
+	for _, id := range Ids(value) {
+
+		if contains(ids, id) {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(ids []int, id int) bool {
+
+	for _, value := range ids {
+
+		if value == id {
+
+			return true
+		}
+	}
+
+	return false
+}
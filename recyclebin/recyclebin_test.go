@@ -0,0 +1,69 @@
+package recyclebin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePurgeDays(t *testing.T) {
+
+	for value, expected := range map[string]int{"": 0, "0": 0, " 30 ": 30, "3650": 3650} {
+
+		if days, err := ParsePurgeDays(value); err != nil || days != expected {
+			t.Fatalf("expected %q to be %d days, got %d %v", value, expected, days, err)
+		}
+	}
+
+	for _, value := range []string{"-1", "3651", "thirty"} {
+
+		if _, err := ParsePurgeDays(value); err != ErrPurgeDays {
+			t.Fatalf("expected %q to be refused, got %v", value, err)
+		}
+	}
+}
+
+func TestPurgeBefore(t *testing.T) {
+
+	now := time.Date(2024, 5, 31, 10, 0, 0, 0, time.UTC)
+
+	if before, ok := PurgeBefore(now, 30); !ok || !before.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected records deleted before 1 May to be purged, got %v %v", before, ok)
+	}
+
+	if _, ok := PurgeBefore(now, 0); ok {
+		t.Fatalf("expected no purge without days")
+	}
+}
+
+func TestRelink(t *testing.T) {
+
+	if relinked := Relink("1,7", "1,2,3,7", []int{2, 3}); relinked != "1,2,3,7" {
+		t.Fatalf("expected the stripped categories back in place, got %q", relinked)
+	}
+
+	if relinked := Relink("7,9", "1,2,3,7", []int{2, 3}); relinked != "2,3,7,9" {
+		t.Fatalf("expected categories removed since to stay removed and new ones kept, got %q", relinked)
+	}
+
+	if relinked := Relink("", "4,5", []int{4, 5}); relinked != "4,5" {
+		t.Fatalf("expected an entry left without categories to get them back, got %q", relinked)
+	}
+}
+
+func TestLinked(t *testing.T) {
+
+	if !Linked("1, 2,3", []int{3}) || Linked("1,2", []int{3}) || Linked("", []int{3}) {
+		t.Fatalf("expected only lists with one of the ids to be linked")
+	}
+}
+
+func TestFind(t *testing.T) {
+
+	if module, ok := Find(MemberGroups); !ok || module.Label != "Member Groups" {
+		t.Fatalf("expected the member groups module, got %+v %v", module, ok)
+	}
+
+	if module, ok := Find("pages"); ok || module.Key != Entries {
+		t.Fatalf("expected an unknown module to fall back to entries, got %+v %v", module, ok)
+	}
+}
@@ -0,0 +1,286 @@
+package revisions
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// revisions kept per entry when its channel has no limit configured
+	DefaultLimit = 50
+
+	// most revisions a channel may keep per entry
+	MaxLimit = 500
+
+	// line pairs compared before a diff falls back to showing both sides as changed
+	maxDiffCells = 250000
+)
+
+// kinds of the rows of a side-by-side diff
+const (
+	Same    = "same"
+	Changed = "changed"
+	Added   = "added"
+	Removed = "removed"
+)
+
+// Snapshot is the saved state of an entry. The category names are kept for the diff, the ids for restoring.
+type Snapshot struct {
+	Title           string  `json:"title"`
+	Description     string  `json:"description"`
+	CoverImage      string  `json:"coverImage"`
+	Excerpt         string  `json:"excerpt"`
+	Author          string  `json:"author"`
+	Tags            string  `json:"tags"`
+	ReadingTime     int     `json:"readingTime"`
+	CategoryIds     string  `json:"categoryIds"`
+	Categories      string  `json:"categories"`
+	MetaTitle       string  `json:"metaTitle"`
+	MetaDescription string  `json:"metaDescription"`
+	Keyword         string  `json:"keyword"`
+	ImageAltTag     string  `json:"imageAltTag"`
+	Fields          []Field `json:"fields"`
+}
+
+// Field is the value of an additional field of the entry's channel
+type Field struct {
+	FieldId int    `json:"fieldId"`
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+}
+
+// Row is a line of a side-by-side diff, the side a line is missing from is empty
+type Row struct {
+	Kind string `json:"kind"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// Change is the side-by-side diff of one field of two snapshots
+type Change struct {
+	Field   string `json:"field"`
+	Label   string `json:"label"`
+	Changed bool   `json:"changed"`
+	Rows    []Row  `json:"rows"`
+}
+
+// Limit returns the revisions kept per entry for a channel's configured limit
+func Limit(configured int) int {
+
+	if configured <= 0 {
+
+		return DefaultLimit
+	}
+
+	if configured > MaxLimit {
+
+		return MaxLimit
+	}
+
+	return configured
+}
+
+// Compare diffs every field of two snapshots, the entry fields first and the additional fields after them in the
+// order of the newer snapshot. Additional fields only one of the snapshots has are compared against an empty value.
+func Compare(older, newer Snapshot) []Change {
+
+	changes := []Change{
+		compare("title", "Title", older.Title, newer.Title),
+		compare("description", "Description", older.Description, newer.Description),
+		compare("coverImage", "Cover Image", older.CoverImage, newer.CoverImage),
+		compare("excerpt", "Excerpt", older.Excerpt, newer.Excerpt),
+		compare("author", "Author", older.Author, newer.Author),
+		compare("tags", "Tags", older.Tags, newer.Tags),
+		compare("readingTime", "Reading Time", readingTime(older.ReadingTime), readingTime(newer.ReadingTime)),
+		compare("categories", "Categories", older.Categories, newer.Categories),
+		compare("metaTitle", "Meta Title", older.MetaTitle, newer.MetaTitle),
+		compare("metaDescription", "Meta Description", older.MetaDescription, newer.MetaDescription),
+		compare("keyword", "Meta Keywords", older.Keyword, newer.Keyword),
+		compare("imageAltTag", "Image Alt Tag", older.ImageAltTag, newer.ImageAltTag),
+	}
+
+	olderFields := make(map[int]Field, len(older.Fields))
+
+	for _, field := range older.Fields {
+
+		olderFields[field.FieldId] = field
+	}
+
+	compared := make(map[int]bool, len(newer.Fields))
+
+	for _, field := range newer.Fields {
+
+		compared[field.FieldId] = true
+
+		changes = append(changes, compare("field-"+strconv.Itoa(field.FieldId), field.Name, olderFields[field.FieldId].Value, field.Value))
+	}
+
+	for _, field := range older.Fields {
+
+		if compared[field.FieldId] {
+
+			continue
+		}
+
+		changes = append(changes, compare("field-"+strconv.Itoa(field.FieldId), field.Name, field.Value, ""))
+	}
+
+	return changes
+}
+
+func readingTime(minutes int) string {
+
+	if minutes == 0 {
+
+		return ""
+	}
+
+	return strconv.Itoa(minutes)
+}
+
+func compare(field, label, older, newer string) Change {
+
+	return Change{Field: field, Label: label, Changed: older != newer, Rows: Lines(older, newer)}
+}
+
+// Lines diffs two values line by line. Rich text is split after its block elements so that an edited paragraph
+// shows as one changed row instead of the whole description.
+func Lines(older, newer string) []Row {
+
+	oldLines, newLines := split(older), split(newer)
+
+	if len(oldLines)*len(newLines) > maxDiffCells {
+
+		return pair(oldLines, newLines)
+	}
+
+	// common[i][j] is the length of the longest common subsequence of oldLines[i:] and newLines[j:]
+	common := make([][]int, len(oldLines)+1)
+
+	for i := range common {
+
+		common[i] = make([]int, len(newLines)+1)
+	}
+
+	for i := len(oldLines) - 1; i >= 0; i-- {
+
+		for j := len(newLines) - 1; j >= 0; j-- {
+
+			if oldLines[i] == newLines[j] {
+
+				common[i][j] = common[i+1][j+1] + 1
+
+			} else if common[i+1][j] >= common[i][j+1] {
+
+				common[i][j] = common[i+1][j]
+
+			} else {
+
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	var (
+		rows               []Row
+		removed, added     []string
+		oldIndex, newIndex int
+	)
+
+	flush := func() {
+
+		rows = append(rows, pair(removed, added)...)
+
+		removed, added = nil, nil
+	}
+
+	for oldIndex < len(oldLines) || newIndex < len(newLines) {
+
+		switch {
+
+		case oldIndex < len(oldLines) && newIndex < len(newLines) && oldLines[oldIndex] == newLines[newIndex]:
+
+			flush()
+
+			rows = append(rows, Row{Kind: Same, Old: oldLines[oldIndex], New: newLines[newIndex]})
+
+			oldIndex++
+
+			newIndex++
+
+		case newIndex == len(newLines) || (oldIndex < len(oldLines) && common[oldIndex+1][newIndex] >= common[oldIndex][newIndex+1]):
+
+			removed = append(removed, oldLines[oldIndex])
+
+			oldIndex++
+
+		default:
+
+			added = append(added, newLines[newIndex])
+
+			newIndex++
+		}
+	}
+
+	flush()
+
+	return rows
+}
+
+// pair lines up the removed and added lines of a hunk side by side
+func pair(removed, added []string) []Row {
+
+	var rows []Row
+
+	for index := 0; index < len(removed) || index < len(added); index++ {
+
+		switch {
+
+		case index >= len(added):
+
+			rows = append(rows, Row{Kind: Removed, Old: removed[index]})
+
+		case index >= len(removed):
+
+			rows = append(rows, Row{Kind: Added, New: added[index]})
+
+		default:
+
+			rows = append(rows, Row{Kind: Changed, Old: removed[index], New: added[index]})
+		}
+	}
+
+	return rows
+}
+
+var blockBreaks = strings.NewReplacer(
+	"</p>", "</p>\n",
+	"</div>", "</div>\n",
+	"</li>", "</li>\n",
+	"</h1>", "</h1>\n",
+	"</h2>", "</h2>\n",
+	"</h3>", "</h3>\n",
+	"</h4>", "</h4>\n",
+	"</h5>", "</h5>\n",
+	"</h6>", "</h6>\n",
+	"</blockquote>", "</blockquote>\n",
+	"</pre>", "</pre>\n",
+	"<br>", "<br>\n",
+	"<br/>", "<br/>\n",
+	"<br />", "<br />\n",
+)
+
+func split(value string) []string {
+
+	var lines []string
+
+	for _, line := range strings.Split(blockBreaks.Replace(value), "\n") {
+
+		if line = strings.TrimSpace(line); line != "" {
+
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
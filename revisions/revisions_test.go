@@ -0,0 +1,66 @@
+package revisions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+
+	rows := Lines("<p>intro</p><p>old body</p><p>outro</p><p>dropped</p>", "<p>intro</p><p>new body</p><p>outro</p>")
+
+	expected := []Row{
+		{Kind: Same, Old: "<p>intro</p>", New: "<p>intro</p>"},
+		{Kind: Changed, Old: "<p>old body</p>", New: "<p>new body</p>"},
+		{Kind: Same, Old: "<p>outro</p>", New: "<p>outro</p>"},
+		{Kind: Removed, Old: "<p>dropped</p>"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("unexpected rows %+v", rows)
+	}
+
+	if rows := Lines("", "first\nsecond"); !reflect.DeepEqual(rows, []Row{{Kind: Added, New: "first"}, {Kind: Added, New: "second"}}) {
+		t.Fatalf("unexpected rows of a new value %+v", rows)
+	}
+
+	if rows := Lines("", ""); len(rows) != 0 {
+		t.Fatalf("expected no rows for empty values, got %+v", rows)
+	}
+}
+
+func TestCompare(t *testing.T) {
+
+	older := Snapshot{Title: "Draft", Categories: "News", Fields: []Field{{FieldId: 1, Name: "Subtitle", Value: "a"}, {FieldId: 2, Name: "Source", Value: "b"}}}
+
+	newer := Snapshot{Title: "Final", Categories: "News", ReadingTime: 3, Fields: []Field{{FieldId: 3, Name: "Video", Value: "c"}, {FieldId: 1, Name: "Subtitle", Value: "a"}}}
+
+	changed := make(map[string]bool)
+
+	var fields []string
+
+	for _, change := range Compare(older, newer) {
+
+		changed[change.Field] = change.Changed
+
+		fields = append(fields, change.Field)
+	}
+
+	for field, expected := range map[string]bool{"title": true, "categories": false, "readingTime": true, "field-1": false, "field-2": true, "field-3": true} {
+
+		if changed[field] != expected {
+			t.Fatalf("expected %v to be changed %v", field, expected)
+		}
+	}
+
+	if tail := fields[len(fields)-3:]; !reflect.DeepEqual(tail, []string{"field-3", "field-1", "field-2"}) {
+		t.Fatalf("unexpected additional field order %v", tail)
+	}
+}
+
+func TestLimit(t *testing.T) {
+
+	if Limit(0) != DefaultLimit || Limit(-1) != DefaultLimit || Limit(10) != 10 || Limit(MaxLimit+1) != MaxLimit {
+		t.Fatalf("unexpected limits")
+	}
+}
@@ -172,12 +172,38 @@ func SetupRoutes() *gin.Engine {
 
 	CE.GET("/draftentrieslist/:id", controllers.Entries)
 
+	CE.GET("/reviewentries", controllers.AllEntries)
+
+	CE.GET("/approvedentries", controllers.AllEntries)
+
+	CE.GET("/reviewentrieslist/:id", controllers.Entries)
+
+	CE.GET("/approvedentrieslist/:id", controllers.Entries)
+
 	CE.POST("/entryisactive", controllers.EntryIsActive)
 
 	CE.POST("/entryparentidupdate/:id", controllers.EntryParentIdUpdate)
 
 	CE.POST("/reorder", controllers.EntryReorder)
 
+	CE.GET("/relatedentries", controllers.RelatedEntrySearch)
+
+	CE.POST("/previewtoken/:id", controllers.EntryPreviewToken)
+
+	CE.GET("/revisions/:id", controllers.EntryRevisions)
+
+	CE.GET("/revisiondiff/:id", controllers.EntryRevisionDiff)
+
+	CE.POST("/restorerevision/:id", controllers.RestoreEntryRevision)
+
+	CE.GET("/workflow/:id", controllers.EntryWorkflow)
+
+	CE.POST("/transition/:id", controllers.TransitionEntryWorkflow)
+
+	CE.POST("/lock/:id", controllers.EntryLock)
+
+	CE.POST("/unlock/:id", controllers.ReleaseEntryLock)
+
 	CE.POST("/updatepermissionmembergroupid", controllers.UpdateAccPermissionMembergroupId)
 
 	/*channels module*/
@@ -274,6 +300,8 @@ func SetupRoutes() *gin.Engine {
 
 	M.GET("/updatemember/:id", controllers.EditMember)
 
+	M.GET("/notes/:id", controllers.MemberNotesHighlights)
+
 	M.POST("/updatemember", controllers.UpdateMember)
 
 	M.GET("/deletemember", controllers.DeleteMember)
@@ -445,6 +473,17 @@ func SetupRoutes() *gin.Engine {
 
 	GS.POST("/update", controllers.UpdateGeneralSettings)
 
+	/*Recycle Bin*/
+	RB := S.Group("/recyclebin")
+
+	RB.GET("/", controllers.RecycleBin)
+
+	RB.POST("/restore", controllers.RestoreRecycleBin)
+
+	RB.POST("/delete", controllers.DeleteRecycleBin)
+
+	RB.POST("/settings", controllers.UpdateRecycleBinSettings)
+
 	/*System Module*/
 	SS := S.Group("/personalize")
 
@@ -0,0 +1,212 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// what a scheduled job does to its entry
+const (
+	ActionPublish   = "publish"
+	ActionUnpublish = "unpublish"
+)
+
+// states of a scheduled job, a job is pending until an instance claims it and done or failed once it ran
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusDone      = "done"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+const (
+	// datetime-local layout of the editor inputs
+	InputLayout = "2006-01-02T15:04"
+
+	DefaultInterval = 30 * time.Second
+
+	// a claim older than this is taken to be left by an instance that stopped while running the job
+	DefaultClaimTimeout = 5 * time.Minute
+
+	// jobs claimed per run
+	batchSize = 50
+)
+
+var ErrUnpublishBeforePublish = errors.New("the unpublish time has to be after the publish time")
+
+// Job is a publish or unpublish of an entry due at RunAt
+type Job struct {
+	Id       int
+	EntryId  int
+	Action   string
+	RunAt    time.Time
+	TenantId int
+}
+
+// Store keeps the jobs. Claim has to hand a job to one instance only, however many ask at once.
+type Store interface {
+	Claim(instance string, now time.Time, staleBefore time.Time, limit int) ([]Job, error)
+	Apply(job Job, now time.Time) (channelId int, message string, err error)
+	Finish(job Job, instance string, now time.Time, message string, failed bool) error
+}
+
+// Scheduler runs the due jobs of a store
+type Scheduler struct {
+	Store        Store
+	Instance     string
+	Interval     time.Duration
+	ClaimTimeout time.Duration
+
+	// called after a job changed the status of its entry
+	Applied func(job Job, channelId int)
+
+	// called when the store itself fails
+	Failed func(err error)
+}
+
+// Status returns the entry status an action sets
+func Status(action string) int {
+
+	if action == ActionPublish {
+
+		return 1
+	}
+
+	return 2
+}
+
+// ParseInput reads a datetime-local input in the tenant's location, false when the input is empty
+func ParseInput(value string, location *time.Location) (time.Time, bool, error) {
+
+	if value == "" {
+
+		return time.Time{}, false, nil
+	}
+
+	if location == nil {
+
+		location = time.UTC
+	}
+
+	parsed, err := time.ParseInLocation(InputLayout, value, location)
+
+	if err != nil {
+
+		return time.Time{}, false, err
+	}
+
+	return parsed.UTC(), true, nil
+}
+
+// FormatInput writes a time for a datetime-local input in the tenant's location
+func FormatInput(value time.Time, location *time.Location) string {
+
+	if value.IsZero() {
+
+		return ""
+	}
+
+	if location == nil {
+
+		location = time.UTC
+	}
+
+	return value.In(location).Format(InputLayout)
+}
+
+// Validate checks the publish and unpublish times of an entry, either may be zero
+func Validate(publishAt, unpublishAt time.Time) error {
+
+	if !publishAt.IsZero() && !unpublishAt.IsZero() && !unpublishAt.After(publishAt) {
+
+		return ErrUnpublishBeforePublish
+	}
+
+	return nil
+}
+
+// RunOnce claims the due jobs and runs them, returning how many ran. Jobs that were due while no instance ran are
+// caught up on the next run.
+func (scheduler *Scheduler) RunOnce(now time.Time) int {
+
+	timeout := scheduler.ClaimTimeout
+
+	if timeout <= 0 {
+
+		timeout = DefaultClaimTimeout
+	}
+
+	jobs, err := scheduler.Store.Claim(scheduler.Instance, now, now.Add(-timeout), batchSize)
+
+	if err != nil {
+
+		scheduler.fail(err)
+
+		return 0
+	}
+
+	for _, job := range jobs {
+
+		channelId, message, err := scheduler.Store.Apply(job, now)
+
+		failed := err != nil
+
+		if failed {
+
+			message = err.Error()
+		}
+
+		if err := scheduler.Store.Finish(job, scheduler.Instance, now, message, failed); err != nil {
+
+			scheduler.fail(err)
+		}
+
+		if !failed && channelId != 0 && scheduler.Applied != nil {
+
+			scheduler.Applied(job, channelId)
+		}
+	}
+
+	return len(jobs)
+}
+
+// Run runs the due jobs every interval until the context is done
+func (scheduler *Scheduler) Run(ctx context.Context) {
+
+	interval := scheduler.Interval
+
+	if interval <= 0 {
+
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+
+	defer ticker.Stop()
+
+	for {
+
+		// a full batch may leave more due jobs behind
+		for scheduler.RunOnce(time.Now().UTC()) == batchSize {
+		}
+
+		select {
+
+		case <-ctx.Done():
+
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+func (scheduler *Scheduler) fail(err error) {
+
+	if scheduler.Failed != nil {
+
+		scheduler.Failed(err)
+	}
+}
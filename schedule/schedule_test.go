@@ -0,0 +1,208 @@
+package schedule
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeJob struct {
+	job       Job
+	status    string
+	claimedBy string
+	claimedOn time.Time
+	message   string
+}
+
+type fakeStore struct {
+	mu      sync.Mutex
+	jobs    []*fakeJob
+	applied map[int]int
+	fail    map[int]bool
+}
+
+func (store *fakeStore) Claim(instance string, now time.Time, staleBefore time.Time, limit int) ([]Job, error) {
+
+	store.mu.Lock()
+
+	defer store.mu.Unlock()
+
+	var claimed []Job
+
+	for _, job := range store.jobs {
+
+		stale := job.status == StatusRunning && job.claimedOn.Before(staleBefore)
+
+		if job.job.RunAt.After(now) || (job.status != StatusPending && !stale) || len(claimed) == limit {
+			continue
+		}
+
+		job.status, job.claimedBy, job.claimedOn = StatusRunning, instance, now
+
+		claimed = append(claimed, job.job)
+	}
+
+	return claimed, nil
+}
+
+func (store *fakeStore) Apply(job Job, now time.Time) (int, string, error) {
+
+	store.mu.Lock()
+
+	defer store.mu.Unlock()
+
+	if store.fail[job.Id] {
+		return 0, "", errors.New("entry not found")
+	}
+
+	store.applied[job.Id]++
+
+	return 7, job.Action + "ed", nil
+}
+
+func (store *fakeStore) Finish(job Job, instance string, now time.Time, message string, failed bool) error {
+
+	store.mu.Lock()
+
+	defer store.mu.Unlock()
+
+	for _, stored := range store.jobs {
+
+		if stored.job.Id == job.Id && stored.claimedBy == instance {
+
+			stored.status, stored.message = StatusDone, message
+
+			if failed {
+				stored.status = StatusFailed
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestRunOnceRunsDueJobsOnce(t *testing.T) {
+
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	store := &fakeStore{applied: map[int]int{}, fail: map[int]bool{3: true}, jobs: []*fakeJob{
+		{job: Job{Id: 1, EntryId: 10, Action: ActionPublish, RunAt: now.Add(-time.Hour)}, status: StatusPending},
+		{job: Job{Id: 2, EntryId: 11, Action: ActionUnpublish, RunAt: now.Add(time.Hour)}, status: StatusPending},
+		{job: Job{Id: 3, EntryId: 12, Action: ActionPublish, RunAt: now}, status: StatusPending},
+		{job: Job{Id: 4, EntryId: 13, Action: ActionPublish, RunAt: now}, status: StatusCancelled},
+	}}
+
+	var (
+		notifiedMu sync.Mutex
+		notified   []int
+	)
+
+	applied := func(job Job, channelId int) {
+
+		notifiedMu.Lock()
+
+		defer notifiedMu.Unlock()
+
+		notified = append(notified, job.Id)
+	}
+
+	first := &Scheduler{Store: store, Instance: "a", Applied: applied}
+
+	second := &Scheduler{Store: store, Instance: "b", Applied: applied}
+
+	var wait sync.WaitGroup
+
+	for _, scheduler := range []*Scheduler{first, second, first, second} {
+
+		wait.Add(1)
+
+		go func(scheduler *Scheduler) {
+
+			defer wait.Done()
+
+			scheduler.RunOnce(now)
+		}(scheduler)
+	}
+
+	wait.Wait()
+
+	if store.applied[1] != 1 || store.applied[2] != 0 || store.applied[4] != 0 {
+		t.Fatalf("unexpected applied jobs %v", store.applied)
+	}
+
+	if store.jobs[0].status != StatusDone || store.jobs[0].message != "published" {
+		t.Fatalf("expected the due job to be recorded done, got %+v", store.jobs[0])
+	}
+
+	if store.jobs[2].status != StatusFailed || store.jobs[2].message != "entry not found" {
+		t.Fatalf("expected the failing job to be recorded failed, got %+v", store.jobs[2])
+	}
+
+	if store.jobs[1].status != StatusPending {
+		t.Fatalf("expected the future job to stay pending, got %+v", store.jobs[1])
+	}
+
+	if first.RunOnce(now.Add(2*time.Hour)) != 1 || store.applied[2] != 1 {
+		t.Fatalf("expected the future job to run once it is due")
+	}
+
+	if len(notified) != 2 || notified[0] != 1 || notified[1] != 2 {
+		t.Fatalf("expected the applied jobs to be notified once, got %v", notified)
+	}
+}
+
+func TestRunOnceRetakesStaleClaims(t *testing.T) {
+
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	store := &fakeStore{applied: map[int]int{}, fail: map[int]bool{}, jobs: []*fakeJob{
+		{job: Job{Id: 1, Action: ActionPublish, RunAt: now.Add(-time.Hour)}, status: StatusRunning, claimedBy: "gone", claimedOn: now.Add(-time.Minute)},
+	}}
+
+	scheduler := &Scheduler{Store: store, Instance: "a"}
+
+	if scheduler.RunOnce(now) != 0 {
+		t.Fatalf("expected a fresh claim of another instance to be left alone")
+	}
+
+	if scheduler.RunOnce(now.Add(DefaultClaimTimeout)) != 1 || store.jobs[0].status != StatusDone {
+		t.Fatalf("expected a stale claim to be run again, got %+v", store.jobs[0])
+	}
+}
+
+func TestParseInput(t *testing.T) {
+
+	location := time.FixedZone("IST", 5*3600+1800)
+
+	parsed, ok, err := ParseInput("2026-10-18T09:30", location)
+
+	if err != nil || !ok || !parsed.Equal(time.Date(2026, 10, 18, 4, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected parsed time %v %v %v", parsed, ok, err)
+	}
+
+	if FormatInput(parsed, location) != "2026-10-18T09:30" {
+		t.Fatalf("unexpected formatted time %q", FormatInput(parsed, location))
+	}
+
+	if _, ok, err := ParseInput("", location); ok || err != nil {
+		t.Fatalf("expected an empty input to clear the time")
+	}
+
+	if _, _, err := ParseInput("18/10/2026", location); err == nil {
+		t.Fatalf("expected a malformed input to be rejected")
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	publishAt := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	if Validate(publishAt, publishAt) != ErrUnpublishBeforePublish || Validate(publishAt, publishAt.Add(-time.Hour)) != ErrUnpublishBeforePublish {
+		t.Fatalf("expected an unpublish time before the publish time to be rejected")
+	}
+
+	if Validate(publishAt, publishAt.Add(time.Hour)) != nil || Validate(time.Time{}, publishAt) != nil || Validate(publishAt, time.Time{}) != nil {
+		t.Fatalf("unexpected validation error")
+	}
+}
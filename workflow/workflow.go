@@ -0,0 +1,220 @@
+package workflow
+
+import (
+	"errors"
+	"strings"
+)
+
+// entry statuses, draft, published and unpublished are the ones entries always had
+const (
+	Draft       = 0
+	Published   = 1
+	Unpublished = 2
+	InReview    = 3
+	Approved    = 4
+)
+
+// workflows a channel can use
+const (
+	// drafts are published directly
+	None = ""
+
+	// draft, in review, published
+	Review = "review"
+
+	// draft, in review, approved, published
+	Approval = "approval"
+)
+
+const (
+	ActionSubmit    = "submit"
+	ActionApprove   = "approve"
+	ActionReject    = "reject"
+	ActionPublish   = "publish"
+	ActionUnpublish = "unpublish"
+)
+
+// permissions of the Entries module in the roles module that gate the transitions, a transition without one only
+// needs the entries access every editor has
+const (
+	PermissionReview  = "Review"
+	PermissionPublish = "Publish"
+)
+
+var (
+	ErrTransitionNotAllowed = errors.New("the entry can not move to that state from its current one")
+	ErrCommentRequired      = errors.New("a comment is required to reject an entry")
+	ErrReviewRequired       = errors.New("the channel requires a review, submit the entry for review before publishing")
+)
+
+// Transition moves an entry from one of its From statuses to To
+type Transition struct {
+	Action          string `json:"action"`
+	From            []int  `json:"-"`
+	To              int    `json:"to"`
+	Permission      string `json:"permission"`
+	CommentRequired bool   `json:"commentRequired"`
+}
+
+var workflows = map[string][]Transition{
+	None: {
+		{Action: ActionPublish, From: []int{Draft, Unpublished, InReview, Approved}, To: Published},
+		{Action: ActionUnpublish, From: []int{Published}, To: Unpublished},
+	},
+	Review: {
+		{Action: ActionSubmit, From: []int{Draft, Unpublished}, To: InReview},
+		{Action: ActionApprove, From: []int{InReview}, To: Published, Permission: PermissionPublish},
+		{Action: ActionReject, From: []int{InReview, Approved}, To: Draft, Permission: PermissionReview, CommentRequired: true},
+		{Action: ActionPublish, From: []int{Approved, Unpublished}, To: Published, Permission: PermissionPublish},
+		{Action: ActionUnpublish, From: []int{Published}, To: Unpublished, Permission: PermissionPublish},
+	},
+	Approval: {
+		{Action: ActionSubmit, From: []int{Draft, Unpublished}, To: InReview},
+		{Action: ActionApprove, From: []int{InReview}, To: Approved, Permission: PermissionReview},
+		{Action: ActionReject, From: []int{InReview, Approved}, To: Draft, Permission: PermissionReview, CommentRequired: true},
+		{Action: ActionPublish, From: []int{Approved, Unpublished}, To: Published, Permission: PermissionPublish},
+		{Action: ActionUnpublish, From: []int{Published}, To: Unpublished, Permission: PermissionPublish},
+	},
+}
+
+// Valid reports whether a channel can be set to a workflow
+func Valid(workflow string) bool {
+
+	_, ok := workflows[workflow]
+
+	return ok
+}
+
+// Label names an entry status
+func Label(status int) string {
+
+	switch status {
+
+	case Published:
+
+		return "Published"
+
+	case Unpublished:
+
+		return "Unpublished"
+
+	case InReview:
+
+		return "In Review"
+
+	case Approved:
+
+		return "Approved"
+	}
+
+	return "Draft"
+}
+
+// Transitions lists the transitions of a workflow out of a status
+func Transitions(workflow string, from int) []Transition {
+
+	var transitions []Transition
+
+	for _, transition := range workflows[workflow] {
+
+		if contains(transition.From, from) {
+
+			transitions = append(transitions, transition)
+		}
+	}
+
+	return transitions
+}
+
+// Find returns the transition of an action out of a status
+func Find(workflow string, from int, action string) (Transition, error) {
+
+	for _, transition := range Transitions(workflow, from) {
+
+		if transition.Action == action {
+
+			return transition, nil
+		}
+	}
+
+	return Transition{}, ErrTransitionNotAllowed
+}
+
+// Check validates the comment given with a transition
+func (transition Transition) Check(comment string) error {
+
+	if transition.CommentRequired && strings.TrimSpace(comment) == "" {
+
+		return ErrCommentRequired
+	}
+
+	return nil
+}
+
+// Next returns the permission of whoever moves an entry on from a status, empty when nobody has to
+func Next(workflow string, status int) string {
+
+	for _, transition := range Transitions(workflow, status) {
+
+		if transition.Action == ActionApprove || transition.Action == ActionPublish {
+
+			return transition.Permission
+		}
+	}
+
+	return ""
+}
+
+// Save returns the status an editor save leaves an entry in and the permission it needs. Without a workflow the
+// requested status is kept. With one, a publish needs the entry approved or already published, taking a published
+// entry down needs the publish permission, and a draft save of an entry under review keeps it under review, an
+// approved entry has to be reviewed again.
+func Save(workflow string, current int, requested int) (status int, permission string, err error) {
+
+	if workflow == None {
+
+		return requested, "", nil
+	}
+
+	if requested == Published {
+
+		if current == Published {
+
+			return Published, PermissionPublish, nil
+		}
+
+		transition, err := Find(workflow, current, ActionPublish)
+
+		if err != nil || transition.To != Published {
+
+			return current, "", ErrReviewRequired
+		}
+
+		return Published, transition.Permission, nil
+	}
+
+	if current == Published {
+
+		return requested, PermissionPublish, nil
+	}
+
+	if current == InReview || current == Approved {
+
+		return InReview, "", nil
+	}
+
+	return requested, "", nil
+}
+
+func contains(statuses []int, status int) bool {
+
+	for _, candidate := range statuses {
+
+		if candidate == status {
+
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package workflow
+
+import "testing"
+
+func TestFind(t *testing.T) {
+
+	transition, err := Find(Approval, InReview, ActionApprove)
+
+	if err != nil || transition.To != Approved || transition.Permission != PermissionReview {
+		t.Fatalf("unexpected approve transition %+v %v", transition, err)
+	}
+
+	if transition, err := Find(Review, InReview, ActionApprove); err != nil || transition.To != Published || transition.Permission != PermissionPublish {
+		t.Fatalf("expected a single review to publish on approval, got %+v %v", transition, err)
+	}
+
+	if _, err := Find(Approval, Draft, ActionPublish); err != ErrTransitionNotAllowed {
+		t.Fatalf("expected a draft not to be published past the review")
+	}
+
+	if _, err := Find(None, Draft, ActionSubmit); err != ErrTransitionNotAllowed {
+		t.Fatalf("expected channels without a workflow to have no review")
+	}
+}
+
+func TestCheck(t *testing.T) {
+
+	reject, _ := Find(Approval, Approved, ActionReject)
+
+	if reject.Check(" ") != ErrCommentRequired || reject.Check("needs sources") != nil {
+		t.Fatalf("expected a rejection to require a comment")
+	}
+
+	submit, _ := Find(Approval, Draft, ActionSubmit)
+
+	if submit.Check("") != nil {
+		t.Fatalf("expected a submission without a comment")
+	}
+}
+
+func TestNext(t *testing.T) {
+
+	if Next(Approval, InReview) != PermissionReview || Next(Approval, Approved) != PermissionPublish || Next(Review, InReview) != PermissionPublish {
+		t.Fatalf("unexpected next reviewers")
+	}
+
+	if Next(Approval, Published) != "" {
+		t.Fatalf("expected nobody to act on a published entry")
+	}
+}
+
+func TestSave(t *testing.T) {
+
+	cases := []struct {
+		workflow   string
+		current    int
+		requested  int
+		status     int
+		permission string
+		err        error
+	}{
+		{None, Draft, Published, Published, "", nil},
+		{Approval, Draft, Published, Draft, "", ErrReviewRequired},
+		{Approval, InReview, Published, InReview, "", ErrReviewRequired},
+		{Approval, Approved, Published, Published, PermissionPublish, nil},
+		{Approval, Published, Published, Published, PermissionPublish, nil},
+		{Approval, Approved, Draft, InReview, "", nil},
+		{Approval, InReview, Draft, InReview, "", nil},
+		{Review, Published, Draft, Draft, PermissionPublish, nil},
+		{Review, Unpublished, Draft, Draft, "", nil},
+	}
+
+	for _, test := range cases {
+
+		status, permission, err := Save(test.workflow, test.current, test.requested)
+
+		if status != test.status || permission != test.permission || err != test.err {
+			t.Fatalf("unexpected save of %+v: %v %q %v", test, status, permission, err)
+		}
+	}
+}